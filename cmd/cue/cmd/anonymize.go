@@ -0,0 +1,63 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"cuelang.org/go/cue/load"
+)
+
+func newAnonymizeCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "anonymize [packages]",
+		Short: "anonymize packages for sharing",
+		Long: `Anonymize writes an anonymized copy of the given packages as a txtar
+archive, so that they can be shared, for instance to report a bug in
+the evaluator, without revealing a proprietary configuration.
+
+The archive holds the cue.mod/module.cue file of the current module and
+the files of the packages, including the packages they import, as long
+as these are within the module. In the CUE files, field names and other
+declared identifiers are consistently replaced by meaningless names,
+string literals by meaningless strings, and comments are removed. The
+structure of the configuration is preserved, but the results of
+operations on strings, such as regular expressions, may change.
+
+Import paths, attributes, the text of string interpolations, the
+contents of cue.mod and data files are left unchanged.
+
+Packages with errors are anonymized as well, as the errors may be the
+subject of a bug report. See "cue bug" to also record the command that
+shows a bug.
+`,
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, runAnonymize),
+	}
+	cmd.Flags().StringP(string(flagOutFile), "o", "", "write the archive to the given file instead of stdout")
+	return cmd
+}
+
+func runAnonymize(cmd *Command, args []string) error {
+	cfg, err := defaultConfig()
+	if err != nil {
+		return err
+	}
+	a, _, err := moduleFilesArchive(load.Instances(args, cfg.loadCfg), true)
+	if err != nil {
+		return err
+	}
+	return writeArchive(cmd, a)
+}
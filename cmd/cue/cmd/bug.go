@@ -0,0 +1,250 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/txtar"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/mod/module"
+	"cuelang.org/go/tools/anonymize"
+)
+
+func newBugCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bug [flags] [cue] <command> [arguments]",
+		Short: "create a reproducer for a bug report",
+		Long: `Bug creates a reproducer for a bug report: a txtar archive holding
+a cue command along with the files it uses, which can be attached to an
+issue at https://cuelang.org/issues.
+
+The command is given as the arguments to bug, optionally preceded by
+"cue". Flags for bug itself must come before the command:
+
+	cue bug -o bug.txtar export ./foo -e bar
+
+The archive holds the cue.mod/module.cue file of the current module and
+the files of the packages loaded by the command, including the packages
+they import, as long as these are within the module. Dependencies from a
+registry are not included, as they can be fetched again. The comment of
+the archive holds the version of cue and the command in the form of a
+testscript, so that the archive can be run with testscript. Adjust the
+command to state the expected outcome, such as "! exec" for a command
+that is expected to fail.
+
+With --obfuscate, the CUE files are anonymized as with "cue anonymize",
+so that the reproducer does not reveal details of the configuration.
+Anonymization may change the behavior of a configuration, so check that
+the reproducer still shows the bug.
+`,
+		RunE: mkRunE(c, runBug),
+		Args: cobra.MinimumNArgs(1),
+	}
+	// Flags following the command belong to the command.
+	cmd.Flags().SetInterspersed(false)
+	cmd.Flags().StringP(string(flagOutFile), "o", "", "write the archive to the given file instead of stdout")
+	cmd.Flags().Bool(string(flagObfuscate), false, "anonymize the CUE files")
+	return cmd
+}
+
+func runBug(cmd *Command, args []string) error {
+	if args[0] == "cue" {
+		args = args[1:]
+	}
+	pkgArgs, err := bugPackageArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	cfg, err := defaultConfig()
+	if err != nil {
+		return err
+	}
+	// Errors in the instances are not fatal, as they may be the bug
+	// being reported.
+	insts := load.Instances(pkgArgs, cfg.loadCfg)
+	a, root, err := moduleFilesArchive(insts, flagObfuscate.Bool(cmd))
+	if err != nil {
+		return err
+	}
+
+	var comment bytes.Buffer
+	comment.WriteString("# Reproducer created by cue bug.\n#\n")
+	var version bytes.Buffer
+	if err := writeVersion(&version); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(strings.TrimRight(version.String(), "\n"), "\n") {
+		comment.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+	comment.WriteString("\n")
+	if dir, err := filepath.Rel(root, rootWorkingDir); err == nil && dir != "." && filepath.IsLocal(dir) {
+		fmt.Fprintf(&comment, "cd %s\n", quoteScriptArg(filepath.ToSlash(dir)))
+	}
+	comment.WriteString("exec cue")
+	for _, arg := range args {
+		comment.WriteString(" " + quoteScriptArg(arg))
+	}
+	comment.WriteString("\n")
+
+	a.Comment = comment.Bytes()
+	return writeArchive(cmd, a)
+}
+
+// bugPackageArgs returns the arguments of the given cue command that
+// denote packages or files.
+func bugPackageArgs(cmd *Command, args []string) ([]string, error) {
+	sub, rest, err := cmd.root.Find(args)
+	if err != nil {
+		return nil, err
+	}
+	if sub == cmd.root {
+		return nil, fmt.Errorf("unknown command %q", args[0])
+	}
+	if err := sub.ParseFlags(rest); err != nil {
+		return nil, err
+	}
+	pkgArgs := sub.Flags().Args()
+	if sub == cmd.cmdCmd && len(pkgArgs) > 0 {
+		// The first argument is the name of the command.
+		pkgArgs = pkgArgs[1:]
+	}
+	return pkgArgs, nil
+}
+
+// quoteScriptArg quotes arg as needed to use it as an argument in a
+// testscript command.
+func quoteScriptArg(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\n'\"#$\\") {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+}
+
+// moduleFilesArchive returns an archive holding the cue.mod/module.cue
+// file of the module of insts and the files of insts and their
+// dependencies within the module, along with the root directory of the
+// module. If anonymized is set, the CUE files are anonymized.
+func moduleFilesArchive(insts []*build.Instance, anonymized bool) (*txtar.Archive, string, error) {
+	root := rootWorkingDir
+	for _, inst := range insts {
+		if inst.Root != "" {
+			root = inst.Root
+			break
+		}
+	}
+	a := &txtar.Archive{}
+	seen := map[string]bool{}
+	add := func(filename string) error {
+		rel, err := filepath.Rel(root, filename)
+		if err != nil || !filepath.IsLocal(rel) {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		a.Files = append(a.Files, txtar.File{Name: name, Data: data})
+		return nil
+	}
+	if err := add(filepath.Join(root, "cue.mod", "module.cue")); err != nil && !os.IsNotExist(err) {
+		return nil, "", err
+	}
+	for _, inst := range insts {
+		for _, p := range append([]*build.Instance{inst}, inst.Dependencies()...) {
+			for _, f := range append(p.BuildFiles, p.OrphanedFiles...) {
+				if f.Filename == "-" {
+					continue
+				}
+				if err := add(f.Filename); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+	}
+	if anonymized {
+		modPath := ""
+		if len(insts) > 0 {
+			modPath = module.ParseImportPath(insts[0].Module).Path
+		}
+		if err := anonymizeArchive(a, modPath); err != nil {
+			return nil, "", err
+		}
+	}
+	return a, root, nil
+}
+
+// anonymizeArchive anonymizes the CUE files in a, except for those in the
+// cue.mod directory. The packages within the module with path modPath
+// are anonymized along with the files.
+func anonymizeArchive(a *txtar.Archive, modPath string) error {
+	var files []*ast.File
+	var indices []int
+	for i, f := range a.Files {
+		if !strings.HasSuffix(f.Name, ".cue") || strings.HasPrefix(f.Name, "cue.mod/") {
+			continue
+		}
+		file, err := parser.ParseFile(f.Name, f.Data)
+		if err != nil {
+			// Keep files that cannot be parsed, as the parse error may be
+			// the bug being reported.
+			continue
+		}
+		files = append(files, file)
+		indices = append(indices, i)
+	}
+	err := anonymize.Files(files, &anonymize.Config{
+		IsLocal: func(path string) bool {
+			path = module.ParseImportPath(path).Path
+			return modPath != "" && (path == modPath || strings.HasPrefix(path, modPath+"/"))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for i, f := range files {
+		data, err := format.Node(f)
+		if err != nil {
+			return err
+		}
+		a.Files[indices[i]].Data = data
+	}
+	return nil
+}
+
+// writeArchive writes a to the file given by --outfile or to stdout.
+func writeArchive(cmd *Command, a *txtar.Archive) error {
+	data := txtar.Format(a)
+	if out := flagOutFile.String(cmd); out != "" && out != "-" {
+		return os.WriteFile(out, data, 0o666)
+	}
+	_, err := cmd.OutOrStdout().Write(data)
+	return err
+}
@@ -16,8 +16,11 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/tools/flow"
 	"github.com/spf13/cobra"
 )
 
@@ -131,16 +134,47 @@ again).
 		}
 	}
 
+Workflow commands may also declare typed flags in their $flags field.
+Flags follow the command name and are injected into the $flags field
+before the tasks are run. As the flags of a command are only known
+once its inputs are loaded, the values of non-boolean flags must be
+given as --name=value:
+
+	// Deploy the application.
+	command: deploy: {
+		$flags: {
+			// env is the target environment.
+			env: *"staging" | "prod"
+			"dry-run": *false | bool
+		}
+		print: cli.Print & {
+			text: "deploying to \($flags.env)"
+		}
+	}
+
+	$ cue cmd deploy --env=prod --dry-run ./...
+	deploying to prod
+
+To list the workflow commands defined for the given instances, along with
+their documentation, the flags and tags they accept and the tasks they
+create, run
+
+	$ cue cmd --list
+
 Run "cue help commands" for more details on tasks and workflow commands.
 `,
+		ValidArgsFunction: mkCompletion(c, completeCommands),
 		RunE: mkRunE(c, func(cmd *Command, args []string) error {
+			if flagList.Bool(cmd) {
+				return listCommands(cmd, args)
+			}
 			if len(args) == 0 {
 				w := cmd.OutOrStderr()
 				fmt.Fprintln(w, "cmd must be run as one of its subcommands")
 				fmt.Fprintln(w, "Run 'cue help cmd' for known subcommands.")
 				return ErrPrintedError
 			}
-			tools, err := buildTools(cmd, args[1:])
+			tools, err := buildTools(cmd, commandInputs(args[1:]))
 			if err != nil {
 				return err
 			}
@@ -161,6 +195,193 @@ Run "cue help commands" for more details on tasks and workflow commands.
 	cmd.Flags().SetInterspersed(false)
 
 	addInjectionFlags(cmd.Flags(), true, false)
+	cmd.Flags().Bool(string(flagList), false,
+		"list the available commands instead of running one; any arguments select the inputs")
 
 	return cmd
 }
+
+// commandInputs returns the arguments of a workflow command which select
+// its inputs, skipping any flags declared by the command. As the types of
+// these flags are only known once the inputs are loaded, the values of
+// non-boolean flags must be given as --name=value.
+func commandInputs(args []string) []string {
+	var inputs []string
+	for i, arg := range args {
+		if arg == "--" {
+			return append(inputs, args[i+1:]...)
+		}
+		if len(arg) > 1 && arg[0] == '-' {
+			continue
+		}
+		inputs = append(inputs, arg)
+	}
+	return inputs
+}
+
+// listCommands prints the workflow commands defined in the tool files of the
+// instances selected by args.
+func listCommands(cmd *Command, args []string) error {
+	tools, err := buildTools(cmd, args)
+	if err != nil {
+		return err
+	}
+	w := cmd.OutOrStdout()
+	if tools == nil {
+		return nil
+	}
+	root := tools.Value()
+	commands := root.LookupPath(cue.MakePath(cue.Str(commandSection)))
+	if !commands.Exists() {
+		return nil
+	}
+	iter, err := commands.Fields()
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		sub, err := customCommand(cmd, commandSection, name, tools)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(w, "%s", name)
+		if sub.Short != "" {
+			fmt.Fprintf(w, "\t%s", sub.Short)
+		}
+		fmt.Fprintln(w)
+		if sub.Long != "" {
+			for _, line := range strings.Split(sub.Long, "\n") {
+				fmt.Fprintln(w, strings.TrimRight("\t"+line, " \t"))
+			}
+		}
+		if flags := commandFlags(iter.Value()); len(flags) > 0 {
+			fmt.Fprintln(w, "\tflags:")
+			for _, f := range flags {
+				fmt.Fprintf(w, "\t\t%s\n", f)
+			}
+		}
+		if tags := commandTags(iter.Value()); len(tags) > 0 {
+			fmt.Fprintln(w, "\ttags:")
+			for _, t := range tags {
+				fmt.Fprintf(w, "\t\t%s\n", t)
+			}
+		}
+
+		cmdPath := cue.MakePath(cue.Str(commandSection), cue.Str(name))
+		c := flow.New(&flow.Config{
+			Root:           cmdPath,
+			InferTasks:     true,
+			IgnoreConcrete: true,
+		}, tools, newTaskFunc(cmd))
+		if tasks := c.Tasks(); len(tasks) > 0 {
+			fmt.Fprintln(w, "\ttasks:")
+			for _, t := range tasks {
+				fmt.Fprintf(w, "\t\t%s", taskName(cmdPath, t))
+				if kind := taskKind(t.Value()); kind != "" {
+					fmt.Fprintf(w, " (%s)", kind)
+				}
+				if deps := t.Dependencies(); len(deps) > 0 {
+					names := make([]string, len(deps))
+					for i, d := range deps {
+						names[i] = taskName(cmdPath, d)
+					}
+					fmt.Fprintf(w, " after %s", strings.Join(names, ", "))
+				}
+				fmt.Fprintln(w)
+			}
+		}
+	}
+
+	// Tags declared outside of any command apply to all commands.
+	var tags []string
+	iter, _ = root.Fields()
+	for iter.Next() {
+		if iter.Selector().Unquoted() != commandSection {
+			tags = append(tags, commandTags(iter.Value())...)
+		}
+	}
+	if len(tags) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Tags accepted by all commands:")
+		for _, t := range tags {
+			fmt.Fprintf(w, "\t%s\n", t)
+		}
+	}
+	return nil
+}
+
+// commandFlags describes the flags declared in the $flags field of the
+// command v.
+func commandFlags(v cue.Value) []string {
+	iter, err := v.LookupPath(cue.MakePath(cue.Str(flagsField))).Fields(cue.Optional(true))
+	if err != nil {
+		return nil
+	}
+	var flags []string
+	for iter.Next() {
+		f := iter.Value()
+		s := fmt.Sprintf("--%s=%s", iter.Selector().Unquoted(), f.IncompleteKind())
+		if iter.Selector().ConstraintType() == cue.RequiredConstraint {
+			s += " (required)"
+		} else if d, ok := f.Default(); ok && d.IsConcrete() {
+			s += fmt.Sprintf(" (default %v)", d)
+		}
+		flags = append(flags, s)
+	}
+	return flags
+}
+
+// commandTags describes the fields within v that may be set with the -t flag.
+func commandTags(v cue.Value) []string {
+	var tags []string
+	add := func(v cue.Value) {
+		a := v.Attribute("tag")
+		if a.Err() != nil {
+			return
+		}
+		name, err := a.String(0)
+		if err != nil {
+			return
+		}
+		s := fmt.Sprintf("-t %s=%s", name, v.IncompleteKind())
+		if short, ok, _ := a.Lookup(1, "short"); ok {
+			s += fmt.Sprintf(" or -t %s", short)
+		}
+		if d, ok := v.Default(); ok && d.IsConcrete() {
+			s += fmt.Sprintf(" (default %v)", d)
+		}
+		tags = append(tags, s)
+	}
+	v.Walk(func(v cue.Value) bool {
+		add(v)
+		return true
+	}, nil)
+	return tags
+}
+
+// taskName returns the path of t relative to the command at root, or its full
+// path if the task is defined outside of the command.
+func taskName(root cue.Path, t *flow.Task) string {
+	sels := t.Path().Selectors()
+	rootSels := root.Selectors()
+	if len(sels) <= len(rootSels) {
+		return t.Path().String()
+	}
+	for i, sel := range rootSels {
+		if sel.String() != sels[i].String() {
+			return t.Path().String()
+		}
+	}
+	return cue.MakePath(sels[len(rootSels):]...).String()
+}
+
+// taskKind reports the kind of task v, such as "tool/exec.Run".
+func taskKind(v cue.Value) string {
+	kind, err := v.LookupPath(cue.MakePath(cue.Str("$id"))).String()
+	if err != nil {
+		kind, _ = v.LookupPath(cue.MakePath(cue.Str("kind"))).String()
+		kind = legacyKinds[kind]
+	}
+	return kind
+}
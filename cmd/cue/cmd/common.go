@@ -16,10 +16,12 @@ package cmd
 
 import (
 	"cmp"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 	"golang.org/x/text/language"
@@ -32,38 +34,63 @@ import (
 	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/cueconfig"
 	"cuelang.org/go/internal/encoding"
 	"cuelang.org/go/internal/filetypes"
+	"cuelang.org/go/internal/mod/modload"
 )
 
 var requestedVersion = os.Getenv("CUE_SYNTAX_OVERRIDE")
 
 func defaultConfig() (*config, error) {
-	reg, err := getCachedRegistry()
+	// Vendored dependencies are loaded without a registry.
+	var reg modload.Registry
+	vendor := useVendor()
+	if !vendor {
+		var err error
+		reg, err = getCachedRegistry()
+		if err != nil {
+			return nil, err
+		}
+	}
+	workspace, err := findWorkspace()
 	if err != nil {
 		return nil, err
 	}
+	version := internal.APIVersionSupported
+	if requestedVersion != "" {
+		switch {
+		case strings.HasPrefix(requestedVersion, "v0.1"):
+			version = -1000 + 100
+		}
+	}
+	// TODO: consolidate all options into a single CUE_DEBUG variable.
+	trace := os.Getenv("CUE_DEBUG_PARSER_TRACE") != ""
+
+	// Parsed files are cached unless the parser is traced, which
+	// requires files to be parsed every time.
+	var parseCacheDir string
+	if dir, err := cueconfig.CacheDir(os.Getenv); err == nil && !trace {
+		parseCacheDir = filepath.Join(dir, "syntax")
+	}
 	return &config{
 		loadCfg: &load.Config{
 			ParseFile: func(name string, src interface{}) (*ast.File, error) {
-				version := internal.APIVersionSupported
-				if requestedVersion != "" {
-					switch {
-					case strings.HasPrefix(requestedVersion, "v0.1"):
-						version = -1000 + 100
-					}
-				}
 				options := []parser.Option{
 					parser.FromVersion(version),
 					parser.ParseComments,
 				}
-				// TODO: consolidate all options into a single CUE_DEBUG variable.
-				if os.Getenv("CUE_DEBUG_PARSER_TRACE") != "" {
+				if trace {
 					options = append(options, parser.Trace)
 				}
 				return parser.ParseFile(name, src, options...)
 			},
-			Registry: reg,
+			ParseCacheDir: parseCacheDir,
+			ParseCacheKey: fmt.Sprintf("cmd/cue version %d", version),
+			Registry:      reg,
+			Vendor:        vendor,
+			Workspace:     workspace,
 		},
 	}, nil
 }
@@ -111,6 +138,10 @@ type buildPlan struct {
 	expressions []ast.Expr // only evaluate these expressions within results
 	schema      ast.Expr   // selects schema in instance for orphaned values
 
+	// schemaInsts holds the instances from which the schema for
+	// orphaned values is built, if any.
+	schemaInsts []*build.Instance
+
 	// orphan placement flags.
 	perFile    bool
 	useList    bool
@@ -430,7 +461,7 @@ func (p *buildPlan) getDecoders(b *build.Instance) (schemas, values []*decoderIn
 		}
 		switch f.Encoding {
 		case build.Protobuf, build.YAML, build.TOML, build.JSON, build.JSONL,
-			build.Text, build.Binary:
+			build.Markdown, build.Text, build.Binary:
 			if f.Interpretation == build.ProtobufJSON {
 				// Need a schema.
 				values = append(values, &decoderInfo{f, nil})
@@ -602,6 +633,7 @@ func parseArgs(cmd *Command, args []string, cfg *config) (p *buildPlan, err erro
 				return nil, err
 			}
 			p.instance = inst
+			p.schemaInsts = []*build.Instance{schema}
 			p.encConfig.Schema = inst.Value()
 			if p.schema != nil {
 				v := cmd.ctx.BuildExpr(p.schema,
@@ -701,6 +733,16 @@ func (b *buildPlan) parseFlags() (err error) {
 	switch b.cfg.mode {
 	case filetypes.Export:
 		b.encConfig.EscapeHTML = flagEscape.Bool(b.cmd)
+		switch s := flagSortFields.String(b.cmd); s {
+		case "":
+		case "source":
+			b.encConfig.FieldOrder = cue.SourceOrder
+		case "alpha":
+			b.encConfig.FieldOrder = cue.AlphabeticalOrder
+		default:
+			return errors.Newf(token.NoPos,
+				"invalid value %q for --%s: must be source or alpha", s, flagSortFields)
+		}
 	case filetypes.Def:
 		b.encConfig.InlineImports = flagInlineImports.Bool(b.cmd)
 	}
@@ -711,7 +753,13 @@ func buildInstances(cmd *Command, binst []*build.Instance, ignoreErrors bool) ([
 	// TODO:
 	// If there are no files and User is true, then use those?
 	// Always use all files in user mode?
-	instances, err := cmd.ctx.BuildInstances(binst)
+	var instances []cue.Value
+	var err error
+	if cmd.stats != nil {
+		instances, err = buildInstancesWithStats(cmd, binst)
+	} else {
+		instances, err = cmd.ctx.BuildInstances(binst)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -746,6 +794,30 @@ func buildInstances(cmd *Command, binst []*build.Instance, ignoreErrors bool) ([
 	return insts, nil
 }
 
+// buildInstancesWithStats is like [cue.Context.BuildInstances], but builds
+// the instances one at a time to record the stats of each.
+func buildInstancesWithStats(cmd *Command, binst []*build.Instance) ([]cue.Value, error) {
+	var instances []cue.Value
+	var errs errors.Error
+	for _, b := range binst {
+		start, counts := time.Now(), adt.TotalStats()
+		v, err := cmd.ctx.BuildInstances([]*build.Instance{b})
+		cmd.stats.Packages = append(cmd.stats.Packages, PackageStats{
+			ID:       b.ID(),
+			Duration: time.Since(start),
+			CUE:      adt.TotalStats().Since(counts),
+		})
+		if err != nil {
+			errs = errors.Append(errs, errors.Promote(err, ""))
+		}
+		instances = append(instances, v...)
+	}
+	if errs != nil {
+		return instances, errs
+	}
+	return instances, nil
+}
+
 func buildToolInstances(ctx *cue.Context, binst []*build.Instance) ([]*cue.Instance, error) {
 	// Reuse the same context, if there is one, so that the @embed interpreter can be used.
 	// Note that ctx may be nil when we do `cue help cmd`.
@@ -16,8 +16,17 @@ package cmd
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
 )
 
 var validCompletionArgs = []string{"bash", "zsh", "fish", "powershell"}
@@ -46,6 +55,11 @@ $ cue completion fish | source
 
 # To load completions for each session, execute once:
 $ cue completion fish > ~/.config/fish/completions/cue.fish
+
+Besides commands and flags, the completion scripts complete the packages
+within the current directory, the workflow commands defined in _tool.cue
+files for "cue cmd", and the fields of the loaded configuration for the
+--expression flag.
 `
 
 func newCompletionCmd(c *Command) *cobra.Command {
@@ -77,3 +91,156 @@ func runCompletion(cmd *Command, args []string) error {
 	}
 	return nil
 }
+
+type completionFunction func(cmd *Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// mkCompletion wraps f for use as a cobra completion function.
+// Completion functions are called by cobra's hidden __complete command,
+// which bypasses mkRunE, so only the minimal setup is done here.
+func mkCompletion(c *Command, f completionFunction) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		c.Command = cmd
+		if c.ctx == nil {
+			c.ctx = cuecontext.New()
+		}
+		return f(c, args, toComplete)
+	}
+}
+
+// completionMaxDepth is the maximum depth of the directories below the
+// current directory whose packages are completed.
+const completionMaxDepth = 4
+
+// completePackages completes the relative paths of the packages within the
+// current directory, as well as the names of the CUE files in the directory
+// being completed. The shell falls back to completing file names if there
+// are no matches, as inputs may also be data files.
+//
+// As completion runs on every key press, packages are only completed when
+// the current directory is within a module, and only up to
+// completionMaxDepth directories deep. Directories that cannot hold the
+// packages of the module, such as cue.mod, vendor and those of nested
+// modules, are skipped.
+func completePackages(cmd *Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var comps []string
+	add := func(s string) {
+		if strings.HasPrefix(s, toComplete) && !slices.Contains(comps, s) {
+			comps = append(comps, s)
+		}
+	}
+
+	// Complete file names as typed so far, such as "x.cue" or "./a/x.cue".
+	fileDir, filePrefix := ".", ""
+	if i := strings.LastIndexByte(toComplete, '/'); i >= 0 {
+		fileDir, filePrefix = path.Clean(toComplete[:i+1]), toComplete[:i+1]
+	}
+	entries, _ := os.ReadDir(filepath.FromSlash(fileDir))
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".cue") {
+			add(filePrefix + e.Name())
+		}
+	}
+
+	if _, err := findModuleRoot(); err == nil {
+		_ = filepath.WalkDir(".", func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			name := d.Name()
+			if d.IsDir() {
+				if p == "." {
+					return nil
+				}
+				if name == "cue.mod" || name == "vendor" ||
+					strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+					strings.Count(p, string(filepath.Separator)) >= completionMaxDepth {
+					return filepath.SkipDir
+				}
+				if _, err := os.Stat(filepath.Join(p, "cue.mod")); err == nil {
+					return filepath.SkipDir // a nested module
+				}
+				return nil
+			}
+			if !strings.HasSuffix(name, ".cue") {
+				return nil
+			}
+			if dir := filepath.ToSlash(filepath.Dir(p)); dir == "." {
+				add(".")
+			} else {
+				add("./" + dir)
+				add("./" + dir + "/...")
+			}
+			add("./...")
+			return nil
+		})
+	}
+	if len(comps) == 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	slices.Sort(comps)
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCommands completes the names of the workflow commands defined in
+// the _tool.cue files of the instances given by args, followed by their
+// short descriptions. Once the command name is given, packages are
+// completed.
+func completeCommands(cmd *Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return completePackages(cmd, args, toComplete)
+	}
+	tools, err := buildTools(cmd, nil)
+	if err != nil || tools == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	parent := &cobra.Command{}
+	addCustomCommands(cmd, parent, commandSection, tools)
+	var comps []string
+	for _, sub := range parent.Commands() {
+		if strings.HasPrefix(sub.Name(), toComplete) {
+			comps = append(comps, sub.Name()+"\t"+sub.Short)
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeExpression completes the field paths of the instance given by
+// args, as accepted by the --expression flag. Fields are completed one
+// selector at a time.
+func completeExpression(cmd *Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	const directive = cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+
+	cfg, err := defaultConfig()
+	if err != nil {
+		return nil, directive
+	}
+	setTags(cfg.loadCfg, cmd.Flags())
+	insts := loadFromArgs(args, cfg.loadCfg)
+	if len(insts) == 0 || insts[0].Err != nil {
+		return nil, directive
+	}
+	v := cmd.ctx.BuildInstance(insts[0])
+
+	prefix, partial := "", toComplete
+	if i := strings.LastIndexByte(toComplete, '.'); i >= 0 {
+		prefix, partial = toComplete[:i+1], toComplete[i+1:]
+		v = v.LookupPath(cue.ParsePath(toComplete[:i]))
+	}
+	iter, err := v.Fields(cue.Definitions(true), cue.Optional(true))
+	if err != nil {
+		return nil, directive
+	}
+	var comps []string
+	for iter.Next() {
+		sel := iter.Selector().String()
+		if !strings.HasPrefix(sel, partial) {
+			continue
+		}
+		comps = append(comps, prefix+sel)
+		if iter.Value().IncompleteKind() == cue.StructKind {
+			// Allow continuing with the fields of a struct.
+			comps = append(comps, prefix+sel+".")
+		}
+	}
+	return comps, directive
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/errors"
@@ -33,6 +34,7 @@ import (
 	itask "cuelang.org/go/internal/task"
 	"cuelang.org/go/internal/value"
 	_ "cuelang.org/go/pkg/tool/cli" // Register tasks
+	_ "cuelang.org/go/pkg/tool/docker"
 	_ "cuelang.org/go/pkg/tool/exec"
 	_ "cuelang.org/go/pkg/tool/file"
 	_ "cuelang.org/go/pkg/tool/http"
@@ -42,6 +44,9 @@ import (
 
 const commandSection = "command"
 
+// flagsField is the field of a command which declares its flags.
+const flagsField = "$flags"
+
 func lookupString(obj cue.Value, key, def string) string {
 	str, err := obj.LookupPath(cue.MakePath(cue.Str(key))).String()
 	if err == nil {
@@ -133,22 +138,113 @@ func customCommand(c *Command, typ, name string, tools *cue.Instance) (*cobra.Co
 		Use:   usage,
 		Short: lookupString(o, "$short", short),
 		Long:  lookupString(o, "$long", long),
-		// Note that we don't use mkRunE here, as the parent func is already wrapped by
-		// another mkRunE call, and all Command initialization has already happened.
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// TODO:
-			// - parse flags and env vars
-			// - constrain current config with config section
-
-			return doTasks(c, name, tools)
-		},
 	}
-
-	// TODO: implement var/flag handling.
+	sub.Flags().SortFlags = false
+	// Invalid flags are reported when running the command, so that its
+	// remaining documentation is still available.
+	flagErr := addCommandFlags(sub, o)
+	// Note that we don't use mkRunE here, as the parent func is already wrapped by
+	// another mkRunE call, and all Command initialization has already happened.
+	sub.RunE = func(cmd *cobra.Command, args []string) error {
+		// TODO:
+		// - parse env vars
+		// - constrain current config with config section
+
+		if flagErr != nil {
+			return flagErr
+		}
+		root, err := injectCommandFlags(sub, tools.Value(), name, args)
+		if err != nil {
+			return err
+		}
+		return doTasks(c, name, root)
+	}
 	return sub, nil
 }
 
-func doTasks(cmd *Command, command string, root *cue.Instance) error {
+// addCommandFlags adds a flag to sub for each field declared in the $flags
+// field of the command o. The type of a flag is derived from the type of its
+// field, its default value from the default of the field, if any, and its
+// usage from the doc comment of the field.
+func addCommandFlags(sub *cobra.Command, o cue.Value) error {
+	flags := o.LookupPath(cue.MakePath(cue.Str(flagsField)))
+	if !flags.Exists() {
+		return nil
+	}
+	iter, err := flags.Fields(cue.Optional(true))
+	if err != nil {
+		return err
+	}
+	fs := sub.Flags()
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		v := iter.Value()
+		usage := flagUsage(v)
+		d, _ := v.Default()
+		switch k := v.IncompleteKind(); k {
+		case cue.StringKind:
+			s, _ := d.String()
+			fs.String(name, s, usage)
+		case cue.IntKind:
+			i, _ := d.Int64()
+			fs.Int64(name, i, usage)
+		case cue.BoolKind:
+			b, _ := d.Bool()
+			fs.Bool(name, b, usage)
+		default:
+			return errors.Newf(v.Pos(),
+				"unsupported type %v for flag %s; must be string, int, or bool", k, name)
+		}
+		if iter.Selector().ConstraintType() == cue.RequiredConstraint {
+			sub.MarkFlagRequired(name)
+		}
+	}
+	return nil
+}
+
+// flagUsage returns the doc comment of the flag field v as a single line.
+func flagUsage(v cue.Value) string {
+	var lines []string
+	for _, cg := range v.Doc() {
+		lines = append(lines, strings.Fields(cg.Text())...)
+	}
+	return strings.Join(lines, " ")
+}
+
+// injectCommandFlags parses the flags of the custom command sub in args and
+// fills the values of the flags that were set into the $flags field of the
+// command called name within root.
+func injectCommandFlags(sub *cobra.Command, root cue.Value, name string, args []string) (cue.Value, error) {
+	fs := sub.Flags()
+	if err := fs.Parse(args); err != nil {
+		return cue.Value{}, err
+	}
+	if err := sub.ValidateRequiredFlags(); err != nil {
+		return cue.Value{}, err
+	}
+	var err error
+	fs.Visit(func(f *pflag.Flag) {
+		var x any
+		switch f.Value.Type() {
+		case "bool":
+			x, _ = fs.GetBool(f.Name)
+		case "int64":
+			x, _ = fs.GetInt64(f.Name)
+		default:
+			x = f.Value.String()
+		}
+		p := cue.MakePath(cue.Str(commandSection), cue.Str(name), cue.Str(flagsField), cue.Str(f.Name))
+		root = root.FillPath(p, x)
+		if e := root.LookupPath(p).Err(); e != nil && err == nil {
+			err = errors.Append(
+				errors.Newf(token.NoPos, "invalid value %q for flag --%s", f.Value, f.Name),
+				errors.Promote(e, ""))
+		}
+	})
+	return root, err
+}
+
+func doTasks(cmd *Command, command string, root cue.InstanceOrValue) error {
 	cfg := &flow.Config{
 		Root:           cue.MakePath(cue.Str(commandSection), cue.Str(command)),
 		InferTasks:     true,
@@ -253,8 +349,8 @@ func newTaskFunc(cmd *Command) flow.TaskFunc {
 			c := &itask.Context{
 				Context: t.Context(),
 				Stdin:   cmd.InOrStdin(),
-				Stdout:  cmd.OutOrStdout(),
-				Stderr:  cmd.OutOrStderr(),
+				Stdout:  t.Output(cmd.OutOrStdout()),
+				Stderr:  t.Output(cmd.OutOrStderr()),
 				Obj:     obj,
 			}
 			value, err := runner.Run(c)
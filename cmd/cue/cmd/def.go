@@ -31,8 +31,16 @@ func newDefCmd(c *Command) *cobra.Command {
 Printing is skipped if validation fails.
 
 The --expression flag is used to only print parts of a configuration.
+
+The --inline-imports flag makes the output self-contained, so that it
+can be used by tools that cannot resolve imports: references to values of
+imported packages, other than builtin packages such as "strings", are
+replaced by the referenced values. Values that are referred to more than
+once, or that refer to themselves, are instead declared once in a let
+clause at the end of the file, with a comment giving their original path.
 `,
-		RunE: mkRunE(c, runDef),
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, runDef),
 	}
 
 	addOutFlags(cmd.Flags(), true)
@@ -40,6 +48,7 @@ The --expression flag is used to only print parts of a configuration.
 	addInjectionFlags(cmd.Flags(), false, false)
 
 	cmd.Flags().StringArrayP(string(flagExpression), "e", nil, "evaluate this expression only")
+	cmd.RegisterFlagCompletionFunc(string(flagExpression), mkCompletion(c, completeExpression))
 
 	cmd.Flags().BoolP(string(flagAttributes), "A", false,
 		"display field attributes")
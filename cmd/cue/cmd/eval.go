@@ -49,14 +49,17 @@ Examples:
   "a"
   "c"
 `,
-		RunE: mkRunE(c, runEval),
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, watchable(runEval)),
 	}
 
 	addOutFlags(cmd.Flags(), true)
 	addOrphanFlags(cmd.Flags())
 	addInjectionFlags(cmd.Flags(), false, false)
+	addWatchFlag(cmd.Flags())
 
 	cmd.Flags().StringArrayP(string(flagExpression), "e", nil, "evaluate this expression only")
+	cmd.RegisterFlagCompletionFunc(string(flagExpression), mkCompletion(c, completeExpression))
 
 	cmd.Flags().BoolP(string(flagConcrete), "c", false,
 		"require the evaluation to be concrete")
@@ -0,0 +1,265 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/debug"
+	"cuelang.org/go/internal/core/eval"
+	"cuelang.org/go/internal/core/runtime"
+	"cuelang.org/go/internal/value"
+)
+
+func newExplainCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain <path> [inputs]",
+		Short: "show where the value of a field comes from",
+		Long: `explain prints the conjuncts that contribute to the value of a field.
+
+The first argument is the path of the field, such as "a.b" or
+"#Def.list[0]". The remaining arguments select the instances to load, as
+for the other commands. For each instance, explain prints the final value
+of the field, followed by the position and source of every conjunct that
+was unified to obtain it.
+
+Conjuncts that are references are followed, and the conjuncts of the
+referenced value are listed below them, indented. Conjuncts that stem from
+a pattern constraint, such as [string]: int, are marked as such.
+For conjuncts that are disjunctions, explain shows which disjuncts remain
+compatible with the final value, and whether a default was selected.
+
+Examples:
+
+  $ cat <<EOF > foo.cue
+  a: *1 | int
+  a: b
+  b: 3
+  EOF
+
+  $ cue explain a foo.cue
+  a: 3
+      ./foo.cue:1:4: *1 | int (selects int)
+      ./foo.cue:2:4: b
+          ./foo.cue:3:4: 3
+`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: mkCompletion(c, completeExplain),
+		RunE:              mkRunE(c, runExplain),
+	}
+
+	addOrphanFlags(cmd.Flags())
+	addInjectionFlags(cmd.Flags(), false, false)
+
+	return cmd
+}
+
+// completeExplain completes the field path given as the first argument,
+// and the inputs after that.
+func completeExplain(cmd *Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeExpression(cmd, nil, toComplete)
+	}
+	return completePackages(cmd, args[1:], toComplete)
+}
+
+func runExplain(cmd *Command, args []string) error {
+	path := cue.ParsePath(args[0])
+	if err := path.Err(); err != nil {
+		return err
+	}
+	b, err := parseArgs(cmd, args[1:], &config{})
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	iter := b.instances()
+	defer iter.close()
+	for i := 0; iter.scan(); i++ {
+		v := iter.value().LookupPath(path)
+		if err := v.Err(); err != nil && !v.Exists() {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		final, _ := v.Default()
+		fmt.Fprintf(w, "%s: %s\n", path, oneLine(fmt.Sprint(final)))
+
+		r, x := value.ToInternal(v)
+		e := &explainer{
+			w:       w,
+			r:       r,
+			ctx:     eval.NewContext(r, x),
+			final:   final,
+			visited: map[*adt.Vertex]bool{x: true},
+		}
+		e.conjuncts(x, 1)
+	}
+	return iter.err()
+}
+
+// An explainer prints the conjuncts of a vertex.
+type explainer struct {
+	w     io.Writer
+	r     *runtime.Runtime
+	ctx   *adt.OpContext
+	final cue.Value
+
+	// visited holds the vertices that are already being explained,
+	// to avoid following reference cycles.
+	visited map[*adt.Vertex]bool
+}
+
+// conjuncts prints the leaf conjuncts of x, indented by the given depth.
+func (e *explainer) conjuncts(x *adt.Vertex, depth int) {
+	x.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		e.conjunct(x, c, depth)
+		return true
+	})
+}
+
+func (e *explainer) conjunct(x *adt.Vertex, c adt.Conjunct, depth int) {
+	indent := strings.Repeat("    ", depth)
+
+	var pos token.Pos
+	var text string
+	var notes []string
+
+	src := c.Source()
+	_, isPattern := c.Field().(*adt.BulkOptionalField)
+	switch f := src.(type) {
+	case *ast.Field:
+		if isPattern {
+			pos, text = f.Pos(), e.format(f)
+		} else {
+			pos, text = f.Value.Pos(), e.format(f.Value)
+		}
+	case nil:
+		text = debug.NodeString(e.r, c.Expr(), &debug.Config{Compact: true})
+	default:
+		pos, text = src.Pos(), e.format(src)
+	}
+	if isPattern {
+		notes = append(notes, "pattern constraint")
+	}
+	if note := e.disjunction(x, c); note != "" {
+		notes = append(notes, note)
+	}
+
+	fmt.Fprintf(e.w, "%s%s: %s", indent, position(pos), text)
+	if len(notes) > 0 {
+		fmt.Fprintf(e.w, " (%s)", strings.Join(notes, "; "))
+	}
+	fmt.Fprintln(e.w)
+
+	env, expr := c.EnvExpr()
+	r, ok := expr.(adt.Resolver)
+	if !ok {
+		return
+	}
+	arc, _ := e.ctx.Resolve(adt.MakeConjunct(env, expr, c.CloseInfo), r)
+	if arc == nil || e.visited[arc] {
+		return
+	}
+	e.visited[arc] = true
+	e.conjuncts(arc, depth+1)
+	delete(e.visited, arc)
+}
+
+// disjunction reports which disjuncts of the conjunct c of x are compatible
+// with the final value. It returns the empty string if c does not evaluate
+// to a disjunction.
+func (e *explainer) disjunction(x *adt.Vertex, c adt.Conjunct) string {
+	n := &adt.Vertex{Parent: x.Parent, Label: x.Label}
+	n.AddConjunct(c)
+	n.Finalize(e.ctx)
+	d, ok := n.BaseValue.(*adt.Disjunction)
+	if !ok {
+		return ""
+	}
+
+	var selected []string
+	def := ""
+	for i, dv := range d.Values {
+		dc := value.Make(e.ctx, dv)
+		if dc.Unify(e.final).Err() != nil {
+			continue
+		}
+		s := oneLine(fmt.Sprint(dc))
+		if i < d.NumDefaults {
+			s = "*" + s
+			if def == "" {
+				def = s
+			}
+		}
+		selected = append(selected, s)
+	}
+	switch {
+	case len(selected) == 0:
+		return "no disjunct matches"
+	case len(selected) == 1:
+		return "selects " + selected[0]
+	case def != "" && e.final.IsConcrete():
+		return "selects default " + def
+	}
+	return "leaves " + strings.Join(selected, " | ")
+}
+
+// format returns the source of n on a single line.
+func (e *explainer) format(n ast.Node) string {
+	b, err := format.Node(n)
+	if err != nil {
+		return fmt.Sprint(n)
+	}
+	return oneLine(string(b))
+}
+
+// oneLine returns the first line of s, followed by an ellipsis if s spans
+// multiple lines.
+func oneLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " ..."
+	}
+	return s
+}
+
+// position formats pos relative to the current directory, in the same way
+// as the positions of errors.
+func position(pos token.Pos) string {
+	if !pos.IsValid() {
+		return "-"
+	}
+	p := pos.Position()
+	path := p.Filename
+	if rel, err := filepath.Rel(rootWorkingDir, path); err == nil {
+		path = rel
+		if !strings.HasPrefix(path, ".") {
+			path = fmt.Sprintf(".%c%s", filepath.Separator, path)
+		}
+	}
+	return fmt.Sprintf("%s:%d:%d", path, p.Line, p.Column)
+}
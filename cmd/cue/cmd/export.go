@@ -15,8 +15,23 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
 	"github.com/spf13/cobra"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
 	"cuelang.org/go/internal/encoding"
 	"cuelang.org/go/internal/filetypes"
 )
@@ -76,6 +91,20 @@ If the package is not explicitly defined by the '-p' flag, it must be uniquely
 defined by the files in the current directory.
 
 
+Incomplete values
+
+All exported values must be concrete. The --allow-incomplete flag, which may
+be repeated, selects values that may remain incomplete with a path pattern
+relative to the exported value. A pattern is a path in which a field may be
+replaced by *, a list index by [*], and any number of fields and indices by
+**. Values may also be marked with an @export(incomplete) attribute. Such
+values are omitted from the output if they are not concrete; they are still
+reported if they are in error for other reasons.
+
+	# everything except the image fields of containers must be concrete
+	cue export --allow-incomplete 'spec.containers[*].image' ./k8s
+
+
 Formats
 
 The following formats are recognized:
@@ -94,17 +123,53 @@ The following formats are recognized:
 
  binary  output as raw binary
               The evaluated value must be of type string or bytes.
+
+Splitting output
+
+The --outdir flag writes each element of a top-level list, or each regular
+field of a top-level struct, to its own file in the given directory. Files
+are named after the field name or list index, unless --split is given: it is
+a CUE expression evaluated within each element that must yield the name of
+the file to write. The file extension is derived from the output format.
+
+	# write each Kubernetes object to a file named after its metadata.name
+	cue export --out yaml --outdir manifests --split metadata.name ./k8s
+
+With --tree, the exported value must be a struct whose field names are file
+paths relative to the --outdir directory, which may include subdirectories.
+The encoding of each file is derived from its extension, such as .json or
+.yaml. Files with other extensions are written as text or binary, in which
+case the field must be a string or bytes. Permissions may be set with a
+@file(permissions=0o755) attribute on the field; by default files are
+created with 0o666, subject to the umask.
+
+	$ cat <<EOF > site.cue
+	files: {
+		"config/app.yaml": {port: 8080}
+		"bin/start.sh":    "#!/bin/sh\nexec app\n" @file(permissions=0o755)
+	}
+	EOF
+
+	$ cue export --outdir out --tree -e files site.cue
 `,
 		// TODO: some formats are missing for sure, like "jsonl" or "textproto" from internal/filetypes/types.cue.
-		RunE: mkRunE(c, runExport),
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, watchable(runExport)),
 	}
 
 	addOutFlags(cmd.Flags(), true)
 	addOrphanFlags(cmd.Flags())
 	addInjectionFlags(cmd.Flags(), false, false)
+	addWatchFlag(cmd.Flags())
 
+	cmd.Flags().StringArray(string(flagAllowIncomplete), nil, "omit the values matching this path pattern from the output if they are not concrete")
 	cmd.Flags().Bool(string(flagEscape), false, "use HTML escaping")
 	cmd.Flags().StringArrayP(string(flagExpression), "e", nil, "export this expression only")
+	cmd.RegisterFlagCompletionFunc(string(flagExpression), mkCompletion(c, completeExpression))
+	cmd.Flags().String(string(flagOutDir), "", "write each element of the output to its own file in this directory")
+	cmd.Flags().String(string(flagSortFields), "", "order struct fields by \"source\" position or \"alpha\"betically instead of topologically")
+	cmd.Flags().String(string(flagSplit), "", "expression evaluated within each element to name its file; requires --outdir")
+	cmd.Flags().Bool(string(flagTree), false, "treat field names as file paths, with encodings derived from their extensions; requires --outdir")
 
 	return cmd
 }
@@ -115,16 +180,31 @@ func runExport(cmd *Command, args []string) error {
 		return err
 	}
 
+	if dir := flagOutDir.String(cmd); dir != "" {
+		if flagTree.Bool(cmd) {
+			return exportTree(cmd, b, dir)
+		}
+		return exportSplit(cmd, b, dir)
+	} else if flagSplit.String(cmd) != "" {
+		return errors.Newf(token.NoPos, "--split requires --outdir")
+	} else if flagTree.Bool(cmd) {
+		return errors.Newf(token.NoPos, "--tree requires --outdir")
+	}
+
 	enc, err := encoding.NewEncoder(cmd.ctx, b.outFile, b.encConfig)
 	if err != nil {
 		return err
 	}
 
+	attrs := b.hasExportAttrs()
 	iter := b.instances()
 	defer iter.close()
 	for iter.scan() {
-		v := iter.value()
-		err := enc.Encode(v)
+		v, err := omitIncomplete(cmd, iter.value(), attrs)
+		if err != nil {
+			return err
+		}
+		err = enc.Encode(v)
 		if err != nil {
 			return err
 		}
@@ -137,3 +217,363 @@ func runExport(cmd *Command, args []string) error {
 	}
 	return nil
 }
+
+// splitExt maps output encodings to the file extension used for
+// files written by --outdir.
+var splitExt = map[build.Encoding]string{
+	build.CUE:    ".cue",
+	build.JSON:   ".json",
+	build.YAML:   ".yaml",
+	build.TOML:   ".toml",
+	build.Text:   ".txt",
+	build.Binary: ".bin",
+}
+
+// exportSplit writes each element of the exported values to a separate
+// file in dir.
+func exportSplit(cmd *Command, b *buildPlan, dir string) error {
+	if b.outFile.Filename != "-" {
+		return errors.Newf(token.NoPos, "cannot combine --outdir with --outfile")
+	}
+	ext, ok := splitExt[b.outFile.Encoding]
+	if !ok {
+		return errors.Newf(token.NoPos, "--outdir does not support encoding %q", b.outFile.Encoding)
+	}
+	var split ast.Expr
+	if s := flagSplit.String(cmd); s != "" {
+		var err error
+		if split, err = parser.ParseExpr("--split", s); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return err
+	}
+
+	written := map[string]cue.Path{}
+	attrs := b.hasExportAttrs()
+	iter := b.instances()
+	defer iter.close()
+	for iter.scan() {
+		v, err := omitIncomplete(cmd, iter.value(), attrs)
+		if err != nil {
+			return err
+		}
+		elems, err := splitElems(v)
+		if err != nil {
+			return err
+		}
+		for _, e := range elems {
+			name := e.defName
+			if split != nil {
+				nv := v.Context().BuildExpr(split, cue.Scope(e.v), cue.InferBuiltins(true))
+				if name, err = nv.String(); err != nil {
+					return errors.Wrapf(err, e.v.Pos(), "invalid file name for %v", e.v.Path())
+				}
+			}
+			if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+				return errors.Newf(e.v.Pos(), "invalid file name %q for %v", name, e.v.Path())
+			}
+			if p, ok := written[name]; ok {
+				return errors.Newf(e.v.Pos(), "file name %q for %v already used for %v", name, e.v.Path(), p)
+			}
+			written[name] = e.v.Path()
+
+			f := *b.outFile
+			f.Filename = filepath.Join(dir, name+ext)
+			enc, err := encoding.NewEncoder(cmd.ctx, &f, b.encConfig)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(e.v); err != nil {
+				return err
+			}
+			if err := enc.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return iter.err()
+}
+
+// exportTree writes the fields of the exported struct to the files in dir
+// named by their labels.
+func exportTree(cmd *Command, b *buildPlan, dir string) error {
+	if b.outFile.Filename != "-" {
+		return errors.Newf(token.NoPos, "cannot combine --outdir with --outfile")
+	}
+	if flagSplit.String(cmd) != "" {
+		return errors.Newf(token.NoPos, "cannot combine --tree with --split")
+	}
+
+	written := map[string]cue.Path{}
+	attrs := b.hasExportAttrs()
+	iter := b.instances()
+	defer iter.close()
+	for iter.scan() {
+		v, err := omitIncomplete(cmd, iter.value(), attrs)
+		if err != nil {
+			return err
+		}
+		if k := v.IncompleteKind(); k != cue.StructKind {
+			return errors.Newf(v.Pos(), "--tree requires a struct, found %v", k)
+		}
+		fields, err := v.Fields()
+		if err != nil {
+			return err
+		}
+		for fields.Next() {
+			name := fields.Selector().Unquoted()
+			fv := fields.Value()
+			if name == "" || path.Clean(name) != name || !filepath.IsLocal(filepath.FromSlash(name)) {
+				return errors.Newf(fv.Pos(), "invalid file path %q for %v", name, fv.Path())
+			}
+			if p, ok := written[name]; ok {
+				return errors.Newf(fv.Pos(), "file path %q for %v already used for %v", name, fv.Path(), p)
+			}
+			written[name] = fv.Path()
+
+			perm, err := filePermissions(fv)
+			if err != nil {
+				return err
+			}
+			f, err := treeFile(fv, filepath.Join(dir, filepath.FromSlash(name)))
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(f.Filename), 0o777); err != nil {
+				return err
+			}
+			enc, err := encoding.NewEncoder(cmd.ctx, f, b.encConfig)
+			if err != nil {
+				return err
+			}
+			if err := enc.Encode(fv); err != nil {
+				return err
+			}
+			if err := enc.Close(); err != nil {
+				return err
+			}
+			if perm != 0 {
+				if err := os.Chmod(f.Filename, perm); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return iter.err()
+}
+
+// treeFile returns the file to which --tree writes v, deriving its encoding
+// from the extension of filename.
+func treeFile(v cue.Value, filename string) (*build.File, error) {
+	f, err := filetypes.ParseFileAndType(filename, "", filetypes.Export)
+	if err == nil && f.Encoding != build.Text {
+		return f, nil
+	}
+	// Write text and the values of files with unknown extensions as is.
+	// Unlike the text encoding, the binary one does not add a final
+	// newline.
+	switch v.IncompleteKind() {
+	case cue.StringKind, cue.BytesKind:
+		return &build.File{Filename: filename, Encoding: build.Binary}, nil
+	}
+	if err == nil {
+		return f, nil
+	}
+	return nil, errors.Wrapf(err, v.Pos(),
+		"cannot write %v to %s: not a string or bytes", v.Path(), filepath.ToSlash(filename))
+}
+
+// filePermissions returns the permissions set by a @file(permissions=x)
+// attribute on v, or 0 if there is none.
+func filePermissions(v cue.Value) (os.FileMode, error) {
+	a := v.Attribute("file")
+	if a.Err() != nil {
+		return 0, nil
+	}
+	s, ok, err := a.Lookup(0, "permissions")
+	if err != nil || !ok {
+		return 0, err
+	}
+	perm, err := strconv.ParseUint(s, 0, 32)
+	if err != nil || perm > 0o7777 {
+		return 0, errors.Newf(v.Pos(), "invalid permissions %q for %v", s, v.Path())
+	}
+	return os.FileMode(perm), nil
+}
+
+type splitElem struct {
+	v       cue.Value
+	defName string // name used in absence of --split
+}
+
+// splitElems returns the elements of a list or the regular fields of
+// a struct.
+func splitElems(v cue.Value) ([]splitElem, error) {
+	var elems []splitElem
+	switch v.IncompleteKind() {
+	case cue.ListKind:
+		iter, err := v.List()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; iter.Next(); i++ {
+			elems = append(elems, splitElem{iter.Value(), strconv.Itoa(i)})
+		}
+	case cue.StructKind:
+		iter, err := v.Fields()
+		if err != nil {
+			return nil, err
+		}
+		for iter.Next() {
+			elems = append(elems, splitElem{iter.Value(), iter.Selector().Unquoted()})
+		}
+	default:
+		return nil, errors.Newf(v.Pos(), "--outdir requires a list or struct, found %v", v.IncompleteKind())
+	}
+	return elems, nil
+}
+
+// hasExportAttrs reports whether the CUE files of b, including those of the
+// packages they import, contain an @export attribute.
+func (b *buildPlan) hasExportAttrs() bool {
+	insts := slices.Clone(b.insts)
+	if b.instance != nil {
+		if inst := b.instance.Value().BuildInstance(); inst != nil {
+			insts = append(insts, inst)
+		}
+	}
+	seen := map[*build.Instance]bool{}
+	found := false
+	var visit func(inst *build.Instance)
+	visit = func(inst *build.Instance) {
+		if found || seen[inst] {
+			return
+		}
+		seen[inst] = true
+		for _, f := range inst.Files {
+			ast.Walk(f, func(n ast.Node) bool {
+				if a, ok := n.(*ast.Attribute); ok {
+					if key, _ := a.Split(); key == "export" {
+						found = true
+					}
+				}
+				return !found
+			}, nil)
+		}
+		for _, imp := range inst.Imports {
+			visit(imp)
+		}
+	}
+	for _, inst := range insts {
+		visit(inst)
+	}
+	return found
+}
+
+// omitIncomplete returns v without the values that may remain incomplete,
+// as selected by the --allow-incomplete patterns or, if attrs is set, an
+// @export(incomplete) attribute, and that are not concrete. Such values are
+// omitted from the output rather than reported as errors. Values that are in
+// error for other reasons are kept, so that their errors are reported as
+// usual.
+func omitIncomplete(cmd *Command, v cue.Value, attrs bool) (cue.Value, error) {
+	patterns := flagAllowIncomplete.StringArray(cmd)
+	if len(patterns) == 0 && !attrs {
+		return v, nil
+	}
+	root := v.Path().String()
+	omit := map[string]bool{}
+	add := func(w cue.Value) {
+		p := w.Path().String()
+		if p != root && w.Validate(cue.Concrete(true)) != nil && w.Validate() == nil {
+			omit[p] = true
+		}
+	}
+	for _, pattern := range patterns {
+		values, err := v.MatchPath(pattern)
+		if err != nil {
+			return v, err
+		}
+		for _, w := range values {
+			add(w)
+		}
+	}
+	if attrs {
+		v.Walk(func(w cue.Value) bool {
+			a := w.Attribute("export")
+			if a.Err() != nil {
+				return true
+			}
+			if ok, _ := a.Flag(0, "incomplete"); ok {
+				add(w)
+				return false
+			}
+			return true
+		}, nil)
+	}
+	if len(omit) == 0 {
+		return v, nil
+	}
+
+	// Only the values containing omitted values need to be rebuilt.
+	parents := map[string]bool{}
+	for p := range omit {
+		sels := cue.ParsePath(p).Selectors()
+		for i := range sels {
+			parents[cue.MakePath(sels[:i]...).String()] = true
+		}
+	}
+	var rebuild func(v cue.Value) ast.Expr
+	rebuild = func(v cue.Value) ast.Expr {
+		if !parents[v.Path().String()] {
+			return internal.ToExpr(v.Syntax(cue.Final(), cue.Docs(true), cue.Attributes(true)))
+		}
+		switch v.IncompleteKind() {
+		case cue.StructKind:
+			// Use explicit braces, so that the docs of a single field are
+			// not taken to belong to its parent.
+			s := ast.NewStruct()
+			for _, a := range v.Attributes(cue.DeclAttr) {
+				s.Elts = append(s.Elts, attrSyntax(a))
+			}
+			iter, _ := v.Fields()
+			for iter.Next() {
+				w := iter.Value()
+				if omit[w.Path().String()] {
+					continue
+				}
+				f := &ast.Field{
+					Label: ast.NewString(iter.Selector().Unquoted()),
+					Value: rebuild(w),
+				}
+				for _, a := range w.Attributes(cue.FieldAttr) {
+					f.Attrs = append(f.Attrs, attrSyntax(a))
+				}
+				for _, cg := range w.Doc() {
+					ast.AddComment(f, cg)
+				}
+				s.Elts = append(s.Elts, f)
+			}
+			return s
+		case cue.ListKind:
+			l := &ast.ListLit{}
+			iter, _ := v.List()
+			for iter.Next() {
+				if !omit[iter.Value().Path().String()] {
+					l.Elts = append(l.Elts, rebuild(iter.Value()))
+				}
+			}
+			return l
+		}
+		return internal.ToExpr(v.Syntax(cue.Final()))
+	}
+	w := v.Context().BuildExpr(rebuild(v))
+	return w, w.Err()
+}
+
+// attrSyntax returns the syntax of the attribute a.
+func attrSyntax(a cue.Attribute) *ast.Attribute {
+	return &ast.Attribute{Text: fmt.Sprintf("@%s(%s)", a.Name(), a.Contents())}
+}
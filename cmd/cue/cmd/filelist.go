@@ -0,0 +1,136 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/filetypes"
+)
+
+func addFileListFlag(f *pflag.FlagSet) {
+	f.String(string(flagFiles0From), "",
+		"read a NUL-separated list of files to process from the named file, or - for stdin")
+}
+
+// readFileList returns the files listed in the file named by the
+// --files0-from flag, such as the output of "git diff --name-only -z".
+// Files which no longer exist, for example because a change deleted
+// them, are skipped. It returns a nil slice if the flag is not set.
+func readFileList(cmd *Command, args []string) ([]string, error) {
+	from := flagFiles0From.String(cmd)
+	if from == "" {
+		return nil, nil
+	}
+	if len(args) > 0 {
+		return nil, errors.Newf(token.NoPos, "cannot combine --%s with arguments", flagFiles0From)
+	}
+	var data []byte
+	var err error
+	if from == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(from)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range splitFileList(data) {
+		if _, err := os.Stat(name); err == nil {
+			files = append(files, name)
+		}
+	}
+	if files == nil {
+		files = []string{}
+	}
+	return files, nil
+}
+
+// splitFileList splits a NUL-separated list of file names. Names are kept
+// as is, as they may start or end with spaces; only the empty element
+// following a terminating NUL byte is dropped. Lists without any NUL bytes
+// are split on newlines instead, for convenience when writing lists by
+// hand, in which case surrounding spaces and empty lines are ignored.
+func splitFileList(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	if bytes.Contains(data, []byte{0}) {
+		names := strings.Split(string(data), "\x00")
+		if names[len(names)-1] == "" {
+			names = names[:len(names)-1]
+		}
+		return names
+	}
+	var names []string
+	for _, name := range strings.Split(string(data), "\n") {
+		if name := strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fileListPackages maps a list of files to the package arguments which
+// load them along with the rest of their packages. CUE files that
+// belong to a package are replaced by a "dir:pkg" argument; other files
+// of a known type are passed through unchanged. The second result holds the absolute
+// paths of the CUE files, which can be used to restrict processing to
+// the listed files.
+func fileListPackages(files []string) (args []string, only map[string]bool, err error) {
+	only = map[string]bool{}
+	for _, name := range files {
+		if !strings.HasSuffix(name, ".cue") {
+			// Skip files of unknown types, such as documentation.
+			if _, err := filetypes.ParseFile(name, filetypes.Input); err == nil {
+				args = append(args, name)
+			}
+			continue
+		}
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		only[abs] = true
+
+		f, err := parser.ParseFile(name, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg := f.PackageName()
+		if pkg == "" {
+			args = append(args, name)
+			continue
+		}
+		dir := filepath.Dir(name)
+		if !filepath.IsAbs(dir) {
+			dir = "." + string(filepath.Separator) + dir
+		}
+		args = append(args, dir+":"+pkg)
+	}
+	slices.Sort(args)
+	return slices.Compact(args), only, nil
+}
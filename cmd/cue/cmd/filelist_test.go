@@ -0,0 +1,39 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/go-quicktest/qt"
+)
+
+func TestSplitFileList(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"a.cue\x00b dir/c.cue\x00", []string{"a.cue", "b dir/c.cue"}},
+		{"a.cue\x00b.cue", []string{"a.cue", "b.cue"}},
+		{"a.cue\nb.cue\n", []string{"a.cue", "b.cue"}},
+		{" a.cue \r\n\n b.cue\n", []string{"a.cue", "b.cue"}},
+		{" a.cue \x00b.cue\n\x00", []string{" a.cue ", "b.cue\n"}},
+		{"\x00\x00a.cue\x00", []string{"", "", "a.cue"}},
+	}
+	for _, tc := range testCases {
+		qt.Check(t, qt.DeepEquals(splitFileList([]byte(tc.in)), tc.want), qt.Commentf("input %q", tc.in))
+	}
+}
@@ -39,7 +39,8 @@ to your program.
 
 Without any packages, fix applies to all files within a module.
 `,
-		RunE: mkRunE(c, runFixAll),
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, runFixAll),
 	}
 
 	cmd.Flags().BoolP(string(flagForce), "f", false,
@@ -24,6 +24,7 @@ import (
 const (
 	flagAll             flagName = "all"
 	flagAllErrors       flagName = "all-errors"
+	flagAllowIncomplete flagName = "allow-incomplete"
 	flagCheck           flagName = "check"
 	flagDiff            flagName = "diff"
 	flagDryRun          flagName = "dry-run"
@@ -31,6 +32,7 @@ const (
 	flagExpression      flagName = "expression"
 	flagExt             flagName = "ext"
 	flagFiles           flagName = "files"
+	flagFiles0From      flagName = "files0-from"
 	flagForce           flagName = "force"
 	flagGlob            flagName = "name"
 	flagIgnore          flagName = "ignore"
@@ -41,19 +43,33 @@ const (
 	flagLanguageVersion flagName = "language-version"
 	flagList            flagName = "list"
 	flagMerge           flagName = "merge"
+	flagNoCache         flagName = "no-cache"
+	flagObfuscate       flagName = "obfuscate"
 	flagOut             flagName = "out"
+	flagOutDir          flagName = "outdir"
 	flagOutFile         flagName = "outfile"
 	flagPackage         flagName = "package"
 	flagPath            flagName = "path"
+	flagPolicy          flagName = "policy"
+	flagPrint           flagName = "print"
 	flagProtoEnum       flagName = "proto_enum"
 	flagProtoPath       flagName = "proto_path"
 	flagRecursive       flagName = "recursive"
+	flagRemoveEmpty     flagName = "remove-empty"
+	flagRequireRegular  flagName = "require-regular"
 	flagSchema          flagName = "schema"
+	flagSign            flagName = "sign"
 	flagSimplify        flagName = "simplify"
+	flagSortFields      flagName = "sort-fields"
 	flagSource          flagName = "source"
+	flagSplit           flagName = "split"
+	flagStats           flagName = "stats"
 	flagStrict          flagName = "strict"
 	flagTrace           flagName = "trace"
+	flagTree            flagName = "tree"
+	flagUpdate          flagName = "update"
 	flagVerbose         flagName = "verbose"
+	flagWatch           flagName = "watch"
 	flagWithContext     flagName = "with-context"
 
 	// Hidden flags.
@@ -81,6 +97,8 @@ func addGlobalFlags(f *pflag.FlagSet) {
 	f.BoolP(string(flagVerbose), "v", false,
 		"print information about progress")
 	f.BoolP(string(flagAllErrors), "E", false, "print all available errors")
+	f.String(string(flagStats), "",
+		"write evaluation statistics to the specified file before exiting, or - for stderr")
 
 	// Deprecated flags are hidden but still work for now.
 	// TODO(mvdan): make this flag give a warning or error in early 2025.
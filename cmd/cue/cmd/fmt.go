@@ -47,7 +47,14 @@ Arguments are interpreted as import paths (see 'cue help inputs') unless --files
 in which case the arguments are file paths to descend into and format all CUE files.
 Directories named "cue.mod" and those beginning with "." and "_" are skipped unless
 given as explicit arguments.
+
+With --files0-from, the CUE files to format are read as a NUL-separated list
+from the named file, or from stdin if it is "-". Files that do not exist are
+skipped, which makes it easy to format only the files touched by a change:
+
+	git diff --name-only -z | cue fmt --files0-from -
 `,
+		ValidArgsFunction: mkCompletion(c, completePackages),
 		RunE: mkRunE(c, func(cmd *Command, args []string) error {
 			check := flagCheck.Bool(cmd)
 			doDiff := flagDiff.Bool(cmd)
@@ -57,8 +64,30 @@ given as explicit arguments.
 				formatOpts = append(formatOpts, format.Simplify())
 			}
 
+			listed, err := readFileList(cmd, args)
+			if err != nil {
+				return err
+			}
+
 			var foundBadlyFormatted bool
-			if !flagFiles.Bool(cmd) { // format packages
+			if listed != nil { // format listed files
+				for _, path := range listed {
+					if !strings.HasSuffix(path, ".cue") {
+						continue
+					}
+					file, err := filetypes.ParseFile(path, filetypes.Input)
+					if err != nil {
+						return err
+					}
+					wasModified, err := formatFile(file, formatOpts, doDiff, check, cmd)
+					if err != nil {
+						return err
+					}
+					if wasModified {
+						foundBadlyFormatted = true
+					}
+				}
+			} else if !flagFiles.Bool(cmd) { // format packages
 				builds := loadFromArgs(args, &load.Config{
 					Tests:       true,
 					Tools:       true,
@@ -169,6 +198,7 @@ given as explicit arguments.
 	cmd.Flags().Bool(string(flagCheck), false, "exits with non-zero status if any files are not formatted")
 	cmd.Flags().BoolP(string(flagDiff), "d", false, "display diffs instead of rewriting files")
 	cmd.Flags().Bool(string(flagFiles), false, "treat arguments as file paths to descend into rather than import paths")
+	addFileListFlag(cmd.Flags())
 
 	return cmd
 }
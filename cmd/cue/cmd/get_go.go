@@ -202,6 +202,46 @@ This tells CUE that only the values enumerated by #enumSwitch are valid values
 for #Switch. Note that there are now two definitions of #Switch. CUE handles
 this in the usual way by unifying the two definitions, in which case the more
 restrictive enum interpretation of #Switch remains.
+
+The --enums flag applies this interpretation to all exported types with
+constants, except for types with custom JSON or text marshaling:
+
+	#Switch: #enumSwitch
+
+As with all Go types, #Switch is a definition, so any struct using it is
+closed: data with fields not known to the Go type fails validation, just
+like values of #Switch not listed in its const block.
+
+
+Interfaces
+
+Go interfaces with methods are translated to top (_), as any type may
+implement them. Interfaces are however often used as sum types, in which
+case the implementations in the same package are all the values it may
+hold. For instance, the Go types
+
+	package foo
+
+	type Shape interface{ Area() float64 }
+
+	type Circle struct{ Radius float64 }
+	type Square struct{ Side float64 }
+
+	func (c Circle) Area() float64  { return math.Pi * c.Radius * c.Radius }
+	func (s *Square) Area() float64 { return s.Side * s.Side }
+
+translate into
+
+	#Shape: _
+
+The --interfaces flag translates each exported interface type with methods
+to the disjunction of the exported types of the package that implement it,
+either directly or through a pointer, if there are any:
+
+	#Shape: #Circle | #Square
+
+Interface types declared in other packages, and implementations declared
+in other packages, are not taken into account.
 `,
 		// - TODO: interpret cuego's struct tags and annotations.
 
@@ -216,12 +256,20 @@ restrictive enum interpretation of #Switch remains.
 
 	cmd.Flags().StringP(string(flagPackage), "p", "", "package name for generated CUE files")
 
+	cmd.Flags().Bool(string(flagEnums), false,
+		"restrict types with constants to the enumerated values")
+
+	cmd.Flags().Bool(string(flagInterfaces), false,
+		"restrict interface types to their implementations in the same package")
+
 	return cmd
 }
 
 const (
-	flagExclude flagName = "exclude"
-	flagLocal   flagName = "local"
+	flagEnums      flagName = "enums"
+	flagExclude    flagName = "exclude"
+	flagInterfaces flagName = "interfaces"
+	flagLocal      flagName = "local"
 )
 
 func (e *extractor) initExclusions(str string) {
@@ -260,6 +308,8 @@ type extractor struct {
 
 	exclusions []*regexp.Regexp
 	exclude    string
+	enums      bool
+	interfaces bool
 }
 
 type pkgInfo struct {
@@ -402,6 +452,8 @@ func extract(cmd *Command, args []string) error {
 	}
 
 	e.initExclusions(flagExclude.String(cmd))
+	e.enums = flagEnums.Bool(cmd)
+	e.interfaces = flagInterfaces.Bool(cmd)
 
 	e.done = map[string]bool{}
 
@@ -482,6 +534,12 @@ func (e *extractor) extractPkg(root string, p *packages.Package) error {
 	if e.exclude != "" {
 		args += " --exclude=" + e.exclude
 	}
+	if e.enums {
+		args += " --enums"
+	}
+	if e.interfaces {
+		args += " --interfaces"
+	}
 
 	for i, f := range p.Syntax {
 		e.cmap = ast.NewCommentMap(p.Fset, f, f.Comments)
@@ -677,6 +735,7 @@ func (e *extractor) reportDecl(x *ast.GenDecl) (a []cueast.Decl) {
 			enums := e.consts[typ.String()]
 			name := v.Name.Name
 			mapNamed := false
+			marshaled := false
 			underlying := e.pkg.TypesInfo.TypeOf(v.Type)
 			if b, ok := underlying.Underlying().(*types.Basic); ok && b.Kind() != types.String {
 				switch b.Kind() {
@@ -694,6 +753,7 @@ func (e *extractor) reportDecl(x *ast.GenDecl) (a []cueast.Decl) {
 					// TODO: add the underlying tag as a Go tag once we have
 					// proper string escaping for CUE.
 					a = append(a, e.def(x.Doc, name, altType, true))
+					marshaled = true
 					break
 				}
 				fallthrough
@@ -704,6 +764,11 @@ func (e *extractor) reportDecl(x *ast.GenDecl) (a []cueast.Decl) {
 					continue
 				}
 				if s := e.altType(typ); s != nil {
+					a = append(a, e.def(x.Doc, name, s, true))
+					marshaled = true
+					break
+				}
+				if s := e.implementations(typ); s != nil {
 					a = append(a, e.def(x.Doc, name, s, true))
 					break
 				}
@@ -716,7 +781,13 @@ func (e *extractor) reportDecl(x *ast.GenDecl) (a []cueast.Decl) {
 
 			if len(enums) > 0 && ast.IsExported(name) {
 				enumName := "#enum" + name
-				cueast.AddComment(a[len(a)-1], internal.NewComment(false, enumName))
+				if e.enums && !marshaled && slices.ContainsFunc(enums, func(v string) bool { return v != "_" }) {
+					// The constants are typed with the enum type itself,
+					// so they already carry the underlying type.
+					a[len(a)-1].(*cueast.Field).Value = cueast.NewIdent(enumName)
+				} else {
+					cueast.AddComment(a[len(a)-1], internal.NewComment(false, enumName))
+				}
 
 				// Constants are mapped as definitions.
 				var exprs []cueast.Expr
@@ -855,6 +926,44 @@ func (e *extractor) altType(typ types.Type) cueast.Expr {
 	return nil
 }
 
+// implementations returns the disjunction of the exported types of the
+// current package that implement typ, if interfaces are to be restricted
+// to their implementations and typ is an exported interface type with
+// methods. It returns nil otherwise.
+func (e *extractor) implementations(typ types.Type) cueast.Expr {
+	named, ok := typ.(*types.Named)
+	if !e.interfaces || !ok || !named.Obj().Exported() || named.Obj().Pkg() != e.pkg.Types {
+		return nil
+	}
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() {
+		return nil
+	}
+	var exprs []cueast.Expr
+	scope := e.pkg.Types.Scope()
+	for _, name := range scope.Names() { // sorted
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || !tn.Exported() || tn.IsAlias() || e.filter(name) {
+			continue
+		}
+		t := tn.Type()
+		if n, ok := t.(*types.Named); ok && n.TypeParams().Len() > 0 {
+			continue
+		}
+		if types.IsInterface(t) || !supportedType(nil, t) {
+			continue
+		}
+		if types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface) {
+			e.logf("    %v implements %s", shortTypeName(t), shortTypeName(typ))
+			exprs = append(exprs, e.ident(name, true))
+		}
+	}
+	if len(exprs) == 0 {
+		return nil
+	}
+	return cueast.NewBinExpr(cuetoken.OR, exprs...)
+}
+
 func addDoc(g *ast.CommentGroup, x cueast.Node) bool {
 	doc := makeDoc(g, true)
 	if doc != nil {
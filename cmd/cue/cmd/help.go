@@ -242,15 +242,28 @@ which inserts one field per file matched:
 	b: _ @embed(glob=images/*.*, type=binary)
 	b: [string]: bytes
 
-Note that "**" glob patterns are not supported at this time.
+A "**" path element in a glob pattern matches any number of directories,
+skipping hidden directories and nested modules. Matched files can be
+skipped with one or more "exclude" patterns:
+
+	c: _ @embed(glob=data/**/*.json, exclude=**/testdata/**)
 
 If the file extension in "file" or "glob" does not imply a file type,
 it must be specified with the "type" encoding as shown above.
-See the "filetypes" help topic for more.
+See the "filetypes" help topic for more. In addition, CSV files can
+be embedded with type=csv or the .csv extension.
+
+Some file types accept decoding options, given as flags, which are
+ignored for files of other types:
+
+	stream   decode all documents in an NDJSON or multi-document YAML
+	         file as a list
+	header   decode the records of a CSV file as structs, using the
+	         first record as field names
+
+For example:
 
-Files containing multiple documents cannot be embedded directly.
-For NDJSON or multi-document YAML files, embed as type=text
-and use APIs like yaml.Extract to decode as a list.
+	d: _ @embed(glob=logs/*.ndjson, stream)
 
 Note that embedding cue files is not supported at this time.
 
@@ -281,6 +294,9 @@ If an environment variable is unset or empty, sensible default setting is used.
 
 		- mod/download for modules fetched from registries
 		- mod/extract for extracted module archives
+		- syntax for the parsed syntax of CUE files, keyed by their contents
+		- vet for the inputs that passed validation by "cue vet"
+		- wasm for compiled Wasm modules, when Wasm support is enabled
 
 	CUE_CONFIG_DIR
 		A directory to hold configuration and long-lived state files.
@@ -294,6 +310,18 @@ If an environment variable is unset or empty, sensible default setting is used.
 		The configuration to use when downloading and publishing modules.
 		See "cue help registryconfig" for details.
 
+	CUE_CREDENTIAL_HELPER
+		A program to run to obtain credentials for registries which
+		have not been logged into with "cue login". It follows the
+		protocol of the Docker credential helpers, so that programs such
+		as "docker-credential-gcr" can be used directly: it is run with
+		the argument "get" and the registry host on standard input.
+
+	CUE_WORK
+		The path of the cue.work file to use, or "off" to disable workspaces.
+		By default, the current directory and its parents are searched for
+		a cue.work file. See "cue help modules" for details.
+
 	CUE_EXPERIMENT
 		Comma-separated list of experiment flags to enable or disable:
 
@@ -318,6 +346,9 @@ If an environment variable is unset or empty, sensible default setting is used.
 			when interacting with module registries.
 		sortfields
 			Force fields in stucts to be sorted lexicographically.
+		sorterrors
+			Force multiple errors reported for a value to be sorted
+			by position and path, independently of evaluation order.
 		openinline (default true)
 			Permit disallowed fields to be selected into literal struct
 			that would normally result in a close error, mimicking evalv2
@@ -349,6 +380,37 @@ For a detailed reference on modules:
 
 	https://cuelang.org/docs/reference/modules/
 
+Several modules in the same repository can be developed together using a
+workspace. A cue.work file in a parent directory of the modules lists their
+directories, relative to the cue.work file:
+
+	use: [
+		"./frontend",
+		"./schemas",
+	]
+
+When a command runs within a workspace, packages from the modules it lists
+are loaded from their directories instead of from a registry, regardless of
+the version required in cue.mod/module.cue, and even if the module is not a
+dependency yet. The cue.work file is found by searching the current
+directory and its parents; see "cue help environment" for $CUE_WORK.
+
+A module can require that its dependencies are signed with sigstore, as done
+by "cue mod publish --sign", by adding signature policies to its
+cue.mod/module.cue file (this needs language version v0.12.0 or later):
+
+	signatures: [{
+		modules: ["example.com/org"]
+		identity: "release@example.com"
+		issuer: "https://accounts.google.com"
+	}]
+
+Each policy applies to the listed module paths and all module paths below
+them. When a module matched by a policy is downloaded into the cache, its
+signature is checked by running "cosign verify", which must be found in
+$PATH, and the download fails if no valid signature by the identity exists.
+Modules already in the cache are not checked again.
+
 For information on commands that interact with modules:
 
     cue help mod
@@ -555,6 +617,8 @@ var filetypeHelp = &cobra.Command{
     json        .json           JSON files.
     yaml        .yaml/.yml      YAML files.
     toml        .toml           TOML files
+    markdown    .md/.markdown   Markdown files with optional YAML or
+                                TOML front matter.
     jsonl       .jsonl/.ndjson  Line-separated JSON values.
     jsonschema                  JSON Schema.
     openapi                     OpenAPI schema.
@@ -672,6 +736,19 @@ if the user includes the flag "-t prod" on the command line.
 
    package foo
 
+Selecting fields
+
+An @if attribute may also be attached to a field, at any depth, in
+which case the field is only included in a build if the expression
+evaluates to true. A field may have at most one @if attribute.
+
+For example, the following "debug" field is only included if the user
+includes the flag "-t debug", and the "replicas" field only if the
+user does not include the flag "-t dev".
+
+   debug:    true @if(debug)
+   replicas: 3    @if(!dev)
+
 Ignoring files
 
 An "ignore" attribute causes a file to be unconditionally excluded
@@ -894,6 +971,18 @@ cuelang.org/go/pkg/tool/tool.cue.
 		// $long is a longer description that spans multiple lines and
 		// likely contain examples of usage of the command.
 		$long?: string
+
+		// $flags declares the flags accepted by the command, which may be
+		// referred to by its tasks. The type of each field determines the type
+		// of the flag, its default the default of the flag, and its doc comment
+		// the usage of the flag. Required fields declare required flags.
+		//
+		// Example:
+		//     $flags: {
+		//         // env is the target environment.
+		//         env: *"staging" | "prod"
+		//     }
+		$flags?: [string]: string | int | bool
 	}
 
 	// Tasks defines a hierarchy of tasks. A command completes if all
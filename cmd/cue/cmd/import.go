@@ -18,7 +18,12 @@ import (
 	"bytes"
 	"cmp"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"unicode"
@@ -37,6 +42,8 @@ import (
 	"cuelang.org/go/encoding/json"
 	"cuelang.org/go/encoding/protobuf"
 	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/cueversion"
+	"cuelang.org/go/internal/filetypes"
 	pkgyaml "cuelang.org/go/pkg/encoding/yaml"
 )
 
@@ -56,6 +63,7 @@ the following modes:
    json       Look for JSON files (.json .jsonl .ndjson).
    yaml       Look for YAML files (.yaml .yml).
    toml       Look for TOML files (.toml).
+   markdown   Look for Markdown files (.md .markdown).
    text       Look for text files (.txt).
    binary     Look for files with extensions specified by --ext
               and interpret them as binary.
@@ -107,6 +115,20 @@ subdirectories as well all dependencies.
 The module root is implicitly added as an import path.
 
 
+Markdown mode
+
+Markdown mode imports the YAML or TOML front matter of Markdown
+files, such as the content files of static site generators, into
+the frontMatter field and the remaining text into the body field.
+YAML front matter is delimited by lines consisting of "---" and
+TOML front matter by lines consisting of "+++".
+
+The following command imports each Markdown file in the content
+directory tree into a CUE file next to it:
+
+   cue import markdown -p content ./content/...
+
+
 Binary mode
 
 Loads matched files as binary.
@@ -223,6 +245,24 @@ Examples:
   }]
 
 
+Remote and streamed inputs
+
+Inputs may be given as http or https URLs, optionally preceded by a file
+type qualifier as in "yaml: https://example.com/config". The fetched contents are
+imported as if they were a file in the current directory named after the last
+element of the URL path. If that name has no known extension, the file type
+is inferred from the Content-Type of the response.
+
+Data can also be streamed from another tool by reading stdin with an explicit
+file type. Together with the placement flags this allows importing the state
+of live systems in a single step:
+
+  $ kubectl get deployments -o json | \
+      cue import -p k8s -o deployments.cue -l 'strings.ToLower(kind)' json: -
+
+  $ cue import -p k8s -l metadata.name https://example.com/manifests/app.yaml
+
+
 Embedded data files
 
 The --recursive or -R flag enables the parsing of fields that are string
@@ -294,6 +334,8 @@ func runImport(cmd *Command, args []string) (err error) {
 			c.fileFilter = `\.(yaml|yml)$`
 		case "toml":
 			c.fileFilter = `\.toml$`
+		case "markdown":
+			c.fileFilter = `\.(md|markdown)$`
 		case "text":
 			c.fileFilter = `\.txt$`
 		case "binary":
@@ -315,6 +357,10 @@ func runImport(cmd *Command, args []string) (err error) {
 		c.fileFilter = `\.(` + strings.Join(extensions, "|") + `)$`
 	}
 
+	if args, err = fetchURLArgs(cmd, args, c.loadCfg); err != nil {
+		return err
+	}
+
 	b, err := parseArgs(cmd, args, c)
 	if err != nil {
 		return err
@@ -626,3 +672,88 @@ func (h *hoister) uniqueName(base, prefix, typ string) string {
 		typ += "x"
 	}
 }
+
+// fetchURLArgs fetches any http or https URLs in args and makes their
+// contents available to the loader as overlay files in the current
+// directory. It returns args with each URL replaced by the name of the
+// corresponding overlay file.
+func fetchURLArgs(cmd *Command, args []string, cfg *load.Config) ([]string, error) {
+	var client *http.Client
+	scoped := false // whether a file type qualifier such as "json:" applies
+	for i, rawURL := range args {
+		if !isURL(rawURL) {
+			if strings.HasSuffix(rawURL, ":") {
+				scoped = true
+			}
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			client = &http.Client{Transport: cueversion.NewTransport("cmd/cue", nil)}
+		}
+		req, err := http.NewRequestWithContext(backgroundContext(), "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", rawURL, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error fetching %s: %s", rawURL, resp.Status)
+		}
+
+		name := path.Base(u.Path)
+		if name == "." || name == "/" {
+			name = cmp.Or(u.Hostname(), "import")
+		}
+		if _, err := filetypes.ParseFile(name, filetypes.Input); err != nil && !scoped {
+			ext := contentTypeExt(resp.Header.Get("Content-Type"))
+			if ext == "" {
+				return nil, fmt.Errorf("cannot determine file type of %s; precede it with a qualifier such as json: or yaml:", rawURL)
+			}
+			name += ext
+		}
+		if cfg.Overlay == nil {
+			cfg.Overlay = map[string]load.Source{}
+		}
+		abs := filepath.Join(rootWorkingDir, name)
+		if _, ok := cfg.Overlay[abs]; ok {
+			return nil, fmt.Errorf("multiple URLs map to file name %q", name)
+		}
+		cfg.Overlay[abs] = load.FromBytes(data)
+		args[i] = name
+	}
+	return args, nil
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// contentTypeExt returns the file extension corresponding to a
+// Content-Type header, or "" if it is not a supported data format.
+func contentTypeExt(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return ".json"
+	case mediaType == "application/x-ndjson", mediaType == "application/jsonl":
+		return ".jsonl"
+	case strings.HasSuffix(mediaType, "yaml"):
+		return ".yaml"
+	case strings.HasSuffix(mediaType, "toml"):
+		return ".toml"
+	case mediaType == "text/plain":
+		return ".txt"
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+//go:build !windows
+
+package imports
+
+import (
+	"slices"
+	"testing"
+
+	"cuelang.org/go/internal/golangorgx/gopls/hooks"
+	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	. "cuelang.org/go/internal/golangorgx/gopls/test/integration"
+	"github.com/go-quicktest/qt"
+)
+
+func TestMain(m *testing.M) {
+	Main(m, hooks.Options)
+}
+
+const files = `
+-- cue.mod/module.cue --
+module: "mod.example"
+language: version: "v0.11.0"
+deps: "other.example/lib@v0": v: "v0.1.0"
+-- a.cue --
+package a
+
+import (
+	"mod.example/"
+	"str"
+	"o"
+)
+-- b/b.cue --
+package b
+-- c/c.cue --
+package other
+-- _hidden/d.cue --
+package d
+-- run_tool.cue --
+package a
+
+import "tool/"
+`
+
+func labels(list *protocol.CompletionList) []string {
+	var a []string
+	if list == nil {
+		return nil
+	}
+	for _, item := range list.Items {
+		a = append(a, item.Label)
+	}
+	return a
+}
+
+func TestImportCompletion(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		list := env.Completion(env.RegexpSearch("a.cue", `"mod.example/()`))
+		qt.Assert(t, qt.DeepEquals(labels(list), []string{
+			"mod.example/b",
+			"mod.example/c:other",
+		}))
+		item := list.Items[0]
+		qt.Assert(t, qt.Equals(item.Detail, "mod.example"))
+		qt.Assert(t, qt.DeepEquals(item.TextEdit.Range, protocol.Range{
+			Start: protocol.Position{Line: 3, Character: 2},
+			End:   protocol.Position{Line: 3, Character: 14},
+		}))
+
+		list = env.Completion(env.RegexpSearch("a.cue", `"str()"`))
+		qt.Assert(t, qt.DeepEquals(labels(list), []string{
+			"strconv",
+			"strings",
+			"struct",
+		}))
+
+		// Dependencies that are not in the module cache are completed
+		// up to their module path.
+		list = env.Completion(env.RegexpSearch("a.cue", `"o()"`))
+		qt.Assert(t, qt.DeepEquals(labels(list), []string{
+			"other.example/lib",
+		}))
+
+		// There are no completions outside of import paths.
+		list = env.Completion(env.RegexpSearch("a.cue", `()package`))
+		qt.Assert(t, qt.HasLen(labels(list), 0))
+	})
+}
+
+func TestImportCompletionTool(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("run_tool.cue")
+		list := env.Completion(env.RegexpSearch("run_tool.cue", `"tool/()`))
+		qt.Assert(t, qt.IsTrue(slices.Contains(labels(list), "tool/exec")))
+	})
+}
+
+func TestAddImportFix(t *testing.T) {
+	const files = `
+-- cue.mod/module.cue --
+module: "mod.example"
+language: version: "v0.11.0"
+-- a.cue --
+package a
+
+import "list"
+
+x: strings.Join(list.Sort(["b", "a"], list.Ascending), " ")
+y: other.z
+w: b.v
+-- b.cue --
+package a
+
+b: v: 1
+-- c/c.cue --
+package other
+
+z: 1
+-- d/d.cue --
+package d
+`
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		actions, err := env.Editor.CodeAction(env.Ctx, env.RegexpSearch("a.cue", "strings"), nil)
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.HasLen(actions, 1))
+		qt.Assert(t, qt.Equals(actions[0].Title, `Add import "strings"`))
+		qt.Assert(t, qt.Equals(actions[0].Kind, protocol.QuickFix))
+
+		env.ApplyCodeAction(actions[0])
+		qt.Assert(t, qt.Equals(env.BufferText("a.cue"), `package a
+
+import "list"
+import "strings"
+
+x: strings.Join(list.Sort(["b", "a"], list.Ascending), " ")
+y: other.z
+w: b.v
+`))
+
+		// Packages of the module are found by their name.
+		actions, err = env.Editor.CodeAction(env.Ctx, env.RegexpSearch("a.cue", "other"), nil)
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.HasLen(actions, 1))
+		qt.Assert(t, qt.Equals(actions[0].Title, `Add import "mod.example/c:other"`))
+
+		// Fields declared in other files of the package are not imports.
+		actions, err = env.Editor.CodeAction(env.Ctx, env.RegexpSearch("a.cue", "b.v"), nil)
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.HasLen(actions, 0))
+	})
+}
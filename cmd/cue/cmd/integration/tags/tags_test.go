@@ -0,0 +1,85 @@
+//go:build !windows
+
+package tags
+
+import (
+	"testing"
+
+	"cuelang.org/go/internal/golangorgx/gopls/hooks"
+	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	. "cuelang.org/go/internal/golangorgx/gopls/test/integration"
+	"github.com/go-quicktest/qt"
+)
+
+func TestMain(m *testing.M) {
+	Main(m, hooks.Options)
+}
+
+const files = `
+-- cue.mod/module.cue --
+module: "mod.example"
+language: version: "v0.11.0"
+-- a.cue --
+package a
+
+env:    *"dev" | string @tag(env, short=dev|prod)
+region: string @tag(region)
+-- run_tool.cue --
+package a
+
+import "tool/exec"
+
+command: deploy: exec.Run & {
+	cmd: ["cue", "export", "-t", "env=prod", "."]
+}
+`
+
+func TestTagDefinition(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		loc := env.GoToDefinition(env.RegexpSearch("a.cue", "env:"))
+		qt.Assert(t, qt.Equals(loc.URI, env.Sandbox.Workdir.URI("run_tool.cue")))
+		qt.Assert(t, qt.DeepEquals(loc.Range, protocol.Range{
+			Start: protocol.Position{Line: 5, Character: 31},
+			End:   protocol.Position{Line: 5, Character: 34},
+		}))
+	})
+}
+
+func TestTagHover(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		content, _, err := env.Editor.Hover(env.Ctx, env.RegexpSearch("a.cue", "@tag.env"))
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.StringContains(content.Value, "injected with `cue -t env=value`"))
+		qt.Assert(t, qt.StringContains(content.Value, "Injected by 1 known invocation"))
+	})
+}
+
+func TestTagNeverInjected(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		env.AfterChange(
+			Diagnostics(
+				ForFile("a.cue"),
+				AtPosition("a.cue", 3, 15),
+				WithMessage(`tag "region" is never injected`),
+			),
+			NoDiagnostics(AtPosition("a.cue", 2, 24)),
+		)
+	})
+}
+
+func TestTagInjectedAfterChange(t *testing.T) {
+	Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		env.AfterChange(
+			Diagnostics(ForFile("a.cue"), WithMessage(`tag "region" is never injected`)),
+		)
+		env.OpenFile("run_tool.cue")
+		env.RegexpReplace("run_tool.cue", `"env=prod"`, `"env=prod", "-t", "region=eu"`)
+		env.AfterChange(
+			NoDiagnostics(ForFile("a.cue")),
+		)
+	})
+}
@@ -43,6 +43,41 @@ package c
 	})
 }
 
+// TestFilesInModuleAreDiagnosed ensures that changes to files at the root of
+// the module, as well as in its subdirectories, are associated with the view
+// of the workspace folder, so that the files are diagnosed.
+func TestFilesInModuleAreDiagnosed(t *testing.T) {
+	const files = `
+-- cue.mod/module.cue --
+module: "mod.example"
+language: version: "v0.11.0"
+-- a.cue --
+package a
+
+x: string @tag(x)
+-- b/b.cue --
+package b
+
+y: string @tag(y)
+-- run_tool.cue --
+package a
+
+import "tool/exec"
+
+command: run: exec.Run & {
+	cmd: ["cue", "export", "-t", "z=1", "."]
+}
+`
+	WithOptions().Run(t, files, func(t *testing.T, env *Env) {
+		env.OpenFile("a.cue")
+		env.OpenFile("b/b.cue")
+		env.AfterChange(
+			Diagnostics(ForFile("a.cue"), WithMessage(`tag "x" is never injected`)),
+			Diagnostics(ForFile("b/b.cue"), WithMessage(`tag "y" is never injected`)),
+		)
+	})
+}
+
 // TestMultipleWorkspaceFolders verifies the behaviour of starting 'cue lsp'
 // with multiple WorkspaceFolders. This is currently not supported, and hence
 // the test is a negative test that asserts 'cue lsp' will fail (during the
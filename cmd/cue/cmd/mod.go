@@ -48,9 +48,11 @@ See also:
 	cmd.AddCommand(newModFixCmd(c))
 	cmd.AddCommand(newModGetCmd(c))
 	cmd.AddCommand(newModInitCmd(c))
+	cmd.AddCommand(newModOutdatedCmd(c))
 	cmd.AddCommand(newModRegistryCmd(c))
 	cmd.AddCommand(newModResolveCmd(c))
 	cmd.AddCommand(newModTidyCmd(c))
 	cmd.AddCommand(newModUploadCmd(c))
+	cmd.AddCommand(newModVendorCmd(c))
 	return cmd
 }
@@ -16,9 +16,11 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"cuelang.org/go/internal/cueversion"
 	"cuelang.org/go/internal/mod/semver"
@@ -41,15 +43,32 @@ The editing flags specify a sequence of editing operations.
 
 The -require=path@version and -drop-require=path@majorversion flags add
 and drop a requirement on the given module path and version. Note that
--require overrides any existing requirements on path. These flags are
-mainly for tools that understand the module graph. Users should prefer
-'cue mod get path@version' which makes other cue.mod adjustments as
-needed to satisfy constraints imposed by other modules.
+-require overrides any existing requirements on path. The major version
+may be omitted from -drop-require when only one major version of the
+module is required, or when one of them is the default.
+These flags are mainly for tools that understand the module graph.
+Users should prefer 'cue mod get path@version' which makes other cue.mod
+adjustments as needed to satisfy constraints imposed by other modules.
 
 The --module flag changes the module's path (the module.cue file's module field).
-The --source flag changes the module's declared source.
+The --source flag changes the module's declared source. Its argument
+is either a source kind such as "git", or a comma-separated list of
+field=value pairs such as "kind=git".
 The --drop-source flag removes the source field.
 
+The --print flag prints the resulting module.cue file instead of writing
+it back. The --json flag does the same, but prints the file in JSON format
+for use by other tools. The JSON object has the same fields as module.cue:
+
+	{
+		"module": "example.com/foo@v0",
+		"language": {"version": "v0.12.0"},
+		"source": {"kind": "git"},
+		"deps": {
+			"example.com/bar@v1": {"v": "v1.2.3", "default": true}
+		}
+	}
+
 Note that this command is not yet stable and may be changed.
 `,
 		RunE: mkRunE(c, editCmd.run),
@@ -61,6 +80,8 @@ Note that this command is not yet stable and may be changed.
 	addFlagVar(cmd, flagFunc(editCmd.flagModule), "module", "set the module path")
 	addFlagVar(cmd, flagFunc(editCmd.flagRequire), "require", "add a required module@version")
 	addFlagVar(cmd, flagFunc(editCmd.flagDropRequire), "drop-require", "remove a requirement")
+	cmd.Flags().Bool(string(flagJSON), false, "print the resulting module file in JSON format instead of writing it")
+	cmd.Flags().Bool(string(flagPrint), false, "print the resulting module file instead of writing it")
 
 	return cmd
 }
@@ -70,6 +91,10 @@ type modEditCmd struct {
 }
 
 func (c *modEditCmd) run(cmd *Command, args []string) error {
+	printJSON, printCUE := flagJSON.Bool(cmd), flagPrint.Bool(cmd)
+	if printJSON && printCUE {
+		return fmt.Errorf("cannot use both --%s and --%s", flagJSON, flagPrint)
+	}
 	modPath, mf, data, err := readModuleFile()
 	if err != nil {
 		return err
@@ -83,6 +108,18 @@ func (c *modEditCmd) run(cmd *Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("invalid resulting module.cue file after edits: %v", err)
 	}
+	switch {
+	case printJSON:
+		b, err := json.MarshalIndent(mf, "", "\t")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", b)
+		return err
+	case printCUE:
+		_, err := cmd.OutOrStdout().Write(newData)
+		return err
+	}
 	if bytes.Equal(newData, data) {
 		return nil
 	}
@@ -100,6 +137,21 @@ func (c *modEditCmd) flagSource(arg string) error {
 	src := &modfile.Source{
 		Kind: arg,
 	}
+	if strings.Contains(arg, "=") {
+		src.Kind = ""
+		for _, field := range strings.Split(arg, ",") {
+			name, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return fmt.Errorf("invalid source field %q: want field=value", field)
+			}
+			switch name {
+			case "kind":
+				src.Kind = value
+			default:
+				return fmt.Errorf("unknown source field %q", name)
+			}
+		}
+	}
 	if err := src.Validate(); err != nil {
 		return err
 	}
@@ -189,11 +241,18 @@ func (c *modEditCmd) flagRequire(arg string) error {
 }
 
 func (c *modEditCmd) flagDropRequire(arg string) error {
+	if _, _, ok := module.SplitPathVersion(arg); !ok {
+		if err := module.CheckPathWithoutVersion(arg); err != nil {
+			return err
+		}
+		c.addEdit(func(f *modfile.File) error {
+			return dropRequireDefault(f, arg)
+		})
+		return nil
+	}
 	if err := module.CheckPath(arg); err != nil {
 		return err
 	}
-	// TODO allow dropping a requirement without specifying
-	// the major version - we can use the default field to disambiguate.
 	c.addEdit(func(f *modfile.File) error {
 		delete(f.Deps, arg)
 		return nil
@@ -201,6 +260,31 @@ func (c *modEditCmd) flagDropRequire(arg string) error {
 	return nil
 }
 
+// dropRequireDefault removes the requirement on the module with the given
+// path without a major version. The requirement is ambiguous if several
+// major versions are required and none of them is the default.
+func dropRequireDefault(f *modfile.File, basePath string) error {
+	var found []string
+	for mpath, dep := range f.Deps {
+		if p, _, _ := module.SplitPathVersion(mpath); p != basePath {
+			continue
+		}
+		if dep.Default {
+			delete(f.Deps, mpath)
+			return nil
+		}
+		found = append(found, mpath)
+	}
+	switch len(found) {
+	case 0:
+		return nil
+	case 1:
+		delete(f.Deps, found[0])
+		return nil
+	}
+	return fmt.Errorf("cannot drop requirement on %s: several major versions are required and none is the default", basePath)
+}
+
 func addFlagVar(cmd *cobra.Command, v pflag.Value, name string, usage string) {
 	flags := cmd.Flags()
 	flags.Var(v, name, usage)
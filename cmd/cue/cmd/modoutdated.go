@@ -0,0 +1,168 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"cuelang.org/go/internal/mod/modload"
+	"cuelang.org/go/internal/mod/semver"
+)
+
+func newModOutdatedCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outdated",
+		Short: "list dependencies with newer versions available",
+		Long: `Outdated lists the dependencies in the cue.mod/module.cue file
+for which a newer version is available in the registry.
+
+For each such dependency, it prints the module path, the current
+version, the latest version with the same major and minor version
+(PATCH), and the latest version with the same major version (LATEST).
+A "-" indicates that there is no such newer version. Prerelease
+versions are not considered.
+
+The --update flag updates the dependencies as "cue mod get" would
+and rewrites the cue.mod/module.cue file accordingly:
+
+	--update=patch   update to the latest patch version
+	--update=minor   update to the latest minor or patch version
+
+See "cue help environment" for details on how $CUE_REGISTRY is used to
+determine the modules registry.
+
+Note that this command is not yet stable and may be changed.
+`,
+		RunE: mkRunE(c, runModOutdated),
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.Flags().String(string(flagUpdate), "", "update dependencies to the latest patch or minor version (patch|minor)")
+
+	return cmd
+}
+
+// outdatedDep describes a dependency for which newer versions exist.
+type outdatedDep struct {
+	path    string // module path without major version suffix
+	current string
+	patch   string // latest version with the same major and minor version
+	latest  string // latest version with the same major version
+}
+
+func runModOutdated(cmd *Command, args []string) error {
+	update := flagUpdate.String(cmd)
+	switch update {
+	case "", "patch", "minor":
+	default:
+		return fmt.Errorf("invalid value %q for --update; must be patch or minor", update)
+	}
+	reg, err := getCachedRegistry()
+	if err != nil {
+		return err
+	}
+	ctx := backgroundContext()
+	modPath, mf, oldData, err := readModuleFile()
+	if err != nil {
+		return err
+	}
+
+	// TODO(go1.23) use slices.Sorted(maps.Keys(mf.Deps)).
+	mpaths := make([]string, 0, len(mf.Deps))
+	for mpath := range mf.Deps {
+		mpaths = append(mpaths, mpath)
+	}
+	slices.Sort(mpaths)
+
+	var deps []outdatedDep
+	for _, mpath := range mpaths {
+		current := mf.Deps[mpath].Version
+		versions, err := reg.ModuleVersions(ctx, mpath)
+		if err != nil {
+			return err
+		}
+		d := outdatedDep{current: current}
+		d.path, _, _ = strings.Cut(mpath, "@")
+		for _, v := range versions {
+			if semver.Prerelease(v) != "" || semver.Compare(v, current) <= 0 {
+				continue
+			}
+			if semver.Compare(v, d.latest) > 0 {
+				d.latest = v
+			}
+			if semver.MajorMinor(v) == semver.MajorMinor(current) && semver.Compare(v, d.patch) > 0 {
+				d.patch = v
+			}
+		}
+		if d.latest != "" {
+			deps = append(deps, d)
+		}
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tCURRENT\tPATCH\tLATEST")
+	for _, d := range deps {
+		fmt.Fprintf(w, "%s@%s\t%s\t%s\t%s\n",
+			d.path, semver.Major(d.current), d.current, orDash(d.patch), orDash(d.latest))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if update == "" {
+		return nil
+	}
+	var versions []string
+	for _, d := range deps {
+		switch {
+		case update == "minor":
+			versions = append(versions, d.path+"@"+d.latest)
+		case d.patch != "":
+			versions = append(versions, d.path+"@"+d.patch)
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	newMf, err := modload.UpdateVersions(ctx, os.DirFS(filepath.Dir(filepath.Dir(modPath))), ".", reg, versions)
+	if err != nil {
+		return suggestModCommand(err)
+	}
+	data, err := newMf.Format()
+	if err != nil {
+		return fmt.Errorf("internal error: invalid module.cue file generated: %v", err)
+	}
+	if bytes.Equal(data, oldData) {
+		return nil
+	}
+	return os.WriteFile(modPath, data, 0o666)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -64,6 +64,13 @@ The --out flag can be used to write the module's contents to a directory
 in OCI Image Layout format. See this link for more details on the format:
 https://github.com/opencontainers/image-spec/blob/8f3820ccf8f65db8744e626df17fe8a64462aece/image-layout.md
 
+The --sign flag signs the published module with sigstore by running
+"cosign sign" on the module's manifest, which must be found in $PATH.
+cosign uses its usual configuration, so by default this signs keylessly
+with an identity obtained through OpenID Connect. Dependents can require
+such signatures through the signatures field in their cue.mod/module.cue
+file; see "cue help modules".
+
 Note that this command is not yet stable and may be changed.
 `,
 		RunE: mkRunE(c, runModUpload),
@@ -72,6 +79,7 @@ Note that this command is not yet stable and may be changed.
 	cmd.Flags().BoolP(string(flagDryRun), "n", false, "only run simulation")
 	cmd.Flags().Bool(string(flagJSON), false, "print verbose information in JSON format (implies --dry-run)")
 	cmd.Flags().String(string(flagOut), "", "write module contents to specified directory in OCI Image Layout format (implies --dry-run)")
+	cmd.Flags().Bool(string(flagSign), false, "sign the published module with cosign")
 
 	return cmd
 }
@@ -97,6 +105,10 @@ func runModUpload(cmd *Command, args []string) error {
 	if outDir != "" || useJSON {
 		dryRun = true
 	}
+	sign := flagSign.Bool(cmd)
+	if sign && dryRun {
+		return fmt.Errorf("cannot use --sign with --dry-run, --json or --out")
+	}
 	resolver := &publishRegistryResolverShim{
 		resolver: resolver0,
 		outDir:   flagOut.String(cmd),
@@ -264,6 +276,11 @@ func runModUpload(cmd *Command, args []string) error {
 			return err
 		}
 	}
+	if sign {
+		if err := signModule(ctx, ref, resolver.insecure); err != nil {
+			return fmt.Errorf("published %s to %v but could not sign it: %v", mv, shortString(ref), err)
+		}
+	}
 
 	// Do not output full OCI references by default without --json, as sources
 	// like git may cause non-deterministic digests due to commit hashes
@@ -291,6 +308,9 @@ func runModUpload(cmd *Command, args []string) error {
 	case dryRun:
 		// See comment above about short vs regular OCI reference output.
 		fmt.Printf("dry-run published %s to %v\n", mv, shortString(ref))
+	case sign:
+		// See comment above about short vs regular OCI reference output.
+		fmt.Printf("published and signed %s to %v\n", mv, shortString(ref))
 	default:
 		// See comment above about short vs regular OCI reference output.
 		fmt.Printf("published %s to %v\n", mv, shortString(ref))
@@ -0,0 +1,103 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cuelabs.dev/go/oci/ociregistry"
+	"cuelabs.dev/go/oci/ociregistry/ociref"
+
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/module"
+)
+
+// cosignProgram holds the name of the sigstore tool used to sign and
+// verify modules.
+const cosignProgram = "cosign"
+
+// signModule signs the module manifest at ref, which must include
+// a digest. The signature is made for the digest rather than the tag,
+// as tags are mutable.
+func signModule(ctx context.Context, ref ociref.Reference, insecure bool) error {
+	ref.Tag = ""
+	args := []string{"sign", "--yes"}
+	if insecure {
+		args = append(args, "--allow-http-registry")
+	}
+	return runCosign(ctx, append(args, ref.String())...)
+}
+
+// moduleVerifier returns a function suitable for [modconfig.Config.VerifyModule]
+// that enforces the signature policies in the current module's
+// cue.mod/module.cue file. It returns nil if there is no current module or
+// it has no signature policies.
+func moduleVerifier() func(ctx context.Context, mv module.Version, loc modconfig.HostLocation, manifestDigest ociregistry.Digest) error {
+	modRoot, err := findModuleRoot()
+	if err != nil {
+		return nil
+	}
+	modPath := filepath.Join(modRoot, "cue.mod", "module.cue")
+	data, err := os.ReadFile(modPath)
+	if err != nil {
+		return nil
+	}
+	// Any errors in the module file are reported when it's loaded.
+	mf, err := modfile.ParseNonStrict(data, modPath)
+	if err != nil || len(mf.Signatures) == 0 {
+		return nil
+	}
+	return func(ctx context.Context, mv module.Version, loc modconfig.HostLocation, manifestDigest ociregistry.Digest) error {
+		policy := mf.SignaturePolicy(mv.BasePath())
+		if policy == nil {
+			return nil
+		}
+		ref := ociref.Reference{
+			Host:       loc.Host,
+			Repository: loc.Repository,
+			Digest:     manifestDigest,
+		}
+		args := []string{
+			"verify",
+			"--certificate-identity", policy.Identity,
+			"--certificate-oidc-issuer", policy.Issuer,
+		}
+		if loc.Insecure {
+			args = append(args, "--allow-http-registry")
+		}
+		return runCosign(ctx, append(args, ref.String())...)
+	}
+}
+
+func runCosign(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, cosignProgram, args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s %s: %v\n%s", cosignProgram, args[0], err, msg)
+		}
+		return fmt.Errorf("%s %s: %v", cosignProgram, args[0], err)
+	}
+	return nil
+}
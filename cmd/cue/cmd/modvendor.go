@@ -0,0 +1,89 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"cuelang.org/go/internal/mod/modload"
+	"cuelang.org/go/internal/mod/modvendor"
+	"cuelang.org/go/mod/modfile"
+)
+
+func newModVendorCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vendor",
+		Short: "make a copy of all module dependencies",
+		Long: `Vendor copies all the dependencies of the current module into the
+cue.mod/vendor directory, replacing any previous contents of that directory.
+
+When cue.mod/vendor/modules.txt exists, cue commands load all dependencies
+from the vendor directory instead of fetching them from a registry, so that
+the module can be evaluated without network access, for example in
+air-gapped environments or for reproducible builds. The vendored modules
+must match the dependencies in cue.mod/module.cue exactly; run 'cue mod
+vendor' again after changing them.
+
+The module must be tidy; see 'cue help mod tidy'.
+
+Note that this command is not yet stable and may be changed.
+`,
+		RunE: mkRunE(c, runModVendor),
+		Args: cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func runModVendor(cmd *Command, args []string) error {
+	reg, err := getCachedRegistry()
+	if err != nil {
+		return err
+	}
+	ctx := backgroundContext()
+	modRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+	if err := modload.CheckTidy(ctx, os.DirFS(modRoot), ".", reg); err != nil {
+		return suggestModCommand(err)
+	}
+	data, err := os.ReadFile(filepath.Join(modRoot, "cue.mod", "module.cue"))
+	if err != nil {
+		return err
+	}
+	mf, err := modfile.Parse(data, "cue.mod/module.cue")
+	if err != nil {
+		return err
+	}
+	return modvendor.Write(ctx, vendorDir(modRoot), reg, mf.DepVersions())
+}
+
+func vendorDir(modRoot string) string {
+	return filepath.Join(modRoot, "cue.mod", modvendor.Dir)
+}
+
+// useVendor reports whether dependencies of the current module should be
+// loaded from its vendor directory.
+func useVendor() bool {
+	modRoot, err := findModuleRoot()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(vendorDir(modRoot), modvendor.ManifestFile))
+	return err == nil
+}
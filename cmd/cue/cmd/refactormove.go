@@ -0,0 +1,499 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/mod/module"
+	"github.com/spf13/cobra"
+)
+
+func newRefactorMoveCmd(c *Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "move <package> <#Definition> <file>",
+		Short: "move a definition to another file or package",
+		Long: `Move moves the definition with the given name from a package to the
+given file, which may belong to another package of the same module.
+
+All references to the definition within the main module are rewritten to
+refer to its new location and the imports of the affected files are updated
+accordingly. Modified files are formatted.
+
+If the destination file does not exist, it is created. Its package is then
+taken from the --package flag or, if that is not set, from the other CUE
+files in its directory.
+
+A definition may only be moved to another package if it does not refer to
+other declarations of its original package, as the move could otherwise
+introduce an import cycle. Moves that would make the destination package
+part of an import cycle in any other way are rejected as well. No files are
+modified if the move fails.
+
+For example:
+
+	cue refactor move ./schema '#Person' ./people/person.cue
+
+Note that this command is not yet stable and may be changed.
+`,
+		RunE: mkRunE(c, runRefactorMove),
+		Args: cobra.ExactArgs(3),
+	}
+
+	cmd.Flags().StringP(string(flagPackage), "p", "",
+		"package name of the destination file if it does not exist yet")
+
+	return cmd
+}
+
+// refactorPackage is a package of the main module. Only the files residing
+// in the package directory itself are included, so that each file belongs to
+// exactly one package.
+type refactorPackage struct {
+	importPath string
+	name       string
+	dir        string
+	files      []*ast.File
+}
+
+func runRefactorMove(cmd *Command, args []string) error {
+	pkgArg, name, fileArg := args[0], args[1], args[2]
+	if !strings.HasPrefix(name, "#") || !ast.IsValidIdent(name) {
+		return fmt.Errorf("%q is not a definition", name)
+	}
+
+	modRoot, err := findModuleRoot()
+	if err != nil {
+		return err
+	}
+
+	srcInsts := load.Instances([]string{pkgArg}, nil)
+	if len(srcInsts) != 1 {
+		return fmt.Errorf("%s must refer to a single package", pkgArg)
+	}
+	srcInst := srcInsts[0]
+	if srcInst.Err != nil {
+		return srcInst.Err
+	}
+	modPath, _, _ := module.SplitPathVersion(srcInst.Module)
+	if modPath == "" {
+		return fmt.Errorf("cannot refactor packages outside of a module")
+	}
+
+	var pkgs []*refactorPackage
+	insts := load.Instances([]string{"./..."}, &load.Config{
+		Dir:     modRoot,
+		Package: "*",
+		Tests:   true,
+		Tools:   true,
+	})
+	for _, inst := range insts {
+		if inst.Err != nil {
+			return inst.Err
+		}
+		p := &refactorPackage{
+			importPath: cleanImportPath(inst.ImportPath),
+			name:       inst.PkgName,
+			dir:        inst.Dir,
+		}
+		for _, f := range inst.Files {
+			if filepath.Dir(f.Filename) == inst.Dir {
+				p.files = append(p.files, f)
+			}
+		}
+		pkgs = append(pkgs, p)
+	}
+
+	m := &mover{
+		name:     name,
+		modified: map[*ast.File]bool{},
+	}
+	for _, p := range pkgs {
+		if p.dir == srcInst.Dir && p.name == srcInst.PkgName {
+			m.src = p
+		}
+	}
+	if m.src == nil {
+		return fmt.Errorf("package %s is not part of the main module", pkgArg)
+	}
+
+	filename := fileArg
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(rootWorkingDir, filename)
+	}
+	rel, err := filepath.Rel(modRoot, filename)
+	if err != nil || !strings.HasSuffix(filename, ".cue") ||
+		rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) ||
+		strings.HasPrefix(rel, "cue.mod"+string(filepath.Separator)) {
+		return fmt.Errorf("%s is not a CUE file of the main module", fileArg)
+	}
+
+	for _, p := range pkgs {
+		for _, f := range p.files {
+			if f.Filename == filename {
+				m.dst, m.dstFile = p, f
+			}
+		}
+	}
+	if m.dstFile == nil {
+		if _, err := os.Stat(filename); err == nil {
+			return fmt.Errorf("%s is not part of a package of the main module", fileArg)
+		}
+		dir := filepath.Dir(filename)
+		pkgName := flagPackage.String(cmd)
+		var candidates []*refactorPackage
+		for _, p := range pkgs {
+			if p.dir == dir && (pkgName == "" || p.name == pkgName) {
+				candidates = append(candidates, p)
+			}
+		}
+		switch {
+		case len(candidates) == 1:
+			m.dst = candidates[0]
+		case pkgName == "":
+			return fmt.Errorf("cannot determine package of %s; use --%s", fileArg, flagPackage)
+		case !ast.IsValidIdent(pkgName) || strings.HasPrefix(pkgName, "_"):
+			return fmt.Errorf("invalid package name %q", pkgName)
+		default:
+			relDir, _ := filepath.Rel(modRoot, dir)
+			path := modPath
+			if relDir != "." {
+				path += "/" + filepath.ToSlash(relDir)
+			}
+			m.dst = &refactorPackage{
+				importPath: cleanImportPath(path + ":" + pkgName),
+				name:       pkgName,
+				dir:        dir,
+			}
+			pkgs = append(pkgs, m.dst)
+		}
+		m.dstFile = &ast.File{
+			Filename: filename,
+			Decls:    []ast.Decl{&ast.Package{Name: ast.NewIdent(m.dst.name)}},
+		}
+		m.dst.files = append(m.dst.files, m.dstFile)
+		m.modified[m.dstFile] = true
+	}
+
+	if err := m.move(); err != nil {
+		return err
+	}
+	if m.src != m.dst {
+		for _, p := range pkgs {
+			for _, f := range p.files {
+				m.rewriteRefs(p, f)
+			}
+		}
+	}
+
+	// Prepare all modified files before writing any of them, so that the
+	// module is left untouched if the move fails.
+	var errs errors.Error
+	var files []*ast.File
+	for _, p := range pkgs {
+		for _, f := range p.files {
+			if !m.modified[f] {
+				continue
+			}
+			if err := astutil.Sanitize(f); err != nil {
+				errs = errors.Append(errs, errors.Promote(err, "sanitize"))
+				continue
+			}
+			removeEmptyImports(f)
+			files = append(files, f)
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	if err := m.checkImportCycles(pkgs); err != nil {
+		return err
+	}
+	out := make([][]byte, len(files))
+	for i, f := range files {
+		b, err := format.Node(f)
+		if err != nil {
+			errs = errors.Append(errs, errors.Promote(err, "format"))
+			continue
+		}
+		out[i] = b
+	}
+	if errs != nil {
+		return errs
+	}
+	for i, f := range files {
+		if err := os.WriteFile(f.Filename, out[i], 0o666); err != nil {
+			errs = errors.Append(errs, errors.Promote(err, "write"))
+		}
+	}
+	return errs
+}
+
+// removeEmptyImports removes the import declarations that no longer import
+// any packages.
+func removeEmptyImports(f *ast.File) {
+	f.Decls = slices.DeleteFunc(f.Decls, func(d ast.Decl) bool {
+		x, ok := d.(*ast.ImportDecl)
+		return ok && len(x.Specs) == 0
+	})
+}
+
+// cleanImportPath returns the canonical form of an import path without a
+// major version, as it would be written in an import declaration.
+func cleanImportPath(path string) string {
+	ip := module.ParseImportPath(path)
+	ip.Version = ""
+	return ip.Canonical().String()
+}
+
+// A mover moves all top-level declarations of a definition from one
+// package to a file of another package.
+type mover struct {
+	name string
+
+	src     *refactorPackage
+	dst     *refactorPackage
+	dstFile *ast.File
+
+	modified map[*ast.File]bool
+}
+
+// move detaches the declarations of the definition from the source package
+// and appends them to the destination file.
+func (m *mover) move() error {
+	if m.src != m.dst {
+		for _, f := range m.dst.files {
+			for _, d := range f.Decls {
+				if declName(d) == m.name {
+					return errors.Newf(d.Pos(), "%s is already declared in package %s",
+						m.name, m.dst.importPath)
+				}
+			}
+		}
+	}
+
+	declared := map[string]bool{}
+	for _, f := range m.src.files {
+		for _, d := range f.Decls {
+			declared[declName(d)] = true
+		}
+	}
+
+	var moved []ast.Decl
+	for _, f := range m.src.files {
+		decls := f.Decls[:0]
+		for _, d := range f.Decls {
+			if declName(d) != m.name || f == m.dstFile {
+				decls = append(decls, d)
+				continue
+			}
+			if m.src != m.dst {
+				if err := m.checkRefs(f, d, declared); err != nil {
+					return err
+				}
+			}
+			moved = append(moved, d)
+			m.modified[f] = true
+		}
+		f.Decls = decls
+	}
+	if len(moved) == 0 {
+		if declared[m.name] {
+			return fmt.Errorf("%s is already declared in %s", m.name, m.dstFile.Filename)
+		}
+		return fmt.Errorf("definition %s not found in package %s", m.name, m.src.importPath)
+	}
+
+	for _, d := range moved {
+		// References from within the moved declarations to other parts of
+		// the destination package no longer need an import.
+		astutil.Apply(d, func(c astutil.Cursor) bool {
+			if x, ok := c.Node().(*ast.SelectorExpr); ok && m.isImportRef(x, m.dst) {
+				c.Replace(ast.NewIdent(selName(x)))
+			}
+			return true
+		}, nil)
+	}
+	ast.SetRelPos(moved[0], token.NewSection)
+	m.dstFile.Decls = append(m.dstFile.Decls, moved...)
+	m.modified[m.dstFile] = true
+	return nil
+}
+
+// checkRefs reports an error if the declaration d of file f refers to
+// declarations of its package other than the moved definition itself.
+func (m *mover) checkRefs(f *ast.File, d ast.Decl, declared map[string]bool) error {
+	unresolved := map[*ast.Ident]bool{}
+	for _, x := range f.Unresolved {
+		unresolved[x] = true
+	}
+	var err error
+	ast.Walk(d, func(n ast.Node) bool {
+		x, ok := n.(*ast.Ident)
+		if !ok || err != nil {
+			return err == nil
+		}
+		pkgLevel := x.Scope == f || (unresolved[x] && declared[x.Name])
+		switch {
+		case !pkgLevel:
+		case x.Name == m.name:
+			// The reference is resolved anew in the destination file.
+			x.Node, x.Scope = nil, nil
+		default:
+			err = errors.Newf(x.Pos(),
+				"cannot move %s: it refers to %s, which is declared in package %s",
+				m.name, x.Name, m.src.importPath)
+		}
+		return true
+	}, nil)
+	return err
+}
+
+// checkImportCycles reports an error if the move makes the destination
+// package part of an import cycle among the packages of pkgs. This happens if
+// a package that now imports the destination package is, directly or
+// indirectly, imported by it. As the move only adds imports of the
+// destination package, and to the destination package itself, any new cycle
+// includes it.
+func (m *mover) checkImportCycles(pkgs []*refactorPackage) error {
+	imports := map[string][]string{}
+	for _, p := range pkgs {
+		for _, f := range p.files {
+			f.VisitImports(func(d *ast.ImportDecl) {
+				for _, spec := range d.Specs {
+					path, err := literal.Unquote(spec.Path.Value)
+					if err != nil {
+						continue
+					}
+					path = cleanImportPath(path)
+					if !slices.Contains(imports[p.importPath], path) {
+						imports[p.importPath] = append(imports[p.importPath], path)
+					}
+				}
+			})
+		}
+	}
+	dst := m.dst.importPath
+	if cycle := importChain(imports, dst, dst, map[string]bool{}); cycle != nil {
+		return fmt.Errorf("cannot move %s to package %s: it would create an import cycle: %s",
+			m.name, dst, strings.Join(append([]string{dst}, cycle...), " imports "))
+	}
+	return nil
+}
+
+// importChain returns a chain of imports leading from package from to
+// package to, excluding from, or nil if there is none. Packages in seen
+// are not visited.
+func importChain(imports map[string][]string, from, to string, seen map[string]bool) []string {
+	for _, path := range imports[from] {
+		if path == to {
+			return []string{path}
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if chain := importChain(imports, path, to, seen); chain != nil {
+			return append([]string{path}, chain...)
+		}
+	}
+	return nil
+}
+
+// rewriteRefs rewrites the references to the moved definition in file f of
+// package p to refer to its new location.
+func (m *mover) rewriteRefs(p *refactorPackage, f *ast.File) {
+	unresolved := map[*ast.Ident]bool{}
+	for _, x := range f.Unresolved {
+		if x.Name == m.name {
+			unresolved[x] = true
+		}
+	}
+	astutil.Apply(f, func(c astutil.Cursor) bool {
+		switch x := c.Node().(type) {
+		case *ast.Ident:
+			if p == m.src && x.Name == m.name && (unresolved[x] || x.Scope == f) {
+				c.Replace(m.dstRef())
+				m.modified[f] = true
+			}
+		case *ast.SelectorExpr:
+			if selName(x) != m.name || !m.isImportRef(x, m.src) {
+				break
+			}
+			if p == m.dst {
+				c.Replace(ast.NewIdent(m.name))
+			} else {
+				c.Replace(m.dstRef())
+			}
+			m.modified[f] = true
+		}
+		return true
+	}, nil)
+}
+
+// dstRef returns a reference to the moved definition from another package.
+// The import is added by [astutil.Sanitize] if needed.
+func (m *mover) dstRef() ast.Expr {
+	return &ast.SelectorExpr{
+		X: &ast.Ident{
+			Name: m.dst.name,
+			Node: ast.NewImport(nil, m.dst.importPath),
+		},
+		Sel: ast.NewIdent(m.name),
+	}
+}
+
+// isImportRef reports whether x selects a value from an import of package p.
+func (m *mover) isImportRef(x *ast.SelectorExpr, p *refactorPackage) bool {
+	id, ok := x.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	spec, ok := id.Node.(*ast.ImportSpec)
+	if !ok {
+		return false
+	}
+	path, err := literal.Unquote(spec.Path.Value)
+	return err == nil && cleanImportPath(path) == p.importPath
+}
+
+// declName returns the name of a top-level field or let declaration, or
+// the empty string otherwise.
+func declName(d ast.Decl) string {
+	switch x := d.(type) {
+	case *ast.Field:
+		name, _, _ := ast.LabelName(x.Label)
+		return name
+	case *ast.LetClause:
+		return x.Ident.Name
+	}
+	return ""
+}
+
+func selName(x *ast.SelectorExpr) string {
+	name, _, _ := ast.LabelName(x.Sel)
+	return name
+}
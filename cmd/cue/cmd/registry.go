@@ -18,7 +18,9 @@ func getRegistryResolver() (*modconfig.Resolver, error) {
 }
 
 func getCachedRegistry() (modload.Registry, error) {
-	return modconfig.NewRegistry(newModConfig())
+	cfg := newModConfig()
+	cfg.VerifyModule = moduleVerifier()
+	return modconfig.NewRegistry(cfg)
 }
 
 func newModConfig() *modconfig.Config {
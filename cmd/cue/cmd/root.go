@@ -23,6 +23,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/text/message"
@@ -55,8 +56,14 @@ type runFunction func(cmd *Command, args []string) error
 // wasmInterp is set when the cuewasm build tag is enbabled.
 var wasmInterp cuecontext.ExternInterpreter
 
+// statsEncoder returns the encoder for the file given by the --stats flag,
+// or by $CUE_STATS_FILE if the flag is not set. The file's extension
+// selects the encoding, with "-" being JSON written to stderr.
 func statsEncoder(cmd *Command) (*encoding.Encoder, error) {
-	file := os.Getenv("CUE_STATS_FILE")
+	file := flagStats.String(cmd)
+	if file == "" {
+		file = os.Getenv("CUE_STATS_FILE")
+	}
 	if file == "" {
 		return nil, nil
 	}
@@ -83,6 +90,25 @@ type Stats struct {
 		AllocBytes   uint64
 		AllocObjects uint64
 	}
+
+	// Packages holds the stats of each package instance, in the order in
+	// which they were built. They can be used to find which packages are
+	// slow to evaluate.
+	Packages []PackageStats `json:",omitempty"`
+}
+
+// PackageStats holds the stats for building and evaluating a single
+// package instance.
+type PackageStats struct {
+	// ID identifies the instance, as in [build.Instance.ID].
+	ID string
+
+	// Duration is the time, in nanoseconds, taken to build and evaluate
+	// the instance.
+	Duration time.Duration
+
+	// CUE holds the evaluator counts for the instance.
+	CUE stats.Counts
 }
 
 func mkRunE(c *Command, f runFunction) func(*cobra.Command, []string) error {
@@ -129,6 +155,10 @@ func mkRunE(c *Command, f runFunction) func(*cobra.Command, []string) error {
 			defer pprof.StopCPUProfile()
 		}
 
+		if statsEnc != nil {
+			c.stats = &Stats{}
+		}
+
 		err = f(c, args)
 
 		// TODO(mvdan): support -memprofilerate like `go help testflag`.
@@ -145,7 +175,7 @@ func mkRunE(c *Command, f runFunction) func(*cobra.Command, []string) error {
 		}
 
 		if statsEnc != nil {
-			var stats Stats
+			stats := c.stats
 			stats.CUE = adt.TotalStats()
 
 			// Fill in the runtime stats, which are cumulative counters.
@@ -167,6 +197,14 @@ func mkRunE(c *Command, f runFunction) func(*cobra.Command, []string) error {
 			stats.Go.AllocBytes = m.TotalAlloc
 			stats.Go.AllocObjects = m.Mallocs
 
+			// Durations are not deterministic either, so they are left out
+			// in the tests.
+			if testing.Testing() {
+				for i := range stats.Packages {
+					stats.Packages[i].Duration = 0
+				}
+			}
+
 			statsEnc.Encode(c.ctx.Encode(stats))
 			statsEnc.Close()
 		}
@@ -219,8 +257,11 @@ func New(args []string) (*Command, error) {
 
 	for _, sub := range []*cobra.Command{
 		c.cmdCmd,
+		newAnonymizeCmd(c),
+		newBugCmd(c),
 		newCompletionCmd(c),
 		newEvalCmd(c),
+		newExplainCmd(c),
 		newDefCmd(c),
 		newExportCmd(c),
 		newFixCmd(c),
@@ -229,6 +270,7 @@ func New(args []string) (*Command, error) {
 		newImportCmd(c),
 		newLoginCmd(c),
 		newModCmd(c),
+		newRefactorCmd(c),
 		newTrimCmd(c),
 		newVersionCmd(c),
 		newVetCmd(c),
@@ -282,6 +324,10 @@ type Command struct {
 
 	ctx *cue.Context
 
+	// stats collects the stats of the command if they were requested
+	// via --stats or $CUE_STATS_FILE, and is nil otherwise.
+	stats *Stats
+
 	hasErr bool
 }
 
@@ -351,7 +397,7 @@ func (c *Command) Run(ctx context.Context) (err error) {
 	// - user defined
 	// - help
 	// For the latter two, we need to use the default loading.
-	if err := c.root.Execute(); err != nil {
+	if err := c.root.ExecuteContext(ctx); err != nil {
 		return err
 	}
 	if c.hasErr {
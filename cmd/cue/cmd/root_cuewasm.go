@@ -17,11 +17,21 @@
 package cmd
 
 import (
+	"os"
+	"path/filepath"
+
 	"cuelang.org/go/cue/interpreter/wasm"
+	"cuelang.org/go/internal/cueconfig"
 )
 
 func init() {
 	// The wasm interpreter can be enabled by default once we are ready to ship the feature.
 	// For now, it's not ready, and makes cue binaries heavier by over 2MiB.
-	wasmInterp = wasm.New()
+	var opts []wasm.Option
+	// Keep compiled Wasm modules in the cache directory so that they
+	// are not compiled again every time cue runs.
+	if dir, err := cueconfig.CacheDir(os.Getenv); err == nil {
+		opts = append(opts, wasm.WithCache(wasm.NewCacheWithDir(filepath.Join(dir, "wasm"))))
+	}
+	wasmInterp = wasm.New(opts...)
 }
@@ -213,6 +213,25 @@ func TestScript(t *testing.T) {
 				ts.Setenv("CUE_REGISTRY", u.Host+"+insecure")
 				ts.Defer(srv.Close)
 			},
+			// httpfiles starts an HTTP server serving the files in a directory
+			// and sets the argument environment variable name to its URL.
+			// Files with a ".ctype" suffix hold the Content-Type to use for
+			// the file of the same name without the suffix.
+			"httpfiles": func(ts *testscript.TestScript, neg bool, args []string) {
+				if neg || len(args) != 2 {
+					ts.Fatalf("usage: httpfiles <envvar-name> <dir>")
+				}
+				dir := ts.MkAbs(args[1])
+				fileSrv := http.FileServer(http.Dir(dir))
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if ctype, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(r.URL.Path)+".ctype")); err == nil {
+						w.Header().Set("Content-Type", strings.TrimSpace(string(ctype)))
+					}
+					fileSrv.ServeHTTP(w, r)
+				}))
+				ts.Setenv(args[0], srv.URL)
+				ts.Defer(srv.Close)
+			},
 			// find-files recursively lists files under a directory, like `find -type f` on Linux.
 			// It prints slash-separated paths relative to the root working directory of the testscript run,
 			// for the sake of avoiding verbose and non-deterministic absolute paths.
@@ -15,21 +15,19 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/format"
-	"cuelang.org/go/cue/load"
-	"cuelang.org/go/internal/diff"
 	"cuelang.org/go/tools/trim"
 )
 
 // TODO:
 // - remove the limitations mentioned in the documentation
-// - implement verification post-processing as extra safety
 
 // newTrimCmd creates a trim command
 func newTrimCmd(c *Command) *cobra.Command {
@@ -82,17 +80,48 @@ Examples:
 
 It is guaranteed that the resulting files give the same output as before the
 removal.
+
+Imports and let declarations that are no longer used after trimming are
+removed, as are fields whose structs became empty, as long as the field is
+still declared elsewhere. With --remove-empty, files that are left without
+any declarations are deleted, unless they are the last file of a package.
+
+With --files0-from, only the CUE files in the NUL-separated list read from the
+named file, or from stdin if it is "-", are trimmed. Their packages are still
+loaded in full, so that constraints from other files are taken into account.
 `,
-		RunE: mkRunE(c, runTrim),
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, runTrim),
 	}
 
 	addOutFlags(cmd.Flags(), false)
 	cmd.Flags().BoolP(string(flagDryRun), "n", false, "only run simulation")
+	cmd.Flags().Bool(string(flagRemoveEmpty), false, "delete files that are empty after trimming")
+	addFileListFlag(cmd.Flags())
 
 	return cmd
 }
 
 func runTrim(cmd *Command, args []string) error {
+	// only restricts trimming to the files given by --files0-from, if any.
+	// The rest of their packages is still loaded to provide context.
+	var only map[string]bool
+	listed, err := readFileList(cmd, args)
+	if err != nil {
+		return err
+	}
+	if listed != nil {
+		if args, only, err = fileListPackages(listed); err != nil {
+			return err
+		}
+		if len(only) == 0 {
+			return nil
+		}
+	}
+	selected := func(f *ast.File) bool {
+		return only == nil || only[f.Filename]
+	}
+
 	defCfg, err := defaultConfig()
 	if err != nil {
 		return err
@@ -115,42 +144,27 @@ func runTrim(cmd *Command, args []string) error {
 		}
 	}
 
-	overlay := map[string]load.Source{}
+	// wasEmpty records the files that had no declarations to begin with, so
+	// that --remove-empty only deletes files that were emptied by trimming.
+	wasEmpty := map[*ast.File]bool{}
 
 	for i, inst := range binst {
+		for _, f := range inst.Files {
+			wasEmpty[f] = isEmptyFile(f)
+		}
 		root := instances[i]
-		err := trim.Files(inst.Files, root.Value(), &trim.Config{
-			Trace: flagTrace.Bool(cmd),
+		files := slices.DeleteFunc(slices.Clone(inst.Files), func(f *ast.File) bool {
+			return !selected(f)
+		})
+		// Unless forced, verify that the output does not change, so that
+		// the files are never written if it does.
+		err := trim.Files(files, root.Value(), &trim.Config{
+			Trace:  flagTrace.Bool(cmd),
+			Verify: !flagIgnore.Bool(cmd),
 		})
 		if err != nil {
 			return err
 		}
-
-		for _, f := range inst.Files {
-			overlay[f.Filename] = load.FromFile(f)
-		}
-
-	}
-
-	cfg := *defCfg.loadCfg
-	cfg.Overlay = overlay
-	tinsts, err := buildInstances(cmd, load.Instances(args, &cfg), false)
-	if err != nil {
-		return err
-	}
-	if len(tinsts) != len(binst) {
-		return errors.New("unexpected number of new instances")
-	}
-	if !flagIgnore.Bool(cmd) {
-		for i, p := range instances {
-			k, script := diff.Final.Diff(p.Value(), tinsts[i].Value())
-			if k != diff.Identity {
-				diff.Print(os.Stdout, script)
-				fmt.Println("Aborting trim, output differs after trimming. This is a bug! Use -i to force trim.")
-				fmt.Println("You can file a bug here: https://cuelang.org/issues/new?assignees=&labels=NeedsInvestigation&template=bug_report.md&title=")
-				return ErrPrintedError
-			}
-		}
 	}
 
 	if flagDryRun.Bool(cmd) {
@@ -158,9 +172,22 @@ func runTrim(cmd *Command, args []string) error {
 	}
 
 	for _, inst := range binst {
+		remaining := len(inst.Files)
 		for _, f := range inst.Files {
+			if !selected(f) {
+				continue
+			}
 			filename := f.Filename
 
+			if flagRemoveEmpty.Bool(cmd) && dst == "" && remaining > 1 &&
+				!wasEmpty[f] && isEmptyFile(f) {
+				if err := os.Remove(filename); err != nil {
+					return err
+				}
+				remaining--
+				continue
+			}
+
 			opts := []format.Option{}
 			if flagSimplify.Bool(cmd) {
 				opts = append(opts, format.Simplify())
@@ -189,3 +216,16 @@ func runTrim(cmd *Command, args []string) error {
 	}
 	return nil
 }
+
+// isEmptyFile reports whether f declares nothing besides its package clause
+// and imports.
+func isEmptyFile(f *ast.File) bool {
+	for _, d := range f.Decls {
+		switch d.(type) {
+		case *ast.Package, *ast.ImportDecl, *ast.CommentGroup:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"runtime/debug"
@@ -44,8 +45,11 @@ func newVersionCmd(c *Command) *cobra.Command {
 var version string
 
 func runVersion(cmd *Command, args []string) error {
-	w := cmd.OutOrStdout()
+	return writeVersion(cmd.OutOrStdout())
+}
 
+// writeVersion writes the version information printed by cue version to w.
+func writeVersion(w io.Writer) error {
 	// read in build info
 	bi, ok := readBuildInfo()
 	if !ok {
@@ -15,6 +15,8 @@
 package cmd
 
 import (
+	"slices"
+
 	"github.com/spf13/cobra"
 	"golang.org/x/text/message"
 
@@ -61,21 +63,85 @@ Examples:
 
 More than one expression may be given using multiple -d flags. Each non-CUE
 file must match all expression values.
+
+Schemas often declare fields that data must supply as regular fields, such
+as "name: string", rather than as required fields, such as "name!: string".
+The --require-regular flag reports regular fields outside of definitions
+that do not have a concrete value as missing required fields, so that data
+which omits them fails with a clear error even without the -c flag.
+
+
+Policies
+
+The --policy flag additionally checks the policy rules declared alongside
+the data. Rules are declared in #deny and #warn definitions, which map
+the name of each rule to a message, or a list of messages, describing a
+violation. Typically, each rule is guarded by a condition, so that it is
+only present when it is violated:
+
+  #Deployment: {
+    replicas: int
+    image:    string
+
+    #deny: {
+      if replicas < 2 {
+        minReplicas: "need at least 2 replicas, got \(replicas)"
+      }
+    }
+    #warn: {
+      if strings.HasSuffix(image, ":latest") {
+        pinnedImage: "image \(image) is not pinned"
+      }
+    }
+  }
+
+With --policy, vet reports each violation along with its severity, the
+name of its rule, and the path of the struct declaring it, after the
+values passed validation. Rules are checked in all structs, including
+those of data files. Violations of #deny rules are errors, whereas
+violations of #warn rules are printed as warnings that do not cause vet
+to fail. Messages must be concrete strings.
+
+Example output:
+
+  deny minReplicas: deployments.web: need at least 2 replicas, got 1
+  warn pinnedImage: deployments.web: image nginx:latest is not pinned
+
+Caching
+
+Vet records the inputs that passed validation in the vet directory of
+CUE_CACHE_DIR (see "cue help environment"). When vet is run again with
+the same flags, packages and data files whose inputs, including the
+schemas they are checked against and all their dependencies, did not
+change are not validated again. Packages that embed files and runs that
+use -T are never cached. With --policy, inputs are only cached if they
+have no policy violations, including warnings. Use --no-cache to
+validate all inputs regardless of the cache.
 `
 
 func newVetCmd(c *Command) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "vet",
-		Short: "validate data",
-		Long:  vetDoc,
-		RunE:  mkRunE(c, doVet),
+		Use:               "vet",
+		Short:             "validate data",
+		Long:              vetDoc,
+		ValidArgsFunction: mkCompletion(c, completePackages),
+		RunE:              mkRunE(c, watchable(doVet)),
 	}
 
 	addOrphanFlags(cmd.Flags())
+	cmd.RegisterFlagCompletionFunc(string(flagSchema), mkCompletion(c, completeExpression))
 	addInjectionFlags(cmd.Flags(), false, false)
+	addWatchFlag(cmd.Flags())
+	addFileListFlag(cmd.Flags())
 
 	cmd.Flags().BoolP(string(flagConcrete), "c", false,
 		"require the evaluation to be concrete")
+	cmd.Flags().Bool(string(flagRequireRegular), false,
+		"report regular fields without a concrete value as missing required fields")
+	cmd.Flags().Bool(string(flagPolicy), false,
+		"report violations of the rules declared in #deny and #warn definitions")
+	cmd.Flags().Bool(string(flagNoCache), false,
+		"validate all inputs, even those that passed before")
 
 	return cmd
 }
@@ -89,6 +155,18 @@ func newVetCmd(c *Command) *cobra.Command {
 // TODO: allow unrooted schema, such as JSON schema to compare against
 // other values.
 func doVet(cmd *Command, args []string) error {
+	listed, err := readFileList(cmd, args)
+	if err != nil {
+		return err
+	}
+	if listed != nil {
+		if args, _, err = fileListPackages(listed); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return nil
+		}
+	}
 	b, err := parseArgs(cmd, args, &config{
 		noMerge: true,
 	})
@@ -99,15 +177,21 @@ func doVet(cmd *Command, args []string) error {
 	// Go into a special vet mode if the user explicitly specified non-cue
 	// files on the command line.
 	// TODO: unify these two modes.
+	cache := newVetCache(cmd)
 	if len(b.orphaned) > 0 {
-		return vetFiles(cmd, b)
+		return vetFiles(cmd, b, cache)
+	}
+
+	keys := skipCached(b, cache)
+	if keys == nil {
+		return nil
 	}
 
 	shown := false
 
 	iter := b.instances()
 	defer iter.close()
-	for iter.scan() {
+	for i := 0; iter.scan(); i++ {
 		v := iter.value()
 		// TODO: use ImportPath or some other sanitized path.
 
@@ -123,10 +207,15 @@ func doVet(cmd *Command, args []string) error {
 			cue.Attributes(true),
 			cue.Definitions(true),
 			cue.Hidden(true),
+			cue.RequireRegular(flagRequireRegular.Bool(cmd)),
 		}
 		w := cmd.Stderr()
 		err := v.Validate(append(opt, cue.Concrete(concrete))...)
-		if err != nil && !hasFlag {
+		if err == nil {
+			if !flagPolicy.Bool(cmd) || vetPolicy(cmd, v) {
+				cache.store(keys[i])
+			}
+		} else if !hasFlag {
 			err = v.Validate(append(opt, cue.Concrete(false))...)
 			if !shown && err == nil {
 				shown = true
@@ -143,24 +232,78 @@ func doVet(cmd *Command, args []string) error {
 	return nil
 }
 
-func vetFiles(cmd *Command, b *buildPlan) error {
+// skipCached removes the instances that passed validation before from b.
+// It returns the cache keys of the remaining values to validate, in the
+// order in which b.instances iterates over them, or nil if there is
+// nothing left to validate.
+func skipCached(b *buildPlan, cache *vetCache) (keys []string) {
+	if len(b.insts) == 0 {
+		// b.instance, if any, was built from the schema instances.
+		if b.instance == nil {
+			return []string{}
+		}
+		key := cache.key(b.schemaInsts)
+		if cache.hit(key) {
+			return nil
+		}
+		return []string{key}
+	}
+	insts := b.insts[:0]
+	for _, inst := range b.insts {
+		key := cache.key(append(slices.Clip(b.schemaInsts), inst))
+		if cache.hit(key) {
+			continue
+		}
+		insts = append(insts, inst)
+		keys = append(keys, key)
+	}
+	b.insts = insts
+	return keys
+}
+
+func vetFiles(cmd *Command, b *buildPlan, cache *vetCache) error {
 	// Use -r type root, instead of -e
 
 	if !b.encConfig.Schema.Exists() {
 		return errors.New("data files specified without a schema")
 	}
 
+	// Validate each file separately, so that the result for each file
+	// can be cached.
+	for _, di := range b.orphaned {
+		key := cache.key(b.schemaInsts, di.file)
+		if cache.hit(key) {
+			continue
+		}
+		b.orphaned = []*decoderInfo{di}
+		if err := vetFile(cmd, b, key, cache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vetFile validates the values of the single data file in b.orphaned.
+func vetFile(cmd *Command, b *buildPlan, key string, cache *vetCache) error {
+	ok := true
 	iter := b.instances()
 	defer iter.close()
 	for iter.scan() {
 		v := iter.value()
 
 		// Always concrete when checking against concrete files.
-		err := v.Validate(cue.Concrete(true))
+		err := v.Validate(cue.Concrete(true), cue.RequireRegular(flagRequireRegular.Bool(cmd)))
 		printError(cmd, err)
+		ok = ok && err == nil
+		if err == nil && flagPolicy.Bool(cmd) {
+			ok = vetPolicy(cmd, v) && ok
+		}
 	}
 	if err := iter.err(); err != nil {
 		return err
 	}
+	if ok {
+		cache.store(key)
+	}
 	return nil
 }
@@ -0,0 +1,168 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/internal/cueconfig"
+	"cuelang.org/go/internal/cueversion"
+)
+
+// A vetCache records which inputs passed validation by cue vet, so that
+// validating them again can be skipped when they have not changed.
+//
+// An entry is an empty file in $CUE_CACHE_DIR/vet named after the hash
+// of the contents of all files involved, of the flags that affect
+// validation, and of the version of cue.
+type vetCache struct {
+	dir   string // "" if caching is disabled
+	flags []byte // hash of the flags and environment
+}
+
+func newVetCache(cmd *Command) *vetCache {
+	// Injected variables, such as the current time, differ between runs.
+	if flagNoCache.Bool(cmd) || flagInjectVars.Bool(cmd) {
+		return &vetCache{}
+	}
+	dir, err := cueconfig.CacheDir(os.Getenv)
+	if err != nil {
+		return &vetCache{}
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "cue vet %s %s\n", cueversion.ModuleVersion(), os.Getenv("CUE_EXPERIMENT"))
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch flagName(f.Name) {
+		case flagNoCache, flagWatch, flagStats:
+			// These do not affect the outcome of validation.
+			return
+		}
+		fmt.Fprintf(h, "--%s=%s\n", f.Name, f.Value)
+	})
+	return &vetCache{
+		dir:   filepath.Join(dir, "vet"),
+		flags: h.Sum(nil),
+	}
+}
+
+// key returns the cache key for validating the given data files against
+// the given instances, including their dependencies. It returns "" if the
+// result cannot be cached.
+func (c *vetCache) key(insts []*build.Instance, files ...*build.File) string {
+	if c.dir == "" {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(c.flags)
+	for _, inst := range insts {
+		for _, p := range append([]*build.Instance{inst}, inst.Dependencies()...) {
+			fmt.Fprintf(h, "package %s %s\n", p.ImportPath, p.Dir)
+			for _, f := range p.Files {
+				// Embedded files are not part of the instance.
+				if usesEmbed(f) {
+					return ""
+				}
+			}
+			for _, f := range p.BuildFiles {
+				if !hashFile(h, f) {
+					return ""
+				}
+			}
+			for _, f := range p.OrphanedFiles {
+				if !hashFile(h, f) {
+					return ""
+				}
+			}
+		}
+	}
+	for _, f := range files {
+		if !hashFile(h, f) {
+			return ""
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile writes the contents of f to h. It reports false if the
+// contents of f cannot be determined.
+func hashFile(h hash.Hash, f *build.File) bool {
+	var data []byte
+	switch src := f.Source.(type) {
+	case nil:
+		if f.Filename == "-" {
+			return false
+		}
+		b, err := os.ReadFile(f.Filename)
+		if err != nil {
+			return false
+		}
+		data = b
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return false
+	}
+	fmt.Fprintf(h, "file %s %s %s %s %v %d\n",
+		f.Filename, f.Encoding, f.Interpretation, f.Form, f.Tags, len(data))
+	h.Write(data)
+	return true
+}
+
+// usesEmbed reports whether f embeds other files with @embed.
+func usesEmbed(f *ast.File) bool {
+	for _, d := range f.Decls {
+		a, ok := d.(*ast.Attribute)
+		if !ok {
+			continue
+		}
+		if key, body := a.Split(); key == "extern" && strings.TrimSpace(body) == "embed" {
+			return true
+		}
+	}
+	return false
+}
+
+// hit reports whether the validation identified by key passed before.
+func (c *vetCache) hit(key string) bool {
+	if key == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(c.dir, key))
+	return err == nil
+}
+
+// store records that the validation identified by key passed. Failures to
+// write the cache are ignored.
+func (c *vetCache) store(key string) {
+	if key == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o777); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(c.dir, key), nil, 0o666)
+}
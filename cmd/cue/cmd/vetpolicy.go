@@ -0,0 +1,107 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+)
+
+// policySeverities lists the definitions that declare policy rules, along
+// with the severity of their violations, in the order in which they are
+// reported for each struct.
+var policySeverities = []string{"deny", "warn"}
+
+// vetPolicy reports the violations of the policy rules declared in the
+// #deny and #warn definitions of v and of all the structs it contains.
+// Denied rules are reported as errors. Warnings are printed to stderr
+// without causing vet to fail. It reports whether there were no
+// violations at all.
+func vetPolicy(cmd *Command, v cue.Value) (ok bool) {
+	ok = true
+	var errs errors.Error
+	var walk func(v cue.Value)
+	walk = func(v cue.Value) {
+		switch v.IncompleteKind() {
+		case cue.StructKind:
+			for _, sev := range policySeverities {
+				rules := v.LookupPath(cue.MakePath(cue.Def(sev)))
+				if !rules.Exists() {
+					continue
+				}
+				w := cmd.OutOrStderr()
+				if sev == "deny" {
+					w = cmd.Stderr()
+				}
+				n, err := printViolations(w, sev, v.Path(), rules)
+				errs = errors.Append(errs, err)
+				ok = ok && n == 0 && err == nil
+			}
+			iter, _ := v.Fields()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		case cue.ListKind:
+			iter, _ := v.List()
+			for iter.Next() {
+				walk(iter.Value())
+			}
+		}
+	}
+	walk(v)
+	printError(cmd, errs)
+	return ok
+}
+
+// printViolations prints the messages of the violated rules in rules, a
+// struct mapping the name of each rule to a message or a list of messages.
+// Rules are typically declared within if comprehensions, so that only the
+// violated ones are present. It returns the number of messages printed.
+func printViolations(w io.Writer, sev string, path cue.Path, rules cue.Value) (n int, err errors.Error) {
+	prefix := ""
+	if p := path.String(); p != "" {
+		prefix = p + ": "
+	}
+	iter, err1 := rules.Fields()
+	if err1 != nil {
+		return 0, errors.Promote(err1, "invalid policy rules")
+	}
+	for iter.Next() {
+		name, rule := iter.Selector().String(), iter.Value()
+		var msgs []string
+		switch rule.IncompleteKind() {
+		case cue.StringKind:
+			msgs = []string{""}
+			err1 = rule.Decode(&msgs[0])
+		case cue.ListKind:
+			err1 = rule.Decode(&msgs)
+		default:
+			err1 = errors.Newf(rule.Pos(),
+				"policy rule %s must be a string or a list of strings", name)
+		}
+		if err1 != nil {
+			err = errors.Append(err, errors.Promote(err1, "invalid policy rule "+name))
+			continue
+		}
+		for _, msg := range msgs {
+			fmt.Fprintf(w, "%s %s: %s%s\n", sev, name, prefix, msg)
+			n++
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/pflag"
+
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+)
+
+// watchInterval is how often the watched file set is polled for changes
+// when file system notifications are not available.
+var watchInterval = 250 * time.Millisecond
+
+// watchSettle is how long to wait for further changes after a change is
+// notified, as saving a file often involves several file system events.
+const watchSettle = 50 * time.Millisecond
+
+func addWatchFlag(f *pflag.FlagSet) {
+	f.BoolP(string(flagWatch), "w", false,
+		"re-run the command whenever any of the loaded files change")
+}
+
+// watchable wraps a run function so that, when the --watch flag is set,
+// it is re-run each time one of the files making up the loaded instances
+// changes. Errors from individual runs are printed rather than returned,
+// so that the loop keeps going until the process is interrupted.
+func watchable(f runFunction) runFunction {
+	return func(cmd *Command, args []string) error {
+		if !flagWatch.Bool(cmd) {
+			return f(cmd, args)
+		}
+		for _, arg := range args {
+			if arg == "-" {
+				return errors.Newf(token.NoPos, "cannot use --watch when reading from stdin")
+			}
+		}
+		ctx := cmd.Context()
+		for {
+			if err := f(cmd, args); err != nil && err != ErrPrintedError {
+				printError(cmd, err)
+			}
+			// The set of files is determined once per run. Any change to
+			// the set itself, such as a new import or file, involves a
+			// change to a file or directory in the current set.
+			names, err := watchFileSet(args)
+			if err != nil {
+				return err
+			}
+			if err := waitForChange(ctx, names); err != nil {
+				return nil // interrupted
+			}
+			fmt.Fprintf(cmd.OutOrStderr(), "# change detected; re-running %s\n", cmd.Name())
+		}
+	}
+}
+
+// waitForChange blocks until one of the given files or directories changes,
+// or until ctx is done, in which case it returns the error of ctx. It relies
+// on file system notifications where they are available, and falls back to
+// polling otherwise.
+func waitForChange(ctx context.Context, names []string) error {
+	// The snapshot is taken before setting up notifications, so that a
+	// change made while doing so is not missed if they fail.
+	snap := statFiles(names)
+	if err := notifyChange(ctx, names); err == nil || ctx.Err() != nil {
+		return ctx.Err()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchInterval):
+		}
+		if !snap.equal(statFiles(names)) {
+			return nil
+		}
+	}
+}
+
+// notifyChange waits for a file system notification of a change to one of
+// the given files or directories. It reports an error if notifications are
+// not available, or if they fail while waiting.
+func notifyChange(ctx context.Context, names []string) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	files := make(map[string]bool)
+	for _, name := range names {
+		name, err := filepath.Abs(name)
+		if err != nil {
+			return err
+		}
+		files[name] = true
+		// Watching a directory reports changes to the files within it.
+		if fi, err := os.Stat(name); err == nil && fi.IsDir() {
+			if err := w.Add(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A change is either one to a watched file, or the addition or removal
+	// of a file in a watched directory.
+	const dirOps = fsnotify.Create | fsnotify.Remove | fsnotify.Rename
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-settle:
+			return nil
+		case err, ok := <-w.Errors:
+			if !ok {
+				err = errors.Newf(token.NoPos, "file watcher closed")
+			}
+			return err
+		case ev, ok := <-w.Events:
+			if !ok {
+				return errors.Newf(token.NoPos, "file watcher closed")
+			}
+			if settle == nil && (ev.Has(dirOps) || (files[ev.Name] && ev.Op != fsnotify.Chmod)) {
+				settle = time.After(watchSettle)
+			}
+		}
+	}
+}
+
+// fileSnapshot records the modification time and size of a set of files.
+// A missing file is recorded with a zero entry, so that creating or
+// removing a file is observed as a change.
+type fileSnapshot map[string]fileStamp
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (s fileSnapshot) equal(t fileSnapshot) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for name, a := range s {
+		b, ok := t[name]
+		if !ok || !a.modTime.Equal(b.modTime) || a.size != b.size {
+			return false
+		}
+	}
+	return true
+}
+
+// watchFileSet loads the instances for args and returns all files
+// involved, including those of transitively imported packages. The
+// directories holding those files are included as well, so that adding a
+// new file to a package is also detected.
+func watchFileSet(args []string) ([]string, error) {
+	cfg, err := defaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	return watchFiles(loadFromArgs(args, cfg.loadCfg)), nil
+}
+
+// statFiles records the state of the given files.
+func statFiles(names []string) fileSnapshot {
+	snap := make(fileSnapshot, len(names))
+	for _, name := range names {
+		var stamp fileStamp
+		if fi, err := os.Stat(name); err == nil {
+			stamp = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+		snap[name] = stamp
+	}
+	return snap
+}
+
+// watchFiles returns the sorted list of files and directories that
+// contribute to the given instances.
+func watchFiles(insts []*build.Instance) []string {
+	seen := map[*build.Instance]bool{}
+	var names []string
+	add := func(name string) {
+		if name == "" || name == "-" {
+			return
+		}
+		names = append(names, name, filepath.Dir(name))
+	}
+	var walk func(inst *build.Instance)
+	walk = func(inst *build.Instance) {
+		if seen[inst] {
+			return
+		}
+		seen[inst] = true
+		if inst.Dir != "" {
+			names = append(names, inst.Dir)
+		}
+		for _, f := range inst.BuildFiles {
+			add(f.Filename)
+		}
+		for _, f := range inst.OrphanedFiles {
+			add(f.Filename)
+		}
+		for _, f := range inst.IgnoredFiles {
+			// Ignored files may become part of the build after an edit,
+			// for example when a build attribute or package clause changes.
+			add(f.Filename)
+		}
+		for _, imp := range inst.Imports {
+			walk(imp)
+		}
+	}
+	for _, inst := range insts {
+		walk(inst)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
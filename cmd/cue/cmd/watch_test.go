@@ -0,0 +1,112 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-quicktest/qt"
+)
+
+func TestWatchSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "x.cue")
+	qt.Assert(t, qt.IsNil(os.WriteFile(file, []byte("a: 1\n"), 0o666)))
+
+	names, err := watchFileSet([]string{file})
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.IsTrue(slices.Contains(names, file)), qt.Commentf("names: %q", names))
+	qt.Assert(t, qt.IsTrue(slices.Contains(names, dir)), qt.Commentf("names: %q", names))
+
+	snap1 := statFiles(names)
+	qt.Assert(t, qt.IsTrue(snap1.equal(statFiles(names))))
+
+	qt.Assert(t, qt.IsNil(os.WriteFile(file, []byte("a: 12\n"), 0o666)))
+	future := time.Now().Add(time.Hour)
+	qt.Assert(t, qt.IsNil(os.Chtimes(file, future, future)))
+	qt.Assert(t, qt.IsFalse(snap1.equal(statFiles(names))))
+
+	// Removing a file is a change as well.
+	snap2 := statFiles(names)
+	qt.Assert(t, qt.IsNil(os.Remove(file)))
+	qt.Assert(t, qt.IsFalse(snap2.equal(statFiles(names))))
+}
+
+func TestWatchRerun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "x.cue")
+	qt.Assert(t, qt.IsNil(os.WriteFile(file, []byte("a: 1\n"), 0o666)))
+
+	c, err := New([]string{"export", "--watch", file})
+	qt.Assert(t, qt.IsNil(err))
+	var out syncBuffer
+	c.SetOutput(&out)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	waitForOutput(t, &out, `"a": 1`, nil)
+	// The file may be written before the command starts watching it, so it
+	// is written repeatedly until the change is picked up.
+	waitForOutput(t, &out, `"a": 2`, func() {
+		qt.Assert(t, qt.IsNil(os.WriteFile(file, []byte("a: 2\n"), 0o666)))
+	})
+	qt.Assert(t, qt.StringContains(out.String(), "# change detected; re-running export"))
+
+	cancel()
+	qt.Assert(t, qt.IsNil(<-done))
+}
+
+// waitForOutput waits for out to contain want, calling poke, if not nil,
+// while waiting.
+func waitForOutput(t *testing.T, out *syncBuffer, want string, poke func()) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %q in output:\n%s", want, out.String())
+		}
+		if poke != nil {
+			poke()
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// findWorkspace returns the path of the cue.work file to use, or the empty
+// string if there is none. $CUE_WORK can be set to the path of a cue.work
+// file, or to "off" to disable workspaces.
+func findWorkspace() (string, error) {
+	switch env := os.Getenv("CUE_WORK"); env {
+	case "off":
+		return "", nil
+	case "":
+	default:
+		return filepath.Abs(env)
+	}
+	dir := rootWorkingDir
+	for {
+		workFile := filepath.Join(dir, "cue.work")
+		if _, err := os.Stat(workFile); err == nil {
+			return workFile, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+		dir1 := filepath.Dir(dir)
+		if dir1 == dir {
+			return "", nil
+		}
+		dir = dir1
+	}
+}
@@ -140,3 +140,63 @@ func TestNewStruct(t *testing.T) {
 		t.Equal(string(b), tc.want)
 	})
 }
+
+func TestStructBuilder(t *testing.T) {
+	inner := ast.NewStructBuilder()
+	inner.AddField("port", ast.NewIdent("int"), ast.WithLineComment("TCP port"))
+	inner.AddField("host", ast.NewIdent("string"), ast.WithConstraint(token.OPTION))
+
+	b := ast.NewStructBuilder()
+	b.AddField(ast.NewIdent("#Server"), inner.Struct(),
+		ast.WithDoc("Server describes a server.\n\nIt is used for tests."))
+	b.AddField("name", ast.NewString("x"), ast.WithAttr("@go(Name)"))
+	b.AddField("x-y", ast.NewLit(token.INT, "1"))
+	b.AddField("#def", ast.NewLit(token.INT, "2"))
+	b.AddField("if", ast.NewBool(true))
+	b.AddComment("Embedded values.")
+	b.AddEmbed(ast.NewSel(ast.NewIdent("strings"), "MinRunes"))
+	b.AddField("list", ast.NewStructBuilder().Struct(),
+		ast.WithConstraint(token.NOT), ast.WithDoc("Doc."))
+	b.AddField("last", ast.NewNull())
+
+	f := b.File("example",
+		ast.NewImport(nil, "strings"),
+		ast.NewImport(ast.NewIdent("l"), "list"),
+	)
+	got, err := format.Node(f)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(got), `package example
+
+import (
+	"strings"
+	l "list"
+)
+
+// Server describes a server.
+//
+// It is used for tests.
+#Server: {
+	port:  int // TCP port
+	host?: string
+}
+
+name:   "x" @go(Name)
+"x-y":  1
+"#def": 2
+"if":   true
+
+// Embedded values.
+
+strings.MinRunes
+
+// Doc.
+list!: {}
+
+last: null
+`))
+
+	f = ast.NewStructBuilder().File("", ast.NewImport(nil, "strings"))
+	got, err = format.Node(f)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(got), "import \"strings\"\n"))
+}
@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"hash/fnv"
 	"reflect"
+	"slices"
 
 	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/token"
 )
 
 // A Cursor describes a node encountered during Apply.
@@ -30,7 +32,9 @@ import (
 // The methods Replace, Delete, InsertBefore, and InsertAfter
 // can be used to change the AST without disrupting Apply.
 // Delete, InsertBefore, and InsertAfter are only defined for modifying
-// a StructLit and will panic in any other context.
+// a StructLit and will panic in any other context. InsertBeforeField and
+// InsertAfterField insert declarations relative to a field of the current
+// node and are only defined if it is a StructLit or File.
 type Cursor interface {
 	// Node returns the current Node.
 	Node() ast.Node
@@ -71,6 +75,24 @@ type Cursor interface {
 	// Unless n is wrapped by ApplyRecursively, Apply does not walk n.
 	InsertBefore(n ast.Node)
 
+	// InsertBeforeField inserts n before the field with the given label
+	// in the current Node, which must be a StructLit or File. It reports
+	// whether such a field was found; n is not inserted otherwise.
+	//
+	// Comments that directly precede the field remain attached to it: n is
+	// inserted before them. If the field starts a new section, so does n.
+	// Unless n is wrapped by ApplyRecursively, Apply does not walk n.
+	InsertBeforeField(label string, n ast.Node) bool
+
+	// InsertAfterField inserts n after the field with the given label in
+	// the current Node, which must be a StructLit or File. It reports
+	// whether such a field was found; n is not inserted otherwise.
+	//
+	// Comments on the same line as the field remain attached to it: n is
+	// inserted after them.
+	// Unless n is wrapped by ApplyRecursively, Apply does not walk n.
+	InsertAfterField(label string, n ast.Node) bool
+
 	self() *cursor
 }
 
@@ -98,6 +120,10 @@ type cursor struct {
 	typ      interface{} // the type of the node
 	index    int         // position of any of the sub types.
 	replaced bool
+
+	// inserted records the declarations inserted into the current node
+	// with InsertBeforeField or InsertAfterField that are not to be walked.
+	inserted map[ast.Node]bool
 }
 
 func newCursor(parent Cursor, n ast.Node, typ interface{}) *cursor {
@@ -170,6 +196,95 @@ func (c *cursor) InsertAfter(n ast.Node)  { panic("unsupported") }
 func (c *cursor) InsertBefore(n ast.Node) { panic("unsupported") }
 func (c *cursor) Delete()                 { panic("unsupported") }
 
+func (c *cursor) InsertBeforeField(label string, n ast.Node) bool {
+	return c.insertField(label, n, false)
+}
+
+func (c *cursor) InsertAfterField(label string, n ast.Node) bool {
+	return c.insertField(label, n, true)
+}
+
+func (c *cursor) insertField(label string, n ast.Node, after bool) bool {
+	var decls *[]ast.Decl
+	switch x := c.node.(type) {
+	case *ast.StructLit:
+		decls = &x.Elts
+	case *ast.File:
+		decls = &x.Decls
+	default:
+		panic(fmt.Sprintf("cannot insert field into %T", c.node))
+	}
+	i, ok := fieldIndex(*decls, label)
+	if !ok {
+		return false
+	}
+	if r, ok := n.(recursive); ok {
+		n = r.Node
+	} else {
+		if c.inserted == nil {
+			c.inserted = map[ast.Node]bool{}
+		}
+		c.inserted[n] = true
+	}
+	d := n.(ast.Decl)
+
+	a := *decls
+	rel := token.Newline
+	if after {
+		// Skip comments on the same line as the field.
+		for i++; i < len(a); i++ {
+			cg, ok := a[i].(*ast.CommentGroup)
+			if !ok || !cg.Line {
+				break
+			}
+		}
+	} else {
+		rel = relPos(a[i])
+		// Skip comments that directly precede the field.
+		for i > 0 && rel < token.NewSection {
+			if _, ok := a[i-1].(*ast.CommentGroup); !ok {
+				break
+			}
+			i--
+			rel = relPos(a[i])
+		}
+		if rel < token.Newline {
+			rel = token.Newline
+		}
+	}
+	if relPos(d) == token.NoRelPos {
+		if i == 0 {
+			rel = token.NoRelPos
+		}
+		setRelPos(d, rel)
+	}
+	*decls = slices.Insert(a, i, d)
+	return true
+}
+
+// relPos returns the relative position of the first token of n, taking
+// into account any doc comments.
+func relPos(n ast.Node) token.RelPos {
+	for _, cg := range ast.Comments(n) {
+		if cg.Doc || cg.Position == 0 {
+			return cg.Pos().RelPos()
+		}
+	}
+	return n.Pos().RelPos()
+}
+
+// setRelPos sets the relative position of the first token of n, including
+// any doc comments.
+func setRelPos(n ast.Node, rel token.RelPos) {
+	for _, cg := range ast.Comments(n) {
+		if cg.Doc || cg.Position == 0 {
+			cg.List[0].Slash = cg.List[0].Slash.WithRel(rel)
+			break
+		}
+	}
+	ast.SetRelPos(n, rel)
+}
+
 // Apply traverses a syntax tree recursively, starting with root,
 // and calling pre and post for each node as described below.
 // Apply returns the syntax tree, possibly modified.
@@ -236,7 +351,13 @@ func applyDeclList(v applyVisitor, parent Cursor, list []ast.Decl) []ast.Decl {
 	if file, ok := parent.Node().(*ast.File); ok {
 		c.cursor.file = &info{f: file, current: c}
 	}
+	inserted := parent.self().inserted
+	parent.self().inserted = nil
 	for i, x := range list {
+		if inserted[x] {
+			c.decls = append(c.decls, x)
+			continue
+		}
 		c.node = x
 		c.typ = &list[i]
 		applyCursor(v, c)
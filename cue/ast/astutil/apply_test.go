@@ -319,6 +319,65 @@ a: list6c6973
 			}
 			return true
 		},
+	}, {
+		name: "insert before and after field",
+		in: `
+		a: 1
+
+		// b is b.
+		b: 2 // two
+		c: {
+			x: 1
+		}
+		`,
+		out: `
+a: 1
+
+new: 1
+
+// b is b.
+b:    2 // two
+next: 1
+c: {
+	x:     1
+	inner: 1
+}
+`,
+		before: func(c astutil.Cursor) bool {
+			switch x := c.Node().(type) {
+			case *ast.File:
+				ok := c.InsertBeforeField("b", &ast.Field{
+					Label: ast.NewIdent("new"),
+					Value: ast.NewLit(token.INT, "1"),
+				})
+				qt.Check(t, qt.IsTrue(ok))
+				ok = c.InsertAfterField("b", &ast.Field{
+					Label: ast.NewIdent("next"),
+					Value: ast.NewLit(token.INT, "1"),
+				})
+				qt.Check(t, qt.IsTrue(ok))
+				ok = c.InsertAfterField("missing", &ast.Field{
+					Label: ast.NewIdent("never"),
+					Value: ast.NewLit(token.INT, "1"),
+				})
+				qt.Check(t, qt.IsFalse(ok))
+			case *ast.StructLit:
+				c.InsertAfterField("x", astutil.ApplyRecursively(&ast.Field{
+					Label: ast.NewIdent("inner"),
+					Value: ast.NewLit(token.INT, "0"),
+				}))
+			case *ast.BasicLit:
+				// Only fields walked by Apply are changed.
+				if x.Value == "0" {
+					c.Replace(ast.NewLit(token.INT, "1"))
+				}
+			case *ast.Ident:
+				if x.Name == "new" || x.Name == "next" {
+					c.Replace(ast.NewIdent("walked"))
+				}
+			}
+			return true
+		},
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -0,0 +1,170 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package astutil
+
+import (
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+)
+
+// This file contains helpers for programmatically editing CUE files.
+//
+// The helpers modify a file in place and only touch the nodes that are
+// edited, so that the comments and formatting of all other declarations
+// are preserved when the file is formatted again.
+//
+// A path is a sequence of field names, where each name selects the field
+// with that label within the struct that is the value of the field
+// selected by the previous name. For instance, the path ["a", "b"]
+// selects field b in
+//
+//	a: b: 1
+//
+// as well as in
+//
+//	a: {
+//		b: 1
+//	}
+//
+// Only fields declared directly within a file or struct literal are
+// considered: fields within embeddings, comprehensions, or referenced
+// values are not. It is an error if a name selects more than one field.
+
+// ReplaceValue replaces the value of the field at path in f with x.
+// The comments and position of the old value are copied to x, so that
+// comments following the value remain in place.
+func ReplaceValue(f *ast.File, path []string, x ast.Expr) error {
+	if len(path) == 0 {
+		return errors.Newf(f.Pos(), "empty path")
+	}
+	field, err := lookupField(f, path)
+	if err != nil {
+		return err
+	}
+	CopyMeta(x, field.Value)
+	field.Value = x
+	return nil
+}
+
+// DeleteField removes the field at path from f, along with its comments.
+func DeleteField(f *ast.File, path []string) error {
+	if len(path) == 0 {
+		return errors.Newf(f.Pos(), "empty path")
+	}
+	decls, err := lookupDecls(f, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	i, err := findField(*decls, path)
+	if err != nil {
+		return err
+	}
+	*decls = slices.Delete(*decls, i, i+1)
+	return nil
+}
+
+// InsertField adds field after the last declaration of the struct at path
+// in f, or of f itself if path is empty. It is an error if the struct
+// already has a field with the same label.
+func InsertField(f *ast.File, path []string, field *ast.Field) error {
+	decls, err := lookupDecls(f, path)
+	if err != nil {
+		return err
+	}
+	name, _, err := ast.LabelName(field.Label)
+	if err != nil {
+		return errors.Wrapf(err, field.Pos(), "invalid label")
+	}
+	if _, ok := fieldIndex(*decls, name); ok {
+		return errors.Newf(field.Pos(), "field %s already exists", pathString(append(slices.Clip(path), name)))
+	}
+	// Separate the new field from a preceding one line declaration, such
+	// as in a struct that was written on a single line, to keep the
+	// output readable.
+	if len(*decls) > 0 && field.Pos().RelPos() == token.NoRelPos {
+		ast.SetRelPos(field, token.Newline)
+	}
+	*decls = append(*decls, field)
+	return nil
+}
+
+// lookupField returns the field at path, which must not be empty.
+func lookupField(f *ast.File, path []string) (*ast.Field, error) {
+	decls, err := lookupDecls(f, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	i, err := findField(*decls, path)
+	if err != nil {
+		return nil, err
+	}
+	return (*decls)[i].(*ast.Field), nil
+}
+
+// lookupDecls returns the declarations of the struct at path.
+func lookupDecls(f *ast.File, path []string) (*[]ast.Decl, error) {
+	decls := &f.Decls
+	for i := range path {
+		j, err := findField(*decls, path[:i+1])
+		if err != nil {
+			return nil, err
+		}
+		field := (*decls)[j].(*ast.Field)
+		s, ok := field.Value.(*ast.StructLit)
+		if !ok {
+			return nil, errors.Newf(field.Value.Pos(),
+				"value of %s is not a struct literal", pathString(path[:i+1]))
+		}
+		decls = &s.Elts
+	}
+	return decls, nil
+}
+
+// findField returns the index of the field in decls selected by the last
+// name of path.
+func findField(decls []ast.Decl, path []string) (int, error) {
+	name := path[len(path)-1]
+	i, ok := fieldIndex(decls, name)
+	if !ok {
+		return 0, errors.Newf(token.NoPos, "field %s not found", pathString(path))
+	}
+	if _, ok := fieldIndex(decls[i+1:], name); ok {
+		return 0, errors.Newf(decls[i].Pos(), "multiple fields %s", pathString(path))
+	}
+	return i, nil
+}
+
+// fieldIndex reports the index of the first field in decls with the given
+// name.
+func fieldIndex(decls []ast.Decl, name string) (int, bool) {
+	for i, d := range decls {
+		f, ok := d.(*ast.Field)
+		if !ok {
+			continue
+		}
+		if s, _, err := ast.LabelName(f.Label); err == nil && s == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func pathString(path []string) string {
+	return strings.Join(path, ".")
+}
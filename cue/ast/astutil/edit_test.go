@@ -0,0 +1,158 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package astutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+)
+
+const editInput = `// Package comment.
+package p
+
+// Doc for version.
+version: "1.0.0" // current release
+
+deps: {
+	// Doc for foo.
+	foo: "1.2" // pinned
+	bar: "2.0"
+}
+
+short: {a: 1}
+
+a: b: c: 1 // nested
+`
+
+func TestEdit(t *testing.T) {
+	testCases := []struct {
+		desc string
+		edit func(f *ast.File) error
+		want string
+		err  string
+	}{{
+		desc: "replace value",
+		edit: func(f *ast.File) error {
+			return astutil.ReplaceValue(f, []string{"version"}, ast.NewString("1.1.0"))
+		},
+		want: strings.Replace(editInput, `"1.0.0"`, `"1.1.0"`, 1),
+	}, {
+		desc: "replace nested value",
+		edit: func(f *ast.File) error {
+			return astutil.ReplaceValue(f, []string{"deps", "foo"}, ast.NewString("1.3"))
+		},
+		want: strings.Replace(editInput, `"1.2"`, `"1.3"`, 1),
+	}, {
+		desc: "replace value of shorthand field",
+		edit: func(f *ast.File) error {
+			return astutil.ReplaceValue(f, []string{"a", "b", "c"}, ast.NewLit(0, "2"))
+		},
+		want: strings.Replace(editInput, "c: 1", "c: 2", 1),
+	}, {
+		desc: "delete field",
+		edit: func(f *ast.File) error {
+			return astutil.DeleteField(f, []string{"deps", "foo"})
+		},
+		want: strings.Replace(editInput, "\t// Doc for foo.\n\tfoo: \"1.2\" // pinned\n", "", 1),
+	}, {
+		desc: "insert field",
+		edit: func(f *ast.File) error {
+			return astutil.InsertField(f, []string{"deps"}, &ast.Field{
+				Label: ast.NewIdent("baz"),
+				Value: ast.NewString("3.0"),
+			})
+		},
+		want: strings.Replace(editInput, "\tbar: \"2.0\"\n", "\tbar: \"2.0\"\n\tbaz: \"3.0\"\n", 1),
+	}, {
+		desc: "insert field in single line struct",
+		edit: func(f *ast.File) error {
+			return astutil.InsertField(f, []string{"short"}, &ast.Field{
+				Label: ast.NewString("quoted-label"),
+				Value: ast.NewLit(0, "2"),
+			})
+		},
+		want: strings.Replace(editInput, "short: {a: 1}", "short: {a: 1\n\t\"quoted-label\": 2\n}", 1),
+	}, {
+		desc: "insert top-level field",
+		edit: func(f *ast.File) error {
+			return astutil.InsertField(f, nil, &ast.Field{
+				Label: ast.NewIdent("z"),
+				Value: ast.NewBool(true),
+			})
+		},
+		want: editInput + "z: true\n",
+	}, {
+		desc: "field not found",
+		edit: func(f *ast.File) error {
+			return astutil.ReplaceValue(f, []string{"deps", "qux"}, ast.NewNull())
+		},
+		err: "field deps.qux not found",
+	}, {
+		desc: "not a struct",
+		edit: func(f *ast.File) error {
+			return astutil.DeleteField(f, []string{"version", "x"})
+		},
+		err: "value of version is not a struct literal",
+	}, {
+		desc: "field exists",
+		edit: func(f *ast.File) error {
+			return astutil.InsertField(f, []string{"deps"}, &ast.Field{
+				Label: ast.NewIdent("bar"),
+				Value: ast.NewString("3.0"),
+			})
+		},
+		err: "field deps.bar already exists",
+	}, {
+		desc: "empty path",
+		edit: func(f *ast.File) error {
+			return astutil.DeleteField(f, nil)
+		},
+		err: "empty path",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := parser.ParseFile("edit.cue", editInput, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tc.edit(f)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("got error %v; want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := format.Node(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, string(b)); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
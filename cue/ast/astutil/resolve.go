@@ -82,7 +82,12 @@ func Resolve(f *ast.File, errFn ErrFunc) {
 // It will not overwrite already resolved values.
 func ResolveExpr(e ast.Expr, errFn ErrFunc) {
 	f := &ast.File{}
-	walkVisitor(e, &scope{file: f, errFn: errFn, identFn: resolveIdent})
+	walkVisitor(e, &scope{
+		file:    f,
+		index:   map[string]entry{},
+		errFn:   errFn,
+		identFn: resolveIdent,
+	})
 }
 
 // A Scope maintains the set of named language entities declared
@@ -0,0 +1,246 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"cuelang.org/go/cue/token"
+)
+
+// A StructBuilder constructs the declarations of a struct literal or file
+// one at a time. It takes care of setting the relative positions of the
+// declarations it creates, so that the result is formatted as expected:
+// declarations with a doc comment are set off from their neighbors by a
+// blank line and all other declarations are put on a line of their own.
+//
+// Useful for ASTs generated by code other than the CUE parser.
+type StructBuilder struct {
+	decls []Decl
+
+	// section reports whether the next declaration must start a new
+	// section, as the previous one had a doc comment.
+	section bool
+}
+
+// NewStructBuilder returns a new, empty StructBuilder.
+func NewStructBuilder() *StructBuilder {
+	return &StructBuilder{}
+}
+
+// A FieldOption configures a field added with [StructBuilder.AddField].
+type FieldOption func(f *Field)
+
+// WithDoc sets the doc comment of a field. Each line of text is converted
+// to a line comment. An empty text does not add a comment.
+func WithDoc(text string) FieldOption {
+	return func(f *Field) {
+		if cg := newComment(text, true); cg != nil {
+			AddComment(f, cg)
+		}
+	}
+}
+
+// WithLineComment sets the comment that follows a field on the same line.
+// An empty text does not add a comment.
+func WithLineComment(text string) FieldOption {
+	return func(f *Field) {
+		if cg := newComment(text, false); cg != nil {
+			AddComment(f, cg)
+		}
+	}
+}
+
+// WithAttr adds an attribute to a field. The text must be a valid
+// attribute, such as @go(Name).
+func WithAttr(text string) FieldOption {
+	return func(f *Field) {
+		f.Attrs = append(f.Attrs, &Attribute{Text: text})
+	}
+}
+
+// WithConstraint marks a field as optional or required. The token must be
+// either token.OPTION or token.NOT.
+func WithConstraint(t token.Token) FieldOption {
+	switch t {
+	case token.OPTION, token.NOT:
+	default:
+		panic(fmt.Sprintf("invalid field constraint %s", t))
+	}
+	return func(f *Field) {
+		f.Constraint = t
+	}
+}
+
+// AddField adds a field with the given label and value and returns it.
+//
+// The label is either a Label or a string. A string is used as the name of
+// a regular field and is quoted if it is not a valid identifier or if it
+// would otherwise denote a definition or hidden field.
+func (b *StructBuilder) AddField(label any, value Expr, opts ...FieldOption) *Field {
+	var l Label
+	switch x := label.(type) {
+	case Label:
+		l = x
+	case string:
+		l = newLabel(x)
+	default:
+		panic(fmt.Sprintf("unsupported label type %T", label))
+	}
+	f := &Field{Label: l, Value: value}
+	for _, opt := range opts {
+		opt(f)
+	}
+	b.add(f, docComment(f) != nil)
+	return f
+}
+
+// AddEmbed adds an embedded expression and returns its declaration.
+func (b *StructBuilder) AddEmbed(x Expr) *EmbedDecl {
+	d := &EmbedDecl{Expr: x}
+	b.add(d, false)
+	return d
+}
+
+// AddComment adds a comment that is not associated with any declaration.
+// It is set off from its neighbors by a blank line. An empty text does not
+// add a comment.
+func (b *StructBuilder) AddComment(text string) {
+	cg := newComment(text, true)
+	if cg == nil {
+		return
+	}
+	if len(b.decls) > 0 {
+		cg.List[0].Slash = token.NewSection.Pos()
+	}
+	b.decls = append(b.decls, cg)
+	b.section = true
+}
+
+func (b *StructBuilder) add(d Decl, doc bool) {
+	rel := token.Newline
+	if len(b.decls) > 0 && (doc || b.section) {
+		rel = token.NewSection
+	}
+	SetRelPos(d, rel)
+	if doc {
+		// The comment precedes the declaration, so it determines
+		// whether the declaration is preceded by a blank line.
+		docComment(d).List[0].Slash = rel.Pos()
+	}
+	b.decls = append(b.decls, d)
+	b.section = doc
+}
+
+// Decls returns the declarations added so far.
+func (b *StructBuilder) Decls() []Decl {
+	return b.decls
+}
+
+// Struct returns a struct literal holding the declarations added so far.
+// Structs with declarations are formatted over multiple lines.
+func (b *StructBuilder) Struct() *StructLit {
+	s := &StructLit{
+		Lbrace: token.NoSpace.Pos(),
+		Elts:   b.decls,
+	}
+	if len(b.decls) > 0 {
+		s.Rbrace = token.Newline.Pos()
+	}
+	return s
+}
+
+// File returns a file holding the declarations added so far, preceded by a
+// package clause, if pkg is not empty, and a declaration for the given
+// imports, if any.
+func (b *StructBuilder) File(pkg string, imports ...*ImportSpec) *File {
+	f := &File{}
+	if pkg != "" {
+		f.Decls = append(f.Decls, &Package{Name: NewIdent(pkg)})
+	}
+	if len(imports) > 0 {
+		d := NewImportDecl(imports...)
+		if len(f.Decls) > 0 {
+			d.Import = token.NewSection.Pos()
+		}
+		f.Decls = append(f.Decls, d)
+		f.Imports = imports
+	}
+	if len(f.Decls) > 0 && len(b.decls) > 0 {
+		SetRelPos(b.decls[0], token.NewSection)
+		if cg := docComment(b.decls[0]); cg != nil {
+			cg.List[0].Slash = token.NewSection.Pos()
+		}
+	}
+	f.Decls = append(f.Decls, b.decls...)
+	return f
+}
+
+// NewImportDecl creates an import declaration for the given imports, which
+// are typically created with [NewImport]. The imports are parenthesized
+// if there is more than one.
+func NewImportDecl(specs ...*ImportSpec) *ImportDecl {
+	d := &ImportDecl{Specs: specs}
+	if len(specs) > 1 {
+		d.Lparen = token.Blank.Pos()
+		for _, s := range specs {
+			SetRelPos(s, token.Newline)
+		}
+		d.Rparen = token.Newline.Pos()
+	}
+	return d
+}
+
+// newLabel returns a label for a regular field with the given name.
+func newLabel(name string) Label {
+	if IsValidIdent(name) && !strings.HasPrefix(name, "#") &&
+		!strings.HasPrefix(name, "_") && !token.Lookup(name).IsKeyword() {
+		return NewIdent(name)
+	}
+	return NewString(name)
+}
+
+// docComment returns the doc comment of n or nil if it has none.
+func docComment(n Node) *CommentGroup {
+	for _, cg := range Comments(n) {
+		if cg.Doc {
+			return cg
+		}
+	}
+	return nil
+}
+
+// newComment converts text to a comment group. Doc comments are placed
+// before a node, other comments at the end of the line.
+func newComment(text string, doc bool) *CommentGroup {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	cg := &CommentGroup{Doc: doc}
+	if !doc {
+		cg.Line = true
+		cg.Position = 10
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			line = " " + line
+		}
+		cg.List = append(cg.List, &Comment{Text: "//" + line})
+	}
+	return cg
+}
@@ -47,6 +47,7 @@ const (
 	JSON        Encoding = "json"
 	YAML        Encoding = "yaml"
 	TOML        Encoding = "toml"
+	Markdown    Encoding = "markdown"
 	JSONL       Encoding = "jsonl"
 	Text        Encoding = "text"
 	Binary      Encoding = "binary"
@@ -50,6 +50,10 @@ type Instance struct {
 	loadFunc LoadFunc
 	done     bool
 
+	// syntaxFunc, if non-nil, is used by LoadSyntax to obtain the
+	// complete syntax of BuildFiles. See SetLazySyntax.
+	syntaxFunc func(*File) (*ast.File, error)
+
 	// PkgName is the name specified in the package clause.
 	PkgName string
 	hasName bool
@@ -226,6 +230,40 @@ func (inst *Instance) AddFile(filename string, src interface{}) error {
 	return inst.AddSyntax(file)
 }
 
+// SetLazySyntax records that Files holds only the package clauses and
+// imports of the instance's BuildFiles, which suffices to compute its
+// dependencies. The complete syntax of each build file is obtained with
+// parse when [Instance.LoadSyntax] is first called.
+//
+// This is used by cue/load to avoid parsing the files of packages that
+// are never built.
+func (inst *Instance) SetLazySyntax(parse func(*File) (*ast.File, error)) {
+	inst.syntaxFunc = parse
+}
+
+// LoadSyntax ensures that Files holds the complete syntax of all build
+// files of the instance. It only needs to be called for instances for
+// which [Instance.SetLazySyntax] was used; building an instance calls it
+// automatically.
+func (inst *Instance) LoadSyntax() errors.Error {
+	parse := inst.syntaxFunc
+	if parse == nil {
+		return nil
+	}
+	inst.syntaxFunc = nil
+	inst.Files = nil
+	for _, bf := range inst.BuildFiles {
+		f, err := parse(bf)
+		if err != nil {
+			inst.ReportError(errors.Promote(err, "load"))
+		}
+		if f != nil {
+			_ = inst.AddSyntax(f)
+		}
+	}
+	return inst.Err
+}
+
 // AddSyntax adds the given file to list of files for this instance. The package
 // name of the file must match the package name of the instance.
 func (inst *Instance) AddSyntax(file *ast.File) errors.Error {
@@ -128,6 +128,30 @@ func (c *Context) BuildInstance(i *build.Instance, options ...BuildOption) Value
 	return c.make(v)
 }
 
+// RebuildInstance builds a new version of the instance b, which must have
+// been built by c before, in which the given files replace the files of b
+// with the same file name. Files with a name that is not yet part of b are
+// added. It returns the new instance, which can be passed to RebuildInstance
+// again, along with its [Value]. The instance b itself can no longer be
+// rebuilt afterwards.
+//
+// RebuildInstance is like [Context.BuildInstance], but reuses the packages
+// imported by b and the compiled form of the files that did not change,
+// unless the change adds or removes top-level fields of the package. The
+// returned value is evaluated from scratch.
+//
+// The new files may only import packages that are already imported by b.
+// The returned value will represent an error, accessible through
+// [Value.Err], if any error occurred.
+func (c *Context) RebuildInstance(b *build.Instance, files []*ast.File, options ...BuildOption) (*build.Instance, Value) {
+	cfg := c.parseOptions(options)
+	nb, v, err := c.runtime().Rebuild(&cfg, b, files...)
+	if err != nil {
+		return nb, c.makeError(err)
+	}
+	return nb, c.make(v)
+}
+
 func (c *Context) makeError(err errors.Error) Value {
 	b := &adt.Bottom{Err: err}
 	node := &adt.Vertex{BaseValue: b}
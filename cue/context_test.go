@@ -16,11 +16,15 @@ package cue_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/internal/astinternal"
 	"cuelang.org/go/internal/cuetest"
 	"cuelang.org/go/internal/cuetxtar"
@@ -141,6 +145,61 @@ bar: [
 	}
 }
 
+func TestRebuildInstance(t *testing.T) {
+	in := `
+-- cue.mod/module.cue --
+module: "mod.test/foo"
+language: version: "v0.9.0"
+-- a.cue --
+package foo
+
+import "strings"
+
+a: strings.ToUpper(b)
+-- b.cue --
+package foo
+
+b: "x"
+`
+	a := txtar.Parse([]byte(in))
+	inst := cuetxtar.Load(a, t.TempDir())[0]
+	qt.Assert(t, qt.IsNil(inst.Err))
+
+	ctx := cuecontext.New()
+	v := ctx.BuildInstance(inst)
+	qt.Assert(t, qt.IsNil(v.Err()))
+
+	file := func(name, src string) *ast.File {
+		f, err := parser.ParseFile(name, src)
+		qt.Assert(t, qt.IsNil(err))
+		return f
+	}
+	lookup := func(v cue.Value, path string) string {
+		s, err := v.LookupPath(cue.ParsePath(path)).String()
+		qt.Assert(t, qt.IsNil(err))
+		return s
+	}
+	qt.Check(t, qt.Equals(lookup(v, "a"), "X"))
+
+	// Replace a file without changing the top-level fields.
+	inst, v = ctx.RebuildInstance(inst, []*ast.File{file(inst.Files[1].Filename, "package foo\nb: \"y\"")})
+	qt.Assert(t, qt.IsNil(v.Err()))
+	qt.Check(t, qt.Equals(lookup(v, "a"), "Y"))
+
+	// Add a file with a new top-level field.
+	inst, v = ctx.RebuildInstance(inst, []*ast.File{file("c.cue", "package foo\nc: a + b")})
+	qt.Assert(t, qt.IsNil(v.Err()))
+	qt.Check(t, qt.Equals(lookup(v, "c"), "Yy"))
+
+	// Errors are reported, but do not persist after a fix.
+	inst, v = ctx.RebuildInstance(inst, []*ast.File{file("c.cue", "package foo\nc: a + d")})
+	qt.Check(t, qt.ErrorMatches(v.Err(), `c: reference "d" not found`))
+	inst, v = ctx.RebuildInstance(inst, []*ast.File{file("c.cue", "package foo\nc: 1")})
+	qt.Assert(t, qt.IsNil(v.Err()))
+	qt.Check(t, qt.Equals(lookup(v, "a"), "Y"))
+	qt.Check(t, qt.Equals(len(inst.Files), 3))
+}
+
 func TestEncodeType(t *testing.T) {
 	type testCase struct {
 		name    string
@@ -198,3 +257,22 @@ func TestContextCheck(t *testing.T) {
 		c.CompileString("1")
 	}, `.*use cuecontext\.New.*`))
 }
+
+func TestSortErrors(t *testing.T) {
+	const src = `
+b: 1 & 2
+a: "x" & "y"
+c: [1 & 3]
+`
+	ctx := cuecontext.New(cuecontext.CUE_DEBUG("sorterrors"))
+	v := ctx.CompileString(src, cue.Filename("x.cue"))
+	err := v.Validate()
+	qt.Assert(t, qt.IsNotNil(err))
+
+	var got []string
+	for _, e := range errors.Errors(err) {
+		got = append(got, strings.Join(e.Path(), "."))
+	}
+	// Conflict errors have no primary position, so they are sorted by path.
+	qt.Assert(t, qt.DeepEquals(got, []string{"a", "b", "c.0"}))
+}
@@ -18,7 +18,8 @@
 // Values are created with a [cuelang.org/go/cue/cuecontext.Context].
 // Only values created from the same Context can be involved in the same operation.
 // Values created from the same Context are not safe for concurrent use,
-// which we intend to change in the future.
+// which we intend to change in the future, unless they have been fully
+// evaluated with [Value.Freeze].
 //
 // [Context] defines the set of active packages, the translations of field
 // names to unique codes, as well as the set of builtins.
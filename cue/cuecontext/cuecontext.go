@@ -22,8 +22,8 @@ import (
 	"cuelang.org/go/internal/core/runtime"
 	"cuelang.org/go/internal/cuedebug"
 	"cuelang.org/go/internal/envflag"
-
-	_ "cuelang.org/go/pkg"
+	internalpkg "cuelang.org/go/internal/pkg"
+	"cuelang.org/go/pkg"
 )
 
 // Option controls a build context.
@@ -58,6 +58,29 @@ func Interpreter(i ExternInterpreter) Option {
 	}}
 }
 
+// BuiltinPackage makes p available as a builtin package that CUE code
+// compiled by the context can import using importPath. Unlike packages
+// registered with [pkg.Register], the package is not available to other
+// contexts. It panics if importPath is not valid, if a builtin package is
+// already registered for it, or if p is not a valid package.
+//
+// Note that the package is not known to [cuelang.org/go/cue/load], so
+// instances that import it must be built from files compiled with the
+// context, for instance with [cue.Context.BuildFile]. Use [pkg.Register]
+// for packages that must be importable from loaded instances.
+func BuiltinPackage(importPath string, p *pkg.Package) Option {
+	ip, err := internalpkg.FromPublic(importPath, p)
+	if err != nil {
+		panic(fmt.Errorf("cuecontext.BuiltinPackage: %v", err))
+	}
+	return Option{func(r *runtime.Runtime) {
+		if r.HasBuiltin(importPath) {
+			panic(fmt.Errorf("cuecontext.BuiltinPackage: builtin package %q already registered", importPath))
+		}
+		r.AddBuiltin(importPath, internalpkg.PackageFunc(importPath, ip))
+	}}
+}
+
 type EvalVersion = internal.EvaluatorVersion
 
 const (
@@ -87,6 +110,38 @@ func EvaluatorVersion(v EvalVersion) Option {
 	}}
 }
 
+// MaxDepth sets the maximum depth of evaluation. Evaluating a value
+// that nests deeper, such as a deeply nested struct or a long chain of
+// references, results in an error rather than exhausting the stack.
+// A value of zero or less removes the limit. The default is
+// [DefaultMaxDepth].
+func MaxDepth(n int) Option {
+	return Option{func(r *runtime.Runtime) {
+		r.SetMaxDepth(n)
+	}}
+}
+
+// DefaultMaxDepth is the maximum depth of evaluation used when the
+// [MaxDepth] option is not given.
+const DefaultMaxDepth = runtime.DefaultMaxDepth
+
+// MemoryLimit limits the memory that evaluation may allocate over the
+// lifetime of the context to about n bytes. Once the limit is exceeded,
+// evaluating further values results in an error rather than allocating
+// more memory, which allows services to evaluate untrusted CUE without
+// running out of memory. A value of zero or less removes the limit,
+// which is the default.
+//
+// The limit applies to an estimate of the size of the data structures
+// allocated by the evaluator, not to the total memory used by the
+// process. Memory is never returned to the budget, so a context with a
+// memory limit is best used for a bounded amount of work.
+func MemoryLimit(n int64) Option {
+	return Option{func(r *runtime.Runtime) {
+		r.SetMemoryLimit(n)
+	}}
+}
+
 // CUE_DEBUG takes a string with the same contents as CUE_DEBUG and configures
 // the context with the relevant debug options. It panics for unknown or
 // malformed options.
@@ -100,3 +155,25 @@ func CUE_DEBUG(s string) Option {
 		r.SetDebugOptions(&c)
 	}}
 }
+
+// Labels registers the given field labels in advance, for instance those
+// of a schema against which many values will be compiled. Labels are
+// shared by all contexts and are never released, so registering the labels
+// that are expected to occur keeps the label table from growing when
+// compiling repeated inputs in a long-running process.
+func Labels(labels ...string) Option {
+	return Option{func(r *runtime.Runtime) {
+		runtime.RegisterLabels(labels...)
+	}}
+}
+
+// LabelStats describes the size of the label table shared by all contexts.
+type LabelStats = runtime.LabelStats
+
+// ReadLabelStats reports the current size of the label table shared by all
+// contexts. As the table never shrinks, steady growth across repeated
+// compilations of inputs with the same shape may indicate that arbitrary
+// data is being used as field labels.
+func ReadLabelStats() LabelStats {
+	return runtime.ReadLabelStats()
+}
@@ -16,6 +16,7 @@ package cuecontext
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"cuelang.org/go/cue"
@@ -24,6 +25,7 @@ import (
 	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/runtime"
 	"cuelang.org/go/internal/cueexperiment"
+	"cuelang.org/go/pkg"
 )
 
 func TestAPI(t *testing.T) {
@@ -100,3 +102,178 @@ func TestEvalVersion(t *testing.T) {
 
 	test(New(), internal.DefaultVersion)
 }
+
+func TestLabels(t *testing.T) {
+	const label = "label_registered_by_TestLabels"
+	before := ReadLabelStats()
+	ctx := New(Labels(label, label))
+	after := ReadLabelStats()
+	if got := after.Labels - before.Labels; got != 1 {
+		t.Errorf("got %d new labels; want 1", got)
+	}
+	if got := after.Bytes - before.Bytes; got != len(label) {
+		t.Errorf("got %d new bytes; want %d", got, len(label))
+	}
+
+	// Compiling values that only use registered labels does not grow the
+	// table.
+	for i := 0; i < 3; i++ {
+		v := ctx.CompileString(fmt.Sprintf("%s: %d", label, i))
+		if err := v.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := ReadLabelStats(); got != after {
+		t.Errorf("label table grew from %+v to %+v", after, got)
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	const depth = 20
+	src := "x: " + strings.Repeat("{a: ", depth) + "1" + strings.Repeat("}", depth)
+
+	for name, version := range map[string]EvalVersion{"v2": EvalV2, "v3": EvalV3} {
+		t.Run(name, func(t *testing.T) {
+			v := New(EvaluatorVersion(version)).CompileString(src)
+			if err := v.Validate(); err != nil {
+				t.Fatalf("unexpected error with default maximum depth: %v", err)
+			}
+
+			v = New(EvaluatorVersion(version), MaxDepth(depth/2)).CompileString(src)
+			err := v.Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			const want = "evaluation depth limit of 10 exceeded"
+			if got := err.Error(); !strings.Contains(got, want) {
+				t.Errorf("got error %q; want it to contain %q", got, want)
+			}
+
+			v = New(EvaluatorVersion(version), MaxDepth(0)).CompileString(src)
+			if err := v.Validate(); err != nil {
+				t.Errorf("unexpected error without maximum depth: %v", err)
+			}
+		})
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	const src = `x: [for i in list.Range(0, 1000, 1) {a: i, b: "\(i)"}]`
+
+	for name, version := range map[string]EvalVersion{"v2": EvalV2, "v3": EvalV3} {
+		t.Run(name, func(t *testing.T) {
+			ctx := New(EvaluatorVersion(version))
+			v := ctx.CompileString("import \"list\"\n" + src)
+			if err := v.Validate(); err != nil {
+				t.Fatalf("unexpected error without memory limit: %v", err)
+			}
+			if got := (*runtime.Runtime)(ctx).MemoryUsed(); got != 0 {
+				t.Errorf("got %d bytes used without memory limit; want 0", got)
+			}
+
+			ctx = New(EvaluatorVersion(version), MemoryLimit(1<<30))
+			v = ctx.CompileString("import \"list\"\n" + src)
+			if err := v.Validate(); err != nil {
+				t.Fatalf("unexpected error with large memory limit: %v", err)
+			}
+			if got := (*runtime.Runtime)(ctx).MemoryUsed(); got <= 0 {
+				t.Errorf("got %d bytes used; want more than 0", got)
+			}
+
+			ctx = New(EvaluatorVersion(version), MemoryLimit(10_000))
+			v = ctx.CompileString("import \"list\"\n" + src)
+			err := v.Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			const want = "memory limit of 10000 bytes exceeded"
+			if got := err.Error(); !strings.Contains(got, want) {
+				t.Errorf("got error %q; want it to contain %q", got, want)
+			}
+		})
+	}
+}
+
+func TestBuiltinPackage(t *testing.T) {
+	const src = `
+import "acme.dev/funcs"
+
+x: funcs.Double(21)
+`
+	p := &pkg.Package{Funcs: map[string]any{
+		"Double": func(x int) int { return 2 * x },
+	}}
+	ctx := New(BuiltinPackage("acme.dev/funcs", p))
+	v := ctx.CompileString(src)
+	if err := v.Err(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := v.LookupPath(cue.ParsePath("x")).Int64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 42 {
+		t.Errorf("got %d; want 42", got)
+	}
+
+	// The package is not available to other contexts.
+	v = New().CompileString(src)
+	if err := v.Err(); err == nil {
+		t.Error("expected error in context without builtin package")
+	}
+
+	for _, path := range []string{"strings", "acme.dev/funcs"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic registering %q twice", path)
+				}
+			}()
+			New(BuiltinPackage("acme.dev/funcs", p), BuiltinPackage(path, p))
+		}()
+	}
+}
+
+// TestDefaultMaxDepth checks that inputs that nest beyond the default
+// maximum depth result in an error rather than exhausting the stack. The
+// inputs differ per evaluator, as the time it takes to evaluate nested
+// structs and chains of references grows superlinearly with their depth
+// for evalv3 and evalv2, respectively.
+func TestDefaultMaxDepth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("evaluating deeply nested values is slow")
+	}
+	const depth = DefaultMaxDepth + 1
+
+	var refs strings.Builder
+	for i := range depth {
+		fmt.Fprintf(&refs, "a%d: a%d\n", i, i+1)
+	}
+	fmt.Fprintf(&refs, "a%d: 1\n", depth)
+
+	testCases := []struct {
+		name    string
+		version EvalVersion
+		src     string
+	}{{
+		name:    "v2",
+		version: EvalV2,
+		src:     "x: " + strings.Repeat("{a: ", depth) + "1" + strings.Repeat("}", depth),
+	}, {
+		name:    "v3",
+		version: EvalV3,
+		src:     refs.String(),
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := New(EvaluatorVersion(tc.version)).CompileString(tc.src).Validate()
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			want := fmt.Sprintf("evaluation depth limit of %d exceeded", DefaultMaxDepth)
+			if got := err.Error(); !strings.Contains(got, want) {
+				t.Errorf("got error %q; want it to contain %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/internal/core/dep"
+)
+
+// A Dependency is a reference from one value to another.
+type Dependency struct {
+	// Source is the value in the expressions of which the reference occurs.
+	Source Value
+
+	// Target is the referenced value. Its path is relative to the root of
+	// the package in which it is defined.
+	Target Value
+
+	// Reference is the syntax of the reference, if available.
+	Reference ast.Expr
+
+	// ImportPath is the import path of the package that defines Target, or
+	// "" if Target is defined in the same package as Source.
+	ImportPath string
+}
+
+// Deps reports the dependencies of v, which are the values referred to by the
+// expressions defining v. If recursive is true, the dependencies of the
+// fields of v, including definitions, hidden and optional fields, are
+// reported as well, recursively. This allows building the reference graph of
+// a configuration, for instance to determine which values are affected by a
+// change to another.
+//
+// References to let clauses are resolved to the values they refer to.
+// References to values without a path from the root, such as variables of
+// comprehensions, are not reported.
+func (v Value) Deps(recursive bool) []Dependency {
+	if v.v == nil {
+		return nil
+	}
+	var deps []Dependency
+	v.appendDeps(&deps, recursive)
+	return deps
+}
+
+func (v Value) appendDeps(deps *[]Dependency, recursive bool) {
+	ctx := v.ctx()
+	dep.Visit(nil, ctx, v.v, func(d dep.Dependency) error {
+		if d.Node.IsDetached() {
+			// For instance, a variable of a comprehension.
+			return nil
+		}
+		x := Dependency{
+			Source: v,
+			Target: makeValue(v.idx, d.Node, nil),
+		}
+		if d.Reference != nil {
+			x.Reference, _ = d.Reference.Source().(ast.Expr)
+		}
+		if imp := d.Import(); imp != nil {
+			x.ImportPath = imp.ImportPath.StringValue(v.idx)
+		}
+		*deps = append(*deps, x)
+		return nil
+	})
+	if !recursive {
+		return
+	}
+	for _, arc := range v.v.Arcs {
+		if arc.Label.IsLet() || !arc.IsDefined(ctx) {
+			continue
+		}
+		makeChildValue(v, arc).appendDeps(deps, recursive)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/internal/cuetdtest"
+	"cuelang.org/go/internal/cuetest"
+	"cuelang.org/go/internal/tdtest"
+)
+
+func TestDeps(t *testing.T) {
+	type testCase struct {
+		path      string
+		recursive bool
+		want      string
+	}
+	const config = `
+	import "strings"
+
+	db: {host: "localhost", port: 5432}
+	let h = db.host
+	app: {
+		url:  "postgres://\(db.host):\(db.port)"
+		name: strings.ToUpper(h)
+		#d: p: db.port
+		l: [for k, _ in db {k}]
+	}
+	`
+	testCases := []testCase{{
+		path: "app.url",
+		want: `
+app.url -> db.host (db.host)
+app.url -> db.port (db.port)`,
+	}, {
+		path: "app.name",
+		want: `
+app.name -> ToUpper (strings.ToUpper) in "strings"
+app.name -> db.host (h)`,
+	}, {
+		path: "app",
+		want: "",
+	}, {
+		path:      "app",
+		recursive: true,
+		want: `
+app.url -> db.host (db.host)
+app.url -> db.port (db.port)
+app.name -> ToUpper (strings.ToUpper) in "strings"
+app.name -> db.host (h)
+app.#d.p -> db.port (db.port)
+app.l -> db (db)`,
+	}, {
+		path:      "db",
+		recursive: true,
+		want:      "",
+	}}
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		tdtest.Run(t, testCases, func(t *cuetest.T, tc *testCase) {
+			v := getValue(m, config).LookupPath(cue.ParsePath(tc.path))
+			var b strings.Builder
+			for _, d := range v.Deps(tc.recursive) {
+				ref, err := format.Node(d.Reference)
+				if err != nil {
+					t.Fatal(err)
+				}
+				fmt.Fprintf(&b, "\n%v -> %v (%s)", d.Source.Path(), d.Target.Path(), ref)
+				if d.ImportPath != "" {
+					fmt.Fprintf(&b, " in %q", d.ImportPath)
+				}
+			}
+			t.Equal(b.String(), tc.want)
+		})
+	})
+}
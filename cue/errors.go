@@ -18,6 +18,7 @@ import (
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/runtime"
 )
 
 func (v Value) toErr(b *adt.Bottom) (err errors.Error) {
@@ -28,11 +29,21 @@ func (v Value) toErr(b *adt.Bottom) (err errors.Error) {
 			bb.Err = e
 			err = errors.Append(err, &valueError{v: v, err: &bb})
 		}
+		if v.idx != nil && sortErrors(v.idx) {
+			err = errors.Sorted(err)
+		}
 		return err
 	}
 	return &valueError{v: v, err: b}
 }
 
+// sortErrors reports whether the CUE_DEBUG=sorterrors flag is enabled
+// for the given runtime.
+func sortErrors(r *runtime.Runtime) bool {
+	_, flags := r.Settings()
+	return flags.SortErrors
+}
+
 var _ errors.Error = &valueError{}
 
 // A valueError is returned as a result of evaluating a value.
@@ -89,6 +100,36 @@ func (e *valueError) Path() (a []string) {
 	return pathToStrings(e.v.Path())
 }
 
+// Code reports the code of the underlying error, if it has one, or
+// otherwise the code corresponding to the kind of the bottom value.
+func (e *valueError) Code() errors.Code {
+	if code := errors.CodeOf(e.err.Err); code != "" {
+		return code
+	}
+	switch e.err.Code {
+	case adt.IncompleteError:
+		return errors.Incomplete
+	case adt.CycleError:
+		return errors.Cycle
+	case adt.StructuralCycleError:
+		return errors.StructuralCycle
+	case adt.UserError:
+		return errors.User
+	default:
+		return errors.Eval
+	}
+}
+
+// Suggestions reports possible corrections reported by the underlying
+// error.
+func (e *valueError) Suggestions() []string {
+	return errors.Suggestions(e.err.Err)
+}
+
+func (e *valueError) MarshalJSON() ([]byte, error) {
+	return errors.MarshalJSON(e)
+}
+
 var errNotExists = &adt.Bottom{
 	Code:      adt.IncompleteError,
 	NotExists: true,
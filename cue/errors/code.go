@@ -0,0 +1,158 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// A Code classifies an error. Unlike error messages, which may change
+// between releases, codes are stable and can be used by programs to
+// distinguish between kinds of errors.
+//
+// An error reports its code by implementing a method
+//
+//	Code() Code
+//
+// Errors that do not implement this method have no code.
+type Code string
+
+const (
+	// Conflict indicates that values could not be unified.
+	Conflict Code = "E_CONFLICT"
+
+	// Incomplete indicates that a value is not concrete or not fully
+	// specified, such as a reference to a field that does not exist yet.
+	// Such errors may be resolved by making a configuration more specific.
+	Incomplete Code = "E_INCOMPLETE"
+
+	// Cycle indicates a reference cycle that could not be resolved.
+	Cycle Code = "E_CYCLE"
+
+	// StructuralCycle indicates that a value contains itself, resulting in
+	// an infinitely nested value.
+	StructuralCycle Code = "E_STRUCTURAL_CYCLE"
+
+	// NotAllowed indicates a field that is not allowed by a closed struct,
+	// such as a field of a definition that the definition does not
+	// declare.
+	NotAllowed Code = "E_NOT_ALLOWED"
+
+	// OutOfBound indicates a value that does not satisfy a bound or
+	// regular expression constraint, such as 0 for >0.
+	OutOfBound Code = "E_OUT_OF_BOUND"
+
+	// User indicates an error explicitly raised by a configuration, such
+	// as an explicit bottom value (_|_) or a failed call to error.
+	User Code = "E_USER"
+
+	// Eval indicates any other error during evaluation.
+	Eval Code = "E_EVAL"
+)
+
+type coder interface {
+	Code() Code
+}
+
+// CodeOf reports the code of the first error associated with err that has
+// one, or the empty Code if there is none.
+func CodeOf(err error) Code {
+	for _, e := range Errors(err) {
+		var c coder
+		if As(e, &c) {
+			if code := c.Code(); code != "" {
+				return code
+			}
+		}
+	}
+	return ""
+}
+
+// Suggestions reports possible corrections for the first error associated
+// with err that has any, such as the names of existing fields for a
+// misspelled field name.
+//
+// An error reports suggestions by implementing a method
+//
+//	Suggestions() []string
+func Suggestions(err error) []string {
+	for _, e := range Errors(err) {
+		var s suggester
+		if As(e, &s) {
+			if a := s.Suggestions(); len(a) > 0 {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+type suggester interface {
+	Suggestions() []string
+}
+
+type jsonError struct {
+	Code        Code           `json:"code,omitempty"`
+	Message     string         `json:"message"`
+	Path        []string       `json:"path,omitempty"`
+	Positions   []jsonPosition `json:"positions,omitempty"`
+	Suggestions []string       `json:"suggestions,omitempty"`
+}
+
+type jsonPosition struct {
+	Filename string `json:"filename,omitempty"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+}
+
+// MarshalJSON encodes the errors associated with err as a JSON array of
+// objects with the following fields:
+//
+//	code         the Code of the error, if any
+//	message      the error message, without path information
+//	path         the path at which the error occurred, as a list of labels
+//	positions    the positions reported by Positions, each an object with
+//	             fields filename, line, and column
+//	suggestions  the possible corrections reported by Suggestions, if any
+//
+// If err is nil, the result is null.
+func MarshalJSON(err error) ([]byte, error) {
+	var a []jsonError
+	for _, e := range Errors(err) {
+		var msg strings.Builder
+		writeMsg(&msg, e)
+		x := jsonError{
+			Code:        CodeOf(e),
+			Message:     msg.String(),
+			Path:        e.Path(),
+			Suggestions: Suggestions(e),
+		}
+		for _, p := range Positions(e) {
+			pos := p.Position()
+			x.Positions = append(x.Positions, jsonPosition{
+				Filename: pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+			})
+		}
+		a = append(a, x)
+	}
+	return json.Marshal(a)
+}
+
+func (p list) MarshalJSON() ([]byte, error)      { return MarshalJSON(p) }
+func (e *posError) MarshalJSON() ([]byte, error) { return MarshalJSON(e) }
+func (e *wrapped) MarshalJSON() ([]byte, error)  { return MarshalJSON(e) }
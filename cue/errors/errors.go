@@ -399,20 +399,43 @@ func (p list) sanitize() list {
 	return a
 }
 
+// Sorted returns err with its individual errors sorted by position, then
+// path, then message, then input positions. Unlike [Sanitize], no errors
+// are removed, and the order does not depend on the order in which the
+// errors were added. This makes it suitable for producing stable output,
+// for example in tests.
+func Sorted(err error) Error {
+	if err == nil {
+		return nil
+	}
+	a := slices.Clone(list(Errors(err)))
+	if len(a) == 1 {
+		return a[0]
+	}
+	slices.SortStableFunc(a, func(a, b Error) int {
+		if c := compareErrors(a, b); c != 0 {
+			return c
+		}
+		return slices.CompareFunc(a.InputPositions(), b.InputPositions(), token.Pos.Compare)
+	})
+	return a
+}
+
 // Sort sorts an List. *posError entries are sorted by position,
 // other errors are sorted by error message, and before any *posError
 // entry.
 func (p list) Sort() {
-	slices.SortFunc(p, func(a, b Error) int {
-		if c := a.Position().Compare(b.Position()); c != 0 {
-			return c
-		}
-		if c := comparePath(a.Path(), b.Path()); c != 0 {
-			return c
-		}
-		return cmp.Compare(a.Error(), b.Error())
+	slices.SortFunc(p, compareErrors)
+}
 
-	})
+func compareErrors(a, b Error) int {
+	if c := a.Position().Compare(b.Position()); c != 0 {
+		return c
+	}
+	if c := comparePath(a.Path(), b.Path()); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Error(), b.Error())
 }
 
 // RemoveMultiples sorts an List and removes all but the first error per line.
@@ -535,7 +558,11 @@ func writeErr(w io.Writer, err Error) {
 		_, _ = io.WriteString(w, path)
 		_, _ = io.WriteString(w, ": ")
 	}
+	writeMsg(w, err)
+}
 
+// writeMsg writes the message of err and the errors it wraps.
+func writeMsg(w io.Writer, err Error) {
 	for {
 		u := errors.Unwrap(err)
 
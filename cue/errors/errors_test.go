@@ -17,6 +17,7 @@ package errors
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"cuelang.org/go/cue/token"
@@ -78,6 +79,41 @@ func TestErrorList_Sort(t *testing.T) {
 	}
 }
 
+func TestSorted(t *testing.T) {
+	f := token.NewFile("a.cue", -1, 100)
+	f.SetLinesForContent(make([]byte, 100))
+	posA := f.Pos(10, token.NoRelPos)
+	posB := f.Pos(20, token.NoRelPos)
+
+	e1 := Newf(posB, "b")
+	e2 := Newf(posA, "z")
+	e3 := Newf(posA, "a")
+	e4 := Newf(token.NoPos, "no position")
+
+	want := "no position\na\nz\nb\n"
+	for _, err := range []Error{
+		Append(Append(Append(e1, e2), e3), e4),
+		Append(Append(Append(e4, e3), e2), e1),
+		Append(Append(Append(e2, e4), e1), e3),
+	} {
+		var b strings.Builder
+		for _, e := range Errors(Sorted(err)) {
+			b.WriteString(e.Error())
+			b.WriteString("\n")
+		}
+		if got := b.String(); got != want {
+			t.Errorf("got:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	if got := Sorted(e1); got != e1 {
+		t.Errorf("Sorted of single error: got %v; want %v", got, e1)
+	}
+	if got := Sorted(nil); got != nil {
+		t.Errorf("Sorted(nil) = %v; want nil", got)
+	}
+}
+
 func TestErrorList_RemoveMultiples(t *testing.T) {
 	tests := []struct {
 		name string
@@ -163,3 +199,69 @@ func TestPrintError(t *testing.T) {
 		})
 	}
 }
+
+type codedError struct {
+	err  Error
+	code Code
+}
+
+func (e *codedError) Position() token.Pos          { return e.err.Position() }
+func (e *codedError) InputPositions() []token.Pos  { return e.err.InputPositions() }
+func (e *codedError) Error() string                { return e.err.Error() }
+func (e *codedError) Path() []string               { return e.err.Path() }
+func (e *codedError) Msg() (string, []interface{}) { return e.err.Msg() }
+
+func (e *codedError) Code() Code { return e.code }
+
+func TestMarshalJSON(t *testing.T) {
+	f := token.NewFile("a.cue", -1, 100)
+	f.SetLinesForContent([]byte("a: 1\nb: 2\n"))
+
+	conflict := &codedError{
+		err:  Newf(f.Pos(8, 0), "conflicting values"),
+		code: Conflict,
+	}
+	tests := []struct {
+		name string
+		err  error
+		code Code
+		want string
+	}{{
+		name: "Nil",
+		err:  nil,
+		want: `null`,
+	}, {
+		name: "Uncoded",
+		err:  Newf(token.NoPos, "hello %s", "world"),
+		want: `[{"message":"hello world"}]`,
+	}, {
+		name: "Coded",
+		err:  conflict,
+		code: Conflict,
+		want: `[{"code":"E_CONFLICT","message":"conflicting values","positions":[{"filename":"a.cue","line":2,"column":4}]}]`,
+	}, {
+		name: "Wrapped",
+		err:  Wrapf(conflict, token.NoPos, "outer"),
+		code: Conflict,
+		want: `[{"code":"E_CONFLICT","message":"outer: conflicting values","positions":[{"filename":"a.cue","line":2,"column":4}]}]`,
+	}, {
+		name: "List",
+		err:  Append(Newf(token.NoPos, "first"), conflict),
+		code: Conflict,
+		want: `[{"message":"first"},{"code":"E_CONFLICT","message":"conflicting values","positions":[{"filename":"a.cue","line":2,"column":4}]}]`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := CodeOf(tt.err); code != tt.code {
+				t.Errorf("CodeOf: got %q; want %q", code, tt.code)
+			}
+			b, err := MarshalJSON(tt.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("MarshalJSON:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
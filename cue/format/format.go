@@ -62,6 +62,34 @@ func IndentPrefix(n int) Option {
 	return func(c *config) { c.Indent = n }
 }
 
+// IndentSpaces specifies that each level of indentation consists of n
+// spaces instead of a tab. It is equivalent to UseSpaces(n) combined with
+// TabIndent(false).
+func IndentSpaces(n int) Option {
+	return func(c *config) {
+		c.UseSpaces = true
+		c.TabIndent = false
+		c.Tabwidth = n
+	}
+}
+
+// AlignValues specifies whether the values of consecutive fields and
+// trailing comments on consecutive lines are vertically aligned. It is
+// enabled by default. If disabled, a single space is used instead.
+func AlignValues(align bool) Option {
+	return func(c *config) { c.noAlign = !align }
+}
+
+// MaxWidth specifies the maximum desired width of a line, where a tab
+// counts as the tab width set by UseSpaces. A list or the arguments of a
+// call that would otherwise be written on a single line that exceeds this
+// width is wrapped by writing each element on its own line. Other
+// expressions are not wrapped, so lines may still exceed the maximum
+// width. A width of 0, the default, disables wrapping.
+func MaxWidth(n int) Option {
+	return func(c *config) { c.maxWidth = n }
+}
+
 // TODO: make public
 // sortImportsOption causes import declarations to be sorted.
 func sortImportsOption() Option {
@@ -123,6 +151,8 @@ type config struct {
 
 	simplify    bool
 	sortImports bool
+	noAlign     bool
+	maxWidth    int // default: 0 (no wrapping)
 }
 
 func newConfig(opt []Option) *config {
@@ -170,11 +200,24 @@ func (cfg *config) fprint(node interface{}) (out []byte, err error) {
 
 	b := buf.Bytes()
 	if !cfg.TabIndent {
-		b = bytes.ReplaceAll(b, []byte{'\t'}, bytes.Repeat([]byte{' '}, cfg.Tabwidth))
+		b = indentWithSpaces(b, cfg.Tabwidth)
 	}
 	return b, nil
 }
 
+// indentWithSpaces replaces each tab used for indenting the lines of b with
+// tabwidth spaces. Other tabs, such as those within string literals, are
+// left as is.
+func indentWithSpaces(b []byte, tabwidth int) []byte {
+	out := make([]byte, 0, len(b))
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		n := len(line) - len(bytes.TrimLeft(line, "\t"))
+		out = append(out, bytes.Repeat([]byte{' '}, n*tabwidth)...)
+		out = append(out, line[n:]...)
+	}
+	return out
+}
+
 // A formatter walks an [ast.Node], interspersed with comments and spacing
 // directives, in the order that they would occur in printed form.
 type formatter struct {
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -48,11 +49,22 @@ func TestFiles(t *testing.T) {
 
 		opts := []Option{TabIndent(true)}
 		for _, word := range strings.Fields(string(ar.Comment)) {
+			word, arg, _ := strings.Cut(word, "=")
 			switch word {
 			case "simplify":
 				opts = append(opts, Simplify())
 			case "sort-imports":
 				opts = append(opts, sortImportsOption())
+			case "no-align":
+				opts = append(opts, AlignValues(false))
+			case "indent-spaces":
+				n, err := strconv.Atoi(arg)
+				qt.Assert(t, qt.IsNil(err))
+				opts = append(opts, IndentSpaces(n))
+			case "max-width":
+				n, err := strconv.Atoi(arg)
+				qt.Assert(t, qt.IsNil(err))
+				opts = append(opts, MaxWidth(n))
 			}
 		}
 
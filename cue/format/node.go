@@ -15,8 +15,10 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/literal"
@@ -190,10 +192,15 @@ func (f *formatter) walkClauseList(list []ast.Clause, ws whiteSpace) {
 	f.after(nil)
 }
 
-func (f *formatter) walkListElems(list []ast.Expr) {
+// walkListElems prints the elements of a list. If wrap is set, each element
+// is printed on a new line.
+func (f *formatter) walkListElems(list []ast.Expr, wrap bool) {
 	f.before(nil)
 	for _, x := range list {
 		f.before(x)
+		if wrap {
+			f.print(newline, nooverride)
+		}
 
 		// This is a hack to ensure that comments are printed correctly in lists.
 		// A comment must be printed after each element in a list, but we can't
@@ -243,10 +250,13 @@ func (f *formatter) walkListElems(list []ast.Expr) {
 	f.after(nil)
 }
 
-func (f *formatter) walkArgsList(list []ast.Expr, depth int) {
+func (f *formatter) walkArgsList(list []ast.Expr, depth int, wrap bool) {
 	f.before(nil)
 	for _, x := range list {
 		f.before(x)
+		if wrap {
+			f.print(newline, nooverride)
+		}
 		f.exprRaw(x, token.LowestPrec, depth)
 		f.print(comma, blank)
 		f.after(x)
@@ -655,7 +665,15 @@ func (f *formatter) exprRaw(expr ast.Expr, prec1, depth int) {
 		}
 		wasIndented := f.possibleSelectorExpr(x.Fun, token.HighestPrec, depth)
 		f.print(x.Lparen, token.LPAREN)
-		f.walkArgsList(x.Args, depth)
+		wrap := f.exceedsWidth(&ast.ListLit{Elts: x.Args})
+		if wrap {
+			f.print(indent)
+		}
+		f.walkArgsList(x.Args, depth, wrap)
+		if wrap {
+			f.matchUnindent()
+			f.print(newline, nooverride)
+		}
 		f.print(trailcomma, noblank, x.Rparen, token.RPAREN)
 		if wasIndented {
 			f.print(unindent)
@@ -710,7 +728,11 @@ func (f *formatter) exprRaw(expr ast.Expr, prec1, depth int) {
 		}
 
 		f.print(x.Lbrack, token.LBRACK, ws)
-		f.walkListElems(x.Elts)
+		wrap := f.exceedsWidth(x)
+		f.walkListElems(x.Elts, wrap)
+		if wrap {
+			f.print(newline, nooverride)
+		}
 		f.print(trailcomma, noblank)
 		f.visitComments(f.current.pos)
 		f.matchUnindent()
@@ -724,6 +746,25 @@ func (f *formatter) exprRaw(expr ast.Expr, prec1, depth int) {
 	}
 }
 
+// exceedsWidth reports whether the list or arguments x, whose opening
+// bracket was just printed, would make the current line exceed the maximum
+// line width if printed on a single line. It is false if x would not be
+// printed on a single line anyway.
+func (f *formatter) exceedsWidth(x ast.Expr) bool {
+	if f.cfg.maxWidth <= 0 {
+		return false
+	}
+	cfg := *f.cfg
+	cfg.maxWidth = 0
+	cfg.Indent = 0
+	b, err := cfg.fprint(x)
+	if err != nil || bytes.ContainsAny(b, "\n\f") {
+		return false
+	}
+	// The opening bracket is already accounted for by the current column.
+	return f.column()+utf8.RuneCount(b)-1 > f.cfg.maxWidth
+}
+
 func (f *formatter) clause(clause ast.Clause) {
 	switch n := clause.(type) {
 	case *ast.ForClause:
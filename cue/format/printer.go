@@ -15,10 +15,12 @@
 package format
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/errors"
@@ -131,7 +133,16 @@ func (p *printer) Print(v interface{}) {
 			// 2) simplified structs are explicitly referenced separately
 			//    in the AST.
 			if p.indent < 6 {
-				data = literal.IndentTabs(data, p.cfg.Indent+p.indent+1)
+				n := p.cfg.Indent + p.indent + 1
+				data = literal.IndentTabs(data, n)
+				if !p.cfg.TabIndent {
+					// Indent with spaces here, as tabs within the
+					// string cannot be distinguished from indentation
+					// once written.
+					data = strings.ReplaceAll(data,
+						"\n"+strings.Repeat("\t", n),
+						"\n"+strings.Repeat(" ", n*p.cfg.Tabwidth))
+				}
 			}
 
 		case token.INT:
@@ -297,6 +308,9 @@ func (p *printer) writeWhitespace(ws whiteSpace) {
 		p.writeByte(' ', 1)
 		p.spaceBefore = true
 	case ws&noblank != 0:
+	case ws&vtab != 0 && p.cfg.noAlign:
+		p.writeByte(' ', 1)
+		p.spaceBefore = true
 	case ws&vtab != 0:
 		p.writeByte('\v', 1)
 		p.spaceBefore = true
@@ -400,6 +414,22 @@ func (p *printer) writeString(s string, isLit bool) {
 	}
 }
 
+// column returns the width of the current output line, counting a tab as
+// the configured tab width.
+func (p *printer) column() int {
+	n := 0
+	for _, c := range p.output[bytes.LastIndexAny(p.output, "\n\f")+1:] {
+		switch {
+		case c == '\t':
+			n += p.cfg.Tabwidth
+		case c == tabwriter.Escape, !utf8.RuneStart(c):
+		default:
+			n++
+		}
+	}
+	return n
+}
+
 func (p *printer) writeByte(ch byte, n int) {
 	for i := 0; i < n; i++ {
 		p.output = append(p.output, ch)
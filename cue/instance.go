@@ -229,6 +229,7 @@ func Merge(inst ...*Instance) *Instance {
 // Deprecated: use [Context.BuildInstance]
 func (inst *hiddenInstance) Build(p *build.Instance) *Instance {
 	p.Complete()
+	p.LoadSyntax()
 
 	idx := inst.index
 	r := inst.index
@@ -44,17 +44,47 @@
 // to use forward slashes. This argument may not be used in conjunction with the
 // file argument.
 //
+// A path element of "**" in the pattern matches zero or more directories,
+// so that files can be embedded from a whole directory tree. Hidden
+// directories and directories of nested modules are skipped.
+//
+// exclude=$pattern
+//
+// The use of the exclude argument tells embed to skip the files matched
+// by the glob argument that also match the given pattern, which has the
+// same syntax as the glob argument. It may be given more than once.
+//
 // type=$type
 //
 // By default, the file type is interpreted based on the file extension. This
 // behavior can be overridden by the type argument. See cue help filetypes for
 // the list of supported types. This field is required if a file extension is
 // unknown, or if a wildcard is used for the file extension in the glob pattern.
+// In addition to those types, embed supports CSV files with type csv or
+// the .csv extension.
+//
+// # Decoding options
+//
+// The following flags change how files of some types are decoded. They
+// apply to each embedded file of a type that supports them and are
+// ignored for the others, so they can be used with a glob pattern that
+// matches files of different types.
+//
+// stream
+//
+// The stream flag decodes all the values in a file that supports streams,
+// such as multi-document YAML or NDJSON, into a list, even if there is only
+// one value.
+//
+// header
+//
+// The header flag uses the first record of a CSV file as the field names
+// of the remaining records, which are decoded as a list of structs. Without
+// it, a CSV file is decoded as a list of lists of strings.
 //
 // # Limitations
 //
 // The embed interpreter currently does not support:
-// - stream values, such as .ndjson or YAML streams.
 // - schema-based decoding, such as needed for textproto
 //
 // # Example
@@ -79,16 +109,31 @@
 //	// include all files in the y directory as a map of file paths to binary
 //	// data. The entries are unified into the same map as above.
 //	files: _ @embed(glob=y/*.*, type=binary)
+//
+//	// include all YAML files in the z directory tree, except for those in
+//	// testdata directories, decoding each document in a file as a list
+//	// element.
+//	tree: _ @embed(glob=z/**/*.yaml, exclude=**/testdata/**, stream)
+//
+//	// include a CSV file with a header as a list of structs.
+//	rows: _ @embed(file=table.csv, header)
+//
+//	// include all Markdown files in the content directory tree, each as
+//	// a struct holding its front matter and body.
+//	pages: _ @embed(glob=content/**/*.md)
 package embed
 
 import (
+	"encoding/csv"
 	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
@@ -105,7 +150,6 @@ import (
 // TODO: obtain a fs.FS from load or something similar
 // TODO: disallow files from submodules
 // TODO: record files in build.Instance
-// TODO: support stream values
 // TODO: support schema-based decoding
 // TODO: maybe: option to include hidden files?
 
@@ -170,6 +214,21 @@ func (c *compiler) Compile(funcName string, scope adt.Value, a *internal.Attr) (
 		return nil, errors.Promote(err, "invalid type argument")
 	}
 
+	var opts options
+	if opts.stream, err = a.Flag(0, "stream"); err != nil {
+		return nil, errors.Promote(err, "invalid attribute")
+	}
+	if opts.header, err = a.Flag(0, "header"); err != nil {
+		return nil, errors.Promote(err, "invalid attribute")
+	}
+
+	var excludes []string
+	for _, kv := range a.Fields {
+		if kv.Key() == "exclude" {
+			excludes = append(excludes, kv.Value())
+		}
+	}
+
 	c.opCtx = adt.NewContext((*runtime.Runtime)(c.runtime), nil)
 
 	pos := a.Pos
@@ -191,15 +250,28 @@ func (c *compiler) Compile(funcName string, scope adt.Value, a *internal.Attr) (
 	case file != "" && glob != "":
 		return nil, errors.Newf(a.Pos, "attribute cannot have both file and glob field")
 
+	case file != "" && len(excludes) > 0:
+		return nil, errors.Newf(a.Pos, "attribute cannot have exclude field without glob field")
+
 	case file != "":
-		return c.processFile(file, typ, scope)
+		return c.processFile(file, typ, scope, opts)
 
 	default: // glob != "":
-		return c.processGlob(glob, typ, scope)
+		return c.processGlob(glob, excludes, typ, scope, opts)
 	}
 }
 
-func (c *compiler) processFile(file, scope string, schema adt.Value) (adt.Expr, errors.Error) {
+// options holds the decoding options given in an embed attribute.
+type options struct {
+	// stream decodes all the values in a stream into a list.
+	stream bool
+
+	// header decodes the records of a CSV file as structs, using the
+	// first record as field names.
+	header bool
+}
+
+func (c *compiler) processFile(file, scope string, schema adt.Value, opts options) (adt.Expr, errors.Error) {
 	file, err := c.clean(file)
 	if err != nil {
 		return nil, err
@@ -210,19 +282,21 @@ func (c *compiler) processFile(file, scope string, schema adt.Value) (adt.Expr,
 		}
 	}
 
-	return c.decodeFile(file, scope, schema)
+	return c.decodeFile(file, scope, schema, opts)
 }
 
-func (c *compiler) processGlob(glob, scope string, schema adt.Value) (adt.Expr, errors.Error) {
+func (c *compiler) processGlob(glob string, excludes []string, scope string, schema adt.Value, opts options) (adt.Expr, errors.Error) {
 	glob, ce := c.clean(glob)
 	if ce != nil {
 		return nil, ce
 	}
-
-	// Validate that the glob pattern is valid per [pkgpath.Match].
-	// Note that we use Unix match semantics because all embed paths are Unix-like.
-	if _, err := pkgpath.Match(glob, "", pkgpath.Unix); err != nil {
-		return nil, errors.Wrapf(err, c.pos, "invalid glob pattern %q", glob)
+	if err := c.checkPattern(glob); err != nil {
+		return nil, err
+	}
+	for _, x := range excludes {
+		if err := c.checkPattern(x); err != nil {
+			return nil, err
+		}
 	}
 
 	// If we do not have a type, ensure the extension of the base is fully
@@ -237,7 +311,13 @@ func (c *compiler) processGlob(glob, scope string, schema adt.Value) (adt.Expr,
 
 	m := &adt.StructLit{}
 
-	matches, err := fs.Glob(c.fs, glob)
+	var matches []string
+	var err error
+	if slices.Contains(strings.Split(glob, "/"), "**") {
+		matches, err = c.globRecursive(glob)
+	} else {
+		matches, err = fs.Glob(c.fs, glob)
+	}
 	if err != nil {
 		return nil, errors.Promote(err, "failed to match glob")
 	}
@@ -248,9 +328,11 @@ func (c *compiler) processGlob(glob, scope string, schema adt.Value) (adt.Expr,
 			// TODO: allow option for including hidden files?
 			continue
 		}
+		if slices.ContainsFunc(excludes, func(x string) bool { return matchPattern(x, f) }) {
+			continue
+		}
 		// TODO: lots of stat calls happening in this MVP so another won't hurt.
-		// We don't support '**' initially, and '*' only matches files, so skip
-		// any directories.
+		// '*' only matches files, so skip any directories.
 		if fi, err := c.fs.Stat(f); err != nil {
 			return nil, errors.Newf(c.pos, "failed to stat %s: %v", f, err)
 		} else if fi.IsDir() {
@@ -264,7 +346,7 @@ func (c *compiler) processGlob(glob, scope string, schema adt.Value) (adt.Expr,
 			dirs[dir] = f
 		}
 
-		expr, err := c.decodeFile(f, scope, schema)
+		expr, err := c.decodeFile(f, scope, schema, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -284,6 +366,92 @@ func (c *compiler) processGlob(glob, scope string, schema adt.Value) (adt.Expr,
 	return m, nil
 }
 
+// checkPattern checks that pattern is a valid glob pattern. Each path
+// element must be valid per [pkgpath.Match] or be "**".
+func (c *compiler) checkPattern(pattern string) errors.Error {
+	for _, elem := range strings.Split(pattern, "/") {
+		if elem == "**" {
+			continue
+		}
+		// Note that we use Unix match semantics because all embed paths are Unix-like.
+		if _, err := pkgpath.Match(elem, "", pkgpath.Unix); err != nil {
+			return errors.Wrapf(err, c.pos, "invalid glob pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// globRecursive returns the names of the files matching pattern, which
+// contains at least one "**" element. Hidden directories and nested
+// modules are not searched.
+func (c *compiler) globRecursive(pattern string) ([]string, error) {
+	// Start walking from the longest prefix of the pattern
+	// without meta characters.
+	root := "."
+	elems := strings.Split(pattern, "/")
+	for i, elem := range elems[:len(elems)-1] {
+		if strings.ContainsAny(elem, "*?[\\") {
+			break
+		}
+		root = path.Join(elems[:i+1]...)
+	}
+
+	var matches []string
+	err := fs.WalkDir(c.fs, root, func(p string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && p == root {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p == root {
+				return nil
+			}
+			if c.isHidden(p) {
+				return fs.SkipDir
+			}
+			if _, err := c.fs.Stat(path.Join(p, "cue.mod")); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if matchPattern(pattern, p) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// matchPattern reports whether name matches the glob pattern, in which
+// a "**" element matches zero or more path elements. The pattern must
+// have been checked with checkPattern.
+func matchPattern(pattern, name string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := range len(name) + 1 {
+			if matchElems(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchElems(pattern[1:], name[1:])
+}
+
 func (c *compiler) clean(s string) (string, errors.Error) {
 	file := path.Clean(s)
 	if file != s {
@@ -304,7 +472,13 @@ func (c *compiler) isHidden(file string) bool {
 	return strings.HasPrefix(file, ".") || strings.Contains(file, "/.")
 }
 
-func (c *compiler) decodeFile(file, scope string, schema adt.Value) (adt.Expr, errors.Error) {
+func (c *compiler) decodeFile(file, scope string, schema adt.Value, opts options) (adt.Expr, errors.Error) {
+	// CSV is not a file type known to the rest of CUE, so it is handled
+	// separately.
+	if scope == "csv" || (scope == "" && path.Ext(file) == ".csv") {
+		return c.decodeCSV(file, opts)
+	}
+
 	// Do not use the most obvious filetypes.Input in order to disable "auto"
 	// mode.
 	f, err := filetypes.ParseFileAndType(file, scope, filetypes.Def)
@@ -312,20 +486,11 @@ func (c *compiler) decodeFile(file, scope string, schema adt.Value) (adt.Expr, e
 		return nil, errors.Promote(err, "invalid file type")
 	}
 
-	// Open and pre-load the file system using fs.FS, instead of relying
-	r, err := c.fs.Open(file)
-	if err != nil {
-		return nil, errors.Newf(c.pos, "open %v: no such file or directory", file)
+	r, cerr := c.open(file)
+	if cerr != nil {
+		return nil, cerr
 	}
 	defer r.Close()
-
-	info, err := r.Stat()
-	if err != nil {
-		return nil, errors.Promote(err, "failed to decode file")
-	}
-	if info.IsDir() {
-		return nil, errors.Newf(c.pos, "cannot embed directories")
-	}
 	f.Source = r
 
 	// TODO: this really should be done at the start of the build process.
@@ -348,17 +513,22 @@ func (c *compiler) decodeFile(file, scope string, schema adt.Value) (adt.Expr, e
 
 	defer d.Close()
 
+	stream := opts.stream && (f.Encoding == build.YAML || f.Encoding == build.JSONL)
+	if stream {
+		return c.decodeStream(d)
+	}
+
 	n := d.File()
 
 	if d.Next(); !d.Done() {
-		return nil, errors.Newf(c.pos, "streaming not implemented: found more than one value in file")
+		return nil, errors.Newf(c.pos, "found more than one value in file: use the stream flag to embed all values as a list")
 	}
 
 	switch f.Encoding {
 	case build.CUE:
 		return nil, errors.Newf(c.pos, "encoding %q not (yet) supported", f.Encoding)
 	case build.JSONL:
-		return nil, errors.Newf(c.pos, "encoding %q not (yet) supported: requires support for streaming", f.Encoding)
+		return nil, errors.Newf(c.pos, "encoding %q requires the stream flag", f.Encoding)
 	case build.BinaryProto, build.TextProto:
 		return nil, errors.Newf(c.pos, "encoding %q not (yet) supported: requires support for schema-guided decoding", f.Encoding)
 	}
@@ -371,3 +541,91 @@ func (c *compiler) decodeFile(file, scope string, schema adt.Value) (adt.Expr, e
 	_, v := value.ToInternal(val)
 	return v, nil
 }
+
+// open opens the given file, which must not be a directory.
+func (c *compiler) open(file string) (fs.File, errors.Error) {
+	// Open and pre-load the file system using fs.FS, instead of relying
+	r, err := c.fs.Open(file)
+	if err != nil {
+		return nil, errors.Newf(c.pos, "open %v: no such file or directory", file)
+	}
+
+	info, err := r.Stat()
+	if err != nil {
+		r.Close()
+		return nil, errors.Promote(err, "failed to decode file")
+	}
+	if info.IsDir() {
+		r.Close()
+		return nil, errors.Newf(c.pos, "cannot embed directories")
+	}
+	return r, nil
+}
+
+// decodeStream decodes all the values read by d into a list.
+func (c *compiler) decodeStream(d *encoding.Decoder) (adt.Expr, errors.Error) {
+	list := &adt.ListLit{}
+	for ; !d.Done(); d.Next() {
+		val := c.runtime.BuildFile(d.File())
+		if err := val.Err(); err != nil {
+			return nil, errors.Promote(err, "failed to build file")
+		}
+		_, v := value.ToInternal(val)
+		list.Elems = append(list.Elems, v)
+	}
+	if err := d.Err(); err != nil {
+		return nil, errors.Promote(err, "failed to decode file")
+	}
+	return list, nil
+}
+
+// decodeCSV decodes the given CSV file into a list of lists of strings
+// or, if opts.header is set, into a list of structs.
+func (c *compiler) decodeCSV(file string, opts options) (adt.Expr, errors.Error) {
+	r, cerr := c.open(file)
+	if cerr != nil {
+		return nil, cerr
+	}
+	defer r.Close()
+
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errors.Promote(err, "failed to decode file")
+	}
+
+	list := &ast.ListLit{}
+	if !opts.header {
+		for _, rec := range records {
+			row := &ast.ListLit{}
+			for _, field := range rec {
+				row.Elts = append(row.Elts, ast.NewString(field))
+			}
+			list.Elts = append(list.Elts, row)
+		}
+	} else if len(records) > 0 {
+		header := records[0]
+		for i, name := range header {
+			if slices.Contains(header[:i], name) {
+				return nil, errors.Newf(c.pos, "duplicate column %q in header of %s", name, file)
+			}
+		}
+		for _, rec := range records[1:] {
+			row := &ast.StructLit{}
+			for i, field := range rec {
+				row.Elts = append(row.Elts, &ast.Field{
+					Label: ast.NewString(header[i]),
+					Value: ast.NewString(field),
+				})
+			}
+			list.Elts = append(list.Elts, row)
+		}
+	}
+
+	val := c.runtime.BuildExpr(list)
+	if err := val.Err(); err != nil {
+		return nil, errors.Promote(err, "failed to build file")
+	}
+
+	_, v := value.ToInternal(val)
+	return v, nil
+}
@@ -53,3 +53,55 @@ func TestIsHidden(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{{
+		pattern: "*.json",
+		name:    "a.json",
+		want:    true,
+	}, {
+		pattern: "*.json",
+		name:    "x/a.json",
+		want:    false,
+	}, {
+		pattern: "**/*.json",
+		name:    "a.json",
+		want:    true,
+	}, {
+		pattern: "**/*.json",
+		name:    "x/y/a.json",
+		want:    true,
+	}, {
+		pattern: "x/**/a.json",
+		name:    "x/a.json",
+		want:    true,
+	}, {
+		pattern: "x/**/a.json",
+		name:    "y/x/a.json",
+		want:    false,
+	}, {
+		pattern: "x/**",
+		name:    "x/y/z",
+		want:    true,
+	}, {
+		pattern: "**/testdata/**",
+		name:    "x/testdata/a.json",
+		want:    true,
+	}, {
+		pattern: "**/testdata/**",
+		name:    "x/testdata.json",
+		want:    false,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.pattern+"/"+tc.name, func(t *testing.T) {
+			got := matchPattern(tc.pattern, tc.name)
+			if got != tc.want {
+				t.Errorf("matchPattern(%q, %q) = %t; want %t", tc.pattern, tc.name, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gofunc allows Go programs to make their own functions
+// available to CUE, as an alternative to adding builtins to the
+// standard library.
+//
+// This package is EXPERIMENTAL and subject to change.
+//
+// # Overview
+//
+// Functions are registered with an [Interpreter] under a name, and
+// the interpreter is then passed to
+// [cuelang.org/go/cue/cuecontext.New]:
+//
+//	interp := gofunc.New()
+//	interp.MustRegister("reverse", func(s string) string {
+//		r := []rune(s)
+//		slices.Reverse(r)
+//		return string(r)
+//	})
+//	ctx := cuecontext.New(cuecontext.Interpreter(interp))
+//
+// CUE files that use registered functions must declare their intent
+// with the file-level @extern(go) attribute. Each function is then
+// imported into a field with the @go attribute:
+//
+//	@extern(go)
+//
+//	package p
+//
+//	reverse: _ @go()
+//	rev:     _ @go(name=reverse)
+//
+//	x: reverse("hello")
+//
+// By default, a field refers to the function registered under the
+// name of the field. The name argument refers to a function registered
+// under a different name.
+//
+// # Function signatures
+//
+// The signature of a function in CUE is derived from its Go type, in
+// the same way as for the functions of packages registered with
+// [cuelang.org/go/pkg.Register]. The parameters may be of the following
+// types:
+//
+//   - bool, string, and []byte
+//   - sized and unsized integers and floats
+//   - *big.Int, *big.Float, and *apd.Decimal
+//   - [cuelang.org/go/cue.Value], which accepts any CUE value
+//   - any other type that a [cuelang.org/go/cue.Value] can be decoded
+//     into, such as structs, maps, and slices
+//
+// The result may be of any type that
+// [cuelang.org/go/cue.Context.Encode] accepts.
+//
+// Integer arguments that do not fit in the corresponding Go type are
+// an error. A function may additionally return an error as its last
+// result, in which case a non-nil error is reported as the result of
+// the call.
+//
+// Functions must be pure: given the same arguments, they must return
+// the same result, as CUE may call them any number of times, in any
+// order, and from any goroutine.
+package gofunc
+
+import (
+	"fmt"
+	"sync"
+
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/runtime"
+	"cuelang.org/go/internal/pkg"
+)
+
+// An Interpreter is a [cuelang.org/go/cue/cuecontext.ExternInterpreter]
+// for Go functions.
+// It is safe for concurrent use.
+type Interpreter struct {
+	// mu guards funcs.
+	mu    sync.Mutex
+	funcs map[string]*pkg.Builtin
+}
+
+// New returns a new Interpreter without any functions.
+func New() *Interpreter {
+	return &Interpreter{
+		funcs: make(map[string]*pkg.Builtin),
+	}
+}
+
+// Kind returns "go", the name of the interpreter in the file-level
+// @extern attribute.
+func (i *Interpreter) Kind() string {
+	return "go"
+}
+
+// Register makes the Go function fn available to CUE under the given
+// name, replacing any function previously registered under that name.
+// It returns an error if fn is not a function or if its signature is
+// not supported.
+func (i *Interpreter) Register(name string, fn any) error {
+	b, err := pkg.FuncBuiltin(name, fn)
+	if err != nil {
+		return fmt.Errorf("cannot register %v", err)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.funcs[name] = b
+	return nil
+}
+
+// MustRegister is like [Interpreter.Register] but panics if there is
+// an error.
+func (i *Interpreter) MustRegister(name string, fn any) {
+	if err := i.Register(name, fn); err != nil {
+		panic(err)
+	}
+}
+
+// NewCompiler returns a compiler that provides the functions registered
+// with i at the time of the call to the given build.Instance.
+func (i *Interpreter) NewCompiler(b *build.Instance, r *runtime.Runtime) (runtime.Compiler, errors.Error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	funcs := make(map[string]*pkg.Builtin, len(i.funcs))
+	for name, f := range i.funcs {
+		funcs[name] = f
+	}
+	return &compiler{funcs: funcs}, nil
+}
+
+// A compiler is a [runtime.Compiler] that provides registered Go
+// functions to the runtime.
+type compiler struct {
+	funcs map[string]*pkg.Builtin
+}
+
+// Compile returns the function described by the given @go attribute.
+func (c *compiler) Compile(funcName string, scope adt.Value, a *internal.Attr) (adt.Expr, errors.Error) {
+	// The runtime only looks for a name argument after the first
+	// positional argument, as used by @extern attributes, so check
+	// the first position as well to support @go(name=foo).
+	name, _, err := a.Lookup(0, "name")
+	if err != nil {
+		return nil, errors.Promote(err, "invalid attribute")
+	}
+	if name == "" {
+		name = funcName
+	}
+	b, ok := c.funcs[name]
+	if !ok {
+		return nil, errors.Newf(token.NoPos, "function %q not registered", name)
+	}
+	return pkg.ToBuiltin(b), nil
+}
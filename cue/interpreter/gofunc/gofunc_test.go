@@ -0,0 +1,172 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gofunc_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/interpreter/gofunc"
+)
+
+type point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func newInterpreter() *gofunc.Interpreter {
+	interp := gofunc.New()
+	interp.MustRegister("add", func(a, b int64) int64 { return a + b })
+	interp.MustRegister("upper", strings.ToUpper)
+	interp.MustRegister("repeat", func(b []byte, n uint8) []byte {
+		return []byte(strings.Repeat(string(b), int(n)))
+	})
+	interp.MustRegister("half", func(x float32) float32 { return x / 2 })
+	interp.MustRegister("not", func(b bool) bool { return !b })
+	interp.MustRegister("swap", func(p point) point { return point{X: p.Y, Y: p.X} })
+	interp.MustRegister("join", func(s []string) string { return strings.Join(s, ",") })
+	interp.MustRegister("kind", func(v cue.Value) string { return v.Kind().String() })
+	interp.MustRegister("div", func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	})
+	return interp
+}
+
+func build1(ctx *cue.Context, src string) cue.Value {
+	bi := build.NewContext().NewInstance("", nil)
+	bi.AddFile("a.cue", src)
+	bi.Complete()
+	return ctx.BuildInstance(bi)
+}
+
+func TestCall(t *testing.T) {
+	ctx := cuecontext.New(cuecontext.Interpreter(newInterpreter()))
+	v := build1(ctx, `
+@extern(go)
+package p
+
+add:    _ @go()
+upper:  _ @go()
+repeat: _ @go()
+half:   _ @go()
+not:    _ @go()
+swap:   _ @go()
+join:   _ @go()
+kind:   _ @go()
+div:    _ @go()
+plus:   _ @go(name=add)
+
+a: add(1, 2)
+b: upper("abc")
+c: repeat('ab', 3)
+d: half(3)
+e: not(true)
+f: swap({x: 1, y: 2})
+g: join(["a", "b"])
+h: kind({})
+i: div(7, 2)
+j: plus(3, 4)
+`)
+	qt.Assert(t, qt.IsNil(v.Err()))
+
+	want := map[string]string{
+		"a": `3`,
+		"b": `"ABC"`,
+		"c": `'ababab'`,
+		"d": `1.5`,
+		"e": `false`,
+		"f": "{\n\tx: 2.0\n\ty: 1.0\n}",
+		"g": `"a,b"`,
+		"h": `"struct"`,
+		"i": `3`,
+		"j": `7`,
+	}
+	for field, want := range want {
+		got, err := format.Node(v.LookupPath(cue.ParsePath(field)).Syntax(cue.Final()))
+		qt.Assert(t, qt.IsNil(err))
+		qt.Check(t, qt.Equals(string(got), want), qt.Commentf("field %s", field))
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := cuecontext.New(cuecontext.Interpreter(newInterpreter()))
+	v := build1(ctx, `
+@extern(go)
+package p
+
+div:     _ @go()
+repeat:  _ @go()
+missing: _ @go()
+
+a: div(1, 0)
+b: repeat('a', 300)
+`)
+	var msgs []string
+	for _, err := range errors.Errors(v.Validate()) {
+		msgs = append(msgs, err.Error())
+	}
+	qt.Assert(t, qt.DeepEquals(msgs, []string{
+		`@go: function "missing" not registered`,
+	}))
+
+	v = build1(ctx, `
+@extern(go)
+package p
+
+div:    _ @go()
+repeat: _ @go()
+
+a: div(1, 0)
+b: repeat('a', 300)
+`)
+	msgs = nil
+	for _, err := range errors.Errors(v.Validate()) {
+		msgs = append(msgs, err.Error())
+	}
+	qt.Assert(t, qt.DeepEquals(msgs, []string{
+		`a: error in call to div: division by zero`,
+		`b: int 300 overflows uint8 in argument 1 in call to repeat`,
+	}))
+}
+
+func TestRegister(t *testing.T) {
+	interp := gofunc.New()
+	for _, test := range []struct {
+		fn  any
+		err string
+	}{
+		{1, `cannot register f: implementation is int, not a function`},
+		{nil, `cannot register f: implementation is <nil>, not a function`},
+		{(func() int)(nil), `cannot register f: implementation is a nil function`},
+		{func(...int) int { return 0 }, `cannot register f: variadic functions are not supported`},
+		{func() {}, `cannot register f: must return a single value, optionally followed by an error`},
+		{func() error { return nil }, `cannot register f: must return a single value, optionally followed by an error`},
+		{func(chan int) int { return 0 }, `cannot register f: unsupported type chan int for parameter 1`},
+		{func() func() { return nil }, `cannot register f: unsupported result type func\(\)`},
+	} {
+		qt.Check(t, qt.ErrorMatches(interp.Register("f", test.fn), test.err))
+	}
+}
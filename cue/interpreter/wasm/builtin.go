@@ -25,8 +25,9 @@ import (
 // builtin that returns another builtin that corresponds to the external
 // Wasm function declared by the user. name is the name of the function,
 // args are its declared arguments, scope is a CUE value that represents
-// the structure into which to resolve the arguments and i is the
-// loaded Wasm instance that contains the function.
+// the structure into which to resolve the arguments, abi is the ABI used
+// to call the function and i is the loaded Wasm instance that contains
+// the function.
 //
 // This function is implemented as a higher-order function to solve a
 // bootstrapping issue. The user can specifies arbitrary types for the
@@ -45,7 +46,7 @@ import (
 // this higher-order design we get an appropiate OpContext when the
 // runtime calls the nullary builtin hence solving the bootstrapping
 // problem.
-func generateCallThatReturnsBuiltin(name string, scope adt.Value, args []string, i *instance) (adt.Expr, error) {
+func generateCallThatReturnsBuiltin(name string, scope adt.Value, args []string, abi string, i *instance) (adt.Expr, error) {
 	// ensure that the function exists before trying to call it.
 	_, err := i.load(name)
 	if err != nil {
@@ -63,7 +64,12 @@ func generateCallThatReturnsBuiltin(name string, scope adt.Value, args []string,
 				Name:   name,
 				Params: params(args),
 				Result: result.Kind(),
-				Func:   cABIFunc(i, name, sig),
+			}
+			switch abi {
+			case "canonical":
+				b.Func = canonicalABIFunc(i, name, sig)
+			default:
+				b.Func = cABIFunc(i, name, sig)
 			}
 			return pkg.ToBuiltin(b)
 		},
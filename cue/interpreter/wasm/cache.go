@@ -0,0 +1,95 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+)
+
+// A Cache holds the compiled form of Wasm modules so that they are
+// compiled only once, even when they are used by many interpreters,
+// and therefore by many [cue.Context] values. Modules are identified by
+// a hash of their contents, so a module that is changed is compiled
+// again, and the same module stored under different file names is
+// compiled only once.
+//
+// A Cache is safe for concurrent use.
+type Cache struct {
+	dir string
+
+	once sync.Once
+	cc   wazero.CompilationCache
+	err  error
+}
+
+// NewCache returns a new Cache that holds compiled modules in memory.
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// NewCacheWithDir returns a new Cache that additionally stores compiled
+// modules in the given directory, so that they can be reused by later
+// processes. The directory is created when it is first needed.
+func NewCacheWithDir(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// compilationCache returns the underlying wazero cache, creating it if
+// necessary.
+func (c *Cache) compilationCache() (wazero.CompilationCache, error) {
+	c.once.Do(func() {
+		if c.dir == "" {
+			c.cc = wazero.NewCompilationCache()
+			return
+		}
+		c.cc, c.err = wazero.NewCompilationCacheWithDir(c.dir)
+		if c.err != nil {
+			c.err = fmt.Errorf("can't create Wasm cache: %w", c.err)
+		}
+	})
+	return c.cc, c.err
+}
+
+// Precompile compiles the given Wasm module and stores the result in
+// the cache, so that later uses of the module do not pay the cost of
+// compiling it. It returns an error if the module is invalid.
+func (c *Cache) Precompile(wasm []byte) error {
+	r, err := newRuntime(c)
+	if err != nil {
+		return err
+	}
+	// Closing the runtime leaves the compiled module in the cache.
+	defer r.Close(r.ctx)
+
+	if _, err := r.Runtime.CompileModule(r.ctx, wasm); err != nil {
+		return fmt.Errorf("can't compile Wasm module: %w", err)
+	}
+	return nil
+}
+
+// Close releases the resources held by the cache. Interpreters using
+// the cache must not be used after calling Close.
+func (c *Cache) Close() error {
+	// Make sure that the cache is not created after it is closed.
+	c.once.Do(func() {})
+	if c.cc == nil {
+		return nil
+	}
+	return c.cc.Close(context.Background())
+}
@@ -15,7 +15,10 @@
 package wasm
 
 import (
+	"encoding/binary"
 	"fmt"
+	"path/filepath"
+	"unicode/utf8"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/internal/pkg"
@@ -121,6 +124,34 @@ func encBytes(i *instance, b []byte) *memory {
 	return m
 }
 
+// encBuffer copies b into newly allocated guest memory, which must be
+// freed by the caller.
+func encBuffer(i *instance, b []byte) (*memory, error) {
+	m, err := i.Alloc(uint32(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	m.WriteAt(b, 0)
+	return m, nil
+}
+
+// decBuffer returns the contents of the length-prefixed buffer at ptr,
+// which consists of the length of the data as a little-endian uint32
+// followed by the data itself. The buffer is allocated by the guest
+// with allocate, and decBuffer frees it using deallocate.
+func decBuffer(i *instance, ptr uint32) ([]byte, error) {
+	if i.free == nil {
+		return nil, fmt.Errorf("Wasm module %v does not export deallocate", filepath.Base(i.name))
+	}
+	hdr, err := i.Read(ptr, 4)
+	if err != nil {
+		return nil, err
+	}
+	n := binary.LittleEndian.Uint32(hdr)
+	defer i.Free(&memory{i: i, ptr: ptr, len: 4 + n})
+	return i.Read(ptr+4, n)
+}
+
 // cABIFunc implements the Wasm/System V ABI translation. The named
 // function, which must be loadable by the instance, and must be of
 // the specified sig type, will be called by the runtime after its
@@ -161,6 +192,24 @@ func cABIFunc(i *instance, name string, sig []cue.Value) func(*pkg.CallCtxt) {
 				defer i.FreeAll(ms)
 
 				args = append(args, uint64(ms[0].ptr))
+			case cue.StringKind, cue.BytesKind:
+				var b []byte
+				if typ.IncompleteKind() == cue.StringKind {
+					b = []byte(c.String(k))
+				} else {
+					b = c.Bytes(k)
+				}
+				if c.Err != nil {
+					return
+				}
+				m, err := encBuffer(i, b)
+				if err != nil {
+					c.Err = err
+					return
+				}
+				defer i.Free(m)
+
+				args = append(args, m.Args()...)
 			default:
 				panic(fmt.Sprintf("unsupported argument type %v (kind %v)", typ, typ.IncompleteKind()))
 			}
@@ -187,6 +236,24 @@ func cABIFunc(i *instance, name string, sig []cue.Value) func(*pkg.CallCtxt) {
 				c.Ret = decNumber(resTyp, res[0])
 			case cue.StructKind:
 				c.Ret = decodeStruct(retMem.Bytes(), retLayout)
+			case cue.StringKind:
+				b, err := decBuffer(i, api.DecodeU32(res[0]))
+				if err != nil {
+					c.Err = err
+					return
+				}
+				if !utf8.Valid(b) {
+					c.Err = fmt.Errorf("invalid UTF-8 in string returned from Wasm")
+					return
+				}
+				c.Ret = string(b)
+			case cue.BytesKind:
+				b, err := decBuffer(i, api.DecodeU32(res[0]))
+				if err != nil {
+					c.Err = err
+					return
+				}
+				c.Ret = b
 			default:
 				panic(fmt.Sprintf("unsupported result type %v (kind %v)", resTyp, resTyp.IncompleteKind()))
 			}
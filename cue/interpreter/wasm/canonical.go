@@ -0,0 +1,405 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/internal/pkg"
+	"github.com/tetratelabs/wazero/api"
+)
+
+// The maximum number of core Wasm values used to pass parameters and
+// results directly in the canonical ABI. Beyond that, they are passed
+// in guest memory.
+const (
+	maxFlatParams  = 16
+	maxFlatResults = 1
+)
+
+// canonType describes how a value is represented in the canonical ABI
+// of the WebAssembly component model.
+type canonType struct {
+	typ
+	size  int
+	align int
+
+	elem   *canonType   // IFF typ==typList
+	fields []canonField // IFF typ==typStruct
+}
+
+// canonField represents a record field in the canonical ABI.
+type canonField struct {
+	*canonType
+	sel    cue.Selector
+	offset int // memory offset in the parent record.
+}
+
+// canonTypeVal returns the canonical ABI representation of the type t.
+// Strings map to the component model's string type, bytes to list<u8>,
+// lists to list<T>, and structs to records.
+func canonTypeVal(t cue.Value) *canonType {
+	switch t.IncompleteKind() {
+	case cue.BoolKind, cue.IntKind, cue.FloatKind, cue.NumberKind:
+		typ := typVal(t)
+		return &canonType{typ: typ, size: sizeof(typ), align: sizeof(typ)}
+	case cue.StringKind:
+		return &canonType{typ: typString, size: 8, align: 4}
+	case cue.BytesKind:
+		return &canonType{typ: typBytes, size: 8, align: 4}
+	case cue.ListKind:
+		elem := canonTypeVal(t.LookupPath(cue.MakePath(cue.AnyIndex)))
+		return &canonType{typ: typList, size: 8, align: 4, elem: elem}
+	case cue.StructKind:
+		var fields []canonField
+		for i, _ := t.Fields(); i.Next(); {
+			fields = append(fields, canonField{
+				canonType: canonTypeVal(i.Value()),
+				sel:       i.Selector(),
+			})
+		}
+		return canonRecord(fields)
+	default:
+		panic(fmt.Sprintf("unsupported argument type %v (kind %v)", t, t.IncompleteKind()))
+	}
+}
+
+// canonRecord returns the record type with the given fields,
+// computing their offsets.
+func canonRecord(fields []canonField) *canonType {
+	t := &canonType{typ: typStruct, align: 1, fields: fields}
+	off := 0
+	for k := range t.fields {
+		f := &t.fields[k]
+		off = align(off, f.align)
+		f.offset = off
+		off += f.size
+		t.align = max(t.align, f.align)
+	}
+	t.size = align(off, t.align)
+	return t
+}
+
+// flatCount returns the number of core Wasm values used to pass
+// a value of type t directly.
+func (t *canonType) flatCount() int {
+	switch t.typ {
+	case typString, typBytes, typList:
+		return 2
+	case typStruct:
+		n := 0
+		for _, f := range t.fields {
+			n += f.flatCount()
+		}
+		return n
+	default:
+		return 1
+	}
+}
+
+// canonicalABIFunc implements the translation to and from the canonical
+// ABI of the component model. The named function, which must be
+// loadable by the instance, and must be of the specified sig type, is
+// called with its arguments lowered into core Wasm values and guest
+// memory allocated with cabi_realloc. Its result is then lifted back
+// into a Go value and handed to the runtime. If the module exports
+// a cabi_post function for the named function, it is called after the
+// result has been lifted so that the guest can free its memory.
+func canonicalABIFunc(i *instance, name string, sig []cue.Value) func(*pkg.CallCtxt) {
+	argsTyp, resTyp := splitLast(sig)
+	params := make([]canonField, 0, len(argsTyp))
+	flatParams := 0
+	for _, typ := range argsTyp {
+		t := canonTypeVal(typ)
+		params = append(params, canonField{canonType: t})
+		flatParams += t.flatCount()
+	}
+	result := canonTypeVal(resTyp)
+
+	fn, _ := i.load(name)
+	post := i.instance.ExportedFunction("cabi_post_" + name)
+	return func(c *pkg.CallCtxt) {
+		vals := make([]cue.Value, len(params))
+		for k := range params {
+			vals[k] = c.Value(k)
+		}
+		if !c.Do() {
+			return
+		}
+
+		var args []uint64
+		if flatParams <= maxFlatParams {
+			for k, p := range params {
+				var err error
+				args, err = lowerFlat(i, args, vals[k], p.canonType)
+				if err != nil {
+					c.Err = err
+					return
+				}
+			}
+		} else {
+			// Too many parameters to pass directly: store them
+			// in guest memory as a tuple and pass its address.
+			tuple := canonRecord(params)
+			buf := make([]byte, tuple.size)
+			for k, p := range tuple.fields {
+				if err := store(i, buf[p.offset:], vals[k], p.canonType); err != nil {
+					c.Err = err
+					return
+				}
+			}
+			ptr, err := lowerBytes(i, buf, tuple.align)
+			if err != nil {
+				c.Err = err
+				return
+			}
+			args = append(args, uint64(ptr))
+		}
+
+		res, err := fn.Call(i.ctx, args...)
+		if err != nil {
+			c.Err = err
+			return
+		}
+		if result.flatCount() <= maxFlatResults {
+			c.Ret, _, err = liftFlat(i, res, result)
+		} else {
+			// The result is stored in guest memory,
+			// and the function returns its address.
+			var buf []byte
+			buf, err = i.Read(api.DecodeU32(res[0]), uint32(result.size))
+			if err == nil {
+				c.Ret, err = load(i, buf, result)
+			}
+		}
+		if err != nil {
+			c.Err = err
+			return
+		}
+		if post != nil {
+			if _, err := post.Call(i.ctx, res...); err != nil {
+				c.Err = err
+			}
+		}
+	}
+}
+
+// lowerFlat appends the core Wasm values that represent v, which is
+// of type t, to args.
+func lowerFlat(i *instance, args []uint64, v cue.Value, t *canonType) ([]uint64, error) {
+	switch t.typ {
+	case typString, typBytes, typList:
+		ptr, n, err := lowerList(i, v, t)
+		if err != nil {
+			return nil, err
+		}
+		return append(args, api.EncodeU32(ptr), api.EncodeU32(n)), nil
+	case typStruct:
+		for _, f := range t.fields {
+			var err error
+			args, err = lowerFlat(i, args, v.LookupPath(cue.MakePath(f.sel)), f.canonType)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return args, nil
+	case typBool:
+		b, _ := v.Bool()
+		return append(args, encBool(b)), nil
+	case typInt8, typInt16, typInt32:
+		x, _ := v.Int64()
+		return append(args, api.EncodeI32(int32(x))), nil
+	case typUint8, typUint16, typUint32:
+		x, _ := v.Uint64()
+		return append(args, api.EncodeU32(uint32(x))), nil
+	case typInt64:
+		x, _ := v.Int64()
+		return append(args, api.EncodeI64(x)), nil
+	case typUint64:
+		x, _ := v.Uint64()
+		return append(args, x), nil
+	case typFloat32:
+		x, _ := v.Float64()
+		return append(args, api.EncodeF32(float32(x))), nil
+	case typFloat64:
+		x, _ := v.Float64()
+		return append(args, api.EncodeF64(x)), nil
+	}
+	panic(fmt.Sprintf("unsupported argument type: %v", t.typ))
+}
+
+// lowerList stores the elements of v, which is a string, bytes, or list
+// of type t, in newly allocated guest memory, and returns the address
+// and number of elements.
+func lowerList(i *instance, v cue.Value, t *canonType) (ptr, n uint32, err error) {
+	var buf []byte
+	align := 1
+	switch t.typ {
+	case typString:
+		s, _ := v.String()
+		buf, n = []byte(s), uint32(len(s))
+	case typBytes:
+		buf, _ = v.Bytes()
+		n = uint32(len(buf))
+	case typList:
+		elems, _ := v.List()
+		for ; elems.Next(); n++ {
+			buf = append(buf, make([]byte, t.elem.size)...)
+			if err := store(i, buf[int(n)*t.elem.size:], elems.Value(), t.elem); err != nil {
+				return 0, 0, err
+			}
+		}
+		align = t.elem.align
+	}
+	ptr, err = lowerBytes(i, buf, align)
+	return ptr, n, err
+}
+
+// lowerBytes copies buf to newly allocated guest memory with the given
+// alignment and returns its address.
+func lowerBytes(i *instance, buf []byte, align int) (uint32, error) {
+	ptr, err := i.Realloc(uint32(align), uint32(len(buf)))
+	if err != nil {
+		return 0, err
+	}
+	return ptr, i.Write(ptr, buf)
+}
+
+// store serializes v, which is of type t, into buf.
+func store(i *instance, buf []byte, v cue.Value, t *canonType) error {
+	switch t.typ {
+	case typString, typBytes, typList:
+		ptr, n, err := lowerList(i, v, t)
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(buf, ptr)
+		binary.LittleEndian.PutUint32(buf[4:], n)
+	case typStruct:
+		for _, f := range t.fields {
+			if err := store(i, buf[f.offset:], v.LookupPath(cue.MakePath(f.sel)), f.canonType); err != nil {
+				return err
+			}
+		}
+	default:
+		encodeScalar(buf, t.typ, v)
+	}
+	return nil
+}
+
+// liftFlat returns the Go representation of the value of type t
+// represented by the first core Wasm values in vals, along with the
+// remaining values.
+func liftFlat(i *instance, vals []uint64, t *canonType) (any, []uint64, error) {
+	switch t.typ {
+	case typString, typBytes, typList:
+		x, err := liftList(i, api.DecodeU32(vals[0]), api.DecodeU32(vals[1]), t)
+		return x, vals[2:], err
+	case typStruct:
+		m := make(map[string]any)
+		for _, f := range t.fields {
+			var err error
+			m[f.sel.Unquoted()], vals, err = liftFlat(i, vals, f.canonType)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return m, vals, nil
+	case typBool:
+		return api.DecodeU32(vals[0]) != 0, vals[1:], nil
+	case typInt8:
+		return int8(api.DecodeI32(vals[0])), vals[1:], nil
+	case typInt16:
+		return int16(api.DecodeI32(vals[0])), vals[1:], nil
+	case typInt32:
+		return api.DecodeI32(vals[0]), vals[1:], nil
+	case typUint8:
+		return uint8(api.DecodeU32(vals[0])), vals[1:], nil
+	case typUint16:
+		return uint16(api.DecodeU32(vals[0])), vals[1:], nil
+	case typUint32:
+		return api.DecodeU32(vals[0]), vals[1:], nil
+	case typInt64:
+		return int64(vals[0]), vals[1:], nil
+	case typUint64:
+		return vals[0], vals[1:], nil
+	case typFloat32:
+		return api.DecodeF32(vals[0]), vals[1:], nil
+	case typFloat64:
+		return api.DecodeF64(vals[0]), vals[1:], nil
+	}
+	panic(fmt.Sprintf("unsupported result type: %v", t.typ))
+}
+
+// liftList returns the Go representation of the n elements of the
+// string, bytes, or list of type t stored in guest memory at ptr.
+func liftList(i *instance, ptr, n uint32, t *canonType) (any, error) {
+	size := uint64(n)
+	if t.typ == typList {
+		size *= uint64(t.elem.size)
+	}
+	if size > 1<<32-1 {
+		return nil, fmt.Errorf("list of %d elements is too large", n)
+	}
+	buf, err := i.Read(ptr, uint32(size))
+	if err != nil {
+		return nil, err
+	}
+	switch t.typ {
+	case typString:
+		if !utf8.Valid(buf) {
+			return nil, fmt.Errorf("invalid UTF-8 in string returned from Wasm")
+		}
+		return string(buf), nil
+	case typBytes:
+		return buf, nil
+	}
+	elems := make([]any, n)
+	for k := range elems {
+		elems[k], err = load(i, buf[k*t.elem.size:], t.elem)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return elems, nil
+}
+
+// load returns the Go representation of the value of type t
+// serialized in buf.
+func load(i *instance, buf []byte, t *canonType) (any, error) {
+	switch t.typ {
+	case typString, typBytes, typList:
+		ptr := binary.LittleEndian.Uint32(buf)
+		n := binary.LittleEndian.Uint32(buf[4:])
+		return liftList(i, ptr, n, t)
+	case typStruct:
+		m := make(map[string]any)
+		for _, f := range t.fields {
+			x, err := load(i, buf[f.offset:], f.canonType)
+			if err != nil {
+				return nil, err
+			}
+			m[f.sel.Unquoted()] = x
+		}
+		return m, nil
+	case typBool:
+		return buf[0] != 0, nil
+	default:
+		return decodeScalar(buf, t.typ), nil
+	}
+}
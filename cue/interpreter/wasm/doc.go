@@ -58,6 +58,16 @@
 //
 //	isPrime: _ @extern("bar.wasm", abi=c, name=is_prime, sig="func(uint64): bool")
 //
+// # Using Wasm modules from Go
+//
+// Programs that embed CUE can make a Wasm module available to all CUE
+// packages, without it residing next to the CUE files, by passing
+// [WithModule] to [New]. Compiling a Wasm module can take much longer
+// than evaluating the CUE that uses it, so programs that create many
+// [cuelang.org/go/cue.Context] values, such as servers, should share a
+// [Cache] between their interpreters using [WithCache]. Modules can
+// be compiled ahead of their first use with [Cache.Precompile].
+//
 // # Runtime requirements for Wasm modules
 //
 // CUE runs Wasm code in a secure sandbox, which restricts access to
@@ -88,12 +98,16 @@
 //
 // # ABI requirements for Wasm modules
 //
-// Currently only the [System V ABI] (also known as the C ABI) is
-// supported. Furthermore, only scalar data types and structs containing
-// either scalar types or other structs can be exchanged between CUE
-// and Wasm. Scalar means booleans, sized integers, and sized floats.
-// The sig field in the attribute refers to these data types by their
-// CUE names, such as bool, uint16, float64.
+// Two ABIs are supported: the [System V ABI] (also known as the C ABI),
+// selected with abi=c, and the [canonical ABI] of the WebAssembly
+// component model, selected with abi=canonical. If no abi is given in
+// the attribute, the C ABI is used.
+//
+// With the C ABI, scalar data types, strings, bytes, and structs
+// containing either scalar types or other structs can be exchanged
+// between CUE and Wasm. Scalar means booleans, sized integers, and
+// sized floats. The sig field in the attribute refers to these data
+// types by their CUE names, such as bool, uint16, float64, string.
 //
 // Additionally the Wasm module must export two functions with the
 // following C type signature:
@@ -105,6 +119,55 @@
 // takes a Wasm pointer and the size of the buffer it points to and
 // frees it.
 //
+// A string or bytes argument is passed as two arguments: a pointer to
+// a copy of the data, allocated with allocate, followed by its length
+// as an int. The copy is freed after the call returns, so the function
+// must not hold on to it. A function returning a string or bytes must
+// return a pointer to a buffer allocated with allocate that holds the
+// length of the data, as a little-endian 32-bit unsigned integer,
+// followed by the data itself. CUE frees the buffer with deallocate
+// after reading it. For example, a function that converts a string to
+// upper case has the following C type signature:
+//
+//	void*	upper(char *s, int n);
+//
+// and is declared in CUE as
+//
+//	upper: _ @extern("strings.wasm", abi=c, sig="func(string): string")
+//
+// Returned strings must be valid UTF-8.
+//
+// # The canonical ABI
+//
+// The canonical ABI is the ABI used by the component model to pass
+// values to and from the core Wasm module inside a component. Using it
+// allows exporting functions with toolchains that support the component
+// model, such as wit-bindgen, without writing any allocation functions
+// by hand. In addition to the scalar types above, strings, bytes,
+// lists, and structs can be exchanged with CUE. They correspond to the
+// following component model types:
+//
+//	string		string
+//	bytes		list<u8>
+//	[...T]		list<T>
+//	struct		record, with fields in the same order
+//
+// As sig only allows identifiers and selectors, list and struct
+// types must be given a name, for example:
+//
+//	#point: {x: float64, y: float64}
+//	#points: [...#point]
+//	centroid: _ @extern("geo.wasm", abi=canonical, sig="func(#points): #point")
+//
+// CUE loads core Wasm modules only, and not components. A module that
+// uses the canonical ABI is the module produced before it is wrapped
+// into a component, such as the output of cargo for the wasm32-wasip1
+// target when using wit-bindgen. The module must export the cabi_realloc
+// function whenever memory needs to be allocated for arguments. If it
+// exports a function named cabi_post_ followed by the function name,
+// that function is called after the result has been read so that the
+// module can free it.
+//
 // # How to compile Rust for use in CUE
 //
 // To compile Rust code into a Wasm module usable by CUE, make sure
@@ -153,6 +216,7 @@
 //	}
 //
 // [System V ABI]: https://github.com/WebAssembly/tool-conventions/blob/main/BasicCABI.md
+// [canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
 // [no_std]: https://docs.rust-embedded.org/book/intro/no-std.html
 // [WASI]: https://wasi.dev
 // [cargo target]: https://doc.rust-lang.org/cargo/reference/cargo-targets.html
@@ -52,6 +52,23 @@ func sig(a *internal.Attr) (string, error) {
 	return sig, nil
 }
 
+// abi returns the ABI specified in an external attribute, which
+// defaults to the C ABI.
+func abi(a *internal.Attr) (string, error) {
+	abi, ok, err := a.Lookup(1, "abi")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "c", nil
+	}
+	switch abi {
+	case "c", "canonical":
+		return abi, nil
+	}
+	return "", fmt.Errorf("unsupported ABI %q", abi)
+}
+
 func parseFunc(sig string) (*ast.Func, error) {
 	expr, err := parser.ParseExpr("", sig, parser.ParseFuncs)
 	if err != nil {
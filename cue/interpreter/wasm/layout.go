@@ -40,6 +40,9 @@ const (
 	typFloat32
 	typFloat64
 	typStruct
+	typString
+	typBytes
+	typList
 )
 
 // field represents a name struct field.
@@ -92,57 +95,65 @@ func encode(i *instance, v cue.Value, l *structLayout, buf []byte, ms []*memory)
 		arg := v.LookupPath(cue.ParsePath(f.from))
 
 		switch f.typ {
-		case typBool:
-			b, _ := arg.Bool()
-			if b {
-				buf[f.offset] = 1
-			} else {
-				buf[f.offset] = 0
-			}
-
-		case typUint8:
-			u, _ := arg.Uint64()
-			buf[f.offset] = byte(u)
-		case typUint16:
-			u, _ := arg.Uint64()
-			binary.LittleEndian.PutUint16(buf[f.offset:], uint16(u))
-		case typUint32:
-			u, _ := arg.Uint64()
-			binary.LittleEndian.PutUint32(buf[f.offset:], uint32(u))
-		case typUint64:
-			u, _ := arg.Uint64()
-			binary.LittleEndian.PutUint64(buf[f.offset:], u)
-
-		case typInt8:
-			u, _ := arg.Int64()
-			buf[f.offset] = byte(u)
-		case typInt16:
-			u, _ := arg.Int64()
-			binary.LittleEndian.PutUint16(buf[f.offset:], uint16(u))
-		case typInt32:
-			u, _ := arg.Int64()
-			binary.LittleEndian.PutUint32(buf[f.offset:], uint32(u))
-		case typInt64:
-			u, _ := arg.Int64()
-			binary.LittleEndian.PutUint64(buf[f.offset:], uint64(u))
-
-		case typFloat32:
-			x, _ := arg.Float64()
-			binary.LittleEndian.PutUint32(buf[f.offset:], math.Float32bits(float32(x)))
-		case typFloat64:
-			x, _ := arg.Float64()
-			binary.LittleEndian.PutUint64(buf[f.offset:], math.Float64bits(x))
-
 		case typStruct:
 			encode(i, arg, f.inner, buf[f.offset:], ms)
-
 		default:
-			panic(fmt.Sprintf("unsupported argument %v (kind %v)", v, v.IncompleteKind()))
+			encodeScalar(buf[f.offset:], f.typ, arg)
 		}
 	}
 	return buf, ms
 }
 
+// encodeScalar serializes the boolean or number v, which is of type t,
+// into buf.
+func encodeScalar(buf []byte, t typ, v cue.Value) {
+	switch t {
+	case typBool:
+		b, _ := v.Bool()
+		if b {
+			buf[0] = 1
+		} else {
+			buf[0] = 0
+		}
+
+	case typUint8:
+		u, _ := v.Uint64()
+		buf[0] = byte(u)
+	case typUint16:
+		u, _ := v.Uint64()
+		binary.LittleEndian.PutUint16(buf, uint16(u))
+	case typUint32:
+		u, _ := v.Uint64()
+		binary.LittleEndian.PutUint32(buf, uint32(u))
+	case typUint64:
+		u, _ := v.Uint64()
+		binary.LittleEndian.PutUint64(buf, u)
+
+	case typInt8:
+		u, _ := v.Int64()
+		buf[0] = byte(u)
+	case typInt16:
+		u, _ := v.Int64()
+		binary.LittleEndian.PutUint16(buf, uint16(u))
+	case typInt32:
+		u, _ := v.Int64()
+		binary.LittleEndian.PutUint32(buf, uint32(u))
+	case typInt64:
+		u, _ := v.Int64()
+		binary.LittleEndian.PutUint64(buf, uint64(u))
+
+	case typFloat32:
+		x, _ := v.Float64()
+		binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(x)))
+	case typFloat64:
+		x, _ := v.Float64()
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(x))
+
+	default:
+		panic(fmt.Sprintf("unsupported argument %v (kind %v)", v, v.IncompleteKind()))
+	}
+}
+
 // decodeStruct takes the binary representation of a struct described
 // by the layout and returns its Go representation as a map.
 func decodeStruct(buf []byte, l *structLayout) map[string]any {
@@ -150,58 +161,51 @@ func decodeStruct(buf []byte, l *structLayout) map[string]any {
 
 	for _, f := range l.fields {
 		switch f.typ {
-		case typBool:
-			u := buf[f.offset]
-			if u == 1 {
-				m[f.from] = true
-			} else {
-				m[f.from] = false
-			}
-
-		case typUint8:
-			u := buf[f.offset]
-			m[f.from] = u
-		case typUint16:
-			u := binary.LittleEndian.Uint16(buf[f.offset:])
-			m[f.from] = u
-		case typUint32:
-			u := binary.LittleEndian.Uint32(buf[f.offset:])
-			m[f.from] = u
-		case typUint64:
-			u := binary.LittleEndian.Uint64(buf[f.offset:])
-			m[f.from] = u
-
-		case typInt8:
-			u := buf[f.offset]
-			m[f.from] = int8(u)
-		case typInt16:
-			u := binary.LittleEndian.Uint16(buf[f.offset:])
-			m[f.from] = int16(u)
-		case typInt32:
-			u := binary.LittleEndian.Uint32(buf[f.offset:])
-			m[f.from] = int32(u)
-		case typInt64:
-			u := binary.LittleEndian.Uint64(buf[f.offset:])
-			m[f.from] = int64(u)
-
-		case typFloat32:
-			u := binary.LittleEndian.Uint32(buf[f.offset:])
-			m[f.from] = math.Float32frombits(u)
-		case typFloat64:
-			u := binary.LittleEndian.Uint64(buf[f.offset:])
-			m[f.from] = math.Float64frombits(u)
-
 		case typStruct:
 			to := f.offset + f.inner.size
 			m[f.from] = decodeStruct(buf[f.offset:to], f.inner)
-
 		default:
-			panic(fmt.Sprintf("unsupported argument type: %v", f.typ))
+			m[f.from] = decodeScalar(buf[f.offset:], f.typ)
 		}
 	}
 	return m
 }
 
+// decodeScalar returns the Go representation of the boolean or number
+// of type t serialized in buf.
+func decodeScalar(buf []byte, t typ) any {
+	switch t {
+	case typBool:
+		return buf[0] == 1
+
+	case typUint8:
+		return buf[0]
+	case typUint16:
+		return binary.LittleEndian.Uint16(buf)
+	case typUint32:
+		return binary.LittleEndian.Uint32(buf)
+	case typUint64:
+		return binary.LittleEndian.Uint64(buf)
+
+	case typInt8:
+		return int8(buf[0])
+	case typInt16:
+		return int16(binary.LittleEndian.Uint16(buf))
+	case typInt32:
+		return int32(binary.LittleEndian.Uint32(buf))
+	case typInt64:
+		return int64(binary.LittleEndian.Uint64(buf))
+
+	case typFloat32:
+		return math.Float32frombits(binary.LittleEndian.Uint32(buf))
+	case typFloat64:
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf))
+
+	default:
+		panic(fmt.Sprintf("unsupported argument type: %v", t))
+	}
+}
+
 func align(x, n int) int {
 	return (x + n - 1) & ^(n - 1)
 }
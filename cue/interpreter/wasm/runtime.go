@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/tetratelabs/wazero"
@@ -35,15 +36,25 @@ type runtime struct {
 	wazero.Runtime
 }
 
-func newRuntime() runtime {
+// newRuntime returns a new runtime that stores compiled modules in
+// cache, if it is not nil.
+func newRuntime(cache *Cache) (runtime, error) {
 	ctx := context.Background()
-	r := wazero.NewRuntime(ctx)
+	cfg := wazero.NewRuntimeConfig()
+	if cache != nil {
+		cc, err := cache.compilationCache()
+		if err != nil {
+			return runtime{}, err
+		}
+		cfg = cfg.WithCompilationCache(cc)
+	}
+	r := wazero.NewRuntimeWithConfig(ctx, cfg)
 	wasi_snapshot_preview1.MustInstantiate(ctx, r)
 
 	return runtime{
 		ctx:     ctx,
 		Runtime: r,
-	}
+	}, nil
 }
 
 // compile takes the name of a Wasm module, and returns its compiled
@@ -53,7 +64,12 @@ func (r *runtime) compile(name string) (*module, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't compile Wasm module: %w", err)
 	}
+	return r.compileBytes(name, buf)
+}
 
+// compileBytes is like compile, but takes the contents of the module
+// rather than reading it from the named file.
+func (r *runtime) compileBytes(name string, buf []byte) (*module, error) {
 	mod, err := r.Runtime.CompileModule(r.ctx, buf)
 	if err != nil {
 		return nil, fmt.Errorf("can't compile Wasm module: %w", err)
@@ -67,8 +83,15 @@ func (r *runtime) compile(name string) (*module, error) {
 
 // compileAndLoad is a convenience method that compiles a module then
 // loads it into memory returning the loaded instance, or an error.
-func (r *runtime) compileAndLoad(name string) (*instance, error) {
-	m, err := r.compile(name)
+// If buf is nil, the module is read from the named file.
+func (r *runtime) compileAndLoad(name string, buf []byte) (*instance, error) {
+	var m *module
+	var err error
+	if buf == nil {
+		m, err = r.compile(name)
+	} else {
+		m, err = r.compileBytes(name, buf)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +124,7 @@ func (m *module) load() (*instance, error) {
 		instance: wInst,
 		alloc:    wInst.ExportedFunction("allocate"),
 		free:     wInst.ExportedFunction("deallocate"),
+		realloc:  wInst.ExportedFunction("cabi_realloc"),
 	}
 	return &inst, nil
 }
@@ -120,6 +144,11 @@ type instance struct {
 	// free is a guest function that frees guest memory on
 	// behalf of the host.
 	free api.Function
+
+	// realloc is the guest function that allocates guest memory
+	// on behalf of the host in the canonical ABI. It is nil if the
+	// module does not export it.
+	realloc api.Function
 }
 
 // load attempts to load the named function from the instance, returning
@@ -138,6 +167,10 @@ func (i *instance) load(funcName string) (api.Function, error) {
 // Alloc returns a reference to newly allocated guest memory that spans
 // the provided size.
 func (i *instance) Alloc(size uint32) (*memory, error) {
+	if i.alloc == nil || i.free == nil {
+		return nil, fmt.Errorf("Wasm module %v does not export allocate and deallocate", filepath.Base(i.name))
+	}
+
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
@@ -152,6 +185,50 @@ func (i *instance) Alloc(size uint32) (*memory, error) {
 	}, nil
 }
 
+// Realloc allocates guest memory of the given size and alignment
+// using the canonical ABI's cabi_realloc function, and returns its
+// address. Unlike with Alloc, ownership of the memory passes to
+// the guest.
+func (i *instance) Realloc(align, size uint32) (uint32, error) {
+	if i.realloc == nil {
+		return 0, fmt.Errorf("Wasm module %v does not export cabi_realloc", filepath.Base(i.name))
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	res, err := i.realloc.Call(i.ctx, 0, 0, uint64(align), uint64(size))
+	if err != nil {
+		return 0, fmt.Errorf("can't allocate memory: requested %d bytes", size)
+	}
+	return uint32(res[0]), nil
+}
+
+// Read returns a copy of the size bytes of guest memory at ptr.
+// Unlike [memory.Bytes], it returns an error rather than panicking
+// if the memory is out of bounds, as ptr may come from the guest.
+func (i *instance) Read(ptr, size uint32) ([]byte, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	bytes, ok := i.instance.Memory().Read(ptr, size)
+	if !ok {
+		return nil, fmt.Errorf("can't read %d bytes from Wasm address %#x", size, ptr)
+	}
+	return append([]byte{}, bytes...), nil
+}
+
+// Write writes p to guest memory at ptr.
+func (i *instance) Write(ptr uint32, p []byte) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.instance.Memory().Write(ptr, p) {
+		return fmt.Errorf("can't write %d bytes to Wasm address %#x", len(p), ptr)
+	}
+	return nil
+}
+
 // Free frees previously allocated guest memory.
 func (i *instance) Free(m *memory) {
 	i.mu.Lock()
@@ -18,6 +18,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 //go:generate go run gen.go
@@ -40,6 +41,19 @@ func main() {
 	copyWasm(bins, cue)
 
 	os.RemoveAll(target)
+
+	// Modules that exercise an ABI directly, rather than through a
+	// language toolchain, are written in the WebAssembly text format.
+	buildWat(filepath.Join(cwd, "wat"), cue)
+}
+
+func buildWat(srcDir, wasmDir string) {
+	files, _ := filepath.Glob(filepath.Join(srcDir, "*.wat"))
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".wat") + ".wasm"
+		cmd := exec.Command("wat2wasm", "--enable-bulk-memory", "-o", filepath.Join(wasmDir, name), f)
+		cmd.Run()
+	}
 }
 
 func buildRust(srcDir string) {
@@ -0,0 +1,98 @@
+;; canonical.wat implements functions that use the canonical ABI of the
+;; component model. It is written by hand rather than generated from
+;; WIT so that it does not depend on any particular toolchain. Its
+;; interface corresponds to the following WIT world:
+;;
+;;	world canonical {
+;;		record point {
+;;			x: f64,
+;;			y: f64,
+;;		}
+;;		export greet: func(name: string) -> string;
+;;		export sum: func(xs: list<s64>) -> s64;
+;;		export size: func(words: list<string>) -> u32;
+;;		export scale: func(p: point, k: f64) -> point;
+;;		export range: func(n: u32) -> list<u32>;
+;;	}
+(module
+	(memory (export "memory") 1)
+
+	;; heap holds the address of the next free byte. Memory is never
+	;; freed, which is good enough for short-lived test instances.
+	(global $heap (mut i32) (i32.const 1024))
+
+	;; The first 16 bytes of memory are used to return results that
+	;; do not fit in a single value.
+	(data (i32.const 16) "hello, ")
+
+	(func $realloc (export "cabi_realloc")
+		(param $old i32) (param $oldSize i32) (param $align i32) (param $size i32)
+		(result i32)
+		(local $p i32)
+		(local.set $p
+			(i32.and
+				(i32.sub (i32.add (global.get $heap) (local.get $align)) (i32.const 1))
+				(i32.sub (i32.const 0) (local.get $align))))
+		(global.set $heap (i32.add (local.get $p) (local.get $size)))
+		(local.get $p))
+
+	;; greet returns "hello, " followed by name.
+	(func (export "greet") (param $p i32) (param $n i32) (result i32)
+		(local $r i32)
+		(local.set $r
+			(call $realloc (i32.const 0) (i32.const 0) (i32.const 1)
+				(i32.add (local.get $n) (i32.const 7))))
+		(memory.copy (local.get $r) (i32.const 16) (i32.const 7))
+		(memory.copy (i32.add (local.get $r) (i32.const 7)) (local.get $p) (local.get $n))
+		(i32.store (i32.const 0) (local.get $r))
+		(i32.store (i32.const 4) (i32.add (local.get $n) (i32.const 7)))
+		(i32.const 0))
+
+	;; sum returns the sum of the elements of xs.
+	(func (export "sum") (param $p i32) (param $n i32) (result i64)
+		(local $i i32) (local $acc i64)
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(local.set $acc
+					(i64.add (local.get $acc)
+						(i64.load (i32.add (local.get $p) (i32.shl (local.get $i) (i32.const 3))))))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(local.get $acc))
+
+	;; size returns the sum of the lengths of words.
+	(func (export "size") (param $p i32) (param $n i32) (result i32)
+		(local $i i32) (local $acc i32)
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(local.set $acc
+					(i32.add (local.get $acc)
+						(i32.load offset=4 (i32.add (local.get $p) (i32.shl (local.get $i) (i32.const 3))))))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(local.get $acc))
+
+	;; scale returns p with both coordinates multiplied by k.
+	(func (export "scale") (param $x f64) (param $y f64) (param $k f64) (result i32)
+		(f64.store (i32.const 0) (f64.mul (local.get $x) (local.get $k)))
+		(f64.store (i32.const 8) (f64.mul (local.get $y) (local.get $k)))
+		(i32.const 0))
+
+	;; range returns the list of numbers from 0 up to but not including n.
+	(func (export "range") (param $n i32) (result i32)
+		(local $r i32) (local $i i32)
+		(local.set $r
+			(call $realloc (i32.const 0) (i32.const 0) (i32.const 4)
+				(i32.shl (local.get $n) (i32.const 2))))
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(i32.store (i32.add (local.get $r) (i32.shl (local.get $i) (i32.const 2))) (local.get $i))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(i32.store (i32.const 0) (local.get $r))
+		(i32.store (i32.const 4) (local.get $n))
+		(i32.const 0))
+)
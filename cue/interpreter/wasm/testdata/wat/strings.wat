@@ -0,0 +1,98 @@
+;; strings.wat implements functions that exchange strings and bytes
+;; with CUE using the C ABI. String and bytes arguments are passed as
+;; a pointer and a length, and results are returned as a pointer to
+;; a buffer, allocated with allocate, that holds the length of the
+;; data as a little-endian uint32 followed by the data itself.
+(module
+	(memory (export "memory") 1)
+
+	;; heap holds the address of the next free byte. Memory is never
+	;; freed, which is good enough for short-lived test instances.
+	(global $heap (mut i32) (i32.const 1024))
+
+	(func $allocate (export "allocate") (param $size i32) (result i32)
+		(local $p i32)
+		(local.set $p
+			(i32.and
+				(i32.add (global.get $heap) (i32.const 7))
+				(i32.const -8)))
+		(global.set $heap (i32.add (local.get $p) (local.get $size)))
+		(local.get $p))
+
+	(func (export "deallocate") (param $p i32) (param $size i32))
+
+	;; $buffer allocates a length-prefixed buffer for n bytes and
+	;; returns its address.
+	(func $buffer (param $n i32) (result i32)
+		(local $r i32)
+		(local.set $r (call $allocate (i32.add (local.get $n) (i32.const 4))))
+		(i32.store (local.get $r) (local.get $n))
+		(local.get $r))
+
+	;; upper returns s with ASCII letters converted to upper case.
+	(func (export "upper") (param $p i32) (param $n i32) (result i32)
+		(local $r i32) (local $i i32) (local $c i32)
+		(local.set $r (call $buffer (local.get $n)))
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(local.set $c (i32.load8_u (i32.add (local.get $p) (local.get $i))))
+				(if (i32.and
+						(i32.ge_u (local.get $c) (i32.const 97))
+						(i32.le_u (local.get $c) (i32.const 122)))
+					(then
+						(local.set $c (i32.sub (local.get $c) (i32.const 32)))))
+				(i32.store8 offset=4 (i32.add (local.get $r) (local.get $i)) (local.get $c))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(local.get $r))
+
+	;; reverse returns b with its bytes in reverse order.
+	(func (export "reverse") (param $p i32) (param $n i32) (result i32)
+		(local $r i32) (local $i i32)
+		(local.set $r (call $buffer (local.get $n)))
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(i32.store8 offset=4
+					(i32.add (local.get $r) (local.get $i))
+					(i32.load8_u
+						(i32.sub
+							(i32.add (local.get $p) (local.get $n))
+							(i32.add (local.get $i) (i32.const 1)))))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(local.get $r))
+
+	;; concat returns the concatenation of a and b.
+	(func (export "concat") (param $p1 i32) (param $n1 i32) (param $p2 i32) (param $n2 i32) (result i32)
+		(local $r i32)
+		(local.set $r (call $buffer (i32.add (local.get $n1) (local.get $n2))))
+		(memory.copy (i32.add (local.get $r) (i32.const 4)) (local.get $p1) (local.get $n1))
+		(memory.copy
+			(i32.add (i32.add (local.get $r) (i32.const 4)) (local.get $n1))
+			(local.get $p2) (local.get $n2))
+		(local.get $r))
+
+	;; count returns the number of times the byte c occurs in s.
+	(func (export "count") (param $p i32) (param $n i32) (param $c i32) (result i32)
+		(local $i i32) (local $acc i32)
+		(block $done
+			(loop $next
+				(br_if $done (i32.ge_u (local.get $i) (local.get $n)))
+				(if (i32.eq
+						(i32.load8_u (i32.add (local.get $p) (local.get $i)))
+						(local.get $c))
+					(then
+						(local.set $acc (i32.add (local.get $acc) (i32.const 1)))))
+				(local.set $i (i32.add (local.get $i) (i32.const 1)))
+				(br $next)))
+		(local.get $acc))
+
+	;; invalid returns a string that is not valid UTF-8.
+	(func (export "invalid") (param $p i32) (param $n i32) (result i32)
+		(local $r i32)
+		(local.set $r (call $buffer (i32.const 1)))
+		(i32.store8 offset=4 (local.get $r) (i32.const 255))
+		(local.get $r))
+)
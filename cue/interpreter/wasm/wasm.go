@@ -29,12 +29,49 @@ import (
 )
 
 // interpreter is a [cuecontext.ExternInterpreter] for Wasm files.
-type interpreter struct{}
+type interpreter struct {
+	cache *Cache
+
+	// modules maps file names to the contents of the modules
+	// registered with WithModule.
+	modules map[string][]byte
+}
 
 // New returns a new Wasm interpreter as a [cuecontext.ExternInterpreter]
 // suitable for passing to [cuecontext.New].
-func New() cuecontext.ExternInterpreter {
-	return &interpreter{}
+func New(opts ...Option) cuecontext.ExternInterpreter {
+	i := &interpreter{}
+	for _, o := range opts {
+		o(i)
+	}
+	return i
+}
+
+// An Option configures the interpreter returned by [New].
+type Option func(*interpreter)
+
+// WithCache returns an Option that makes the interpreter store compiled
+// Wasm modules in c. Sharing a Cache between interpreters avoids
+// compiling the same module more than once.
+func WithCache(c *Cache) Option {
+	return func(i *interpreter) {
+		i.cache = c
+	}
+}
+
+// WithModule returns an Option that makes the Wasm module with the given
+// contents available under the given file name, such as "foo.wasm", to
+// all CUE packages, so that the functions it exports can be used with
+// @extern attributes without the module being part of the package.
+// A file of the same name in a package takes precedence over the
+// registered module.
+func WithModule(name string, wasm []byte) Option {
+	return func(i *interpreter) {
+		if i.modules == nil {
+			i.modules = make(map[string][]byte)
+		}
+		i.modules[name] = wasm
+	}
 }
 
 func (i *interpreter) Kind() string {
@@ -44,10 +81,15 @@ func (i *interpreter) Kind() string {
 // NewCompiler returns a Wasm compiler that services the specified
 // build.Instance.
 func (i *interpreter) NewCompiler(b *build.Instance, r *coreruntime.Runtime) (coreruntime.Compiler, errors.Error) {
+	wasmRuntime, err := newRuntime(i.cache)
+	if err != nil {
+		return nil, errors.Promote(err, "wasm")
+	}
 	return &compiler{
 		b:           b,
 		runtime:     r,
-		wasmRuntime: newRuntime(),
+		wasmRuntime: wasmRuntime,
+		modules:     i.modules,
 		instances:   make(map[string]*instance),
 	}, nil
 }
@@ -59,11 +101,14 @@ type compiler struct {
 	runtime     *coreruntime.Runtime
 	wasmRuntime runtime
 
+	// modules holds the modules registered with WithModule.
+	modules map[string][]byte
+
 	// mu serializes access to instances.
 	mu sync.Mutex
 
-	// instances maps absolute file names to compiled Wasm modules
-	// loaded into memory.
+	// instances maps absolute file names, or the names of registered
+	// modules, to compiled Wasm modules loaded into memory.
 	instances map[string]*instance
 }
 
@@ -82,20 +127,29 @@ func (c *compiler) Compile(funcName string, scope adt.Value, a *internal.Attr) (
 	}
 
 	file, found := findFile(baseFile, c.b)
+	var buf []byte
+	if !found {
+		buf, found = c.modules[baseFile]
+		file = baseFile
+	}
 	if !found {
 		return nil, errors.Newf(token.NoPos, "load %q: file not found", baseFile)
 	}
 
-	inst, err := c.instance(file)
+	inst, err := c.instance(file, buf)
 	if err != nil {
 		return nil, errors.Newf(token.NoPos, "can't load Wasm module: %v", err)
 	}
 
+	abi, err := abi(a)
+	if err != nil {
+		return nil, errors.Promote(err, "invalid attribute")
+	}
 	args, err := argList(a)
 	if err != nil {
 		return nil, errors.Newf(token.NoPos, "invalid function signature: %v", err)
 	}
-	builtin, err := generateCallThatReturnsBuiltin(funcName, scope, args, inst)
+	builtin, err := generateCallThatReturnsBuiltin(funcName, scope, args, abi, inst)
 	if err != nil {
 		return nil, errors.Newf(token.NoPos, "can't instantiate function: %v", err)
 	}
@@ -103,13 +157,14 @@ func (c *compiler) Compile(funcName string, scope adt.Value, a *internal.Attr) (
 }
 
 // instance returns the instance corresponding to filename, compiling
-// and loading it if necessary.
-func (c *compiler) instance(filename string) (inst *instance, err error) {
+// and loading it if necessary. If buf is not nil, it holds the contents
+// of the module.
+func (c *compiler) instance(filename string, buf []byte) (inst *instance, err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	inst, ok := c.instances[filename]
 	if !ok {
-		inst, err = c.wasmRuntime.compileAndLoad(filename)
+		inst, err = c.wasmRuntime.compileAndLoad(filename, buf)
 		if err != nil {
 			return nil, err
 		}
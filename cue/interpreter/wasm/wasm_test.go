@@ -16,11 +16,13 @@ package wasm_test
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/format"
@@ -84,3 +86,44 @@ func TestWasm(t *testing.T) {
 		fmt.Fprint(t, string(got))
 	})
 }
+
+// TestCacheAndModule tests sharing a cache between contexts, and using
+// a module registered from Go.
+func TestCacheAndModule(t *testing.T) {
+	basic, err := os.ReadFile("testdata/cue/basic.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := wasm.NewCacheWithDir(t.TempDir())
+	defer cache.Close()
+	if err := cache.Precompile(basic); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Precompile([]byte("not wasm")); err == nil {
+		t.Fatal("expected error precompiling invalid module")
+	}
+
+	const src = `
+@extern("wasm")
+package p
+
+add: _ @extern("basic.wasm", abi=c, sig="func(int64, int64): int64")
+x: add(1, 2)
+`
+	for range 2 {
+		interp := wasm.New(wasm.WithCache(cache), wasm.WithModule("basic.wasm", basic))
+		ctx := cuecontext.New(cuecontext.Interpreter(interp))
+
+		bi := build.NewContext().NewInstance("", nil)
+		bi.AddFile("a.cue", src)
+		bi.Complete()
+
+		x, err := ctx.BuildInstance(bi).LookupPath(cue.ParsePath("x")).Int64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x != 3 {
+			t.Errorf("got %d, want 3", x)
+		}
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -26,6 +27,7 @@ import (
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/mod/modvendor"
 	"cuelang.org/go/mod/modconfig"
 	"cuelang.org/go/mod/modfile"
 	"cuelang.org/go/mod/module"
@@ -185,6 +187,21 @@ type Config struct {
 	// have a @if attribute without or after a package clause.
 	//
 	//
+	// Field selection
+	//
+	// Fields with an attribute of the form @if(expr), at any depth, are
+	// likewise removed from the syntax of the files if expr does not
+	// resolve to true. For instance, the field
+	//
+	//    replicas: 3 @if(prod && !debug)
+	//
+	// is only included if Tags includes "prod" but not "debug". It is an
+	// error for a field to have more than one @if attribute.
+	//
+	// As with files, tags are only considered set for files within the
+	// main module, and fields are not removed if AllCUEFiles is set.
+	//
+	//
 	// Value injection
 	//
 	// The Tags values are also used to inject values into fields with a
@@ -250,6 +267,27 @@ type Config struct {
 	// The [cue/build.Instance.Imports] field will be empty.
 	SkipImports bool
 
+	// LazySyntax defers parsing CUE files beyond their imports until their
+	// syntax is needed. The returned instances have their Files fully
+	// parsed, but the Files of their dependencies hold only the package
+	// clauses and imports until they are built or their
+	// [cue/build.Instance.LoadSyntax] method is called. This can
+	// considerably reduce the cost of loading a package from a large module
+	// when only some of the files of its dependencies are used.
+	//
+	// Config.ParseFile is not used for the partial parses. LazySyntax has
+	// no effect if ExcludeInvalidFiles is set.
+	LazySyntax bool
+
+	// ExcludeInvalidFiles causes CUE files that cannot be parsed to be
+	// excluded from their package instead of making the whole instance
+	// unusable. Such files are recorded in the InvalidFiles field of the
+	// instance, with the parse error as their ExcludeReason, but the error
+	// is not reported in the Err field of the instance. This allows tools
+	// to operate on the remaining files of a package while some of them
+	// are being edited.
+	ExcludeInvalidFiles bool
+
 	// If DataFiles is set, the loader includes entries for directories that
 	// have no CUE files, but have recognized data files that could be converted
 	// to CUE.
@@ -268,11 +306,38 @@ type Config struct {
 	// the syntax tree.
 	ParseFile func(name string, src interface{}) (*ast.File, error)
 
+	// ParseCacheDir, if non-empty, is a directory in which the syntax of
+	// the CUE files read by the loader is cached across invocations, keyed
+	// by a hash of their contents, similar to Go's build cache. Files that
+	// have not changed since they were cached are decoded from the cache,
+	// which is considerably cheaper than parsing them. Entries that have
+	// not been used for several days are removed.
+	//
+	// Only files that parse without errors are cached. If ParseFile is
+	// set, the cache is only used if ParseCacheKey is set as well.
+	ParseCacheDir string
+
+	// ParseCacheKey identifies the behavior of ParseFile for the purpose
+	// of ParseCacheDir. ParseFile functions that may return different
+	// syntax for the same input must use different keys.
+	ParseCacheKey string
+
 	// Overlay provides a mapping of absolute file paths to file contents.  If
 	// the file with the given path already exists, the parser will use the
 	// alternative file contents provided by the map.
 	Overlay map[string]Source
 
+	// FS, if non-nil, is used to read all files and directories instead
+	// of the host filesystem, for example an [embed.FS] or a
+	// [testing/fstest.MapFS]. The root of FS is presented to the rest
+	// of the loader, and in file names and positions, as the absolute
+	// path "/" (or the root of the current volume on Windows).
+	//
+	// When FS is set, Dir is interpreted relative to the root of FS and
+	// defaults to that root. Overlay paths are absolute paths under the
+	// same root.
+	FS fs.FS
+
 	// Stdin defines an alternative for os.Stdin for the file "-". When used,
 	// the corresponding build.File will be associated with the full buffer.
 	Stdin io.Reader
@@ -283,11 +348,44 @@ type Config struct {
 	// registry instance using the variables set in [Config.Env]
 	// as documented in `[cue help registryconfig]`.
 	//
+	// The locations returned by the registry need not be stored in the OS
+	// filesystem: their contents are then presented to the loader under
+	// the cue.mod/mount directory of the main module, as is the case for
+	// [cuelang.org/go/mod/modoci.NewRegistry].
+	//
 	// THIS IS EXPERIMENTAL. API MIGHT CHANGE.
 	//
 	// [cue help registryconfig]: https://cuelang.org/docs/reference/command/cue-help-registryconfig/
 	Registry modconfig.Registry
 
+	// Vendor causes module dependencies to be loaded from the
+	// cue.mod/vendor directory in the module root, as written by
+	// "cue mod vendor", instead of from Registry, which is ignored.
+	// The vendor directory must hold exactly the dependencies listed
+	// in the module file.
+	//
+	// THIS IS EXPERIMENTAL. API MIGHT CHANGE.
+	Vendor bool
+
+	// Workspace holds the path of a cue.work file, relative to Dir if not
+	// absolute. If set, the modules in the directories listed by the file
+	// are used in place of any version of those modules when resolving
+	// dependencies, even if they are not dependencies of the main module
+	// yet. A cue.work file looks like:
+	//
+	//	use: [
+	//		"./frontend",
+	//		"./schemas",
+	//	]
+	//
+	// The directories are relative to the directory of the cue.work file.
+	//
+	// THIS IS EXPERIMENTAL. API MIGHT CHANGE.
+	Workspace string
+
+	// workspace holds the workspace loaded from Workspace, or nil.
+	workspace *workspace
+
 	// Env provides environment variables for use in the configuration.
 	// Currently this is only used in the construction of the Registry
 	// value (see above). If this is nil, the current process's environment
@@ -333,7 +431,15 @@ func addImportQualifier(pkg importPath, name string) (importPath, error) {
 // It does not initialize c.Context, because that requires the
 // loader in order to use for build.Loader.
 func (c Config) complete() (cfg *Config, err error) {
-	if c.Dir == "" {
+	if c.FS != nil {
+		// Dir is interpreted within FS, which is presented
+		// under a fixed absolute root.
+		root, err := fsysRoot()
+		if err != nil {
+			return nil, err
+		}
+		c.Dir = filepath.Join(root, filepath.FromSlash(c.Dir))
+	} else if c.Dir == "" {
 		c.Dir, err = os.Getwd()
 		if err != nil {
 			return nil, err
@@ -370,6 +476,13 @@ func (c Config) complete() (cfg *Config, err error) {
 		// We should never use the registry in SkipImports mode
 		// but nil it out to be sure.
 		c.Registry = nil
+	} else if c.Vendor {
+		vendorDir := filepath.Join(c.ModuleRoot, modDir, modvendor.Dir)
+		if registry, err := modvendor.NewRegistry(fsys.ioFS(vendorDir)); err != nil {
+			c.Registry = errorRegistry{err}
+		} else {
+			c.Registry = registry
+		}
 	} else if c.Registry == nil {
 		registry, err := modconfig.NewRegistry(&modconfig.Config{
 			Env: c.Env,
@@ -385,6 +498,21 @@ func (c Config) complete() (cfg *Config, err error) {
 	if err := c.loadModule(); err != nil {
 		return nil, err
 	}
+	if registry, ok := c.Registry.(*modvendor.Registry); ok && c.modFile != nil {
+		if err := registry.CheckDeps(c.modFile.DepVersions()); err != nil {
+			c.Registry = errorRegistry{err}
+		}
+	}
+	if c.Workspace != "" && c.Registry != nil {
+		if err := c.loadWorkspace(); err != nil {
+			return nil, err
+		}
+		c.Registry = &workspaceRegistry{
+			Registry: c.Registry,
+			fs:       fsys,
+			ws:       c.workspace,
+		}
+	}
 	return &c, nil
 }
 
@@ -32,8 +32,10 @@ import (
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/encoding"
+	"cuelang.org/go/internal/source"
 	"cuelang.org/go/mod/module"
 )
 
@@ -62,6 +64,24 @@ type fileSystem struct {
 	overlayDirs map[string]map[string]*overlayFile
 	cwd         string
 	fileCache   *fileCache
+
+	// fsys, if non-nil, is used instead of the OS filesystem.
+	// Absolute paths are mapped to paths within fsys relative
+	// to fsysRoot.
+	fsys     iofs.FS
+	fsysRoot string
+
+	// mounts holds file systems that are not backed by the OS filesystem,
+	// keyed by the absolute directory at which they are presented.
+	// See [fileSystem.absPathForSourceLoc].
+	mounts map[string]iofs.FS
+
+	// lazy holds Config.LazySyntax, unless Config.ExcludeInvalidFiles
+	// requires all files to be parsed up front.
+	lazy bool
+
+	// excludeInvalid holds Config.ExcludeInvalidFiles.
+	excludeInvalid bool
 }
 
 func (fs *fileSystem) getDir(dir string, create bool) map[string]*overlayFile {
@@ -95,8 +115,18 @@ func (fs *fileSystem) ioFS(root string) iofs.FS {
 
 func newFileSystem(cfg *Config) (*fileSystem, error) {
 	fs := &fileSystem{
-		cwd:         cfg.Dir,
-		overlayDirs: map[string]map[string]*overlayFile{},
+		cwd:            cfg.Dir,
+		overlayDirs:    map[string]map[string]*overlayFile{},
+		lazy:           cfg.LazySyntax && !cfg.ExcludeInvalidFiles,
+		excludeInvalid: cfg.ExcludeInvalidFiles,
+	}
+	if cfg.FS != nil {
+		root, err := fsysRoot()
+		if err != nil {
+			return nil, err
+		}
+		fs.fsys = cfg.FS
+		fs.fsysRoot = root
 	}
 
 	// Organize overlay
@@ -139,6 +169,135 @@ func newFileSystem(cfg *Config) (*fileSystem, error) {
 	return fs, nil
 }
 
+// fsysRoot returns the absolute path at which the root of Config.FS
+// is presented.
+func fsysRoot() (string, error) {
+	return filepath.Abs(string(filepath.Separator))
+}
+
+// fsysPath returns the file system that holds the given absolute path
+// and the path within that file system. It returns a nil file system
+// if path should be read from the OS filesystem.
+func (fs *fileSystem) fsysPath(op, path string) (iofs.FS, string, error) {
+	for dir := path; len(fs.mounts) > 0; {
+		if fsys, ok := fs.mounts[dir]; ok {
+			name, err := fsPathRel(op, dir, path)
+			return fsys, name, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if fs.fsys == nil {
+		return nil, path, nil
+	}
+	name, err := fsPathRel(op, fs.fsysRoot, path)
+	return fs.fsys, name, err
+}
+
+// isVirtual reports whether path is read from a file system other than
+// the OS filesystem.
+func (fs *fileSystem) isVirtual(path string) bool {
+	fsys, _, _ := fs.fsysPath("open", path)
+	return fsys != nil
+}
+
+// fsPathRel returns the io/fs path of the absolute path relative to root.
+func fsPathRel(op, root, path string) (string, error) {
+	name, err := filepath.Rel(root, path)
+	if err == nil {
+		name = filepath.ToSlash(name)
+		if iofs.ValidPath(name) {
+			return name, nil
+		}
+	}
+	return "", &iofs.PathError{Op: op, Path: path, Err: iofs.ErrNotExist}
+}
+
+// absPathForSourceLoc returns the absolute directory holding the contents
+// of loc, the location of module mv. Locations that are not backed by the
+// OS filesystem are mounted under root, so that the rest of the loader can
+// read them like any other directory.
+func (fs *fileSystem) absPathForSourceLoc(root string, mv module.Version, loc module.SourceLoc) (string, error) {
+	if osfs, ok := loc.FS.(module.OSRootFS); ok {
+		osPath := osfs.OSRoot()
+		if osPath == "" {
+			return "", fmt.Errorf("cannot get absolute path for FS of type %T", loc.FS)
+		}
+		return filepath.Join(osPath, loc.Dir), nil
+	}
+	if loc.FS == nil {
+		return "", fmt.Errorf("no file system for module %v", mv)
+	}
+	dir := filepath.Join(root, filepath.FromSlash(mv.String()))
+	if fsys, ok := fs.mounts[dir]; ok && fsys != loc.FS {
+		return "", fmt.Errorf("module %v unexpectedly found in multiple file systems", mv)
+	}
+	if fs.mounts == nil {
+		fs.mounts = map[string]iofs.FS{}
+	}
+	fs.mounts[dir] = loc.FS
+	return filepath.Join(dir, filepath.FromSlash(loc.Dir)), nil
+}
+
+func (fs *fileSystem) osReadDir(path string) ([]iofs.DirEntry, error) {
+	fsys, name, err := fs.fsysPath("readdir", path)
+	if err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return os.ReadDir(path)
+	}
+	return iofs.ReadDir(fsys, name)
+}
+
+func (fs *fileSystem) osStat(path string) (iofs.FileInfo, error) {
+	fsys, name, err := fs.fsysPath("stat", path)
+	if err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return os.Stat(path)
+	}
+	return iofs.Stat(fsys, name)
+}
+
+func (fs *fileSystem) osLstat(path string) (iofs.FileInfo, error) {
+	fsys, _, err := fs.fsysPath("lstat", path)
+	if err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return os.Lstat(path)
+	}
+	// io/fs has no notion of symbolic links.
+	return fs.osStat(path)
+}
+
+func (fs *fileSystem) osOpen(path string) (io.ReadCloser, error) {
+	fsys, name, err := fs.fsysPath("open", path)
+	if err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return os.Open(path)
+	}
+	return fsys.Open(name)
+}
+
+func (fs *fileSystem) osReadFile(path string) ([]byte, error) {
+	fsys, name, err := fs.fsysPath("open", path)
+	if err != nil {
+		return nil, err
+	}
+	if fsys == nil {
+		return os.ReadFile(path)
+	}
+	return iofs.ReadFile(fsys, name)
+}
+
 func (fs *fileSystem) makeAbs(path string) string {
 	if filepath.IsAbs(path) {
 		return path
@@ -149,9 +308,9 @@ func (fs *fileSystem) makeAbs(path string) string {
 func (fs *fileSystem) readDir(path string) ([]iofs.DirEntry, errors.Error) {
 	path = fs.makeAbs(path)
 	m := fs.getDir(path, false)
-	items, err := os.ReadDir(path)
+	items, err := fs.osReadDir(path)
 	if err != nil {
-		if !os.IsNotExist(err) || m == nil {
+		if !errors.Is(err, iofs.ErrNotExist) || m == nil {
 			return nil, errors.Wrapf(err, token.NoPos, "readDir")
 		}
 	}
@@ -189,7 +348,7 @@ func (fs *fileSystem) stat(path string) (iofs.FileInfo, errors.Error) {
 	if fi := fs.getOverlay(path); fi != nil {
 		return fi, nil
 	}
-	fi, err := os.Stat(path)
+	fi, err := fs.osStat(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, token.NoPos, "stat")
 	}
@@ -201,7 +360,7 @@ func (fs *fileSystem) lstat(path string) (iofs.FileInfo, errors.Error) {
 	if fi := fs.getOverlay(path); fi != nil {
 		return fi, nil
 	}
-	fi, err := os.Lstat(path)
+	fi, err := fs.osLstat(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, token.NoPos, "stat")
 	}
@@ -214,7 +373,7 @@ func (fs *fileSystem) openFile(path string) (io.ReadCloser, errors.Error) {
 		return io.NopCloser(bytes.NewReader(fi.contents)), nil
 	}
 
-	f, err := os.Open(path)
+	f, err := fs.osOpen(path)
 	if err != nil {
 		return nil, errors.Wrapf(err, token.NoPos, "load")
 	}
@@ -336,7 +495,7 @@ func (fs *ioFS) ReadFile(name string) ([]byte, error) {
 	if fi := fs.fs.getOverlay(fpath); fi != nil {
 		return bytes.Clone(fi.contents), nil
 	}
-	return os.ReadFile(fpath)
+	return fs.fs.osReadFile(fpath)
 }
 
 var _ module.ReadCUEFS = (*ioFS)(nil)
@@ -345,11 +504,39 @@ var _ module.ReadCUEFS = (*ioFS)(nil)
 // reading and updating the syntax file cache, which
 // is shared with the cache used by the [fileSystem.getCUESyntax]
 // method.
+//
+// If Config.ExcludeInvalidFiles is set, the partial syntax of a file with
+// parse errors is returned without error, so that the imports of the valid
+// files of its package can still be resolved. The loader reports the
+// errors when it excludes the file from its package.
 func (fs *ioFS) ReadCUEFile(path string) (*ast.File, error) {
+	f, err := fs.readCUEFile(path)
+	if err != nil && f != nil && fs.fs.excludeInvalid {
+		return f, nil
+	}
+	return f, err
+}
+
+func (fs *ioFS) readCUEFile(path string) (*ast.File, error) {
 	fpath, err := fs.absPathFromFSPath(path)
 	if err != nil {
 		return nil, err
 	}
+	if fs.fs.lazy {
+		// Only the imports are needed to resolve dependencies.
+		bf := &build.File{
+			Filename: fpath,
+			Encoding: build.CUE,
+		}
+		if fi := fs.fs.getOverlay(fpath); fi != nil {
+			if fi.file != nil {
+				bf.Source = fi.file
+			} else {
+				bf.Source = fi.contents
+			}
+		}
+		return fs.fs.getCUEHeader(bf)
+	}
 	cache := fs.fs.fileCache
 	cache.mu.Lock()
 	entry, ok := cache.entries[fpath]
@@ -366,7 +553,7 @@ func (fs *ioFS) ReadCUEFile(path string) (*ast.File, error) {
 		}
 		data = fi.contents
 	} else {
-		data, err = os.ReadFile(fpath)
+		data, err = fs.fs.osReadFile(fpath)
 		if err != nil {
 			cache.mu.Lock()
 			defer cache.mu.Unlock()
@@ -447,16 +634,95 @@ func (fs *fileSystem) getCUESyntax(bf *build.File) (*ast.File, error) {
 			return syntax.file, syntax.err
 		}
 	}
+	var src []byte
+	if disk := fs.fileCache.disk; useCache && disk != nil {
+		if src = fs.cacheableSource(bf); src != nil {
+			if f := disk.get(bf.Filename, src); f != nil {
+				fs.fileCache.entries[bf.Filename] = fileCacheEntry{f, nil}
+				return f, nil
+			}
+			// Avoid reading the file again, without retaining its
+			// contents in bf.
+			bf1 := *bf
+			bf1.Source = src
+			bf = &bf1
+		}
+	}
 	d := encoding.NewDecoder(fs.fileCache.ctx, bf, &fs.fileCache.config)
 	defer d.Close()
 	// Note: CUE files can never have multiple file parts.
 	f, err := d.File(), d.Err()
+	if src != nil && err == nil {
+		fs.fileCache.disk.put(src, f)
+	}
 	if useCache {
 		fs.fileCache.entries[bf.Filename] = fileCacheEntry{f, err}
 	}
 	return f, err
 }
 
+// cacheableSource returns the contents of bf if its syntax can be stored
+// in the parse cache, or nil otherwise.
+func (fs *fileSystem) cacheableSource(bf *build.File) []byte {
+	var src []byte
+	switch s := bf.Source.(type) {
+	case nil:
+		if bf.Filename == "-" {
+			return nil
+		}
+		b, err := fs.osReadFile(bf.Filename)
+		if err != nil {
+			return nil
+		}
+		src = b
+	case []byte:
+		src = s
+	case string:
+		src = []byte(s)
+	default:
+		return nil
+	}
+	if !cacheable(src) {
+		return nil
+	}
+	return src
+}
+
+// getCUEHeader returns the syntax of bf needed to determine its package,
+// build attributes and imports. If Config.LazySyntax is set, plain CUE
+// files are only parsed up to and including their imports. Otherwise it
+// is equivalent to getCUESyntax.
+func (fs *fileSystem) getCUEHeader(bf *build.File) (*ast.File, error) {
+	if !fs.lazy || bf.Form != "" || bf.Interpretation != "" {
+		return fs.getCUESyntax(bf)
+	}
+	if f, ok := bf.Source.(*ast.File); ok {
+		return f, nil
+	}
+	cache := fs.fileCache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if entry, ok := cache.entries[bf.Filename]; ok {
+		return entry.file, entry.err
+	}
+	if entry, ok := cache.headers[bf.Filename]; ok {
+		return entry.file, entry.err
+	}
+	var data []byte
+	var err error
+	if bf.Source == nil {
+		data, err = fs.osReadFile(bf.Filename)
+	} else {
+		data, err = source.ReadAll(bf.Filename, bf.Source)
+	}
+	var f *ast.File
+	if err == nil {
+		f, err = parser.ParseFile(bf.Filename, data, parser.ImportsOnly)
+	}
+	cache.headers[bf.Filename] = fileCacheEntry{f, err}
+	return f, err
+}
+
 func newFileCache(c *Config) *fileCache {
 	return &fileCache{
 		config: encoding.Config{
@@ -466,6 +732,8 @@ func newFileCache(c *Config) *fileCache {
 		},
 		ctx:     cuecontext.New(),
 		entries: make(map[string]fileCacheEntry),
+		headers: make(map[string]fileCacheEntry),
+		disk:    newParseCache(c),
 	}
 }
 
@@ -475,6 +743,13 @@ type fileCache struct {
 	ctx     *cue.Context
 	mu      sync.Mutex
 	entries map[string]fileCacheEntry
+
+	// disk holds the syntax of files across invocations, or is nil if
+	// Config.ParseCacheDir is not set.
+	disk *parseCache
+
+	// headers caches the partial syntax of files parsed by getCUEHeader.
+	headers map[string]fileCacheEntry
 }
 
 type fileCacheEntry struct {
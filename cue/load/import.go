@@ -27,6 +27,7 @@ import (
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/runtime"
 	"cuelang.org/go/internal/filetypes"
 	"cuelang.org/go/mod/module"
 )
@@ -276,6 +277,12 @@ func setFileSource(cfg *Config, f *build.File) error {
 		} else {
 			f.Source = fi.contents
 		}
+	} else if cfg.fileSystem.isVirtual(fullPath) {
+		b, err := cfg.fileSystem.osReadFile(fullPath)
+		if err != nil {
+			return errors.Wrapf(err, token.NoPos, "load")
+		}
+		f.Source = b
 	}
 	return nil
 }
@@ -293,7 +300,7 @@ func (l *loader) _loadFunc(pos token.Pos, path string) *build.Instance {
 		return l.cfg.newErrInstance(errors.Newf(pos, "relative import paths not allowed (%q)", path))
 	}
 
-	if isStdlibPackage(path) {
+	if isStdlibPackage(path) || runtime.IsBuiltin(path) {
 		// It looks like a builtin.
 		return nil
 	}
@@ -360,12 +367,17 @@ func importPathFromAbsDir(c *Config, absDir string, origPath string) (importPath
 		return "", fmt.Errorf("cannot determine import path for %q (root undefined)", origPath)
 	}
 
-	dir := filepath.Clean(absDir)
-	if !strings.HasPrefix(dir, c.ModuleRoot) {
+	rel, err := filepath.Rel(c.ModuleRoot, filepath.Clean(absDir))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
 		return "", fmt.Errorf("cannot determine import path for %q (dir outside of root)", origPath)
 	}
 
-	pkg := filepath.ToSlash(dir[len(c.ModuleRoot):])
+	// Note: the module root may itself end in a separator, such as when
+	// it is the root of a Config.FS.
+	var pkg string
+	if rel != "." {
+		pkg = "/" + filepath.ToSlash(rel)
+	}
 	switch {
 	case strings.HasPrefix(pkg, "/cue.mod/"):
 		pkg = pkg[len("/cue.mod/"):]
@@ -465,7 +477,7 @@ func (l *loader) absDirFromImportPath1(pos token.Pos, p importPath) (absDir stri
 			return "", "", fmt.Errorf("no location found for package %q", unqualified)
 		}
 		var err error
-		absDir, err = absPathForSourceLoc(locs[0])
+		absDir, err = l.cfg.fileSystem.absPathForSourceLoc(mountPath(l.cfg.ModuleRoot), pkg.Mod(), locs[0])
 		if err != nil {
 			return "", "", fmt.Errorf("cannot determine source directory for package %q: %v", unqualified, err)
 		}
@@ -473,16 +485,10 @@ func (l *loader) absDirFromImportPath1(pos token.Pos, p importPath) (absDir stri
 	return absDir, pkg.Mod().Path(), nil
 }
 
-func absPathForSourceLoc(loc module.SourceLoc) (string, error) {
-	osfs, ok := loc.FS.(module.OSRootFS)
-	if !ok {
-		return "", fmt.Errorf("cannot get absolute path for FS of type %T", loc.FS)
-	}
-	osPath := osfs.OSRoot()
-	if osPath == "" {
-		return "", fmt.Errorf("cannot get absolute path for FS of type %T", loc.FS)
-	}
-	return filepath.Join(osPath, loc.Dir), nil
+// mountPath returns the directory under which module contents that are
+// not stored in the OS filesystem are presented to the loader.
+func mountPath(root string) string {
+	return filepath.Join(root, modDir, "mount")
 }
 
 // isStdlibPackage reports whether pkgPath looks like
@@ -24,6 +24,7 @@ import (
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
+	"cuelang.org/go/internal/core/runtime"
 	"cuelang.org/go/internal/filetypes"
 	"cuelang.org/go/internal/mod/modimports"
 	"cuelang.org/go/internal/mod/modpkgload"
@@ -129,6 +130,9 @@ func Instances(args []string, c *Config) []*build.Instance {
 	}
 
 	for _, p := range a {
+		// Tags may be set anywhere in the requested instances,
+		// so these always need their complete syntax.
+		_ = p.LoadSyntax()
 		tags, err := findTags(p)
 		if err != nil {
 			p.ReportError(err)
@@ -180,8 +184,8 @@ func loadPackages(
 	reqs := modrequirements.NewRequirements(
 		mainModPath,
 		cfg.Registry,
-		cfg.modFile.DepVersions(),
-		cfg.modFile.DefaultMajorVersions(),
+		cfg.workspace.rootModules(cfg.modFile.DepVersions()),
+		cfg.workspace.defaultMajorVersions(cfg.modFile.DefaultMajorVersions()),
 	)
 	mainModLoc := module.SourceLoc{
 		FS:  cfg.fileSystem.ioFS(cfg.ModuleRoot),
@@ -208,6 +212,9 @@ func loadPackages(
 				// Should never happen.
 				return nil, fmt.Errorf("invalid import path %q in %s", imp.Path.Value, f.Filename)
 			}
+			if runtime.IsBuiltin(pkgPath) {
+				continue
+			}
 			// Canonicalize the path.
 			pkgPath = module.ParseImportPath(pkgPath).Canonical().String()
 			pkgPaths[pkgPath] = true
@@ -22,9 +22,11 @@ package load
 import (
 	"path/filepath"
 
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/buildattr"
 	"cuelang.org/go/internal/mod/modpkgload"
 )
 
@@ -130,12 +132,39 @@ func (l *loader) cueFilesPackage(files []*build.File) *build.Instance {
 }
 
 // addFiles populates p.Files by reading CUE syntax from p.BuildFiles.
+// If Config.LazySyntax is set, only the headers of the files are added,
+// and the rest of their syntax is read when needed.
 func (l *loader) addFiles(p *build.Instance) {
+	fs := l.cfg.fileSystem
+	parse := func(bf *build.File) (*ast.File, error) {
+		return l.parseFile(p, bf)
+	}
+	if l.cfg.LazySyntax {
+		p.SetLazySyntax(parse)
+		parse = fs.getCUEHeader
+	}
 	for _, bf := range p.BuildFiles {
-		f, err := l.cfg.fileSystem.getCUESyntax(bf)
+		f, err := parse(bf)
 		if err != nil {
 			p.ReportError(errors.Promote(err, "load"))
 		}
 		_ = p.AddSyntax(f)
 	}
 }
+
+// parseFile returns the complete syntax of bf, a build file of p, without
+// the fields excluded by their @if attributes.
+func (l *loader) parseFile(p *build.Instance, bf *build.File) (*ast.File, error) {
+	f, err := l.cfg.fileSystem.getCUESyntax(bf)
+	if f == nil || l.cfg.AllCUEFiles {
+		return f, err
+	}
+	ferr := buildattr.FilterDecls(f, l.tagger.tagIsSetFor(p))
+	switch {
+	case ferr == nil:
+		return f, err
+	case err == nil:
+		return f, ferr
+	}
+	return f, errors.Append(errors.Promote(err, ""), ferr)
+}
@@ -216,9 +216,15 @@ func (fp *fileProcessor) add(root string, file *build.File, mode importMode) {
 	// Note: when path is "-" (stdin), it will already have
 	// been read and file.Source set to the resulting data
 	// by setFileSource.
-	pf, perr := fp.c.fileSystem.getCUESyntax(file)
+	pf, perr := fp.c.fileSystem.getCUEHeader(file)
 	if perr != nil {
-		badFile(errors.Promote(perr, "add failed"))
+		err := errors.Promote(perr, "add failed")
+		if fp.c.ExcludeInvalidFiles {
+			file.ExcludeReason = err
+			p.InvalidFiles = append(p.InvalidFiles, file)
+			return
+		}
+		badFile(err)
 		return
 	}
 
@@ -265,17 +271,7 @@ func (fp *fileProcessor) add(root string, file *build.File, mode importMode) {
 	}
 
 	if !fp.c.AllCUEFiles {
-		tagIsSet := fp.tagger.tagIsSet
-		if p.Module != "" && p.Module != fp.c.Module {
-			// The file is outside the main module so treat all build tag keys as unset.
-			// Note that if there's no module, we don't consider it to be outside
-			// the main module, because otherwise @if tags in non-package files
-			// explicitly specified on the command line will not work.
-			tagIsSet = func(string) bool {
-				return false
-			}
-		}
-		if err := shouldBuildFile(pf, tagIsSet); err != nil {
+		if err := shouldBuildFile(pf, fp.tagger.tagIsSetFor(p)); err != nil {
 			if !errors.Is(err, errExclude) {
 				fp.err = errors.Append(fp.err, err)
 			}
@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"testing/fstest"
 	"text/template"
 	"unicode"
 
@@ -71,11 +72,11 @@ func TestLoad(t *testing.T) {
 		want: `err:    module: 2 errors in empty disjunction:
 module: conflicting values 123 and "" (mismatched types int and string):
     $CWD/testdata/badmod/cue.mod/module.cue:2:9
-    cuelang.org/go/mod/modfile/schema.cue:56:22
+    cuelang.org/go/mod/modfile/schema.cue:63:22
 module: conflicting values 123 and string (mismatched types int and string):
     $CWD/testdata/badmod/cue.mod/module.cue:2:9
-    cuelang.org/go/mod/modfile/schema.cue:56:12
-    cuelang.org/go/mod/modfile/schema.cue:98:12
+    cuelang.org/go/mod/modfile/schema.cue:63:12
+    cuelang.org/go/mod/modfile/schema.cue:111:12
 path:   ""
 module: ""
 root:   ""
@@ -751,6 +752,238 @@ func TestOverlays(t *testing.T) {
 	}
 }
 
+func TestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cue.mod/module.cue": {Data: []byte(`module: "mod.test", language: version: "v0.9.0"`)},
+		"dir/top.cue": {Data: []byte(`
+			package top
+
+			import "mod.test/dir/b:foo"
+
+			msg: "Hello"
+			a:   foo.a
+		`)},
+		"dir/b/foo.cue": {Data: []byte(`
+			package foo
+
+			a: <= 5
+		`)},
+		"dir/b/bar.cue": {Data: []byte(`
+			package foo
+
+			a: >= 5
+		`)},
+	}
+	c := &Config{
+		FS:  fsys,
+		Dir: "dir",
+	}
+	ctx := cuecontext.New()
+	insts := Instances([]string{"."}, c)
+	qt.Assert(t, qt.HasLen(insts, 1))
+	inst := insts[0]
+	qt.Assert(t, qt.IsNil(inst.Err))
+
+	root, err := fsysRoot()
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(inst.Root, root))
+	qt.Assert(t, qt.Equals(inst.Dir, filepath.Join(root, "dir")))
+
+	v := ctx.BuildInstance(inst)
+	qt.Assert(t, qt.IsNil(v.Err()))
+	b, err := format.Node(v.Syntax(cue.Final()))
+	qt.Assert(t, qt.IsNil(err))
+	rmSpace := func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}
+	qt.Assert(t, qt.Equals(string(bytes.Map(rmSpace, b)), `{msg:"Hello"a:5}`))
+
+	// Files outside of FS are not found, even if they exist on disk.
+	insts = Instances([]string{"./b"}, &Config{FS: fsys})
+	qt.Assert(t, qt.ErrorMatches(insts[0].Err, `.*cannot find package.*`))
+}
+
+func TestLazySyntax(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cue.mod/module.cue": {Data: []byte(`module: "mod.test", language: version: "v0.9.0"`)},
+		"main/main.cue": {Data: []byte(`
+			package main
+
+			import "mod.test/dep"
+
+			a: dep.b
+		`)},
+		"dep/dep.cue": {Data: []byte(`
+			package dep
+
+			import "strings"
+
+			b: strings.ToUpper("x")
+		`)},
+		"bad/bad.cue": {Data: []byte(`
+			package bad
+
+			b: 1 +
+		`)},
+	}
+	insts := Instances([]string{"./main"}, &Config{
+		FS:         fsys,
+		LazySyntax: true,
+	})
+	qt.Assert(t, qt.HasLen(insts, 1))
+	inst := insts[0]
+	qt.Assert(t, qt.IsNil(inst.Err))
+	qt.Assert(t, qt.HasLen(inst.Files[0].Decls, 3))
+
+	// Only the package clause and imports of dependencies are parsed.
+	qt.Assert(t, qt.HasLen(inst.Imports, 1))
+	dep := inst.Imports[0]
+	qt.Assert(t, qt.Equals(dep.ImportPath, "mod.test/dep"))
+	qt.Assert(t, qt.HasLen(dep.Files[0].Decls, 2))
+
+	v := cuecontext.New().BuildInstance(inst)
+	qt.Assert(t, qt.IsNil(v.Err()))
+	s, err := v.LookupPath(cue.ParsePath("a")).String()
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(s, "X"))
+	qt.Assert(t, qt.HasLen(dep.Files[0].Decls, 3))
+
+	// The requested instances are always parsed completely.
+	insts = Instances([]string{"./bad"}, &Config{
+		FS:         fsys,
+		LazySyntax: true,
+	})
+	qt.Assert(t, qt.ErrorMatches(insts[0].Err, `.*expected operand.*`))
+}
+
+func TestParseCache(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cue.mod/module.cue": {Data: []byte(`module: "mod.test", language: version: "v0.9.0"`)},
+		"main/main.cue": {Data: []byte(`
+			// Package main.
+			package main
+
+			import "mod.test/dep"
+
+			a: dep.b // line comment
+		`)},
+		"dep/dep.cue": {Data: []byte(`
+			package dep
+
+			import "strings"
+
+			b: strings.ToUpper("x")
+		`)},
+		"bad/bad.cue": {Data: []byte(`
+			package bad
+
+			b: 1 +
+		`)},
+	}
+	dir := t.TempDir()
+	entries := func() []string {
+		files, err := filepath.Glob(filepath.Join(dir, "??", "*"))
+		qt.Assert(t, qt.IsNil(err))
+		return files
+	}
+	load := func(arg string) string {
+		insts := Instances([]string{arg}, &Config{
+			FS:            fsys,
+			ParseCacheDir: dir,
+		})
+		qt.Assert(t, qt.HasLen(insts, 1))
+		if err := insts[0].Err; err != nil {
+			return errors.Details(err, nil)
+		}
+		v := cuecontext.New().BuildInstance(insts[0])
+		qt.Assert(t, qt.IsNil(v.Err()))
+		var buf bytes.Buffer
+		for _, p := range append(insts[0].Dependencies(), insts[0]) {
+			for _, f := range p.Files {
+				b, err := format.Node(f)
+				qt.Assert(t, qt.IsNil(err))
+				fmt.Fprintf(&buf, "%s %v\n%s", f.Filename, f.Decls[len(f.Decls)-1].Pos(), b)
+			}
+		}
+		fmt.Fprint(&buf, v.LookupPath(cue.ParsePath("a")))
+		return buf.String()
+	}
+
+	want := load("./main")
+	qt.Assert(t, qt.HasLen(entries(), 2))
+
+	// Loading again decodes both files from the cache.
+	qt.Assert(t, qt.Equals(load("./main"), want))
+	qt.Assert(t, qt.HasLen(entries(), 2))
+
+	// Files with errors are not cached.
+	qt.Assert(t, qt.Matches(load("./bad"), `(?s).*expected operand.*`))
+	qt.Assert(t, qt.HasLen(entries(), 2))
+
+	// A changed file is parsed again.
+	fsys["dep/dep.cue"] = &fstest.MapFile{Data: []byte("package dep\n\nb: \"y\"\n")}
+	qt.Assert(t, qt.Not(qt.Equals(load("./main"), want)))
+	qt.Assert(t, qt.HasLen(entries(), 3))
+}
+
+func TestExcludeInvalidFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cue.mod/module.cue": {Data: []byte(`module: "mod.test", language: version: "v0.9.0"`)},
+		"main/main.cue": {Data: []byte(`
+			package main
+
+			import "mod.test/dep"
+
+			a: dep.b
+		`)},
+		"main/broken.cue": {Data: []byte(`
+			package main
+
+			c: 1 +
+		`)},
+		"dep/dep.cue": {Data: []byte(`
+			package dep
+
+			b: "x"
+		`)},
+		"dep/broken.cue": {Data: []byte(`
+			package dep
+
+			b: {
+		`)},
+	}
+	insts := Instances([]string{"./main"}, &Config{FS: fsys})
+	qt.Assert(t, qt.ErrorMatches(insts[0].Err, `.*expected operand.*`))
+
+	// LazySyntax does not prevent errors from being detected.
+	insts = Instances([]string{"./main"}, &Config{
+		FS:                  fsys,
+		LazySyntax:          true,
+		ExcludeInvalidFiles: true,
+	})
+	qt.Assert(t, qt.HasLen(insts, 1))
+	inst := insts[0]
+	qt.Assert(t, qt.IsNil(inst.Err))
+	qt.Assert(t, qt.HasLen(inst.BuildFiles, 1))
+	qt.Assert(t, qt.HasLen(inst.InvalidFiles, 1))
+	qt.Assert(t, qt.Equals(filepath.Base(inst.InvalidFiles[0].Filename), "broken.cue"))
+	qt.Assert(t, qt.ErrorMatches(inst.InvalidFiles[0].ExcludeReason, `.*expected operand.*`))
+
+	qt.Assert(t, qt.HasLen(inst.Imports, 1))
+	dep := inst.Imports[0]
+	qt.Assert(t, qt.IsNil(dep.Err))
+	qt.Assert(t, qt.HasLen(dep.InvalidFiles, 1))
+
+	v := cuecontext.New().BuildInstance(inst)
+	qt.Assert(t, qt.IsNil(v.Err()))
+	s, err := v.LookupPath(cue.ParsePath("a")).String()
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(s, "x"))
+}
+
 func TestLoadOrder(t *testing.T) {
 	testDir := t.TempDir()
 	letters := "abcdefghij"
@@ -808,3 +1041,41 @@ func race(t *testing.T, f func() error) {
 	}
 	wg.Wait()
 }
+
+func TestWorkspace(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cue.work":               {Data: []byte(`use: ["./app", "./lib"]`)},
+		"app/cue.mod/module.cue": {Data: []byte(`module: "app.test", language: version: "v0.9.0"`)},
+		"app/app.cue": {Data: []byte(`
+			package app
+
+			import "lib.test/x"
+
+			a: x.b
+		`)},
+		"lib/cue.mod/module.cue": {Data: []byte(`module: "lib.test", language: version: "v0.9.0"`)},
+		"lib/x/x.cue": {Data: []byte(`
+			package x
+
+			b: "from workspace"
+		`)},
+	}
+	insts := Instances([]string{"."}, &Config{
+		FS:        fsys,
+		Dir:       "app",
+		Workspace: "../cue.work",
+	})
+	qt.Assert(t, qt.HasLen(insts, 1))
+	qt.Assert(t, qt.IsNil(insts[0].Err))
+	v := cuecontext.New().BuildInstance(insts[0])
+	s, err := v.LookupPath(cue.ParsePath("a")).String()
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(s, "from workspace"))
+
+	// Without the workspace, the dependency cannot be found.
+	insts = Instances([]string{"."}, &Config{
+		FS:  fsys,
+		Dir: "app",
+	})
+	qt.Assert(t, qt.ErrorMatches(insts[0].Err, `.*cannot find package "lib.test/x".*`))
+}
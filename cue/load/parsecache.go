@@ -0,0 +1,180 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package load
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unicode/utf8"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/internal/astcodec"
+	"cuelang.org/go/internal/cueversion"
+)
+
+const (
+	// parseCacheRefresh is how often the modification time of an entry
+	// that is used is updated, so that it is not trimmed.
+	parseCacheRefresh = time.Hour
+
+	// parseCacheTrimInterval is how often the cache is trimmed.
+	parseCacheTrimInterval = 24 * time.Hour
+
+	// parseCacheTrimLimit is how long an entry may remain unused
+	// before it is removed when the cache is trimmed.
+	parseCacheTrimLimit = 5 * 24 * time.Hour
+)
+
+// A parseCache stores the syntax of CUE files in Config.ParseCacheDir,
+// keyed by a hash of their contents, so that files which have not changed
+// need not be parsed again by later invocations. See Config.ParseCacheDir.
+//
+// An entry is a file named after its key in a subdirectory named after
+// the first two characters of the key, holding the syntax encoded by
+// package astcodec. Failures to read or write the cache are ignored.
+type parseCache struct {
+	dir  string
+	salt []byte // hash of the parser configuration and CUE version
+}
+
+// newParseCache returns the parse cache for cfg, or nil if the cache
+// is not enabled.
+func newParseCache(cfg *Config) *parseCache {
+	if cfg.ParseCacheDir == "" || (cfg.ParseFile != nil && cfg.ParseCacheKey == "") {
+		return nil
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "cue load syntax %d %s %q\n",
+		astcodec.Version, cueversion.ModuleVersion(), cfg.ParseCacheKey)
+	c := &parseCache{
+		dir:  cfg.ParseCacheDir,
+		salt: h.Sum(nil),
+	}
+	c.trim()
+	return c
+}
+
+// cacheable reports whether the syntax of a file with the given contents
+// can be cached. The positions of a file decoded from the cache are
+// derived from the contents as they are passed to the parser, so files
+// that are transformed before parsing, which is the case for files that
+// start with a byte order mark or that are not valid UTF-8, are not
+// cached. Neither are files with //line directives, as these are not
+// preserved by the encoding.
+func cacheable(src []byte) bool {
+	return utf8.Valid(src) &&
+		!bytes.HasPrefix(src, []byte("\ufeff")) &&
+		!bytes.Contains(src, []byte("//line "))
+}
+
+func (c *parseCache) path(src []byte) string {
+	h := sha256.New()
+	h.Write(c.salt)
+	h.Write(src)
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+// get returns the syntax of the file with the given name and contents
+// if it is in the cache, or nil otherwise.
+func (c *parseCache) get(filename string, src []byte) *ast.File {
+	path := c.path(src)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	f, err := astcodec.Decode(filename, src, data)
+	if err != nil {
+		return nil
+	}
+	if fi, err := os.Stat(path); err == nil && time.Since(fi.ModTime()) > parseCacheRefresh {
+		now := time.Now()
+		_ = os.Chtimes(path, now, now)
+	}
+	return f
+}
+
+// put stores f, the syntax parsed from src, in the cache.
+func (c *parseCache) put(src []byte, f *ast.File) {
+	data, err := astcodec.Encode(f)
+	if err != nil {
+		return
+	}
+	path := c.path(src)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return
+	}
+	// Write to a temporary file first, so that concurrent readers never
+	// see a partially written entry.
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// trim removes the entries that have not been used for parseCacheTrimLimit,
+// unless the cache was already trimmed within the last parseCacheTrimInterval.
+// The time of the last trim is recorded as the modification time of the
+// file trim.txt.
+func (c *parseCache) trim() {
+	marker := filepath.Join(c.dir, "trim.txt")
+	now := time.Now()
+	if fi, err := os.Stat(marker); err == nil && now.Sub(fi.ModTime()) < parseCacheTrimInterval {
+		return
+	} else if err != nil && !os.IsNotExist(err) {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o777); err != nil {
+		return
+	}
+	if err := os.WriteFile(marker, nil, 0o666); err != nil {
+		return
+	}
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, d := range dirs {
+		if !d.IsDir() || len(d.Name()) != 2 {
+			continue
+		}
+		sub := filepath.Join(c.dir, d.Name())
+		entries, err := os.ReadDir(sub)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if fi, err := e.Info(); err == nil && now.Sub(fi.ModTime()) > parseCacheTrimLimit {
+				os.Remove(filepath.Join(sub, e.Name()))
+			}
+		}
+	}
+}
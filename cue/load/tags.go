@@ -73,6 +73,21 @@ func (tg *tagger) tagIsSet(key string) bool {
 	return tg.tagMap[key]
 }
 
+// tagIsSetFor returns the function to use to evaluate @if attributes in
+// the files of p. Tags are only considered set within the main module.
+func (tg *tagger) tagIsSetFor(p *build.Instance) func(key string) bool {
+	if p.Module != "" && p.Module != tg.cfg.Module {
+		// The file is outside the main module so treat all build tag keys as unset.
+		// Note that if there's no module, we don't consider it to be outside
+		// the main module, because otherwise @if tags in non-package files
+		// explicitly specified on the command line will not work.
+		return func(string) bool {
+			return false
+		}
+	}
+	return tg.tagIsSet
+}
+
 // A TagVar represents an injection variable.
 type TagVar struct {
 	// Func returns an ast for a tag variable. It is only called once
@@ -44,9 +44,10 @@ func TestTags(t *testing.T) {
 	dir := t.TempDir()
 
 	testCases := []struct {
-		in  string
-		out string
-		err string
+		in   string
+		tags []string
+		out  string
+		err  string
 	}{{
 		in: `
 		rand: int    @tag(foo,var=rand)
@@ -84,6 +85,26 @@ func TestTags(t *testing.T) {
 		u1: string @tag(bar,var=user)
 		`,
 		err: `tag variable 'user' not found`,
+	}, {
+		// Fields are selected by their @if attributes.
+		in: `
+		a: 1 @if(prod)
+		b: 2 @if(!prod)
+		c: {
+			d: 3 @if(prod && debug)
+			e: 4 @if(prod || debug)
+		}
+		`,
+		tags: []string{"prod"},
+		out: `{
+			a: 1
+			c: e: 4
+		}`,
+	}, {
+		in: `
+		a: 1 @if(prod) @if(debug)
+		`,
+		err: `multiple @if attributes (and 1 more errors)`,
 	}}
 
 	for _, tc := range testCases {
@@ -94,6 +115,7 @@ func TestTags(t *testing.T) {
 					filepath.Join(dir, "foo.cue"): FromString(tc.in),
 				},
 				TagVars: testTagVars,
+				Tags:    tc.tags,
 			}
 			b := Instances([]string{"foo.cue"}, cfg)[0]
 
@@ -0,0 +1,170 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package load
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/module"
+)
+
+// workSchema is the schema of a cue.work file.
+const workSchema = `close({
+	use?: [...string]
+})`
+
+// A workspace holds the local modules used in place of dependencies.
+type workspace struct {
+	// modules maps qualified module paths to the modules in the
+	// workspace, excluding the main module.
+	modules map[string]*workspaceModule
+}
+
+type workspaceModule struct {
+	dir  string // absolute directory
+	file *modfile.File
+}
+
+// loadWorkspace reads the workspace file at c.Workspace and sets
+// c.workspace.
+func (c *Config) loadWorkspace() error {
+	workFile := c.Workspace
+	if !filepath.IsAbs(workFile) {
+		workFile = filepath.Join(c.Dir, workFile)
+	}
+	f, cerr := c.fileSystem.openFile(workFile)
+	if cerr != nil {
+		return cerr
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	ctx := cuecontext.New()
+	v := ctx.CompileBytes(data, cue.Filename(workFile))
+	v = ctx.CompileString(workSchema).Unify(v)
+	var work struct {
+		Use []string `json:"use"`
+	}
+	if err := v.Decode(&work); err != nil {
+		return err
+	}
+
+	ws := &workspace{modules: make(map[string]*workspaceModule)}
+	mainModule := ""
+	if c.modFile != nil {
+		mainModule = c.modFile.QualifiedModule()
+	}
+	for _, use := range work.Use {
+		if filepath.IsAbs(use) || !isLocalImport(filepath.ToSlash(use)) {
+			return fmt.Errorf("%s: module directory %q must be a relative path starting with ./ or ../", workFile, use)
+		}
+		dir := filepath.Join(filepath.Dir(workFile), filepath.FromSlash(use))
+		modFile := filepath.Join(dir, modDir, moduleFile)
+		f, cerr := c.fileSystem.openFile(modFile)
+		if cerr != nil {
+			return fmt.Errorf("%s: cannot use %q: %v", workFile, use, cerr)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		mf, err := modfile.ParseNonStrict(data, modFile)
+		if err != nil {
+			return err
+		}
+		mpath := mf.QualifiedModule()
+		if mpath == mainModule {
+			continue
+		}
+		if m, ok := ws.modules[mpath]; ok {
+			return fmt.Errorf("%s: module %s is used in both %s and %s", workFile, mpath, m.dir, dir)
+		}
+		ws.modules[mpath] = &workspaceModule{dir: dir, file: mf}
+	}
+	c.workspace = ws
+	return nil
+}
+
+// rootModules adds the modules in the workspace to the given root
+// module versions of the main module. Modules that are not yet
+// dependencies of the main module are given the zero version of their
+// major version, which is replaced by the workspace module.
+func (ws *workspace) rootModules(deps []module.Version) []module.Version {
+	if ws == nil {
+		return deps
+	}
+	roots := slices.Clone(deps)
+	for mpath, m := range ws.modules {
+		if !slices.ContainsFunc(deps, func(mv module.Version) bool { return mv.Path() == mpath }) {
+			roots = append(roots, module.MustNewVersion(mpath, m.file.MajorVersion()+".0.0"))
+		}
+	}
+	module.Sort(roots)
+	return roots
+}
+
+// defaultMajorVersions adds the major versions of the modules in the
+// workspace to the default major versions of the main module, for
+// those modules that do not have one yet.
+func (ws *workspace) defaultMajorVersions(defaults map[string]string) map[string]string {
+	if ws == nil {
+		return defaults
+	}
+	result := make(map[string]string)
+	for _, m := range ws.modules {
+		result[m.file.ModulePath()] = m.file.MajorVersion()
+	}
+	for p, v := range defaults {
+		result[p] = v
+	}
+	return result
+}
+
+// workspaceRegistry implements [modconfig.Registry] by serving modules in
+// the workspace from their directories, regardless of the version that is
+// requested, and deferring to the underlying registry for other modules.
+type workspaceRegistry struct {
+	modconfig.Registry
+	fs *fileSystem
+	ws *workspace
+}
+
+func (r *workspaceRegistry) Requirements(ctx context.Context, mv module.Version) ([]module.Version, error) {
+	if m, ok := r.ws.modules[mv.Path()]; ok {
+		return m.file.DepVersions(), nil
+	}
+	return r.Registry.Requirements(ctx, mv)
+}
+
+func (r *workspaceRegistry) Fetch(ctx context.Context, mv module.Version) (module.SourceLoc, error) {
+	if m, ok := r.ws.modules[mv.Path()]; ok {
+		return module.SourceLoc{
+			FS:  r.fs.ioFS(m.dir),
+			Dir: ".",
+		}, nil
+	}
+	return r.Registry.Fetch(ctx, mv)
+}
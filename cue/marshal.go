@@ -29,6 +29,7 @@ import (
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
 	"cuelang.org/go/internal/core/export"
+	"cuelang.org/go/internal/core/runtime"
 )
 
 // root.
@@ -98,6 +99,64 @@ func compileInstances(r *Runtime, data []*instanceData) (instances []*Instance,
 // Unmarshal returns a slice of instances from bytes generated by
 // [Runtime.Marshal].
 func (r *Runtime) Unmarshal(b []byte) ([]*Instance, error) {
+	data, err := unmarshalData(b)
+	if err != nil {
+		return nil, err
+	}
+	return compileInstances(r, data)
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler]. It sets v to
+// the value encoded by [Value.MarshalBinary] or [Runtime.Marshal] of a
+// single value.
+//
+// The value is compiled in the context of v. Values can only be combined
+// with values of the same context, so v should typically be set to any
+// value of the context to use, such as the result of
+// [Context.CompileString] on "_", beforehand. If v has no context, a new
+// context is used.
+func (v *Value) UnmarshalBinary(b []byte) error {
+	data, err := unmarshalData(b)
+	if err != nil {
+		return err
+	}
+	var root *instanceData
+	for _, i := range data {
+		if i.Root {
+			if root != nil {
+				return errors.Newf(token.NoPos,
+					"unmarshal failed: data contains more than one value")
+			}
+			root = i
+		}
+	}
+	if root == nil {
+		return errors.Newf(token.NoPos, "unmarshal failed: data contains no value")
+	}
+
+	r := v.idx
+	if r == nil {
+		r = runtime.New()
+	}
+	b2 := unmarshaller{
+		ctxt:    build.NewContext(),
+		imports: map[string]*instanceData{},
+	}
+	for _, i := range data {
+		if i.Path != "" {
+			b2.imports[i.Path] = i
+		}
+	}
+	x := (*Context)(r).BuildInstance(b2.build(root))
+	if err := x.Err(); err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// unmarshalData decodes the instances encoded by [Runtime.Marshal].
+func unmarshalData(b []byte) ([]*instanceData, error) {
 	if len(b) == 0 {
 		return nil, errors.Newf(token.NoPos, "unmarshal failed: empty buffer")
 	}
@@ -119,8 +178,7 @@ func (r *Runtime) Unmarshal(b []byte) ([]*Instance, error) {
 	if err != nil {
 		return nil, errors.Newf(token.NoPos, "unmarshal failed: %v", err)
 	}
-
-	return compileInstances(r, data)
+	return data, nil
 }
 
 // Marshal creates bytes from a group of instances. Imported instances will
@@ -129,6 +187,34 @@ func (r *Runtime) Unmarshal(b []byte) ([]*Instance, error) {
 // The stored instances are functionally the same, but preserving of file
 // information is only done on a best-effort basis.
 func (r *Runtime) Marshal(values ...InstanceOrValue) (b []byte, err error) {
+	vals := make([]Value, len(values))
+	for i, v := range values {
+		vals[i] = v.Value()
+	}
+	return marshal(r.runtime(), vals)
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. It encodes v, along
+// with the non-builtin packages it imports, so that it can be restored with
+// [Value.UnmarshalBinary], possibly by another process. This allows
+// caching the values of large configurations, as restoring a value does
+// not require loading and compiling the packages it was built from again.
+//
+// The value is encoded as CUE, as with [Value.Syntax], and is evaluated
+// again when it is restored. The encoding does not depend on the internal
+// representation of values, so data written by one version of CUE can be
+// read by later ones. As with [Runtime.Marshal], the restored value is
+// functionally the same as v, but positions and comments are not
+// preserved.
+func (v Value) MarshalBinary() ([]byte, error) {
+	if v.v == nil {
+		return nil, errors.Newf(token.NoPos, "marshal failed: value does not exist")
+	}
+	return marshal(v.idx, []Value{v})
+}
+
+// marshal encodes values along with the packages they import.
+func marshal(r *runtime.Runtime, values []Value) (b []byte, err error) {
 	staged := []instanceData{}
 	done := map[string]int{}
 
@@ -137,11 +223,15 @@ func (r *Runtime) Marshal(values ...InstanceOrValue) (b []byte, err error) {
 	var stageInstance func(i Value) (pos int)
 	stageInstance = func(i Value) (pos int) {
 		inst := i.BuildInstance()
-		if p, ok := done[inst.ImportPath]; ok {
+		if inst == nil {
+			// i is not the root of a package: encode it as a package of
+			// its own.
+			inst = &build.Instance{}
+		} else if p, ok := done[inst.ImportPath]; ok {
 			return p
 		}
 		// TODO: support exporting instance
-		file, _ := export.Def(r.runtime(), inst.ID(), i.instance().root)
+		file, _ := export.Def(r, i.instance().ID(), i.v)
 		imports := []string{}
 		file.VisitImports(func(i *ast.ImportDecl) {
 			for _, spec := range i.Specs {
@@ -179,7 +269,10 @@ func (r *Runtime) Marshal(values ...InstanceOrValue) (b []byte, err error) {
 		}
 		// TODO: this should probably be changed upstream, but as the path
 		// is for reference purposes only, this is safe.
-		importPath := filepath.ToSlash(i.instance().ImportPath)
+		importPath := ""
+		if inst := i.instance(); inst != nil {
+			importPath = filepath.ToSlash(inst.ImportPath)
+		}
 
 		staged = append(staged, instanceData{
 			Path:  importPath,
@@ -189,7 +282,7 @@ func (r *Runtime) Marshal(values ...InstanceOrValue) (b []byte, err error) {
 		p := len(staged) - 1
 
 		for _, imp := range imports {
-			i := getImportFromPath(r.runtime(), imp)
+			i := getImportFromPath(r, imp)
 			if i == nil || !strings.Contains(imp, ".") {
 				continue // a builtin package.
 			}
@@ -200,7 +293,7 @@ func (r *Runtime) Marshal(values ...InstanceOrValue) (b []byte, err error) {
 	}
 
 	for _, val := range values {
-		staged[stageInstance(val.Value())].Root = true
+		staged[stageInstance(val)].Root = true
 	}
 
 	buf := &bytes.Buffer{}
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+
+	"cuelang.org/go/internal/core/runtime"
 )
 
 func TestMarshalling(t *testing.T) {
@@ -207,3 +209,99 @@ func TestMarshalMultiPackage(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalBinary(t *testing.T) {
+	pkg := &instanceData{false, "mod.test/foo/pkg", []fileData{{"pkg.cue", []byte(`
+		package pkg
+
+		#Port: int & >0 & <65536
+		`)}}}
+	root := &instanceData{true, "mod.test/foo", []fileData{{"foo.cue", []byte(`
+		package foo
+
+		import (
+			"strings"
+			"mod.test/foo/pkg"
+		)
+
+		_name: "web"
+		service: {
+			name: strings.ToUpper(_name)
+			port: pkg.#Port
+			#replicas: *1 | int
+			replicas: #replicas
+		}
+		`)}}}
+
+	r := &Runtime{}
+	insts, err := compileInstances(r, []*instanceData{pkg, root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		v    Value
+		want string
+	}{{
+		name: "Root",
+		v:    insts[0].Value(),
+	}, {
+		name: "Field",
+		v:    insts[0].Value().LookupPath(ParsePath("service")),
+		want: "{\n\tname:     \"WEB\"\n\tport:     uint & >0 & <65536\n\treplicas: *1 | int\n}",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			want := fmt.Sprint(tc.v)
+			if tc.want != "" && want != tc.want {
+				t.Fatalf("\ngot:  %q;\nwant: %q", want, tc.want)
+			}
+			b, err := tc.v.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Restore into an existing context.
+			ctx := (*Context)(runtime.New())
+			v := ctx.CompileString("_")
+			if err := v.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if v.idx != ctx.runtime() {
+				t.Error("value was not restored in the context of the receiver")
+			}
+			if got := fmt.Sprint(v); got != want {
+				t.Errorf("\ngot:  %q;\nwant: %q", got, want)
+			}
+			// Check that definitions and references are preserved.
+			if got, want := fmt.Sprintf("%#v", v), fmt.Sprintf("%#v", tc.v); got != want {
+				t.Errorf("\ngot:  %q;\nwant: %q", got, want)
+			}
+
+			// Restore into a new context.
+			var v2 Value
+			if err := v2.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(v2); got != want {
+				t.Errorf("\ngot:  %q;\nwant: %q", got, want)
+			}
+		})
+	}
+
+	var v Value
+	if _, err := v.MarshalBinary(); err == nil {
+		t.Error("expected error marshaling a non-existent value")
+	}
+	if err := v.UnmarshalBinary(nil); err == nil {
+		t.Error("expected error unmarshaling an empty buffer")
+	}
+	v1, v2 := insts[0].Value(), insts[0].Value()
+	b, err := r.Marshal(&v1, &v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.UnmarshalBinary(b); err == nil {
+		t.Error("expected error unmarshaling multiple values")
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/internal/core/adt"
+	internaljson "cuelang.org/go/internal/encoding/json"
+)
+
+// A MarshalOption defines options for [Value.MarshalTo].
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	cue    bool
+	indent string
+}
+
+// MarshalCUE causes [Value.MarshalTo] to write CUE instead of JSON.
+// The output is a CUE file: if the value is a struct, its fields are
+// written at the top level without enclosing braces. Unlike the output
+// of package format, field values are not aligned.
+func MarshalCUE() MarshalOption {
+	return func(o *marshalOptions) { o.cue = true }
+}
+
+// MarshalIndent causes [Value.MarshalTo] to write each JSON struct field
+// and list element on a new line, indented by one copy of indent per
+// level of nesting. CUE output is always indented with tabs.
+func MarshalIndent(indent string) MarshalOption {
+	return func(o *marshalOptions) { o.indent = indent }
+}
+
+// MarshalTo writes the concrete value v to w, by default as compact JSON.
+//
+// Unlike [Value.MarshalJSON], MarshalTo does not build the encoding in
+// memory, but writes struct fields and list elements as they are visited,
+// which allows exporting values whose encoding is too large to be held in
+// memory. Fields are written in the same deterministic order as
+// [Value.MarshalJSON].
+//
+// If v is not concrete, an error is returned, in which case part of the
+// output may already have been written to w.
+func (v Value) MarshalTo(w io.Writer, opts ...MarshalOption) error {
+	m := &marshaler{w: bufio.NewWriter(w)}
+	for _, f := range opts {
+		f(&m.marshalOptions)
+	}
+
+	var err error
+	ctx := v.ctx()
+	if m.cue {
+		err = m.cueFile(ctx, v)
+	} else {
+		err = m.value(ctx, v, 0)
+	}
+	if err != nil {
+		return unwrapJSONError(err)
+	}
+	if m.err != nil {
+		return m.err
+	}
+	return m.w.Flush()
+}
+
+type marshaler struct {
+	marshalOptions
+
+	w   *bufio.Writer
+	err error // first error writing to w
+
+	buf []byte // scratch space for encoding scalars
+}
+
+func (m *marshaler) write(b []byte) {
+	if m.err == nil {
+		_, m.err = m.w.Write(b)
+	}
+}
+
+func (m *marshaler) writeString(s string) {
+	if m.err == nil {
+		_, m.err = m.w.WriteString(s)
+	}
+}
+
+// newline starts a new line indented for the given depth, if the output
+// is indented.
+func (m *marshaler) newline(depth int) {
+	indent := m.indent
+	if m.cue {
+		indent = "\t"
+	}
+	if indent != "" {
+		m.writeString("\n")
+		m.writeString(strings.Repeat(indent, depth))
+	}
+}
+
+// cueFile writes v as a CUE file.
+func (m *marshaler) cueFile(ctx *adt.OpContext, v Value) error {
+	v, _ = v.Default()
+	if v.v != nil {
+		if x := v.eval(ctx); x.Kind() == adt.StructKind && adt.IsConcrete(x) {
+			obj, err := v.structValData(ctx)
+			if err != nil {
+				return toMarshalErr(v, err)
+			}
+			for i := range obj.Len() {
+				if err := m.field(ctx, &obj, i, 0); err != nil {
+					return err
+				}
+				m.writeString("\n")
+			}
+			return nil
+		}
+	}
+	if err := m.value(ctx, v, 0); err != nil {
+		return err
+	}
+	m.writeString("\n")
+	return nil
+}
+
+// value writes v, where depth is the nesting level of v.
+func (m *marshaler) value(ctx *adt.OpContext, v Value, depth int) error {
+	v, _ = v.Default()
+	if v.v != nil {
+		switch x := v.eval(ctx); {
+		case !adt.IsConcrete(x):
+
+		case x.Kind() == adt.ListKind:
+			l := v.mustList(ctx)
+			return m.list(&l, depth)
+
+		case x.Kind() == adt.StructKind:
+			obj, err := v.structValData(ctx)
+			if err != nil {
+				return toMarshalErr(v, err)
+			}
+			return m.structFields(&obj, depth)
+
+		case m.cue && x.Kind() == adt.StringKind:
+			m.buf = literal.String.Append(m.buf[:0], x.(*adt.String).Str)
+			m.write(m.buf)
+			return nil
+
+		case m.cue && x.Kind() == adt.BytesKind:
+			m.buf = literal.Bytes.Append(m.buf[:0], string(x.(*adt.Bytes).B))
+			m.write(m.buf)
+			return nil
+		}
+	}
+
+	// The JSON encoding of the remaining kinds of values is also valid CUE.
+	// appendJSON reports an error for incomplete values.
+	b, err := v.appendJSON(ctx, m.buf[:0])
+	if err != nil {
+		return err
+	}
+	m.buf = b
+	m.write(b)
+	return nil
+}
+
+func (m *marshaler) list(l *Iterator, depth int) error {
+	m.writeString("[")
+	n := 0
+	for ; l.Next() && m.err == nil; n++ {
+		if n > 0 && !m.cue {
+			m.writeString(",")
+		}
+		m.newline(depth + 1)
+		if err := m.value(l.ctx, l.Value(), depth+1); err != nil {
+			return err
+		}
+		if m.cue {
+			m.writeString(",")
+		}
+	}
+	if n > 0 {
+		m.newline(depth)
+	}
+	m.writeString("]")
+	return nil
+}
+
+func (m *marshaler) structFields(obj *structValue, depth int) error {
+	m.writeString("{")
+	n := obj.Len()
+	for i := 0; i < n && m.err == nil; i++ {
+		if i > 0 && !m.cue {
+			m.writeString(",")
+		}
+		m.newline(depth + 1)
+		if err := m.field(obj.ctx, obj, i, depth+1); err != nil {
+			return err
+		}
+	}
+	if n > 0 {
+		m.newline(depth)
+	}
+	m.writeString("}")
+	return nil
+}
+
+// field writes the ith field of obj, where depth is the nesting level of
+// the field.
+func (m *marshaler) field(ctx *adt.OpContext, obj *structValue, i, depth int) error {
+	k, v := obj.At(i)
+	switch {
+	case !m.cue:
+		// Do not use json.Marshal as it escapes HTML.
+		b, err := internaljson.Marshal(k)
+		if err != nil {
+			return err
+		}
+		m.write(b)
+		m.writeString(":")
+		if m.indent != "" {
+			m.writeString(" ")
+		}
+
+	case !ast.IsValidIdent(k) || strings.HasPrefix(k, "#") || strings.HasPrefix(k, "_"):
+		m.buf = literal.Label.Append(m.buf[:0], k)
+		m.write(m.buf)
+		m.writeString(": ")
+
+	default:
+		m.writeString(k)
+		m.writeString(": ")
+	}
+	return m.value(ctx, v, depth)
+}
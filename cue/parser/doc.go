@@ -20,4 +20,18 @@
 // The parser accepts a larger language than is syntactically permitted by the
 // CUE spec, for simplicity, and for improved robustness in the presence of
 // syntax errors.
+//
+// # Line directives
+//
+// Generated CUE files can map positions back to the source they were
+// generated from with line directives. A line comment of the form
+//
+//	//line filename:line
+//
+// that is the first token on its line causes the line that follows it to be
+// reported as the given line of the given file, and subsequent lines to be
+// numbered accordingly, until the next line directive. A relative filename
+// is interpreted relative to the directory of the file being parsed.
+// Positions of nodes and errors reflect the directives, but the layout of
+// the source, such as which comments are attached to which nodes, does not.
 package parser
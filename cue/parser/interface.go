@@ -79,6 +79,20 @@ var (
 	allowPartial        = func(p *parser) {
 		p.mode |= partialMode
 	}
+
+	// ErrorTolerant causes the parser to continue after syntax errors
+	// for as long as possible, so that the partial AST covers as much of
+	// the source as possible. This is useful for tools, such as editors,
+	// that operate on source that is being edited.
+	//
+	// The erroneous fragments of the source are represented by
+	// [ast.BadExpr] and [ast.BadDecl] nodes, and the parse functions
+	// return the partial AST alongside the errors, rather than stopping
+	// after too many errors. ParseExpr also returns a partial expression.
+	ErrorTolerant Option = errorTolerant
+	errorTolerant        = func(p *parser) {
+		p.mode |= errorTolerantMode
+	}
 )
 
 // FromVersion specifies until which legacy version the parser should provide
@@ -138,6 +152,7 @@ const (
 	traceMode             // print a trace of parsed productions
 	declarationErrorsMode // report declaration errors
 	allErrorsMode         // report all errors (not just the first 10 on different lines)
+	errorTolerantMode     // continue parsing after errors for as long as possible
 )
 
 // ParseFile parses the source code of a single CUE source file and returns
@@ -232,7 +247,7 @@ func ParseExpr(filename string, src interface{}, mode ...Option) (ast.Expr, erro
 		p.expect(token.EOF)
 	}
 
-	if p.errors != nil {
+	if p.errors != nil && p.mode&errorTolerantMode == 0 {
 		return nil, p.errors
 	}
 	astutil.ResolveExpr(e, p.errf)
@@ -85,6 +85,13 @@ func (p *parser) init(filename string, src []byte, mode []Option) {
 	p.next()
 }
 
+// line returns the line of pos within the source, ignoring any //line
+// directives, so that the layout of comments is determined by the source
+// as written.
+func (p *parser) line(pos token.Pos) int {
+	return p.file.PositionFor(pos, false).Line
+}
+
 type commentState struct {
 	parent *commentState
 	pos    int8
@@ -274,7 +281,7 @@ func (p *parser) next0() {
 
 // Consume a comment and return it and the line on which it ends.
 func (p *parser) consumeComment() (comment *ast.Comment, endline int) {
-	endline = p.file.Line(p.pos)
+	endline = p.line(p.pos)
 
 	comment = &ast.Comment{Slash: p.pos, Text: p.lit}
 	p.next0()
@@ -289,7 +296,7 @@ func (p *parser) consumeComment() (comment *ast.Comment, endline int) {
 func (p *parser) consumeCommentGroup(prevLine, n int) (comments *ast.CommentGroup, endline int) {
 	var list []*ast.Comment
 	var rel token.RelPos
-	endline = p.file.Line(p.pos)
+	endline = p.line(p.pos)
 	switch endline - prevLine {
 	case 0:
 		rel = token.Blank
@@ -298,7 +305,7 @@ func (p *parser) consumeCommentGroup(prevLine, n int) (comments *ast.CommentGrou
 	default:
 		rel = token.NewSection
 	}
-	for p.tok == token.COMMENT && p.file.Line(p.pos) <= endline+n {
+	for p.tok == token.COMMENT && p.line(p.pos) <= endline+n {
 		var comment *ast.Comment
 		comment, endline = p.consumeComment()
 		list = append(list, comment)
@@ -338,13 +345,13 @@ func (p *parser) next() {
 		var comment *ast.CommentGroup
 		var endline int
 
-		currentLine := p.file.Line(p.pos)
-		prevLine := p.file.Line(prev)
+		currentLine := p.line(p.pos)
+		prevLine := p.line(prev)
 		if prevLine == currentLine {
 			// The comment is on same line as the previous token; it
 			// cannot be a lead comment but may be a line comment.
 			comment, endline = p.consumeCommentGroup(prevLine, 0)
-			if p.file.Line(p.pos) != endline {
+			if p.line(p.pos) != endline {
 				// The next token is on a different line, thus
 				// the last comment group is a line comment.
 				comment.Line = true
@@ -359,11 +366,11 @@ func (p *parser) next() {
 			}
 			comment, endline = p.consumeCommentGroup(prevLine, 1)
 			prevLine = currentLine
-			currentLine = p.file.Line(p.pos)
+			currentLine = p.line(p.pos)
 
 		}
 
-		if endline+1 == p.file.Line(p.pos) && p.tok != token.EOF {
+		if endline+1 == p.line(p.pos) && p.tok != token.EOF {
 			// The next token is following on the line immediately after the
 			// comment group, thus the last comment group is a lead comment.
 			comment.Doc = true
@@ -402,7 +409,7 @@ func (p *parser) errf(pos token.Pos, msg string, args ...interface{}) {
 		if n > 0 && errors[n-1].Position().Line() == ePos.Line() {
 			return // discard - likely a spurious error
 		}
-		if n > 10 {
+		if n > 10 && p.mode&errorTolerantMode == 0 {
 			p.panicking = true
 			panic("too many errors")
 		}
@@ -629,7 +636,17 @@ func (p *parser) parseOperand() (expr ast.Expr) {
 	c := p.openComments()
 	pos := p.pos
 	p.errorExpected(pos, "operand")
-	syncExpr(p)
+	switch p.tok {
+	case token.RBRACE, token.RBRACK, token.RPAREN:
+		// Leave the closing token for the enclosing expression, as
+		// skipping it would make the rest of the source misparse.
+		if p.mode&errorTolerantMode != 0 {
+			break
+		}
+		fallthrough
+	default:
+		syncExpr(p)
+	}
 	return c.closeExpr(p, &ast.BadExpr{From: pos, To: p.pos})
 }
 
@@ -727,6 +744,17 @@ func (p *parser) parseFieldList() (list []ast.Decl) {
 
 	for p.tok != token.RBRACE && p.tok != token.EOF {
 		switch p.tok {
+		case token.COMMA:
+			if p.mode&errorTolerantMode == 0 {
+				list = append(list, p.parseField())
+				break
+			}
+			// Report the empty declaration as the default mode does, but
+			// skip it to continue with the next one. The error is discarded
+			// for commas left behind on the line of an earlier error.
+			p.errorExpected(p.pos, "operand")
+			p.next()
+
 		case token.ATTRIBUTE:
 			list = append(list, p.parseAttribute())
 			p.consumeDeclComma()
@@ -1664,7 +1692,7 @@ func (p *parser) parseFile() *ast.File {
 
 	// Don't bother parsing the rest if we had errors scanning the first
 	// Likely not a CUE source file at all.
-	if p.errors != nil {
+	if p.errors != nil && p.mode&errorTolerantMode == 0 {
 		return nil
 	}
 	p.openList()
@@ -1713,6 +1741,15 @@ func (p *parser) parseFile() *ast.File {
 			// rest of package decls
 			// TODO: loop and allow multiple expressions.
 			decls = append(decls, p.parseFieldList()...)
+			for p.mode&errorTolerantMode != 0 && p.tok != token.EOF {
+				// Skip unmatched closing braces and parse the
+				// declarations that follow.
+				pos := p.pos
+				p.errorExpected(pos, "EOF")
+				p.next()
+				decls = append(decls, &ast.BadDecl{From: pos, To: p.pos})
+				decls = append(decls, p.parseFieldList()...)
+			}
 			p.expect(token.EOF)
 		}
 	}
@@ -898,6 +898,87 @@ func TestIncompleteSelection(t *testing.T) {
 	}
 }
 
+func TestErrorTolerant(t *testing.T) {
+	testCases := []struct{ desc, in, out string }{{
+		"missing value before closing brace",
+		"a: {\n\tb: 1\n\tc: \n}\nd: 2",
+		"a: {b: 1, c: <*ast.BadExpr>}, d: 2\nexpected operand, found '}'",
+	}, {
+		"missing value before closing paren",
+		"a: (\n)\nb: 2",
+		"a: (<*ast.BadExpr>), b: 2\nexpected operand, found ')'",
+	}, {
+		"unmatched closing brace",
+		"a: 1\nb: }\nc: 3",
+		"a: 1, b: <*ast.BadExpr>, <*ast.BadDecl>, c: 3\nexpected operand, found '}'",
+	}, {
+		"unmatched closing bracket",
+		"a: 1\nb: ]\nc: 3",
+		"a: 1, b: <*ast.BadExpr>, c: 3\nexpected operand, found ']'",
+	}, {
+		"many errors",
+		strings.Repeat("a: \n}\n", 12) + "b: 1",
+		strings.Repeat("a: <*ast.BadExpr>, <*ast.BadDecl>, ", 12) + "b: 1\nexpected operand, found '}' (and 11 more errors)",
+	}, {
+		"stray comma",
+		"a: 1,, b: 2",
+		"a: 1, b: 2\nexpected operand, found ','",
+	}, {
+		"stray comma in struct",
+		"a: {b: 1,, c: 2}",
+		"a: {b: 1, c: 2}\nexpected operand, found ','",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			f, err := ParseFile("input", tc.in, ErrorTolerant)
+			got := astinternal.DebugStr(f)
+			if err != nil {
+				got += "\n" + err.Error()
+			}
+			if got != tc.out {
+				t.Errorf("\ngot  %q;\nwant %q", got, tc.out)
+			}
+		})
+	}
+
+	x, err := ParseExpr("input", "a + ", ErrorTolerant)
+	if err == nil {
+		t.Errorf("ParseExpr: got no error")
+	}
+	if got, want := astinternal.DebugStr(x), "a+<*ast.BadExpr>"; got != want {
+		t.Errorf("ParseExpr: got %q; want %q", got, want)
+	}
+
+	// Resolving a partial expression must not panic.
+	x, err = ParseExpr("input", "[ ! [ #b = ! ", ErrorTolerant)
+	if err == nil {
+		t.Errorf("ParseExpr: got no error")
+	}
+	if x == nil {
+		t.Errorf("ParseExpr: got no expression")
+	}
+}
+
+func TestLineDirectives(t *testing.T) {
+	const src = `a: 1
+//line config.yaml:10
+b: 2
+`
+	f, err := ParseFile("src.cue", src, ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := f.Decls[1].(*ast.Field)
+	if got, want := b.Pos().String(), "config.yaml:10:1"; got != want {
+		t.Errorf("position of b: got %s; want %s", got, want)
+	}
+	// The directive is still the doc comment of b, even though the
+	// reported lines are not adjacent.
+	if cgs := ast.Comments(b); len(cgs) != 1 || !cgs[0].Doc {
+		t.Errorf("directive is not the doc comment of b: %v", cgs)
+	}
+}
+
 // For debugging, do not delete.
 func TestX(t *testing.T) {
 	t.Skip()
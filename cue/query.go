@@ -15,6 +15,10 @@
 package cue
 
 import (
+	"strings"
+
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/core/adt"
 )
 
@@ -45,12 +49,17 @@ func (v Value) LookupPath(p Path) Value {
 	}
 	n := v.v
 	parent := v.parent_
-	ctx := v.ctx()
+
+	// The context is only needed for selectors that do not name an
+	// existing arc, so it is created on demand.
+	var ctx *adt.OpContext
 
 outer:
 	for _, sel := range p.path {
 		f := sel.sel.feature(v.idx)
 		deref := n.DerefValue()
+		_, isPathError := sel.sel.(pathError)
+		memoize := !isPathError && !sel.sel.isConstraint()
 		for _, a := range deref.Arcs {
 			if a.Label == f {
 				if a.IsConstraint() && !sel.sel.isConstraint() {
@@ -61,6 +70,16 @@ outer:
 				continue outer
 			}
 		}
+		if memoize {
+			// Lookups of absent fields are memoized for finalized values, as
+			// validators often probe for many optional fields.
+			if x := v.idx.Absent().Lookup(deref, f); x != nil {
+				return newErrValue(makeValue(v.idx, n, parent), x)
+			}
+		}
+		if ctx == nil {
+			ctx = v.ctx()
+		}
 		if sel.sel.isConstraint() {
 			x := &adt.Vertex{
 				Parent: n,
@@ -85,8 +104,200 @@ outer:
 			}
 			x.NotExists = true
 		}
+		if memoize {
+			v.idx.Absent().Set(deref, f, x)
+		}
 		v := makeValue(v.idx, n, parent)
 		return newErrValue(v, x)
 	}
 	return makeValue(v.idx, n, parent)
 }
+
+// MatchPath reports all values within v, including v itself, whose path
+// relative to v matches the given pattern. The fields of structs are
+// visited in the order in which they are defined.
+//
+// A pattern has the syntax of a [Path], where any selector may be replaced
+// by one of the wildcards *, [*] and **:
+//
+//	x.*      matches any regular field of x
+//	x[*]     matches any element of the list x
+//	x.**.y   matches y in x or in any value nested within x
+//
+// For example, `spec.containers[*].image` selects the image of each
+// container and `**.env` selects all fields named env at any depth.
+//
+// Wildcards only match regular fields that are present in v. Definitions,
+// hidden fields and optional fields can only be selected by name.
+func (v Value) MatchPath(pattern string) ([]Value, error) {
+	elems, err := parsePathPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	m := &pathMatcher{seen: map[string]bool{}}
+	m.match(v, elems)
+	return m.values, nil
+}
+
+// A patternElem is an element of a path pattern. If wildcard is set, sel is
+// unused.
+type patternElem struct {
+	wildcard string // "*", "[*]", or "**"
+	sel      Selector
+}
+
+func parsePathPattern(pattern string) ([]patternElem, error) {
+	var elems []patternElem
+	s := pattern
+	for first := true; s != ""; first = false {
+		if !first {
+			switch s[0] {
+			case '.':
+				s = s[1:]
+			case '[':
+			default:
+				return nil, errors.Newf(token.NoPos, "invalid path pattern %q: unexpected %q", pattern, s[0])
+			}
+		}
+		var elem patternElem
+		var n int
+		switch {
+		case strings.HasPrefix(s, "**"):
+			elem.wildcard, n = "**", 2
+		case strings.HasPrefix(s, "*"):
+			elem.wildcard, n = "*", 1
+		case strings.HasPrefix(s, "[*]"):
+			elem.wildcard, n = "[*]", 3
+		default:
+			n = selectorLen(s)
+			if n == 0 {
+				return nil, errors.Newf(token.NoPos, "invalid path pattern %q: empty selector", pattern)
+			}
+			sel, err := parseSelector(s[:n])
+			if err != nil {
+				return nil, errors.Wrapf(err, token.NoPos, "invalid path pattern %q", pattern)
+			}
+			elem.sel = sel
+		}
+		s = s[n:]
+		if elem.wildcard == "**" && len(elems) > 0 && elems[len(elems)-1].wildcard == "**" {
+			continue
+		}
+		elems = append(elems, elem)
+	}
+	return elems, nil
+}
+
+// parseSelector parses a single selector, which may be an index expression
+// such as [1] or ["a"].
+func parseSelector(s string) (Selector, error) {
+	prefix := 0
+	if s[0] == '[' {
+		// Index expressions must follow an operand.
+		s, prefix = "x"+s, 1
+	}
+	p := ParsePath(s)
+	if err := p.Err(); err != nil {
+		return Selector{}, err
+	}
+	sels := p.Selectors()
+	if len(sels) != prefix+1 {
+		return Selector{}, errors.Newf(token.NoPos, "invalid selector %q", s[prefix:])
+	}
+	return sels[prefix], nil
+}
+
+// selectorLen returns the length of the selector at the start of s, which
+// extends to the next '.' or '[' that is not part of a quoted string or an
+// index expression.
+func selectorLen(s string) int {
+	inIndex := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			// Skip the quoted string, taking escapes into account.
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '[':
+			if i > 0 && !inIndex {
+				return i
+			}
+			inIndex = true
+		case ']':
+			if inIndex {
+				return i + 1
+			}
+		case '.':
+			if !inIndex {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+type pathMatcher struct {
+	values []Value
+	seen   map[string]bool
+}
+
+func (m *pathMatcher) match(v Value, elems []patternElem) {
+	if !v.Exists() {
+		return
+	}
+	if len(elems) == 0 {
+		// A pattern with several ** wildcards may match the same value
+		// in more than one way.
+		if p := v.Path().String(); !m.seen[p] {
+			m.seen[p] = true
+			m.values = append(m.values, v)
+		}
+		return
+	}
+	e, rest := elems[0], elems[1:]
+	switch e.wildcard {
+	case "":
+		m.match(v.LookupPath(MakePath(e.sel)), rest)
+
+	case "*":
+		m.fields(v, func(w Value) { m.match(w, rest) })
+
+	case "[*]":
+		m.elements(v, func(w Value) { m.match(w, rest) })
+
+	case "**":
+		m.match(v, rest)
+		descend := func(w Value) { m.match(w, elems) }
+		m.fields(v, descend)
+		m.elements(v, descend)
+	}
+}
+
+func (m *pathMatcher) fields(v Value, f func(Value)) {
+	if v.IncompleteKind() != StructKind {
+		return
+	}
+	iter, err := v.Fields()
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		f(iter.Value())
+	}
+}
+
+func (m *pathMatcher) elements(v Value, f func(Value)) {
+	if v.IncompleteKind() != ListKind {
+		return
+	}
+	iter, err := v.List()
+	if err != nil {
+		return
+	}
+	for iter.Next() {
+		f(iter.Value())
+	}
+}
@@ -16,6 +16,8 @@ package cue_test
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
 	"cuelang.org/go/cue"
@@ -134,6 +136,186 @@ func TestLookupPath(t *testing.T) {
 	}
 }
 
+func TestLookupPathAbsent(t *testing.T) {
+	ctx := cuecontext.New()
+	v := mustCompile(t, ctx, `
+		#D: {x?: int, y: 1}
+		d: #D
+		o: {x?: int, y: 1}
+		`)
+
+	// Absent fields are memoized on finalized values; repeated lookups must
+	// report the same result as the first one.
+	for i := 0; i < 2; i++ {
+		for _, p := range []string{"d.x", "d.z", "o.x", "o.z", "z"} {
+			w := v.LookupPath(cue.ParsePath(p))
+			if w.Exists() {
+				t.Errorf("%d: %s exists", i, p)
+			}
+			want := "field not found: " + p
+			if i := strings.LastIndexByte(p, '.'); i >= 0 {
+				want = p[:i] + ": field not found: " + p[i+1:]
+			}
+			if err := w.Err(); err == nil || err.Error() != want {
+				t.Errorf("%d: %s: got error %v; want %v", i, p, err, want)
+			}
+		}
+		if w := v.LookupPath(cue.ParsePath("d.y")); !w.Exists() {
+			t.Errorf("%d: d.y does not exist", i)
+		}
+	}
+
+	// Filling in a previously absent field creates a new value, which must
+	// not use the results memoized for the old one.
+	d := v.LookupPath(cue.ParsePath("o"))
+	if d.LookupPath(cue.ParsePath("x")).Exists() {
+		t.Fatal("o.x exists")
+	}
+	d = d.FillPath(cue.ParsePath("x"), 2)
+	if x := d.LookupPath(cue.ParsePath("x")); !x.Exists() {
+		t.Errorf("o.x does not exist after filling it: %v", x.Err())
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	ctx := cuecontext.New()
+	v := mustCompile(t, ctx, `
+		spec: containers: [{image: "a", env: {X: 1}}, {image: "b"}]
+		env: 3
+		"a-b": [1, [2]]
+		#D: env: 4
+		x: y: env: 5
+		x: z?: env: 6
+		`)
+
+	testCases := []struct {
+		pattern string
+		want    string
+		err     string
+	}{{
+		pattern: "spec.containers[*].image",
+		want:    "spec.containers[0].image spec.containers[1].image",
+	}, {
+		pattern: "**.env",
+		want:    "env spec.containers[0].env x.y.env",
+	}, {
+		pattern: "**.**.env",
+		want:    "env spec.containers[0].env x.y.env",
+	}, {
+		pattern: "x.**.env",
+		want:    "x.y.env",
+	}, {
+		pattern: "*",
+		want:    `spec env "a-b" x`,
+	}, {
+		pattern: `"a-b"[1][0]`,
+		want:    `"a-b"[1][0]`,
+	}, {
+		pattern: `"a-b"[*][*]`,
+		want:    `"a-b"[1][0]`,
+	}, {
+		pattern: "#D.env",
+		want:    "#D.env",
+	}, {
+		pattern: "spec.*[0]",
+		want:    "spec.containers[0]",
+	}, {
+		pattern: "spec.nope.*",
+		want:    "",
+	}, {
+		pattern: "a..b",
+		err:     `invalid path pattern "a..b": empty selector`,
+	}, {
+		pattern: "a.*b",
+		err:     `invalid path pattern "a.*b": unexpected 'b'`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			values, err := v.MatchPath(tc.pattern)
+			if err != nil || tc.err != "" {
+				if got := fmt.Sprint(err); got != tc.err {
+					t.Fatalf("error: got %v; want %v", got, tc.err)
+				}
+				return
+			}
+			var paths []string
+			for _, w := range values {
+				paths = append(paths, w.Path().String())
+			}
+			if got := strings.Join(paths, " "); got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func BenchmarkLookupPathAbsent(b *testing.B) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		flags: {
+			a: true
+			b: false
+			c: true
+		}
+		`)
+	paths := []cue.Path{
+		cue.ParsePath("flags.x"),
+		cue.ParsePath("flags.y"),
+		cue.ParsePath("flags.z"),
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, p := range paths {
+			if v.LookupPath(p).Exists() {
+				b.Fatal("unexpected field")
+			}
+		}
+	}
+}
+
+func BenchmarkLookupPathParallel(b *testing.B) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+		flags: {
+			a: true
+			b: false
+			c: true
+		}
+		`)
+	present := []cue.Path{
+		cue.ParsePath("flags.a"),
+		cue.ParsePath("flags.b"),
+		cue.ParsePath("flags.c"),
+	}
+	absent := []cue.Path{
+		cue.ParsePath("flags.x"),
+		cue.ParsePath("flags.y"),
+		cue.ParsePath("flags.z"),
+	}
+	b.Run("present", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, p := range present {
+					if !v.LookupPath(p).Exists() {
+						b.Fatal("missing field")
+					}
+				}
+			}
+		})
+	})
+	b.Run("absent", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				for _, p := range absent {
+					if v.LookupPath(p).Exists() {
+						b.Fatal("unexpected field")
+					}
+				}
+			}
+		})
+	})
+}
+
 func TestHidden(t *testing.T) {
 	in := `
 -- cue.mod/module.cue --
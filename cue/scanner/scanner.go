@@ -18,8 +18,10 @@
 package scanner
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"unicode"
 	"unicode/utf8"
 
@@ -175,10 +177,49 @@ exit:
 		// TODO: preserve /r/n
 		lit = stripCR(lit)
 	}
+	if bytes.HasPrefix(lit, linePrefix) && s.atLineStart(offs) {
+		s.updateLineInfo(lit[len(linePrefix):])
+	}
 
 	return string(lit)
 }
 
+var linePrefix = []byte("//line ")
+
+// atLineStart reports whether only whitespace precedes offs on its line.
+func (s *Scanner) atLineStart(offs int) bool {
+	for i := offs - 1; i >= 0 && s.src[i] != '\n'; i-- {
+		if s.src[i] != ' ' && s.src[i] != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// updateLineInfo parses the text of a //line filename:line directive and
+// records that the line following the directive is the given line of the
+// given file. A relative filename is interpreted relative to the directory
+// of the file being scanned. Malformed directives are ignored.
+func (s *Scanner) updateLineInfo(text []byte) {
+	i := bytes.LastIndexByte(text, ':')
+	if i < 0 {
+		return
+	}
+	line, err := strconv.Atoi(string(bytes.TrimSpace(text[i+1:])))
+	if err != nil || line <= 0 {
+		return
+	}
+	filename := string(bytes.TrimSpace(text[:i]))
+	if filename == "" {
+		return
+	}
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(s.dir, filename)
+	}
+	// The directive applies from the start of the next line.
+	s.file.AddLineInfo(s.offset+1, filename, line)
+}
+
 func isLetter(ch rune) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch >= utf8.RuneSelf && unicode.IsLetter(ch)
 }
@@ -17,6 +17,7 @@ package scanner
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -542,6 +543,48 @@ func TestInit(t *testing.T) {
 	}
 }
 
+func TestLineDirectives(t *testing.T) {
+	const src = `a: 1
+//line config.yaml:10
+b: 2
+c: 3
+	//line ../other.yaml:3
+d: 4
+x: 5 //line ignored.yaml:1
+e: 6
+//line malformed.yaml
+f: 7
+//line :8
+g: 8
+`
+	want := map[string]string{
+		"a": "dir/src.cue:1:1",
+		"b": "dir/config.yaml:10:1",
+		"c": "dir/config.yaml:11:1",
+		"d": "other.yaml:3:1",
+		"x": "other.yaml:4:1",
+		"e": "other.yaml:5:1",
+		"f": "other.yaml:7:1",
+		"g": "other.yaml:9:1",
+	}
+	file := token.NewFile(filepath.Join("dir", "src.cue"), -1, len(src))
+	var s Scanner
+	s.Init(file, []byte(src), nil, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.IDENT {
+			continue
+		}
+		got := filepath.ToSlash(pos.String())
+		if got != want[lit] {
+			t.Errorf("position of %s: got %s; want %s", lit, got, want[lit])
+		}
+	}
+}
+
 func TestScanInterpolation(t *testing.T) {
 	// error handler
 	eh := func(pos token.Pos, msg string, args []interface{}) {
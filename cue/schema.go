@@ -0,0 +1,748 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/core/dep"
+)
+
+// A CompiledSchema validates JSON data against a schema. It is created by
+// [Value.Compile].
+//
+// The constraints of the schema that can be checked without evaluating CUE,
+// such as the types, bounds, closedness and required fields of structs
+// and the discriminating fields of disjunctions, are computed once when the
+// schema is compiled. Data that satisfies these constraints is validated
+// without constructing a [Value]. Any other data, and any schema with
+// constraints that depend on the data, such as references between fields,
+// is validated by unifying the data with the schema.
+type CompiledSchema struct {
+	v Value
+
+	// root is nil if the data is always validated by unification.
+	root *schemaNode
+
+	// mu serializes validation by unification.
+	mu sync.Mutex
+}
+
+// Compile compiles v into a schema for validating JSON data. It returns an
+// error if v is not valid.
+func (v Value) Compile() (*CompiledSchema, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	s := &CompiledSchema{v: v}
+	if !v.dependsOnData() {
+		s.root = compileSchema(v, 0)
+	}
+	return s, nil
+}
+
+// Value returns the schema from which s was compiled.
+func (s *CompiledSchema) Value() Value {
+	return s.v
+}
+
+// Validate reports an error if the JSON-encoded data is not an instance of
+// the schema. The data must be concrete after unification with the schema,
+// as with [Value.Validate] with the [Concrete] option. The errors are
+// those reported for the unification of the data with the schema.
+//
+// Validate may be called concurrently. Validation by unification is
+// serialized, and uses the [Context] of the schema, which must not be used
+// concurrently otherwise.
+func (s *CompiledSchema) Validate(data []byte) error {
+	if s.root != nil {
+		if x, ok := decodeJSON(data); ok && s.root.check(x) == schemaMatch {
+			return nil
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unify(data)
+}
+
+// unify validates data by unification with the schema.
+func (s *CompiledSchema) unify(data []byte) error {
+	const filename = "data.json"
+	var x any
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&x); err != nil {
+		return errors.Wrapf(err, token.NoPos, "invalid JSON for file %q", filename)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return errors.Newf(token.NoPos, "invalid JSON for file %q: unexpected data after top-level value", filename)
+	}
+	expr, err := parser.ParseExpr(filename, data)
+	if err != nil {
+		// Valid JSON that is not valid CUE, such as strings with the
+		// escape sequence \/, is re-encoded.
+		b, _ := json.Marshal(x)
+		if expr, err = parser.ParseExpr(filename, b); err != nil {
+			return err
+		}
+	}
+	ctx := s.v.Context()
+	return s.v.Unify(ctx.BuildExpr(expr)).Validate(Concrete(true))
+}
+
+// dependsOnData reports whether v may depend on the data it is unified
+// with. This is the case if it refers to a value other than a definition,
+// as the referred value may be a field that is set by the data. This
+// includes the functions and validators of builtin packages.
+func (v Value) dependsOnData() bool {
+	if v.v == nil {
+		return true
+	}
+	depends := false
+	cfg := &dep.Config{Descend: true, Rootless: true}
+	dep.Visit(cfg, v.ctx(), v.v, func(d dep.Dependency) error {
+		if !d.Node.Label.IsDef() {
+			depends = true
+		}
+		return nil
+	})
+	return depends
+}
+
+// hasValidators reports whether the struct or list w may have constraints
+// other than the values of its fields or elements and its closedness, such
+// as pattern constraints or validators like struct.MinFields(1). These are
+// not recorded in w after evaluation, so they are looked up in the
+// expressions from which w is computed.
+func hasValidators(ctx *adt.OpContext, w *adt.Vertex) bool {
+	found := false
+	seen := map[*adt.Vertex]bool{}
+	var visit func(w *adt.Vertex)
+	var visitExpr func(w *adt.Vertex, env *adt.Environment, x adt.Expr)
+	visit = func(w *adt.Vertex) {
+		if seen[w] {
+			return
+		}
+		seen[w] = true
+		w.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+			env, x := c.EnvExpr()
+			visitExpr(w, env, x)
+			return !found
+		})
+	}
+	visitExpr = func(w *adt.Vertex, env *adt.Environment, x adt.Expr) {
+		switch x := x.(type) {
+		case *adt.StructLit:
+			for _, d := range x.Decls {
+				switch d := d.(type) {
+				case *adt.Field, *adt.LetField, *adt.DynamicField, *adt.Ellipsis, *adt.Comprehension:
+				case *adt.BulkOptionalField:
+					found = true
+				case adt.Expr:
+					visitExpr(w, &adt.Environment{Up: env, Vertex: w}, d)
+				default:
+					found = true
+				}
+			}
+		case *adt.ListLit, *adt.Top, *adt.BasicType:
+		case *adt.BinaryExpr:
+			if x.Op != adt.AndOp {
+				found = true
+				return
+			}
+			visitExpr(w, env, x.X)
+			visitExpr(w, env, x.Y)
+		case adt.Resolver:
+			n, b := ctx.Resolve(adt.MakeRootConjunct(env, x), x)
+			if b != nil || n == nil {
+				found = true
+				return
+			}
+			visit(n)
+		case *adt.Vertex:
+			visit(x)
+		default:
+			found = true
+		}
+	}
+	visit(w)
+	return found
+}
+
+// maxSchemaDepth is the maximum nesting of the values of a schema that is
+// compiled. Deeper schemas, including recursive ones, are validated by
+// unification.
+const maxSchemaDepth = 64
+
+// A schemaNode holds the constraints of a value of a compiled schema.
+type schemaNode struct {
+	kind   adt.Kind
+	value  adt.Value // a concrete scalar, if not nil
+	bounds []schemaBound
+
+	// Structs.
+	fields map[string]*schemaField
+	closed bool
+
+	// Lists. rest is nil for closed lists.
+	elems []*schemaNode
+	rest  *schemaNode
+
+	// Disjunctions. The disjunct of data that is a struct is the one
+	// with the value of its field discriminator as the key in byTag, if
+	// discriminator is not empty.
+	disjuncts     []*schemaNode
+	discriminator string
+	byTag         map[string]int
+}
+
+type schemaField struct {
+	node      *schemaNode
+	missingOK bool // the field is optional or its value is concrete
+}
+
+// A schemaBound is a bound or regular expression constraint.
+type schemaBound struct {
+	op  adt.Op
+	num *apd.Decimal
+	str string
+	re  *regexp.Regexp
+	x   adt.Value
+}
+
+// compileSchema returns the constraints of v, or nil if they cannot be
+// checked without unification.
+func compileSchema(v Value, depth int) *schemaNode {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+	w := v.v.DerefValue()
+	switch x := w.BaseValue.(type) {
+	case *adt.StructMarker:
+		return compileStruct(v, w, depth)
+	case *adt.ListMarker:
+		return compileList(v, w, x, depth)
+	case *adt.Disjunction:
+		return compileDisjunction(v, x, depth)
+	case adt.Value:
+		n := &schemaNode{kind: adt.TopKind}
+		if !n.add(x) {
+			return nil
+		}
+		return n
+	}
+	return nil
+}
+
+// add adds the scalar constraint x to n. It reports whether x is supported.
+func (n *schemaNode) add(x adt.Value) bool {
+	switch x := x.(type) {
+	case *adt.Top:
+	case *adt.BasicType:
+		n.kind &= x.K
+	case *adt.Num, *adt.String, *adt.Bool, *adt.Null:
+		n.kind &= x.Kind()
+		n.value = x
+	case *adt.BoundValue:
+		b := schemaBound{op: x.Op, x: x.Value}
+		switch y := x.Value.(type) {
+		case *adt.Num:
+			b.num = &y.X
+		case *adt.String:
+			b.str = y.Str
+		case *adt.Bool, *adt.Null:
+		default:
+			return false
+		}
+		switch x.Op {
+		case adt.LessThanOp, adt.LessEqualOp, adt.GreaterThanOp, adt.GreaterEqualOp:
+			if b.num == nil && x.Value.Kind() != adt.StringKind {
+				return false
+			}
+		case adt.NotEqualOp:
+		case adt.MatchOp, adt.NotMatchOp:
+			if x.Value.Kind() != adt.StringKind {
+				return false
+			}
+			re, err := regexp.Compile(b.str)
+			if err != nil {
+				return false
+			}
+			b.re = re
+		default:
+			return false
+		}
+		n.kind &= x.Kind()
+		n.bounds = append(n.bounds, b)
+	case *adt.Conjunction:
+		for _, y := range x.Values {
+			if !n.add(y) {
+				return false
+			}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func compileStruct(v Value, w *adt.Vertex, depth int) *schemaNode {
+	if hasValidators(v.ctx(), w) {
+		return nil
+	}
+	n := &schemaNode{kind: adt.StructKind, fields: map[string]*schemaField{}}
+	iter, err := v.Fields(Optional(true))
+	if err != nil {
+		return nil
+	}
+	for iter.Next() {
+		sel := iter.Selector()
+		if sel.LabelType() != StringLabel {
+			return nil
+		}
+		fv := iter.Value()
+		f := &schemaField{node: compileSchema(fv, depth+1)}
+		if f.node == nil {
+			return nil
+		}
+		switch sel.ConstraintType() {
+		case OptionalConstraint:
+			f.missingOK = true
+		case 0:
+			f.missingOK = fv.Validate(Concrete(true)) == nil
+		}
+		n.fields[sel.Unquoted()] = f
+	}
+	// Without pattern constraints, all other fields are either allowed or
+	// not.
+	probe := "_"
+	for n.fields[probe] != nil {
+		probe += "_"
+	}
+	n.closed = !v.Allows(Str(probe))
+	return n
+}
+
+func compileList(v Value, w *adt.Vertex, x *adt.ListMarker, depth int) *schemaNode {
+	if hasValidators(v.ctx(), w) {
+		return nil
+	}
+	n := &schemaNode{kind: adt.ListKind}
+	iter, err := v.List()
+	if err != nil {
+		return nil
+	}
+	for iter.Next() {
+		e := compileSchema(iter.Value(), depth+1)
+		if e == nil {
+			return nil
+		}
+		n.elems = append(n.elems, e)
+	}
+	if x.IsOpen {
+		n.rest = &schemaNode{kind: adt.TopKind}
+		if r := v.LookupPath(MakePath(AnyIndex)); r.Exists() {
+			if n.rest = compileSchema(r, depth+1); n.rest == nil {
+				return nil
+			}
+		}
+	}
+	return n
+}
+
+func compileDisjunction(v Value, x *adt.Disjunction, depth int) *schemaNode {
+	n := &schemaNode{kind: adt.TopKind}
+	for _, d := range x.Values {
+		var dn *schemaNode
+		if w, ok := d.(*adt.Vertex); ok {
+			dn = compileSchema(makeValue(v.idx, w, v.parent_), depth+1)
+		} else {
+			dn = &schemaNode{kind: adt.TopKind}
+			if !dn.add(d) {
+				dn = nil
+			}
+		}
+		if dn == nil {
+			return nil
+		}
+		n.disjuncts = append(n.disjuncts, dn)
+	}
+	n.discriminator, n.byTag = discriminator(n.disjuncts)
+	return n
+}
+
+// discriminator returns a field that is present in all disjuncts, which
+// must be structs, with a distinct concrete string, boolean or null value
+// in each, along with the index of the disjunct for each value.
+func discriminator(disjuncts []*schemaNode) (string, map[string]int) {
+	if len(disjuncts) < 2 {
+		return "", nil
+	}
+	for _, d := range disjuncts {
+		if d.kind != adt.StructKind {
+			return "", nil
+		}
+	}
+	var names []string
+	for name := range disjuncts[0].fields {
+		names = append(names, name)
+	}
+	// Choose a field deterministically.
+	slices.Sort(names)
+	for _, name := range names {
+		byTag := map[string]int{}
+		for i, d := range disjuncts {
+			f := d.fields[name]
+			if f == nil || f.node.value == nil || len(f.node.disjuncts) > 0 {
+				break
+			}
+			tag, ok := scalarTag(f.node.value)
+			if !ok {
+				break
+			}
+			if _, dup := byTag[tag]; dup {
+				break
+			}
+			byTag[tag] = i
+		}
+		if len(byTag) == len(disjuncts) {
+			return name, byTag
+		}
+	}
+	return "", nil
+}
+
+// scalarTag returns a key that identifies the string, boolean or null
+// value x.
+func scalarTag(x any) (string, bool) {
+	switch x := x.(type) {
+	case *adt.String:
+		return "s" + x.Str, true
+	case string:
+		return "s" + x, true
+	case *adt.Bool:
+		return scalarTag(x.B)
+	case bool:
+		if x {
+			return "true", true
+		}
+		return "false", true
+	case *adt.Null, nil:
+		return "null", true
+	}
+	return "", false
+}
+
+// A schemaResult is the result of checking data against a schemaNode.
+type schemaResult int
+
+const (
+	schemaMismatch schemaResult = iota // the data is not an instance
+	schemaMatch                        // the data is an instance
+	schemaUnknown                      // unification is needed to decide
+)
+
+// check checks the decoded JSON value x against n.
+func (n *schemaNode) check(x any) schemaResult {
+	if n.disjuncts != nil {
+		return n.checkDisjuncts(x)
+	}
+	switch x := x.(type) {
+	case nil:
+		return n.checkScalar(adt.NullKind, x)
+	case bool:
+		return n.checkScalar(adt.BoolKind, x)
+	case string:
+		return n.checkScalar(adt.StringKind, x)
+	case json.Number:
+		k := adt.IntKind
+		if strings.ContainsAny(string(x), ".eE") {
+			k = adt.FloatKind
+		}
+		d, _, err := apd.NewFromString(string(x))
+		if err != nil {
+			return schemaUnknown
+		}
+		return n.checkScalar(k, d)
+	case []any:
+		if n.kind&adt.ListKind == 0 {
+			return schemaMismatch
+		}
+		if len(x) < len(n.elems) || n.rest == nil && len(x) > len(n.elems) {
+			return schemaMismatch
+		}
+		result := schemaMatch
+		for i, e := range x {
+			en := n.rest
+			if i < len(n.elems) {
+				en = n.elems[i]
+			}
+			result = both(result, en.check(e))
+		}
+		return result
+	case jsonObject:
+		if n.kind&adt.StructKind == 0 {
+			return schemaMismatch
+		}
+		result := schemaMatch
+		for key, value := range x {
+			f := n.fields[key]
+			if f == nil {
+				if n.closed {
+					return schemaMismatch
+				}
+				continue
+			}
+			result = both(result, f.node.check(value))
+		}
+		for name, f := range n.fields {
+			if _, ok := x[name]; !ok && !f.missingOK {
+				// The value is incomplete, which does not rule out
+				// a disjunct.
+				result = both(result, schemaUnknown)
+			}
+		}
+		return result
+	}
+	return schemaUnknown
+}
+
+// both returns the result for data with parts that have the results a and
+// b, where a mismatch of any part is a mismatch of the whole.
+func both(a, b schemaResult) schemaResult {
+	switch {
+	case a == schemaMismatch || b == schemaMismatch:
+		return schemaMismatch
+	case a == schemaUnknown || b == schemaUnknown:
+		return schemaUnknown
+	}
+	return schemaMatch
+}
+
+func (n *schemaNode) checkDisjuncts(x any) schemaResult {
+	if obj, ok := x.(jsonObject); ok && n.discriminator != "" {
+		if tag, ok := obj[n.discriminator]; ok {
+			if key, ok := scalarTag(tag); ok {
+				// All other disjuncts have a different value for the
+				// discriminator.
+				i, ok := n.byTag[key]
+				if !ok {
+					return schemaMismatch
+				}
+				return n.disjuncts[i].check(x)
+			}
+		}
+	}
+	matches, unknown := 0, false
+	for _, d := range n.disjuncts {
+		switch d.check(x) {
+		case schemaMatch:
+			matches++
+		case schemaUnknown:
+			unknown = true
+		}
+	}
+	switch {
+	case unknown:
+		return schemaUnknown
+	case matches == 0:
+		return schemaMismatch
+	case matches == 1:
+		return schemaMatch
+	}
+	// Unifying several disjuncts with a scalar results in the same value.
+	switch x.(type) {
+	case []any, jsonObject:
+		return schemaUnknown
+	}
+	return schemaMatch
+}
+
+// checkScalar checks the scalar x of kind k, where numbers are represented
+// as *apd.Decimal.
+func (n *schemaNode) checkScalar(k adt.Kind, x any) schemaResult {
+	if n.kind&k == 0 {
+		return schemaMismatch
+	}
+	if n.value != nil {
+		if r := compareScalar(n.value, k, x); r != schemaMatch {
+			return r
+		}
+	}
+	for _, b := range n.bounds {
+		if r := b.check(k, x); r != schemaMatch {
+			return r
+		}
+	}
+	return schemaMatch
+}
+
+// compareScalar reports whether the scalar x of kind k equals v.
+func compareScalar(v adt.Value, k adt.Kind, x any) schemaResult {
+	switch v := v.(type) {
+	case *adt.Num:
+		d, ok := x.(*apd.Decimal)
+		switch {
+		case !ok:
+			return schemaMismatch
+		case v.X.Cmp(d) != 0:
+			return schemaMismatch
+		case v.K != k:
+			// For instance, 1 and 1.0.
+			return schemaUnknown
+		}
+		return schemaMatch
+	case *adt.String:
+		if s, ok := x.(string); ok && s == v.Str {
+			return schemaMatch
+		}
+	case *adt.Bool:
+		if b, ok := x.(bool); ok && b == v.B {
+			return schemaMatch
+		}
+	case *adt.Null:
+		if x == nil {
+			return schemaMatch
+		}
+	default:
+		return schemaUnknown
+	}
+	return schemaMismatch
+}
+
+func (b *schemaBound) check(k adt.Kind, x any) schemaResult {
+	var cmp int
+	switch {
+	case b.re != nil:
+		s, ok := x.(string)
+		if !ok {
+			return schemaMismatch
+		}
+		if b.re.MatchString(s) == (b.op == adt.MatchOp) {
+			return schemaMatch
+		}
+		return schemaMismatch
+	case b.op == adt.NotEqualOp:
+		switch compareScalar(b.x, k, x) {
+		case schemaMatch:
+			return schemaMismatch
+		case schemaMismatch:
+			if b.x.Kind()&adt.NumberKind != 0 && k&adt.NumberKind == 0 {
+				// A number bound on a value of another kind.
+				return schemaUnknown
+			}
+			return schemaMatch
+		}
+		return schemaUnknown
+	case b.num != nil:
+		d, ok := x.(*apd.Decimal)
+		if !ok {
+			return schemaMismatch
+		}
+		cmp = d.Cmp(b.num)
+	default:
+		s, ok := x.(string)
+		if !ok {
+			return schemaMismatch
+		}
+		cmp = strings.Compare(s, b.str)
+	}
+	var ok bool
+	switch b.op {
+	case adt.LessThanOp:
+		ok = cmp < 0
+	case adt.LessEqualOp:
+		ok = cmp <= 0
+	case adt.GreaterThanOp:
+		ok = cmp > 0
+	case adt.GreaterEqualOp:
+		ok = cmp >= 0
+	}
+	if ok {
+		return schemaMatch
+	}
+	return schemaMismatch
+}
+
+// A jsonObject is a decoded JSON object.
+type jsonObject map[string]any
+
+// decodeJSON decodes data, representing objects as jsonObject and numbers
+// as json.Number. It reports false if data is not valid JSON or has an
+// object with duplicate keys, which unify rather than override each other.
+func decodeJSON(data []byte) (any, bool) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	x, ok := decodeJSONValue(d)
+	if !ok {
+		return nil, false
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, false
+	}
+	return x, true
+}
+
+func decodeJSONValue(d *json.Decoder) (any, bool) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, false
+	}
+	switch t {
+	case json.Delim('['):
+		a := []any{}
+		for d.More() {
+			x, ok := decodeJSONValue(d)
+			if !ok {
+				return nil, false
+			}
+			a = append(a, x)
+		}
+		_, err := d.Token()
+		return a, err == nil
+	case json.Delim('{'):
+		o := jsonObject{}
+		for d.More() {
+			t, err := d.Token()
+			if err != nil {
+				return nil, false
+			}
+			key := t.(string)
+			if _, dup := o[key]; dup {
+				return nil, false
+			}
+			x, ok := decodeJSONValue(d)
+			if !ok {
+				return nil, false
+			}
+			o[key] = x
+		}
+		_, err := d.Token()
+		return o, err == nil
+	}
+	return t, true
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cue
+
+import (
+	"fmt"
+	"testing"
+
+	"cuelang.org/go/internal/core/runtime"
+)
+
+func TestCompiledSchema(t *testing.T) {
+	testCases := []struct {
+		schema string
+
+		// compiled reports whether the schema is checked without
+		// unification.
+		compiled bool
+
+		// data maps JSON data to whether it is valid.
+		data map[string]bool
+	}{{
+		schema:   `int`,
+		compiled: true,
+		data: map[string]bool{
+			`1`:   true,
+			`1.5`: false,
+			`"a"`: false,
+			`1 2`: false,
+			`{`:   false,
+		},
+	}, {
+		schema:   `number & >=0 & <10`,
+		compiled: true,
+		data: map[string]bool{
+			`0`:    true,
+			`9.5`:  true,
+			`10`:   false,
+			`-1e3`: false,
+		},
+	}, {
+		schema:   `float`,
+		compiled: true,
+		data: map[string]bool{
+			`1.0`: true,
+			`1`:   false,
+		},
+	}, {
+		schema:   `1`,
+		compiled: true,
+		data: map[string]bool{
+			`1`:   true,
+			`2`:   false,
+			`1.0`: false,
+		},
+	}, {
+		schema:   `=~"^[a-z]+$" & !="foo" & <"x"`,
+		compiled: true,
+		data: map[string]bool{
+			`"abc"`: true,
+			`"foo"`: false,
+			`"ABC"`: false,
+			`"xyz"`: false,
+			`"\/"`:  false,
+		},
+	}, {
+		schema:   `[...!=null]`,
+		compiled: true,
+		data: map[string]bool{
+			`[1, "a"]`: true,
+			`[null]`:   false,
+		},
+	}, {
+		schema: `
+			#Pet: {
+				name!: string
+				age?:  int & >=0
+				kind:  *"dog" | "cat"
+				tags:  [...string]
+			}
+			#Pet
+		`,
+		compiled: true,
+		data: map[string]bool{
+			`{"name": "rex", "tags": []}`:                          true,
+			`{"name": "rex", "age": 3, "kind": "cat", "tags": []}`: true,
+			`{"name": "rex", "tags": ["a", 1]}`:                    false,
+			`{"age": 3, "tags": []}`:                               false,
+			`{"name": "rex"}`:                                      true,
+			`{"name": "rex", "tags": [], "owner": "bob"}`:          false,
+			`{"name": "rex", "tags": [], "kind": "cow"}`:           false,
+			`{"name": "rex", "name": "max", "tags": []}`:           false,
+			`{"name": "rex", "name": "rex", "tags": []}`:           true,
+			`[]`: false,
+		},
+	}, {
+		schema: `
+			#Circle: {kind: "circle", radius: number}
+			#Square: {kind: "square", side: number}
+			#Circle | #Square
+		`,
+		compiled: true,
+		data: map[string]bool{
+			`{"kind": "circle", "radius": 1}`: true,
+			`{"kind": "square", "side": 1}`:   true,
+			`{"kind": "square", "radius": 1}`: false,
+			`{"kind": "hexagon"}`:             false,
+			`{"radius": 1}`:                   true,
+			`{"side": 1}`:                     true,
+			`{}`:                              false,
+		},
+	}, {
+		schema:   `{a: int} | {b: int}`,
+		compiled: true,
+		data: map[string]bool{
+			// Both disjuncts remain, as the missing fields are
+			// incomplete.
+			`{"a": 1}`: false,
+			`{}`:       false,
+		},
+	}, {
+		schema:   `close({a: int}) | close({b: int})`,
+		compiled: true,
+		data: map[string]bool{
+			`{"a": 1}`:         true,
+			`{"a": 1, "b": 1}`: false,
+		},
+	}, {
+		schema:   `#A | #B, #A: {a: int}, #B: {b: int}`,
+		compiled: true,
+		data: map[string]bool{
+			`{"a": 1}`:         true,
+			`{"b": 1}`:         true,
+			`{"a": 1, "b": 1}`: false,
+		},
+	}, {
+		schema:   `[int, string, ...bool]`,
+		compiled: true,
+		data: map[string]bool{
+			`[1, "a"]`:       true,
+			`[1, "a", true]`: true,
+			`[1]`:            false,
+			`[1, "a", 2]`:    false,
+		},
+	}, {
+		schema:   `{a: int, b: *1 | int, c?: string}`,
+		compiled: true,
+		data: map[string]bool{
+			`{"a": 1}`:         true,
+			`{"a": 1, "d": 1}`: true,
+			`{"b": 1}`:         false,
+		},
+	}, {
+		// The value of b depends on the data.
+		schema:   `{a: int, b: a + 1}`,
+		compiled: false,
+		data: map[string]bool{
+			`{"a": 1}`:         true,
+			`{"a": 1, "b": 2}`: true,
+			`{"a": 1, "b": 3}`: false,
+		},
+	}, {
+		schema:   `{[=~"^x"]: int}`,
+		compiled: false,
+		data: map[string]bool{
+			`{"x1": 1}`:   true,
+			`{"x1": "a"}`: false,
+		},
+	}, {
+		schema: `
+			import "struct"
+
+			{a?: int} & struct.MinFields(1)
+		`,
+		compiled: false,
+		data: map[string]bool{
+			`{"a": 1}`: true,
+			`{}`:       false,
+		},
+	}, {
+		schema:   `#List, #List: {value: int, next?: #List}`,
+		compiled: false,
+		data: map[string]bool{
+			`{"value": 1, "next": {"value": 2}}`:   true,
+			`{"value": 1, "next": {"value": "a"}}`: false,
+		},
+	}}
+	ctx := (*Context)(runtime.New())
+	for i, tc := range testCases {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			v := ctx.CompileString(tc.schema)
+			s, err := v.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.root != nil; got != tc.compiled {
+				t.Errorf("compiled: got %v; want %v", got, tc.compiled)
+			}
+			for data, valid := range tc.data {
+				err := s.Validate([]byte(data))
+				if got := err == nil; got != valid {
+					t.Errorf("%s: got error %v; want valid %v", data, err, valid)
+				}
+				// The result must be that of unification, including the
+				// errors.
+				want := s.unify([]byte(data))
+				if fmt.Sprint(err) != fmt.Sprint(want) {
+					t.Errorf("%s: got error %v; want %v", data, err, want)
+				}
+				if x, ok := decodeJSON([]byte(data)); ok && s.root != nil && valid {
+					if r := s.root.check(x); r != schemaMatch {
+						t.Errorf("%s: not validated without unification: %v", data, r)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	ctx := (*Context)(runtime.New())
+	v := ctx.CompileString(`a: 1 & 2`)
+	if _, err := v.Compile(); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func BenchmarkCompiledSchema(b *testing.B) {
+	ctx := (*Context)(runtime.New())
+	v := ctx.CompileString(`
+		#Item: {
+			id!:    int & >0
+			name!:  string & =~"^[a-z]+$"
+			price?: number & >=0
+			tags:   [...string]
+		}
+		#Order: {
+			kind!:  "order"
+			items!: [...#Item]
+		}
+		#Refund: {
+			kind!:   "refund"
+			amount!: number
+		}
+		#Order | #Refund
+	`)
+	s, err := v.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	data := []byte(`{"kind": "order", "items": [
+		{"id": 1, "name": "apple", "price": 1.5, "tags": ["fruit"]},
+		{"id": 2, "name": "pear", "tags": []}
+	]}`)
+	b.Run("compiled", func(b *testing.B) {
+		for range b.N {
+			if err := s.Validate(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unify", func(b *testing.B) {
+		for range b.N {
+			if err := s.unify(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -279,7 +279,7 @@ func TestFragment(t *testing.T) {
 	//cue:path: #person
 	let PERSON = {
 		#x: {
-			children: [...#person]
+			children: [...#x]
 		}
 	}
 }`
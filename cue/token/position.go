@@ -17,6 +17,7 @@ package token
 import (
 	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 )
@@ -238,6 +239,11 @@ type File struct {
 	// lines and infos are protected by set.mutex
 	lines []index // lines contains the offset of the first character for each line (the first entry is always 0)
 	infos []lineInfo
+
+	// eofLine is set if the file ends with a newline, in which case a line
+	// starts at the end of the file. Such lines are not recorded in lines,
+	// but are needed to update lines when text is added to the end.
+	eofLine bool
 }
 
 // NewFile returns a new file with the given OS file name. The size provides the
@@ -248,7 +254,7 @@ func NewFile(filename string, deprecatedBase, size int) *File {
 	if deprecatedBase < 0 {
 		deprecatedBase = 1
 	}
-	return &File{sync.RWMutex{}, filename, index(deprecatedBase), index(size), []index{0}, nil}
+	return &File{sync.RWMutex{}, filename, index(deprecatedBase), index(size), []index{0}, nil, false}
 }
 
 // Name returns the file name of file f as registered with AddFile.
@@ -284,6 +290,8 @@ func (f *File) AddLine(offset int) {
 	f.mutex.Lock()
 	if i := len(f.lines); (i == 0 || f.lines[i-1] < x) && x < f.size {
 		f.lines = append(f.lines, x)
+	} else if x == f.size && x > 0 {
+		f.eofLine = true
 	}
 	f.mutex.Unlock()
 }
@@ -368,9 +376,75 @@ func (f *File) SetLinesForContent(content []byte) {
 	// set lines table
 	f.mutex.Lock()
 	f.lines = lines
+	f.eofLine = line > 0
 	f.mutex.Unlock()
 }
 
+// ApplyEdit updates f to reflect that the length bytes at offset were
+// replaced with text. It adjusts the size, the line offsets, and the
+// alternative line information of f for the edit, without requiring the
+// entire content of the file, which is more efficient than calling
+// [File.SetLinesForContent] for every change to a file that is being edited.
+//
+// Pos values obtained from f before the edit still refer to the offsets
+// before the edit. ApplyEdit panics if the replaced range is not within f.
+func (f *File) ApplyEdit(offset, length int, text []byte) {
+	start, end := index(offset), index(offset+length)
+	if start < 0 || length < 0 || end > f.size {
+		panic("illegal edit range")
+	}
+	delta := index(len(text) - length)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	lines := f.lines
+	if f.eofLine {
+		lines = append(lines, f.size)
+	}
+	// Lines starting within (start, end] follow a newline that is replaced.
+	i := searchInts(lines, start) + 1
+	j := searchInts(lines, end) + 1
+	for k := j; k < len(lines); k++ {
+		lines[k] += delta
+	}
+	var added []index
+	for k, b := range text {
+		if b == '\n' {
+			added = append(added, start+index(k)+1)
+		}
+	}
+	lines = slices.Replace(lines, i, j, added...)
+
+	f.size += delta
+	switch {
+	case f.size == 0:
+		lines = lines[:0]
+	case len(lines) == 0 || lines[0] != 0:
+		// Text was added to an empty file.
+		lines = slices.Insert(lines, 0, 0)
+	}
+	f.eofLine = false
+	if n := len(lines); n > 0 && lines[n-1] == f.size && f.size > 0 {
+		f.eofLine = true
+		lines = lines[:n-1]
+	}
+	f.lines = lines
+
+	infos := f.infos[:0]
+	for _, info := range f.infos {
+		switch x := index(info.Offset); {
+		case x <= start:
+		case x <= end:
+			continue // the directive was replaced
+		default:
+			info.Offset += int(delta)
+		}
+		infos = append(infos, info)
+	}
+	f.infos = infos
+}
+
 // A lineInfo object describes alternative file and line number
 // information (such as provided via a //line comment in a .go
 // file) for a given file offset.
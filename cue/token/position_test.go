@@ -171,6 +171,51 @@ func TestLineInfo(t *testing.T) {
 	}
 }
 
+func TestApplyEdit(t *testing.T) {
+	testCases := []struct {
+		content      string
+		offset, size int
+		text         string
+	}{
+		{"", 0, 0, "a\nb"},
+		{"a\nb\n", 0, 0, "x\n"},
+		{"a\nb\n", 4, 0, "c"},
+		{"a\nb\n", 4, 0, "c\n"},
+		{"a\nb\n", 1, 1, ""},
+		{"a\nb\n", 1, 3, ""},
+		{"a\nb\n", 0, 4, ""},
+		{"a\nbc\nd", 3, 0, "\n\n"},
+		{"a\nbc\nd", 2, 4, "x\ny\nz"},
+		{"a\n\n\nb", 1, 2, "\n"},
+		{"a\n\n\nb", 2, 1, ""},
+	}
+	for _, tc := range testCases {
+		f := NewFile("edit", -1, len(tc.content))
+		f.SetLinesForContent([]byte(tc.content))
+		f.ApplyEdit(tc.offset, tc.size, []byte(tc.text))
+
+		content := tc.content[:tc.offset] + tc.text + tc.content[tc.offset+tc.size:]
+		want := NewFile("want", -1, len(content))
+		want.SetLinesForContent([]byte(content))
+		if f.Size() != want.Size() {
+			t.Errorf("%q: got size %d; want %d", content, f.Size(), want.Size())
+		}
+		if !slices.Equal(f.Lines(), want.Lines()) {
+			t.Errorf("%q: got lines %v; want %v", content, f.Lines(), want.Lines())
+		}
+	}
+
+	// Alternative line information moves with the text it applies to.
+	f := NewFile("foo", -1, 10)
+	f.SetLinesForContent([]byte("a\nb\nc\nd\ne\n"))
+	f.AddLineInfo(2, "bar", 10)
+	f.AddLineInfo(6, "baz", 20)
+	f.ApplyEdit(0, 1, []byte("x\ny"))
+	checkPos(t, "line info", f.Position(f.Pos(4, 0)), Position{"bar", 4, 10, 1})
+	f.ApplyEdit(4, 4, nil)
+	checkPos(t, "removed line info", f.Position(f.Pos(4, 0)), Position{"bar", 4, 10, 1})
+}
+
 func TestPositionFor(t *testing.T) {
 	src := []byte(`
 foo
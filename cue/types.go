@@ -16,11 +16,13 @@ package cue
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
+	"slices"
 	"strings"
 
 	"github.com/cockroachdb/apd/v3"
@@ -722,6 +724,45 @@ func (v Value) Default() (Value, bool) {
 	return makeValue(v.idx, d, v.parent_), true
 }
 
+// Freeze fully evaluates v, including all of its fields, elements and
+// disjuncts, and returns v.
+//
+// CUE evaluates values lazily and caches the results in the value itself,
+// which is why values are in general not safe for concurrent use. A frozen
+// value has no evaluation left to do, so its read-only methods, such as
+// LookupPath, Fields, List, Decode, Syntax, Validate and the methods that
+// return concrete Go values, may be called from multiple goroutines
+// concurrently, as may those of the values obtained through them. Methods
+// that create new values, like Unify and FillPath, must still not be used
+// concurrently with each other.
+//
+// Freeze must not be called concurrently with other methods of v.
+func (v Value) Freeze() Value {
+	if v.v == nil {
+		return v
+	}
+	freeze(v.ctx(), v.v, map[*adt.Vertex]bool{})
+	return v
+}
+
+// freeze finalizes x and all vertices reachable from it.
+func freeze(ctx *adt.OpContext, x *adt.Vertex, seen map[*adt.Vertex]bool) {
+	for ; x != nil && !seen[x]; x, _ = x.BaseValue.(*adt.Vertex) {
+		seen[x] = true
+		x.Finalize(ctx)
+		for _, a := range x.Arcs {
+			freeze(ctx, a, seen)
+		}
+		if d, ok := x.BaseValue.(*adt.Disjunction); ok {
+			for _, dv := range d.Values {
+				if w, ok := dv.(*adt.Vertex); ok {
+					freeze(ctx, w, seen)
+				}
+			}
+		}
+	}
+}
+
 // Label reports he label used to obtain this value from the enclosing struct.
 //
 // TODO: get rid of this somehow. Probably by including a FieldInfo struct
@@ -838,6 +879,11 @@ func (v Value) Syntax(opts ...Option) ast.Node {
 		InlineImports:   o.inlineImports,
 		Fragment:        o.raw,
 	}
+	if o.fieldOrder != nil {
+		p.SortFields = func(a, b *adt.Vertex) int {
+			return o.fieldOrder(makeValue(v.idx, a, nil), makeValue(v.idx, b, nil))
+		}
+	}
 
 	pkgID := v.instance().ID()
 
@@ -919,6 +965,41 @@ func (v Value) Doc() []*ast.CommentGroup {
 	return export.ExtractDoc(v.v)
 }
 
+// A DocComment is a documentation comment along with the declaration from
+// which it originates.
+type DocComment struct {
+	// Comment is the documentation comment.
+	Comment *ast.CommentGroup
+
+	// Source is the declaration to which Comment is attached. It is an
+	// *ast.Field for field documentation or an *ast.File for package
+	// documentation.
+	Source ast.Node
+}
+
+// Pos reports the position of the comment.
+func (d DocComment) Pos() token.Pos {
+	return d.Comment.Pos()
+}
+
+// DocComments returns the documentation comments of all declarations that
+// contribute to v, in the same order as [Value.Doc], along with the
+// declaration from which each comment originates.
+//
+// Unlike [Value.Doc], comments with identical text are not merged if they
+// originate from different declarations.
+func (v Value) DocComments() []DocComment {
+	if v.v == nil {
+		return nil
+	}
+	docs := export.ExtractDocOrigins(v.v)
+	a := make([]DocComment, len(docs))
+	for i, d := range docs {
+		a[i] = DocComment{Comment: d.Comment, Source: d.Decl}
+	}
+	return a
+}
+
 // Source returns the original node for this value. The return value may not
 // be an [ast.Expr]. For instance, a struct kind may be represented by a
 // struct literal, a field comprehension, or a file. It returns nil for
@@ -990,6 +1071,78 @@ func (v Value) Pos() token.Pos {
 	return p
 }
 
+// FindByPos returns the most specific value within v, including v itself,
+// whose source spans the given position, such as the field value or list
+// element defined by the expression at that position. This allows mapping
+// a position in a source file, such as that of a cursor in an editor, to
+// the corresponding evaluated value.
+//
+// Regular fields, definitions, hidden fields, optional fields and list
+// elements are searched. If multiple values span pos, the one with the
+// smallest source is returned. Of those, the first one found in a
+// depth-first traversal is returned: for instance, a value defined in a
+// definition is returned in preference to the same value in a field that
+// is unified with that definition if the definition comes first.
+// FindByPos reports false if no value spans pos.
+func (v Value) FindByPos(pos token.Pos) (Value, bool) {
+	if v.v == nil || !pos.IsValid() {
+		return Value{}, false
+	}
+	var (
+		found Value
+		size  = -1
+	)
+	var find func(v Value)
+	find = func(v Value) {
+		if n := v.spanSize(pos); n >= 0 && (size < 0 || n < size) {
+			found, size = v, n
+		}
+		switch v.IncompleteKind() {
+		case StructKind:
+			iter, err := v.Fields(Definitions(true), Hidden(true), Optional(true))
+			if err != nil {
+				return
+			}
+			for iter.Next() {
+				find(iter.Value())
+			}
+		case ListKind:
+			iter, err := v.List()
+			if err != nil {
+				return
+			}
+			for iter.Next() {
+				find(iter.Value())
+			}
+		}
+	}
+	find(v)
+	return found, size >= 0
+}
+
+// spanSize returns the size of the smallest source of the conjuncts of v
+// that spans pos, or -1 if there is no such source.
+func (v Value) spanSize(pos token.Pos) int {
+	size := -1
+	v.v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		src := c.Source()
+		if src == nil {
+			return true
+		}
+		start, end := src.Pos(), src.End()
+		if !start.IsValid() || !end.IsValid() || start.Filename() != pos.Filename() {
+			return true
+		}
+		if start.Offset() <= pos.Offset() && pos.Offset() <= end.Offset() {
+			if n := end.Offset() - start.Offset(); size < 0 || n < size {
+				size = n
+			}
+		}
+		return true
+	})
+	return size
+}
+
 // TODO: IsFinal: this value can never be changed.
 
 // Allows reports whether a field with the given selector could be added to v.
@@ -1299,6 +1452,11 @@ func (v Value) structValOpts(ctx *adt.OpContext, o options) (s structValue, err
 		}
 		arcs = append(arcs, arc)
 	}
+	if o.fieldOrder != nil {
+		slices.SortStableFunc(arcs, func(a, b *adt.Vertex) int {
+			return o.fieldOrder(makeChildValue(v, a), makeChildValue(v, b))
+		})
+	}
 	return structValue{ctx, v, obj, arcs}, nil
 }
 
@@ -1554,13 +1712,51 @@ func (v hiddenValue) Fill(x interface{}, path ...string) Value {
 // Any reference in v referring to the value at the given path will resolve to x
 // in the newly created value. The resulting value is not validated.
 func (v Value) FillPath(p Path, x interface{}) Value {
+	if v.v == nil {
+		// TODO: panic here?
+		return v
+	}
+	return v.FillPaths(Fill{Path: p, Value: x})
+}
+
+// A Fill specifies a value to unify with the value at a path, for use with
+// [Value.FillPaths].
+type Fill struct {
+	Path  Path
+	Value interface{}
+}
+
+// FillPaths creates a new value by unifying v with each of the given values
+// at their paths. Each value is interpreted as described for [Value.FillPath].
+//
+// The result is the same as that of calling FillPath for each of the fills
+// in turn, except that unresolved identifiers in an [ast.Expr] are resolved
+// within v rather than within the result of earlier fills. As the result is
+// evaluated only once, this is much more efficient when filling in many
+// values.
+func (v Value) FillPaths(fills ...Fill) Value {
 	if v.v == nil {
 		// TODO: panic here?
 		return v
 	}
 	ctx := v.ctx()
+	n := &adt.Vertex{}
+	for _, f := range fills {
+		expr, err := v.fillExpr(ctx, f.Path, f.Value)
+		if err != nil {
+			return newErrValue(v, err)
+		}
+		n.AddConjunct(adt.MakeRootConjunct(nil, expr))
+	}
+	n.Finalize(ctx)
+	w := makeValue(v.idx, n, v.parent_)
+	return v.Unify(w)
+}
+
+// fillExpr returns an expression that places the value x at path p.
+func (v Value) fillExpr(ctx *adt.OpContext, p Path, x interface{}) (adt.Expr, *adt.Bottom) {
 	if err := p.Err(); err != nil {
-		return newErrValue(v, mkErr(nil, 0, "invalid path: %v", err))
+		return nil, mkErr(nil, 0, "invalid path: %v", err)
 	}
 	var expr adt.Expr
 	switch x := x.(type) {
@@ -1618,11 +1814,7 @@ func (v Value) FillPath(p Path, x interface{}) Value {
 			expr = &adt.StructLit{Decls: []adt.Decl{f}}
 		}
 	}
-	n := &adt.Vertex{}
-	n.AddConjunct(adt.MakeRootConjunct(nil, expr))
-	n.Finalize(ctx)
-	w := makeValue(v.idx, n, v.parent_)
-	return v.Unify(w)
+	return expr, nil
 }
 
 // Template returns a function that represents the template definition for a
@@ -1806,6 +1998,98 @@ func (v Value) UnifyAccept(w Value, accept Value) Value {
 	return makeValue(v.idx, n, v.parent_)
 }
 
+// A UnifyMode defines how closedness rules apply when unifying values with
+// [Value.UnifyWith].
+type UnifyMode int
+
+const (
+	// UnifyDefault applies the closedness rules of the unified values, just
+	// like [Value.Unify].
+	UnifyDefault UnifyMode = iota
+
+	// UnifyOpen disregards the closedness of the unified values, recursively,
+	// allowing fields of either value that would otherwise not be allowed by
+	// the other.
+	//
+	// UnifyOpen is not supported by the old evaluator (evalv2), in which case
+	// the result is an error.
+	UnifyOpen
+
+	// UnifyClosed unifies values like UnifyDefault and then closes the result
+	// recursively, as with [Value.CloseAll].
+	UnifyClosed
+)
+
+// UnifyWith is like [Value.Unify](w), but applies the closedness rules
+// selected by mode.
+func (v Value) UnifyWith(w Value, mode UnifyMode) Value {
+	switch mode {
+	case UnifyOpen:
+		return v.unifyOpen(w)
+	case UnifyClosed:
+		return v.Unify(w).CloseAll()
+	}
+	return v.Unify(w)
+}
+
+func (v Value) unifyOpen(w Value) Value {
+	if v.v == nil {
+		return w
+	}
+	if w.v == nil {
+		return v
+	}
+
+	ctx := v.ctx()
+	if ctx.Version == internal.EvalV2 {
+		const msg = "unify: UnifyOpen is not supported by evalv2"
+		return newErrValue(v, mkErr(v.v, msg))
+	}
+
+	n := &adt.Vertex{}
+
+	cv := adt.MakeRootConjunct(nil, v.v)
+	cw := adt.MakeRootConjunct(nil, w.v)
+	cv.CloseInfo.FromEmbed = true
+	cw.CloseInfo.FromEmbed = true
+	n.AddConjunct(cv)
+	n.AddConjunct(cw)
+	n.Finalize(ctx)
+
+	n.Parent = v.v.Parent
+	n.Label = v.v.Label
+
+	return makeValue(v.idx, n, v.parent_)
+}
+
+// CloseAll reports v with all its structs closed recursively, as if v were
+// a definition. Fields that are not defined in v are not allowed when
+// unifying the result with other values.
+func (v Value) CloseAll() Value {
+	if v.v == nil {
+		return v
+	}
+
+	n := &adt.Vertex{}
+	ctx := v.ctx()
+
+	c := adt.MakeRootConjunct(nil, v.v)
+	if ctx.Version == internal.EvalV2 {
+		var root adt.CloseInfo
+		c.CloseInfo = root.SpawnRef(v.v, true, nil)
+	} else {
+		c.CloseInfo.FromDef = true
+	}
+	n.AddConjunct(c)
+	n.Finalize(ctx)
+
+	n.Parent = v.v.Parent
+	n.Label = v.v.Label
+	n.ClosedRecursive = true
+
+	return makeValue(v.idx, n, v.parent_)
+}
+
 // Equals reports whether two values are equal, ignoring optional fields.
 // The result is undefined for incomplete values.
 func (v Value) Equals(other Value) bool {
@@ -1858,7 +2142,10 @@ func (v Value) ReferencePath() (root Value, p Path) {
 	}
 	ctx := v.ctx()
 
-	env, expr := c.EnvExpr()
+	env, expr := referenceExpr(v.v, c)
+	if expr == nil {
+		return Value{}, Path{}
+	}
 
 	x, path := reference(v.idx, ctx, env, expr)
 	if x == nil {
@@ -1872,6 +2159,43 @@ func (v Value) ReferencePath() (root Value, p Path) {
 	return makeValue(v.idx, x, nil), Path{path: path}
 }
 
+// referenceExpr returns the expression of conjunct c of v that may be a
+// reference and the environment in which it is to be resolved. It looks
+// through comprehensions and structs that consist of a single embedded
+// expression, such as the elements of a list comprehension. It returns a
+// nil expression if the expression cannot be determined.
+func referenceExpr(v *adt.Vertex, c adt.Conjunct) (*adt.Environment, adt.Expr) {
+	env, elem := c.Env, c.Elem()
+	for {
+		switch x := elem.(type) {
+		case *adt.Comprehension:
+			// The value of a comprehension refers to the variables of its
+			// clauses, which are only known if it yielded a single result.
+			envs := x.Envs()
+			if len(envs) != 1 {
+				return nil, nil
+			}
+			env, elem = adt.EnvExpr(envs[0], x)
+			continue
+
+		case *adt.StructLit:
+			if len(x.Decls) != 1 {
+				break
+			}
+			switch d := x.Decls[0].(type) {
+			case *adt.Comprehension:
+				elem = d
+				continue
+			case adt.Expr:
+				env = &adt.Environment{Up: env, Vertex: v}
+				elem = d
+				continue
+			}
+		}
+		return env, adt.ToExpr(elem)
+	}
+}
+
 func reference(rt *runtime.Runtime, c *adt.OpContext, env *adt.Environment, r adt.Expr) (inst *adt.Vertex, path []Selector) {
 	ctx := c
 	defer ctx.PopState(ctx.PushState(env, r.Source()))
@@ -1886,6 +2210,15 @@ func reference(rt *runtime.Runtime, c *adt.OpContext, env *adt.Environment, r ad
 
 	case *adt.FieldReference:
 		env := ctx.Env(x.UpCount)
+		if a := env.Vertex.LookupRaw(x.Label); a != nil && a.IsDynamic {
+			// x refers to a variable of a for clause. The value variable
+			// refers to an element of the source of the for clause; the
+			// key variable is not a reference.
+			if src, ok := a.BaseValue.(*adt.Vertex); ok {
+				inst, path = mkPath(rt, nil, src)
+			}
+			break
+		}
 		inst, path = mkPath(rt, nil, env.Vertex)
 		path = appendSelector(path, featureToSel(x.Label, rt))
 
@@ -1941,6 +2274,10 @@ type options struct {
 	ignoreClosedness  bool // used for comparing APIs
 	docs              bool
 	disallowCycles    bool // implied by concrete
+	requireRegular    bool
+	fieldOrder        func(a, b Value) int
+	maxErrors         int
+	sortErrors        bool
 }
 
 // An Option defines modes of evaluation.
@@ -1983,6 +2320,37 @@ func Concrete(concrete bool) Option {
 	}
 }
 
+// RequireRegular reports regular fields without a concrete value as
+// missing required fields when validating. Schemas often use regular
+// fields, rather than required fields, for values that data must supply;
+// with this option, data that does not set such fields fails validation
+// even when concrete values are not otherwise required.
+//
+// Fields within definitions are not checked.
+//
+// This option only applies to [Value.Validate].
+func RequireRegular(require bool) Option {
+	return func(p *options) { p.requireRegular = require }
+}
+
+// MaxErrors limits the number of errors reported to n, if n is positive.
+// Validation stops once enough errors have been found, unless errors are
+// sorted with [SortErrors], in which case the first n errors in sort order
+// are reported.
+//
+// This option only applies to [Value.Validate].
+func MaxErrors(n int) Option {
+	return func(p *options) { p.maxErrors = n }
+}
+
+// SortErrors sorts the reported errors by position, then path, then message,
+// making the order independent of evaluation order. See [errors.Sorted].
+//
+// This option only applies to [Value.Validate].
+func SortErrors(sort bool) Option {
+	return func(p *options) { p.sortErrors = sort }
+}
+
 // InlineImports causes references to values within imported packages to be
 // inlined. References to builtin packages are not inlined.
 func InlineImports(expand bool) Option {
@@ -2083,6 +2451,79 @@ func Attributes(include bool) Option {
 	return func(p *options) { p.omitAttrs = !include }
 }
 
+// FieldOrder sets the order in which the fields of structs are reported by
+// [Value.Fields] and [Value.Syntax]. The cmp function compares two fields of
+// the same struct as in [slices.SortStableFunc]; fields for which it returns 0
+// keep their default order. [SourceOrder] and [AlphabeticalOrder] are
+// predefined orderings.
+//
+// By default, fields are ordered topologically, which depends on the order
+// in which they are declared across files and may therefore change when
+// declarations are moved between files.
+func FieldOrder(cmp func(a, b Value) int) Option {
+	return func(p *options) { p.fieldOrder = cmp }
+}
+
+// SourceOrder orders fields by the position at which they are first
+// declared, comparing file names first and offsets within a file second.
+// Fields without a position sort last.
+//
+// It is intended to be used with [FieldOrder].
+func SourceOrder(a, b Value) int {
+	return comparePos(firstPos(a), firstPos(b))
+}
+
+// firstPos reports the first position, in source order, at which a field
+// contributing to v is declared.
+func firstPos(v Value) (pos token.Pos) {
+	if v.v == nil {
+		return token.NoPos
+	}
+	v.v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		src := c.Field().Source()
+		if src == nil {
+			return true
+		}
+		if p := src.Pos(); p.IsValid() && (!pos.IsValid() || comparePos(p, pos) < 0) {
+			pos = p
+		}
+		return true
+	})
+	return pos
+}
+
+func comparePos(a, b token.Pos) int {
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		return cmp.Compare(boolToInt(!a.IsValid()), boolToInt(!b.IsValid()))
+	case a.Filename() != b.Filename():
+		return strings.Compare(a.Filename(), b.Filename())
+	}
+	return cmp.Compare(a.Offset(), b.Offset())
+}
+
+// AlphabeticalOrder orders fields by the string representation of their
+// selector.
+//
+// It is intended to be used with [FieldOrder].
+func AlphabeticalOrder(a, b Value) int {
+	return strings.Compare(fieldSelector(a), fieldSelector(b))
+}
+
+func fieldSelector(v Value) string {
+	if v.v == nil {
+		return ""
+	}
+	return v.v.Label.SelectorString(v.idx)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
 func getOptions(opts []Option) (o options) {
 	o.updateOptions(opts)
 	return
@@ -2110,13 +2551,29 @@ func (v Value) Validate(opts ...Option) error {
 		Final:          o.final,
 		DisallowCycles: o.disallowCycles,
 		AllErrors:      true,
+		RequireRegular: o.requireRegular,
+	}
+	if !o.sortErrors {
+		cfg.MaxErrors = o.maxErrors
 	}
 
 	b := validate.Validate(v.ctx(), v.v, cfg)
-	if b != nil {
-		return v.toErr(b)
+	if b == nil {
+		return nil
 	}
-	return nil
+	err := v.toErr(b)
+	if o.sortErrors {
+		err = errors.Sorted(err)
+	}
+	if o.maxErrors > 0 {
+		if errs := errors.Errors(err); len(errs) > o.maxErrors {
+			err = nil
+			for _, e := range errs[:o.maxErrors] {
+				err = errors.Append(err, e)
+			}
+		}
+	}
+	return err
 }
 
 // Walk descends into all values of v, calling f. If f returns false, Walk
@@ -2462,3 +2919,72 @@ process:
 	}
 	return op, a
 }
+
+// A Disjunct describes a branch of a disjunction.
+type Disjunct struct {
+	// Value holds the value of the branch, unified with the other values
+	// that constrain the disjunction.
+	Value Value
+
+	// Default reports whether the branch is marked as a default.
+	Default bool
+
+	// Positions holds the source positions of the branch, sorted and
+	// without duplicates.
+	Positions []token.Pos
+}
+
+// Disjuncts reports the branches of v if v is a disjunction, or nil
+// otherwise. Branches of nested disjunctions are included as branches of v
+// and branches that are eliminated by unification are excluded. Defaults
+// are reported before other branches.
+//
+// Disjuncts can be used to present the valid variants of a value, for
+// instance to let a user pick one.
+func (v Value) Disjuncts() []Disjunct {
+	if v.v == nil {
+		return nil
+	}
+	ctx := v.ctx()
+	v.v.Finalize(ctx)
+	d, ok := v.v.DerefValue().BaseValue.(*adt.Disjunction)
+	if !ok {
+		return nil
+	}
+	a := make([]Disjunct, 0, len(d.Values))
+	for i, x := range d.Values {
+		var w Value
+		var positions []token.Pos
+		if n, ok := x.(*adt.Vertex); ok {
+			w = makeChildValue(v.parent(), n)
+			n.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+				if p := pos(c.Elem()); p != token.NoPos {
+					positions = append(positions, p)
+				}
+				return true
+			})
+			for _, s := range n.Structs {
+				if p := pos(s.StructLit); p != token.NoPos {
+					positions = append(positions, p)
+				}
+			}
+			if b, ok := n.BaseValue.(adt.Value); ok {
+				if p := pos(b); p != token.NoPos {
+					positions = append(positions, p)
+				}
+			}
+		} else {
+			w = remakeFinal(v, x)
+			if p := pos(x); p != token.NoPos {
+				positions = append(positions, p)
+			}
+		}
+		slices.SortFunc(positions, token.Pos.Compare)
+		a = append(a, Disjunct{
+			Value:     w,
+			Default:   i < d.NumDefaults,
+			Positions: slices.Compact(positions),
+		})
+	}
+	return a
+}
@@ -16,13 +16,16 @@ package cue_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -30,6 +33,8 @@ import (
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/astinternal"
 	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/debug"
@@ -71,7 +76,7 @@ func TestAPI(t *testing.T) {
 			res := runSpec.Unify(v)
 			return res
 		},
-		want: "_|_ // #runSpec.ction: field not allowed",
+		want: "_|_ // #runSpec.ction: field not allowed (did you mean action?)",
 
 		skip: true,
 	}, {
@@ -87,7 +92,7 @@ func TestAPI(t *testing.T) {
 			res := runSpec.Unify(v)
 			return res
 		},
-		want: "_|_ // #runSpec.action.Foo: field not allowed",
+		want: "_|_ // #runSpec.action.Foo: field not allowed (did you mean foo?)",
 	}, {
 		input: `
 		#runSpec: v: {action: foo: int}
@@ -101,7 +106,7 @@ func TestAPI(t *testing.T) {
 			res := w.Unify(v)
 			return res
 		},
-		want: "_|_ // w.ction: field not allowed",
+		want: "_|_ // w.ction: field not allowed (did you mean action?)",
 	}, {
 		// Issue #1879
 		input: `
@@ -633,6 +638,59 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestErrorCode(t *testing.T) {
+	testCases := []struct {
+		value string
+		code  errors.Code
+	}{{
+		value: `a: 1 & 2`,
+		code:  errors.Conflict,
+	}, {
+		value: `a: int & "foo"`,
+		code:  errors.Conflict,
+	}, {
+		value: `a: _|_`,
+		code:  errors.User,
+	}, {
+		value: `a: b + 1, b: int`,
+		code:  errors.Incomplete,
+	}, {
+		value: `a: b: a`,
+		code:  errors.StructuralCycle,
+	}, {
+		value: `a: >0 & 0`,
+		code:  errors.OutOfBound,
+	}, {
+		value: `a: =~"^[a-z]+$" & "A"`,
+		code:  errors.OutOfBound,
+	}, {
+		value: `#D: {b: int}, a: #D & {c: 1}`,
+		code:  errors.NotAllowed,
+	}, {
+		value: `#D: {name: string}, a: #D & {nmae: "x"}`,
+		code:  errors.NotAllowed,
+	}, {
+		value: `a: 1 / 0`,
+		code:  errors.Eval,
+	}}
+	for _, tc := range testCases {
+		cuetdtest.FullMatrix.Run(t, tc.value, func(t *testing.T, m *cuetdtest.M) {
+			err := getValue(m, tc.value).Validate(cue.Concrete(true))
+			if code := errors.CodeOf(err); code != tc.code {
+				t.Errorf("got code %q; want %q (error: %v)", code, tc.code, err)
+			}
+
+			b, err := json.Marshal(err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := `"code":"` + string(tc.code) + `"`; !strings.Contains(string(b), want) {
+				t.Errorf("JSON %s does not contain %s", b, want)
+			}
+		})
+	}
+}
+
 func TestNull(t *testing.T) {
 	testCases := []struct {
 		value string
@@ -1387,6 +1445,52 @@ func TestFillPath(t *testing.T) {
 	})
 }
 
+func TestFillPaths(t *testing.T) {
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		ctx := m.CueContext()
+
+		v := mustCompile(t, ctx, `
+		a: int
+		b: c: string
+		l: [...int]
+		d: 2
+		`)
+		v = v.FillPaths(
+			cue.Fill{Path: cue.ParsePath("a"), Value: 1},
+			cue.Fill{Path: cue.ParsePath("b.c"), Value: "foo"},
+			cue.Fill{Path: cue.ParsePath("l[1]"), Value: 2},
+			cue.Fill{Path: cue.ParsePath("e"), Value: ast.NewIdent("d")},
+			cue.Fill{Path: cue.ParsePath("l"), Value: ctx.CompileString("[1, ...]")},
+		)
+		w := mustCompile(t, ctx, `
+		a: 1
+		b: c: "foo"
+		l: [1, 2]
+		d: 2
+		e: 2
+		`)
+		if diff := cmp.Diff(goValue(v), goValue(w)); diff != "" {
+			t.Errorf("\ngot:  %s\nwant: %s", v, w)
+		}
+
+		v = mustCompile(t, ctx, `a: int`).FillPaths(
+			cue.Fill{Path: cue.ParsePath("a"), Value: 1},
+			cue.Fill{Path: cue.ParsePath("a"), Value: 2},
+		)
+		if err := v.Err(); err == nil {
+			t.Errorf("unexpected success for conflicting fills")
+		}
+
+		v = mustCompile(t, ctx, `a: int`).FillPaths(
+			cue.Fill{Path: cue.ParsePath("a"), Value: 1},
+			cue.Fill{Path: cue.ParsePath("a."), Value: 2},
+		)
+		if err := v.Err(); err == nil || !strings.Contains(err.Error(), "invalid path") {
+			t.Errorf("got %v; want invalid path error", err)
+		}
+	})
+}
+
 func TestFillPathError(t *testing.T) {
 	testCases := []struct {
 		in   string
@@ -1795,6 +1899,107 @@ func TestDefaults(t *testing.T) {
 	}
 }
 
+func TestDisjuncts(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		want  string
+	}{{
+		name:  "scalars",
+		value: `"x" | *"y" | ("z" | 1)`,
+		want:  `*"y"@1:11 "x"@1:4 "z"@1:18 1@1:24`,
+	}, {
+		name: "structs",
+		value: `#A | *#B | (#C | {kind: "d"})
+		#A: {kind: "a", x: int}
+		#B: {kind: "b", y: string}
+		#C: {kind: "c"}`,
+		want: `*{kind:"b",y:string}@3:7 {kind:"a",x:int}@2:7 {kind:"c"}@4:7 {kind:"d"}@1:21`,
+	}, {
+		name:  "eliminated by unification",
+		value: `("x" | *"y" | 1) & string`,
+		want:  `*"y"@1:12 "x"@1:5`,
+	}, {
+		name:  "not a disjunction",
+		value: `int`,
+		want:  ``,
+	}}
+	for _, tc := range testCases {
+		cuetdtest.FullMatrix.Run(t, tc.name, func(t *testing.T, m *cuetdtest.M) {
+			v := getValue(m, "a: "+tc.value).LookupPath(cue.ParsePath("a"))
+
+			var got []string
+			for _, d := range v.Disjuncts() {
+				s := compactRawStr(d.Value)
+				if d.Default {
+					s = "*" + s
+				}
+				for _, p := range d.Positions {
+					s += fmt.Sprintf("@%d:%d", p.Line(), p.Column())
+				}
+				got = append(got, s)
+			}
+			if got := strings.Join(got, " "); got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	const src = `
+	#Def: {
+		kind: *"a" | "b"
+		n:    int | *1
+	}
+	x: #Def & {n: 2}
+	y: [for i, v in [1, 2, 3] {v + i}]
+	z: {for k, v in {a: 1, b: 2} {(k): v * 2}}
+	s: x.kind + "-" + strings.Join(["p", "q"], ",")
+	l: len(y)
+	o: *{a: 1} | {b: 2}
+	`
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		v := getValue(m, "import \"strings\"\n"+src).Freeze()
+		want := fmt.Sprint(v)
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got := fmt.Sprint(v); got != want {
+					t.Errorf("got %s; want %s", got, want)
+				}
+				if err := v.Validate(cue.Concrete(true)); err != nil {
+					t.Error(err)
+				}
+				s, err := v.LookupPath(cue.ParsePath("s")).String()
+				if err != nil || s != "a-p,q" {
+					t.Errorf("got %q, %v; want \"a-p,q\"", s, err)
+				}
+				iter, err := v.Fields(cue.All())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for iter.Next() {
+					d, _ := iter.Value().Default()
+					_ = d.Syntax(cue.Final())
+				}
+				var x struct {
+					Y []int
+					Z map[string]int
+				}
+				if err := v.Decode(&x); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
+
 func TestLen(t *testing.T) {
 	testCases := []struct {
 		input  string
@@ -2260,6 +2465,140 @@ func TestUnifyAccept(t *testing.T) {
 	})
 }
 
+func TestUnifyWith(t *testing.T) {
+	type testCase struct {
+		value string
+		mode  cue.UnifyMode
+		want  string
+		v2    string // result with evalv2, if different
+	}
+	testCases := []testCase{{
+		value: `v: {a: 1}, w: {b: 2}`,
+		mode:  cue.UnifyDefault,
+		want:  `{"a":1,"b":2}`,
+	}, {
+		value: `v: #A, w: {a: 1, b: 2}, #A: {a: int}`,
+		mode:  cue.UnifyDefault,
+		want:  `v.b: field not allowed`,
+	}, {
+		value: `v: #A, w: {a: 1, b: 2}, #A: {a: int}`,
+		mode:  cue.UnifyOpen,
+		want:  `{"a":1,"b":2}`,
+		v2:    "v: unify: UnifyOpen is not supported by evalv2",
+	}, {
+		value: `v: {x: #A}, w: {x: {a: 1, b: 2}}, #A: {a: int}`,
+		mode:  cue.UnifyOpen,
+		want:  `{"x":{"a":1,"b":2}}`,
+		v2:    "v: unify: UnifyOpen is not supported by evalv2",
+	}, {
+		value: `v: {a: 1, x: {}}, w: {x: {}}`,
+		mode:  cue.UnifyClosed,
+		want:  `{"a":1,"x":{}}`,
+	}}
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		tdtest.Run(t, testCases, func(t *cuetest.T, tc *testCase) {
+			v := getValue(m, tc.value)
+			x := v.LookupPath(cue.ParsePath("v"))
+			y := v.LookupPath(cue.ParsePath("w"))
+			got := unifyResult(x.UnifyWith(y, tc.mode))
+			if tc.v2 != "" && m.Name() == "v2" {
+				t.Equal(got, tc.v2)
+				return
+			}
+			t.Equal(got, tc.want)
+		})
+	})
+}
+
+func TestCloseAll(t *testing.T) {
+	type testCase struct {
+		value string
+		want  string
+	}
+	testCases := []testCase{{
+		value: `v: {a: 1}, w: {a: 1}`,
+		want:  `{"a":1}`,
+	}, {
+		value: `v: {a: 1}, w: {b: 2}`,
+		want:  `v.b: field not allowed`,
+	}, {
+		value: `v: {x: y: 1}, w: {x: z: 2}`,
+		want:  `v.x.z: field not allowed`,
+	}, {
+		value: `v: {a: 1, ...}, w: {b: 2}`,
+		want:  `{"a":1,"b":2}`,
+	}}
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		tdtest.Run(t, testCases, func(t *cuetest.T, tc *testCase) {
+			v := getValue(m, tc.value)
+			x := v.LookupPath(cue.ParsePath("v")).CloseAll()
+			y := v.LookupPath(cue.ParsePath("w"))
+			t.Equal(unifyResult(x.Unify(y)), tc.want)
+		})
+	})
+}
+
+// unifyResult returns the JSON encoding of v or the first line of its error
+// message.
+func unifyResult(v cue.Value) string {
+	b, err := v.MarshalJSON()
+	if err != nil {
+		msg, _, _ := strings.Cut(errors.Details(err, nil), "\n")
+		return strings.TrimSuffix(msg, ":")
+	}
+	return string(b)
+}
+
+func TestFieldOrder(t *testing.T) {
+	type testCase struct {
+		name   string
+		order  func(a, b cue.Value) int
+		fields string
+		syntax string
+	}
+	const config = `
+	c: 1
+	a: {y: 1, x: 2}
+	b: 3
+	a: w: 4
+	`
+	testCases := []testCase{{
+		name:   "alphabetical",
+		order:  cue.AlphabeticalOrder,
+		fields: "a b c",
+		syntax: "{a: {w: 4, x: 2, y: 1}, b: 3, c: 1}",
+	}, {
+		name:   "source",
+		order:  cue.SourceOrder,
+		fields: "c a b",
+		syntax: "{c: 1, a: {y: 1, x: 2, w: 4}, b: 3}",
+	}, {
+		name: "reverse",
+		order: func(a, b cue.Value) int {
+			return -cue.AlphabeticalOrder(a, b)
+		},
+		fields: "c b a",
+		syntax: "{c: 1, b: 3, a: {y: 1, x: 2, w: 4}}",
+	}}
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		tdtest.Run(t, testCases, func(t *cuetest.T, tc *testCase) {
+			v := getValue(m, config)
+			iter, err := v.Fields(cue.FieldOrder(tc.order))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields []string
+			for iter.Next() {
+				fields = append(fields, iter.Selector().String())
+			}
+			t.Equal(strings.Join(fields, " "), tc.fields)
+
+			n := v.Syntax(cue.Final(), cue.FieldOrder(tc.order))
+			t.Equal(astinternal.DebugStr(n), tc.syntax)
+		})
+	})
+}
+
 func TestEquals(t *testing.T) {
 	testCases := []struct {
 		a, b string
@@ -2465,6 +2804,36 @@ func TestValidate(t *testing.T) {
 			"variables"?: #variables
 		}
 		`,
+	}, {
+		desc: "require regular",
+		in: `
+		#Schema: {
+			name:  string
+			port:  *8080 | int
+			tags?: [...string]
+		}
+		x: #Schema & {}
+		`,
+		opts: []cue.Option{cue.RequireRegular(true)},
+		err:  true,
+	}, {
+		desc: "require regular satisfied",
+		in: `
+		#Schema: {
+			name:  string
+			port:  *8080 | int
+			tags?: [...string]
+			sub: n: int
+		}
+		x: #Schema & {name: "foo", sub: n: 1}
+		`,
+		opts: []cue.Option{cue.RequireRegular(true)},
+	}, {
+		desc: "require regular ignores definitions",
+		in: `
+		#Schema: name: string
+		`,
+		opts: []cue.Option{cue.RequireRegular(true)},
 	}}
 	for _, tc := range testCases {
 		cuetdtest.FullMatrix.Run(t, tc.desc, func(t *testing.T, m *cuetdtest.M) {
@@ -2482,6 +2851,49 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidateErrorLimits(t *testing.T) {
+	type testCase struct {
+		name string
+		opts []cue.Option
+		want string
+	}
+	const config = `
+	c: 3 & 4
+	a: 1 & 2
+	b: {x: "a" & "b", y: true & false}
+	`
+	testCases := []testCase{{
+		name: "default",
+		want: "c a b.x b.y",
+	}, {
+		name: "max",
+		opts: []cue.Option{cue.MaxErrors(2)},
+		want: "c a",
+	}, {
+		name: "sorted",
+		opts: []cue.Option{cue.SortErrors(true)},
+		want: "a b.x b.y c",
+	}, {
+		name: "sorted max",
+		opts: []cue.Option{cue.SortErrors(true), cue.MaxErrors(3)},
+		want: "a b.x b.y",
+	}, {
+		name: "zero max",
+		opts: []cue.Option{cue.MaxErrors(0)},
+		want: "c a b.x b.y",
+	}}
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		tdtest.Run(t, testCases, func(t *cuetest.T, tc *testCase) {
+			v := getValue(m, config)
+			var got []string
+			for _, err := range errors.Errors(v.Validate(tc.opts...)) {
+				got = append(got, strings.Join(err.Path(), "."))
+			}
+			t.Equal(strings.Join(got, " "), tc.want)
+		})
+	})
+}
+
 func TestPath(t *testing.T) {
 	config := `
 	a: b: c: 5
@@ -2757,6 +3169,56 @@ Another Foo.
 	})
 }
 
+func TestValueDocComments(t *testing.T) {
+	const config = `
+	// A Foo.
+	Foo: {
+		// field1 is an int.
+		field1: int
+	}
+
+	// My foo.
+	foo: Foo & {
+		// field1 is an int.
+		field1: 1
+	}
+	`
+	cuetdtest.FullMatrix.Do(t, func(t *testing.T, m *cuetdtest.M) {
+		v := m.CueContext().CompileString(config, cue.Filename("in.cue"))
+
+		testCases := []struct {
+			path string
+			want []string
+		}{{
+			path: "Foo",
+			want: []string{"in.cue:2:2 Foo: A Foo.\n"},
+		}, {
+			path: "foo",
+			want: []string{"in.cue:8:2 foo: My foo.\n"},
+		}, {
+			// Comments with the same text are reported for each declaration.
+			path: "foo.field1",
+			want: []string{
+				"in.cue:10:3 field1: field1 is an int.\n",
+				"in.cue:4:3 field1: field1 is an int.\n",
+			},
+		}}
+		for _, tc := range testCases {
+			t.Run(tc.path, func(t *testing.T) {
+				var got []string
+				for _, d := range v.LookupPath(cue.ParsePath(tc.path)).DocComments() {
+					label, _, _ := ast.LabelName(d.Source.(*ast.Field).Label)
+					got = append(got, fmt.Sprintf("%v %s: %s", d.Pos(), label, d.Comment.Text()))
+				}
+				slices.Sort(got)
+				if !slices.Equal(got, tc.want) {
+					t.Errorf("got:\n%q\nwant:\n%q", got, tc.want)
+				}
+			})
+		}
+	})
+}
+
 func docStr(docs []*ast.CommentGroup) string {
 	doc := ""
 	for _, d := range docs {
@@ -2948,6 +3410,85 @@ func TestMarshalJSON(t *testing.T) {
 			if got := string(b); got != tc.json {
 				t.Errorf("\n got %v;\nwant %v", got, tc.json)
 			}
+
+			var buf bytes.Buffer
+			err = val.MarshalTo(&buf)
+			checkFatal(t, err, tc.err, "MarshalTo")
+
+			if got := buf.String(); got != tc.json {
+				t.Errorf("MarshalTo:\n got %v;\nwant %v", got, tc.json)
+			}
+		})
+	}
+}
+
+func TestMarshalTo(t *testing.T) {
+	const value = `
+	a: 1
+	b: {
+		"c-d": "foo"
+		_e:   true
+		#f:   null
+		"#g": 'bytes'
+	}
+	h: [1.5, [], {}, {i: [null]}]
+	j: *"default" | string
+	`
+	testCases := []struct {
+		opts []cue.MarshalOption
+		out  string
+	}{{
+		out: `{"a":1,"b":{"c-d":"foo","#g":"Ynl0ZXM="},"h":[1.5,[],{},{"i":[null]}],"j":"default"}`,
+	}, {
+		opts: []cue.MarshalOption{cue.MarshalIndent("  ")},
+		out: `{
+  "a": 1,
+  "b": {
+    "c-d": "foo",
+    "#g": "Ynl0ZXM="
+  },
+  "h": [
+    1.5,
+    [],
+    {},
+    {
+      "i": [
+        null
+      ]
+    }
+  ],
+  "j": "default"
+}`,
+	}, {
+		opts: []cue.MarshalOption{cue.MarshalCUE()},
+		out: `a: 1
+b: {
+	"c-d": "foo"
+	"#g": 'bytes'
+}
+h: [
+	1.5,
+	[],
+	{},
+	{
+		i: [
+			null,
+		]
+	},
+]
+j: "default"
+`,
+	}}
+	for _, tc := range testCases {
+		cuetdtest.FullMatrix.Run(t, "", func(t *testing.T, m *cuetdtest.M) {
+			var buf bytes.Buffer
+			err := getValue(m, value).MarshalTo(&buf, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tc.out {
+				t.Errorf("\n got %v;\nwant %v", got, tc.out)
+			}
 		})
 	}
 }
@@ -3145,6 +3686,24 @@ func TestReferencePath(t *testing.T) {
 		want:           "Pi",
 		wantImportPath: "math",
 		alt:            "3.14159265358979323846264338327950288419716939937510582097494459",
+	}, {
+		input: "v: w: x: {a}, a: 1",
+		want:  "a",
+	}, {
+		input: `
+		v: w: {for k, e in src {x: e.a}}
+		src: p: a: 1`,
+		want: "src.p.a",
+	}, {
+		input: `
+		v: w: {for e in src if e.a > 0 {x: e.a}}
+		src: p: a: 1`,
+		want: "src.p.a",
+	}, {
+		input: `
+		v: w: {for k, e in src {x: k}}
+		src: p: a: 1`,
+		want: "",
 	}}
 	for _, tc := range testCases {
 		cuetdtest.FullMatrix.Run(t, "", func(t *testing.T, m *cuetdtest.M) {
@@ -3194,6 +3753,48 @@ func TestReferencePath(t *testing.T) {
 	}
 }
 
+func TestReferencePathInExpr(t *testing.T) {
+	const input = `
+	a: "\(src.p.b)-\(l[0])"
+	b: {for k, e in src {"\(k)": "\(e.b)"}}
+	c: [for e in l if e > 0 {src.p.b}]
+	l: [1]
+	src: p: b: "x"
+	`
+	testCases := []struct {
+		path string
+		want string
+		args []string // reference paths of the operands of the expression
+	}{{
+		path: "a",
+		args: []string{"", "src.p.b", "", "l[0]", ""},
+	}, {
+		path: "b.p",
+		args: []string{"", "src.p.b", ""},
+	}, {
+		path: "c[0]",
+		want: "src.p.b",
+		args: []string{"src.p", ""},
+	}}
+	for _, tc := range testCases {
+		cuetdtest.FullMatrix.Run(t, tc.path, func(t *testing.T, m *cuetdtest.M) {
+			v := m.CueContext().CompileString(input).LookupPath(cue.ParsePath(tc.path))
+			if _, p := v.ReferencePath(); p.String() != tc.want {
+				t.Errorf("got %q; want %q", p, tc.want)
+			}
+			_, args := v.Expr()
+			var got []string
+			for _, a := range args {
+				_, p := a.ReferencePath()
+				got = append(got, p.String())
+			}
+			if !slices.Equal(got, tc.args) {
+				t.Errorf("got operands %q; want %q", got, tc.args)
+			}
+		})
+	}
+}
+
 func TestZeroValueBuildInstance(t *testing.T) {
 	inst := cue.Value{}.BuildInstance()
 	if inst != nil {
@@ -3254,6 +3855,74 @@ a: x: y: z: "x"`,
 	}
 }
 
+func TestFindByPos(t *testing.T) {
+	const src = `
+#List: {val: int, next?: #List}
+l: #List & {val: 1, next: val: 2}
+a: b: {c: 1 + 2}
+d: [1, {e: "x"}]
+#Def: {f: int}
+g: #Def & {f: 3}
+`
+	testCases := []struct {
+		at   string // the first occurrence of at in src marks the position
+		path string
+		ok   bool
+	}{{
+		at:   "2}",
+		path: "l.next.val",
+		ok:   true,
+	}, {
+		// A position within an expression maps to the value it defines.
+		at:   "+",
+		path: "a.b.c",
+		ok:   true,
+	}, {
+		at:   `"x"`,
+		path: "d[1].e",
+		ok:   true,
+	}, {
+		at:   "{f: int}",
+		path: "#Def",
+		ok:   true,
+	}, {
+		at:   "int}",
+		path: "#Def.f",
+		ok:   true,
+	}, {
+		at:   "f: 3",
+		path: "g.f",
+		ok:   true,
+	}, {
+		at:   "#List &",
+		path: "l",
+		ok:   true,
+	}, {
+		// A position before all values.
+		at: "\n#List",
+	}}
+	for _, tc := range testCases {
+		cuetdtest.FullMatrix.Run(t, tc.at, func(t *testing.T, m *cuetdtest.M) {
+			v := m.CueContext().CompileString(src, cue.Filename("x.cue"))
+			f := token.NewFile("x.cue", -1, len(src))
+			got, ok := v.FindByPos(f.Pos(strings.Index(src, tc.at), token.NoRelPos))
+			if ok != tc.ok {
+				t.Fatalf("got ok %v; want %v", ok, tc.ok)
+			}
+			if ok && got.Path().String() != tc.path {
+				t.Errorf("got path %v; want %v", got.Path(), tc.path)
+			}
+		})
+	}
+
+	// Positions in other files do not match.
+	v := cuecontext.New().CompileString(src, cue.Filename("x.cue"))
+	f := token.NewFile("y.cue", -1, len(src))
+	if _, ok := v.FindByPos(f.Pos(strings.Index(src, "+"), token.NoRelPos)); ok {
+		t.Errorf("found value for position in other file")
+	}
+}
+
 func TestPathCorrection(t *testing.T) {
 	testCases := []struct {
 		input  string
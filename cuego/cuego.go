@@ -17,11 +17,16 @@ package cuego
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/load"
 	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/value"
 )
 
@@ -44,6 +49,28 @@ func Constrain(x interface{}, constraints string) error {
 	return DefaultContext.Constrain(x, constraints)
 }
 
+// MustBind is like Bind, but panics if there is an error.
+func MustBind[T any](pkg, path string) {
+	if err := Bind[T](pkg, path); err != nil {
+		panic(err)
+	}
+}
+
+// Bind associates the value at the given path in a CUE package, typically
+// a definition, with the Go type T. The package is loaded from the module
+// containing the current directory, so that constraints can be maintained
+// in .cue files rather than in Go string literals. For instance:
+//
+//	cuego.Bind[User]("example.com/schemas:api", "#User")
+//
+// Bind reports an error if the package cannot be loaded or if the
+// constraints are not compatible with T. Use [Context.ConstrainPackage]
+// to load the package with a different configuration.
+func Bind[T any](pkg, path string) error {
+	var x T
+	return DefaultContext.ConstrainPackage(x, nil, pkg, path)
+}
+
 // Validate is a wrapper for Validate called on the global context.
 func Validate(x interface{}) error {
 	return DefaultContext.Validate(x)
@@ -66,7 +93,12 @@ func Complete(x interface{}) error {
 // Global constraints that are defined at the time a constraint is
 // created are applied as well.
 type Context struct {
-	typeCache sync.Map // map[reflect.Type]cue.Value
+	typeCache   sync.Map // map[reflect.Type]cue.Value
+	constraints sync.Map // map[reflect.Type]cue.Value
+
+	// users maps a type to the types whose cached value was computed from
+	// it. It is guarded by mutex.
+	users map[reflect.Type]map[reflect.Type]bool
 }
 
 // Validate checks whether x validates against the registered constraints for
@@ -117,21 +149,135 @@ func (c *Context) load(x interface{}) cue.Value {
 		return value.(cue.Value)
 	}
 
-	// fromGoType should prevent the work is done no more than once, but even
-	// if it is, there is no harm done.
-	v := fromGoType(x)
+	mutex.Lock()
+	defer mutex.Unlock()
+	return c.loadLocked(x)
+}
+
+// loadLocked is like load, but must be called with mutex held.
+func (c *Context) loadLocked(x interface{}) cue.Value {
+	t := reflect.TypeOf(x)
+	if value, ok := c.typeCache.Load(t); ok {
+		return value.(cue.Value)
+	}
+
+	v := value.FromGoType(runtime, x)
+	if n, ok := c.nested(t, t, map[reflect.Type]bool{}); ok {
+		v = v.Unify(n)
+	}
+
 	c.typeCache.Store(t, v)
 	return v
 }
 
+// nested returns the constraints associated through Constrain with t and
+// with the types of the struct fields, slice and array elements, and map
+// values reachable from t, at the positions where these appear in t. This
+// allows Validate and Complete to apply constraints on element types, such
+// as those of a []Server, to each element. It reports false if there are
+// no such constraints. Each type visited is recorded as being used by root,
+// so that its cached value can be invalidated when constraints are added
+// later. It must be called with mutex held.
+func (c *Context) nested(root, t reflect.Type, inProgress map[reflect.Type]bool) (v cue.Value, ok bool) {
+	if inProgress[t] {
+		return cue.Value{}, false
+	}
+	if c.users == nil {
+		c.users = map[reflect.Type]map[reflect.Type]bool{}
+	}
+	if c.users[t] == nil {
+		c.users[t] = map[reflect.Type]bool{}
+	}
+	c.users[t][root] = true
+
+	inProgress[t] = true
+	defer delete(inProgress, t)
+
+	if x, found := c.constraints.Load(t); found {
+		v, ok = x.(cue.Value), true
+	}
+	add := func(p cue.Path, x cue.Value) {
+		if !ok {
+			v, ok = runtime.CompileString("_"), true
+		}
+		v = v.FillPath(p, x)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if x, found := c.nested(root, t.Elem(), inProgress); found {
+			// Nil pointers are converted to null.
+			scope := runtime.CompileString("{}").FillPath(cue.MakePath(cue.Str("x")), x)
+			x = runtime.BuildExpr(ast.NewBinExpr(token.OR, ast.NewNull(), ast.NewIdent("x")), cue.Scope(scope))
+			add(cue.MakePath(), x)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := fieldName(&f)
+			if name == "-" {
+				continue
+			}
+			x, found := c.nested(root, f.Type, inProgress)
+			if !found {
+				continue
+			}
+			if name == "" {
+				// Fields of embedded structs are promoted.
+				add(cue.MakePath(), x)
+				continue
+			}
+			// Use an optional selector so as not to introduce fields that
+			// would otherwise be absent.
+			add(cue.MakePath(cue.Str(name).Optional()), x)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if x, found := c.nested(root, t.Elem(), inProgress); found {
+			add(cue.MakePath(cue.AnyIndex), x)
+		}
+
+	case reflect.Map:
+		if x, found := c.nested(root, t.Elem(), inProgress); found {
+			add(cue.MakePath(cue.AnyString), x)
+		}
+	}
+	return v, ok
+}
+
+// fieldName returns the name of the CUE field for f, "" for embedded
+// structs, or "-" if f is omitted.
+func fieldName(f *reflect.StructField) string {
+	name := f.Name
+	if f.Anonymous {
+		name = ""
+	}
+	for _, key := range []string{"json", "yaml", "protobuf"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			if key == "json" && tag == "-" {
+				return "-"
+			}
+			if p := strings.IndexByte(tag, ','); p >= 0 {
+				tag = tag[:p]
+			}
+			if tag != "" {
+				return tag
+			}
+		}
+	}
+	return name
+}
+
 // TODO: should we require that Constrain be defined on exported,
 // named types types only?
 
 // Constrain associates the given CUE constraints with the type of x or reports
 // an error if the constraints are invalid or not compatible with x.
 func (c *Context) Constrain(x interface{}, constraints string) error {
-	c.load(x) // Ensure fromGoType is called outside of lock.
-
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -144,8 +290,43 @@ func (c *Context) Constrain(x interface{}, constraints string) error {
 	if v.Err() != nil {
 		return err
 	}
+	return c.constrain(x, v)
+}
 
-	typ := c.load(x)
+// ConstrainPackage associates the value at the given path within a CUE
+// package with the type of x, or reports an error if the package cannot be
+// loaded or if its constraints are not compatible with x. The package is
+// loaded with [load.Instances] using cfg, which may be nil.
+func (c *Context) ConstrainPackage(x interface{}, cfg *load.Config, pkg, path string) error {
+	insts := load.Instances([]string{pkg}, cfg)
+	if len(insts) != 1 {
+		return errors.Newf(token.NoPos, "cuego: expected one package for %q, found %d", pkg, len(insts))
+	}
+	if err := insts[0].Err; err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	inst := runtime.BuildInstance(insts[0])
+	if err := inst.Err(); err != nil {
+		return err
+	}
+	v := inst.LookupPath(cue.ParsePath(path))
+	if err := v.Err(); err != nil {
+		return err
+	}
+	if !v.Exists() {
+		return errors.Newf(token.NoPos, "cuego: %s not found in package %s", path, pkg)
+	}
+	return c.constrain(x, v)
+}
+
+// constrain unifies v with the constraints for the type of x and stores
+// the result. It must be called with mutex held.
+func (c *Context) constrain(x interface{}, v cue.Value) error {
+	typ := c.loadLocked(x)
 	v = typ.Unify(v)
 
 	if err := v.Validate(); err != nil {
@@ -153,6 +334,13 @@ func (c *Context) Constrain(x interface{}, constraints string) error {
 	}
 
 	t := reflect.TypeOf(x)
+	c.constraints.Store(t, v)
+
+	// Types that contain t need to be recomputed.
+	for u := range c.users[t] {
+		c.typeCache.Delete(u)
+	}
+	delete(c.users, t)
 	c.typeCache.Store(t, v)
 	return nil
 }
@@ -190,13 +378,3 @@ func fromGoValue(x interface{}, nilIsNull bool) (v cue.Value, err error) {
 	// return v, nil
 
 }
-
-func fromGoType(x interface{}) cue.Value {
-	// TODO: remove the need to have a lock here. We could use a new index (new
-	// Instance) here as any previously unrecognized field can never match an
-	// existing one and can only be merged.
-	mutex.Lock()
-	v := value.FromGoType(runtime, x)
-	mutex.Unlock()
-	return v
-}
@@ -15,8 +15,13 @@
 package cuego
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
+
+	"cuelang.org/go/cue/load"
 )
 
 type Sum struct {
@@ -160,3 +165,120 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestConstrainPackage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"cue.mod/module.cue": `module: "example.com/schemas", language: version: "v0.9.0"`,
+		"api/api.cue": `package api
+
+#User: {
+	Name: string & != ""
+	Age:  >=18
+}
+`,
+	}
+	for name, data := range files {
+		name = filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(name), 0o777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	type User struct {
+		Name string
+		Age  int
+	}
+	cfg := &load.Config{Dir: dir}
+
+	c := &Context{}
+	if err := c.ConstrainPackage(User{}, cfg, "example.com/schemas/api", "#User"); err != nil {
+		t.Fatal(err)
+	}
+	checkErr(t, c.Validate(User{Name: "a", Age: 20}), "")
+	checkErr(t, c.Validate(User{Name: "a", Age: 12}), "some error")
+	checkErr(t, c.Validate(User{Age: 20}), "some error")
+
+	err := c.ConstrainPackage(User{}, cfg, "example.com/schemas/api", "#Group")
+	checkErr(t, err, "some error")
+	err = c.ConstrainPackage(User{}, cfg, "example.com/schemas/missing", "#User")
+	checkErr(t, err, "some error")
+}
+
+func TestCompleteNested(t *testing.T) {
+	type Server struct {
+		Name string
+		Port int `json:",omitempty"`
+	}
+	type Config struct {
+		Servers []Server
+		ByName  map[string]Server
+		Ptrs    []*Server
+	}
+
+	c := &Context{}
+	if err := c.Constrain(Server{}, `{Name: !="", Port: *80 | >0}`); err != nil {
+		t.Fatal(err)
+	}
+
+	value := &Config{
+		Servers: []Server{{Name: "a"}, {Name: "b", Port: 8080}},
+		ByName:  map[string]Server{"c": {Name: "c"}},
+		Ptrs:    []*Server{{Name: "d"}, nil},
+	}
+	if err := c.Complete(value); err != nil {
+		t.Fatal(err)
+	}
+	want := &Config{
+		Servers: []Server{{Name: "a", Port: 80}, {Name: "b", Port: 8080}},
+		ByName:  map[string]Server{"c": {Name: "c", Port: 80}},
+		Ptrs:    []*Server{{Name: "d", Port: 80}, nil},
+	}
+	if !reflect.DeepEqual(value, want) {
+		t.Errorf("value:\n got: %#v;\nwant: %#v", value, want)
+	}
+
+	checkErr(t, c.Validate(&Config{Servers: []Server{{Port: 80}}}), "some error")
+	checkErr(t, c.Validate(Config{ByName: map[string]Server{"x": {Name: "x", Port: 80}}}), "")
+}
+
+func TestConstrainConcurrent(t *testing.T) {
+	type A struct{ X int }
+	type B struct{ Y int }
+	type C struct{ Z int }
+	type Outer struct {
+		A A
+		B []B
+	}
+
+	c := &Context{}
+	// Load Outer first so that constraining its field types later must
+	// invalidate its cached value.
+	checkErr(t, c.Validate(Outer{A: A{X: 1}, B: []B{{Y: 1}}}), "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			checkErr(t, c.Constrain(A{}, `{X: <10}`), "")
+		}()
+		go func() {
+			defer wg.Done()
+			checkErr(t, c.Constrain(B{}, `{Y: <10}`), "")
+		}()
+		go func() {
+			defer wg.Done()
+			checkErr(t, c.Constrain(C{}, `{Z: <10}`), "")
+			checkErr(t, c.Validate(Outer{}), "")
+		}()
+	}
+	wg.Wait()
+
+	checkErr(t, c.Validate(Outer{A: A{X: 1}, B: []B{{Y: 1}}}), "")
+	checkErr(t, c.Validate(Outer{A: A{X: 20}}), "some error")
+	checkErr(t, c.Validate(Outer{B: []B{{Y: 20}}}), "some error")
+	checkErr(t, c.Validate(C{Z: 20}), "some error")
+}
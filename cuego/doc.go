@@ -33,6 +33,10 @@
 //	}
 //
 // AddConstraints allows annotating Go types with any CUE constraints.
+// Bind associates a Go type with a definition from a CUE package, so that
+// constraints can be kept in .cue files:
+//
+//	cuego.MustBind[User]("example.com/schemas:api", "#User")
 //
 // # Validating Go Values
 //
@@ -22,7 +22,9 @@ import (
 	"strings"
 	"testing"
 
+	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/load"
 	"cuelang.org/go/encoding/gocode/testdata/pkg1"
 	"cuelang.org/go/encoding/gocode/testdata/pkg2"
 )
@@ -111,6 +113,75 @@ O.P: invalid value 4 (out of bound >5):
 	}
 }
 
+func TestHooks(t *testing.T) {
+	testCases := []struct {
+		value pkg1.String
+		want  string
+	}{{
+		value: "ok",
+		want:  "nil",
+	}, {
+		value: "x",
+		want:  `string "x" is too short`,
+	}, {
+		value: "",
+		want: `
+string "" is too short
+invalid value "" (out of bound !=""):
+    pkg1/instance.cue:x:x
+`,
+	}}
+	for _, tc := range testCases {
+		t.Run(string(tc.value), func(t *testing.T) {
+			got := strings.TrimSpace(errStr(tc.value.ValidateCUE()))
+			want := strings.TrimSpace(tc.want)
+			if got != want {
+				t.Errorf("got:\n%q\nwant:\n%q", got, want)
+			}
+		})
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	insts := load.Instances([]string{"./pkg1"}, &load.Config{Dir: "testdata"})
+	if err := insts[0].Err; err != nil {
+		t.Fatal(err)
+	}
+	v := cuecontext.New().BuildInstance(insts[0])
+
+	b, err := Generate("", v, &Config{
+		Context:   true,
+		ErrorFunc: "newValidationError",
+		BuildTags: "linux && !nocue",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		"//go:build linux && !nocue\n\n// Code generated by gocode.Generate; DO NOT EDIT.\n",
+		"\t\"context\"\n",
+		`
+func ValidateSpecialString(ctx context.Context, x string) error {
+	if err := ctx.Err(); err != nil {
+		return cuegenError(err)
+	}
+	return cuegenError(cuegenCodec.Validate(cuegenvalSpecialString, x))
+}
+`,
+		"\treturn newValidationError(err)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, got)
+		}
+	}
+
+	_, err = Generate("", v, &Config{BuildTags: "linux &&"})
+	if err == nil || !strings.Contains(err.Error(), "invalid build tags") {
+		t.Errorf("got error %v; want invalid build tags", err)
+	}
+}
+
 func errStr(err error) string {
 	if err == nil {
 		return "nil"
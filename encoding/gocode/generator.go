@@ -19,6 +19,7 @@ import (
 	"cmp"
 	"fmt"
 	"go/ast"
+	"go/build/constraint"
 	"go/format"
 	"go/types"
 	"text/template"
@@ -44,9 +45,42 @@ type Config struct {
 	// for complete functions. The default is "-" (disabled).
 	CompleteName string
 
+	// HookName defines the default name of existing, hand-written
+	// validation methods of the form
+	//
+	//	func (x T) HookName() error
+	//
+	// that generated validators call in addition to validating x against
+	// CUE. The errors of both are combined. The hook is only called if
+	// T has such a method and its name differs from that of the generated
+	// validator. The default is "-" (disabled).
+	HookName string
+
 	// The cue.Runtime variable name to use for initializing Codecs.
 	// A new Runtime is created by default.
 	RuntimeVar string
+
+	// Context causes generated validate and complete functions to take a
+	// context.Context as their first argument. They return the error of
+	// the context, if any, before validating. Hooks are passed the context
+	// if they are of the form
+	//
+	//	func (x T) HookName(ctx context.Context) error
+	Context bool
+
+	// ErrorFunc names a function of the form
+	//
+	//	func(err error) error
+	//
+	// in the generated package that converts the non-nil errors returned by
+	// generated functions, for instance to an error type of the caller.
+	// By default errors are returned as is.
+	ErrorFunc string
+
+	// BuildTags holds a build constraint expression, such as
+	// "linux && !nocue", that is added as a //go:build line to the
+	// generated file.
+	BuildTags string
 }
 
 const defaultPrefix = "cuegen"
@@ -72,6 +106,8 @@ const defaultPrefix = "cuegen"
 //	                 Setting this to the empty string disables generation.
 //	complete=<name>  Alternative name for the validation function or method.
 //	                 Setting this to the empty string disables generation.
+//	hook=<name>      Name of an existing validation method of the Go type
+//	                 to call from the generated validator.
 //	func             Generate as a function instead of a method.
 //
 // # Selection and Naming
@@ -105,6 +141,12 @@ func Generate(pkgPath string, inst cue.InstanceOrValue, c *Config) (b []byte, er
 		typeMap: map[string]types.Type{},
 	}
 
+	if c.BuildTags != "" {
+		if _, err := constraint.Parse("//go:build " + c.BuildTags); err != nil {
+			return nil, fmt.Errorf("invalid build tags %q: %v", c.BuildTags, err)
+		}
+	}
+
 	val := inst.Value()
 	pkgName := inst.Value().BuildInstance().PkgName
 	if pkgPath != "" {
@@ -140,12 +182,6 @@ func Generate(pkgPath string, inst cue.InstanceOrValue, c *Config) (b []byte, er
 		}
 	}
 
-	// TODO: add package doc if there is no existing Go package or if it doesn't
-	// have package documentation already.
-	g.exec(headerCode, map[string]string{
-		"pkgName": pkgName,
-	})
-
 	iter, err := val.Fields(cue.Definitions(true))
 	g.addErr(err)
 
@@ -160,12 +196,28 @@ func Generate(pkgPath string, inst cue.InstanceOrValue, c *Config) (b []byte, er
 	b, err = r.Marshal(&val)
 	g.addErr(err)
 
-	g.exec(loadCode, map[string]string{
-		"runtime": g.RuntimeVar,
-		"prefix":  cmp.Or(g.Prefix, defaultPrefix),
-		"data":    string(b),
+	g.exec(loadCode, map[string]interface{}{
+		"runtime":   g.RuntimeVar,
+		"prefix":    cmp.Or(g.Prefix, defaultPrefix),
+		"data":      string(b),
+		"hooks":     g.hooks,
+		"errorFunc": g.ErrorFunc,
 	})
 
+	// The header is generated last, as its imports depend on the
+	// declarations.
+	body := g.w
+	g.w = bytes.Buffer{}
+	// TODO: add package doc if there is no existing Go package or if it doesn't
+	// have package documentation already.
+	g.exec(headerCode, map[string]interface{}{
+		"pkgName":   pkgName,
+		"buildTags": g.BuildTags,
+		"context":   g.Context,
+		"hooks":     g.hooks,
+	})
+	g.w.Write(body.Bytes())
+
 	if g.err != nil {
 		return nil, g.err
 	}
@@ -186,6 +238,10 @@ type generator struct {
 
 	w   bytes.Buffer
 	err errors.Error
+
+	// hooks reports whether any of the generated validators calls a
+	// hand-written validation method.
+	hooks bool
 }
 
 func (g *generator) addErr(err error) {
@@ -246,6 +302,16 @@ func (g *generator) decl(name string, v cue.Value) {
 		}
 	}
 
+	validate := lookupName(attr, "validate", cmp.Or(g.ValidateName, "Validate"))
+	hook := lookupName(attr, "hook", g.HookName)
+	ok, hookCtx := hasHook(g.pkg, typ, hook)
+	if validate == "" || (hook == validate && !isFunc) || !ok || (hookCtx && !g.Context) {
+		hook = ""
+	}
+	if hook != "" {
+		g.hooks = true
+	}
+
 	g.exec(stubCode, map[string]interface{}{
 		"prefix":  cmp.Or(g.Prefix, defaultPrefix),
 		"cueName": name,   // the field name of the CUE type
@@ -254,11 +320,53 @@ func (g *generator) decl(name string, v cue.Value) {
 
 		// @go attribute options
 		"func":     isFunc,
-		"validate": lookupName(attr, "validate", cmp.Or(g.ValidateName, "Validate")),
+		"validate": validate,
 		"complete": lookupName(attr, "complete", g.CompleteName),
+		"hook":     hook,
+		"hookCtx":  hookCtx,
+
+		// Config options
+		"context":   g.Context,
+		"errorFunc": g.ErrorFunc != "",
 	})
 }
 
+// hasHook reports whether typ has a method of the given name with the
+// signature of a validation method, that is, a single error result and
+// either no arguments or a single context.Context argument. The latter is
+// reported by withCtx.
+func hasHook(pkg *packages.Package, typ types.Type, name string) (ok, withCtx bool) {
+	if pkg == nil || typ == nil || name == "" {
+		return false, false
+	}
+	sel := types.NewMethodSet(types.NewPointer(typ)).Lookup(pkg.Types, name)
+	if sel == nil {
+		return false, false
+	}
+	sig, ok := sel.Type().(*types.Signature)
+	if !ok || sig.Results().Len() != 1 ||
+		!types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type()) {
+		return false, false
+	}
+	switch params := sig.Params(); params.Len() {
+	case 0:
+		return true, false
+	case 1:
+		return isContext(params.At(0).Type()), true
+	}
+	return false, false
+}
+
+// isContext reports whether t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
 func lookupName(attr cue.Attribute, option, config string) string {
 	name, ok, _ := attr.Lookup(1, option)
 	if !ok {
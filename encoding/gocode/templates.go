@@ -19,16 +19,28 @@ import (
 )
 
 // Inputs:
-// .pkgName  the Go package name
+// .pkgName    the Go package name
+// .buildTags  build constraint expression for the file, if any
+// .context    whether generated functions take a context.Context
+// .hooks      whether any validator calls a hand-written validation method
 var headerCode = template.Must(template.New("header").Parse(
-	`// Code generated by gocode.Generate; DO NOT EDIT.
+	`{{if .buildTags}}//go:build {{.buildTags}}
+
+{{end -}}
+// Code generated by gocode.Generate; DO NOT EDIT.
 
 package {{.pkgName}}
 
 import (
+{{- if .context}}
+	"context"
+{{- end}}
 	"fmt"
 
 	"cuelang.org/go/cue"
+{{- if .hooks}}
+	"cuelang.org/go/cue/errors"
+{{- end}}
 	"cuelang.org/go/encoding/gocode/gocodec"
 	_ "cuelang.org/go/pkg"
 )
@@ -42,22 +54,49 @@ import (
 // .zero      zero value of the Go type; nil indicates no value
 // .validate  name of the validate function; "" means no validate
 // .complete  name of the complete function; "" means no complete
+// .hook      name of a hand-written validation method; "" means no hook
+// .hookCtx   whether the hook takes a context.Context
+// .context   whether the generated functions take a context.Context
+// .errorFunc whether errors are converted with the Error function
 var stubCode = template.Must(template.New("type").Parse(`
 var {{.prefix}}val{{.cueName}} = {{.prefix}}Make("{{.cueName}}", {{.zero}})
 
 {{ $sig := .goType | printf "(x %s)" -}}
+{{ $args := "()" -}}
+{{ if .context -}}
+{{ $args = "(ctx context.Context)" -}}
+{{ $sig = .goType | printf "(ctx context.Context, x %s)" -}}
+{{ end -}}
+{{ $ret := "return" -}}
+{{ if .errorFunc -}}
+{{ $ret = printf "return %sError" .prefix -}}
+{{ end -}}
 {{if .validate}}
 // {{.validate}}{{if .func}}{{.cueName}}{{end}} validates x.
 func {{if .func}}{{.validate}}{{.cueName}}{{$sig}}
-     {{- else -}}{{$sig}} {{.validate}}(){{end}} error {
-	return {{.prefix}}Codec.Validate({{.prefix}}val{{.cueName}}, x)
+     {{- else}}(x {{.goType}}) {{.validate}}{{$args}}{{end}} error {
+{{- if .context}}
+	if err := ctx.Err(); err != nil {
+		{{$ret}}(err)
+	}
+{{- end}}
+{{- if .hook}}
+	{{$ret}}({{.prefix}}Join({{.prefix}}Codec.Validate({{.prefix}}val{{.cueName}}, x), x.{{.hook}}({{if .hookCtx}}ctx{{end}})))
+{{- else}}
+	{{$ret}}({{.prefix}}Codec.Validate({{.prefix}}val{{.cueName}}, x))
+{{- end}}
 }
 {{end}}
 {{if .complete}}
 // {{.complete}}{{if .func}}{{.cueName}}{{end}} completes x.
 func {{if .func}}{{.complete}}{{.cueName}}{{$sig}}
-     {{- else -}}{{$sig}} {{.complete}}(){{end}} error {
-	return {{.prefix}}Codec.Complete({{.prefix}}val{{.cueName}}, x)
+     {{- else}}(x {{.goType}}) {{.complete}}{{$args}}{{end}} error {
+{{- if .context}}
+	if err := ctx.Err(); err != nil {
+		{{$ret}}(err)
+	}
+{{- end}}
+	{{$ret}}({{.prefix}}Codec.Complete({{.prefix}}val{{.cueName}}, x))
 }
 {{end}}
 `))
@@ -66,6 +105,8 @@ func {{if .func}}{{.complete}}{{.cueName}}{{$sig}}
 // .prefix 	  prefix to all generated variable names
 // .runtime   the variable name of a user-supplied runtime, if any
 // .data      bytes obtained from Instance.MarshalBinary
+// .hooks     whether any validator calls a hand-written validation method
+// .errorFunc the name of a function to convert errors, if any
 var loadCode = template.Must(template.New("load").Parse(`
 var {{.prefix}}Codec, {{.prefix}}Instance_, {{.prefix}}Value = func() (*gocodec.Codec, *cue.Instance, cue.Value) {
 	var r *cue.Runtime
@@ -101,6 +142,31 @@ func {{.prefix}}Make(name string, x interface{}) cue.Value {
 	return v
 }
 
+{{if .hooks -}}
+// {{.prefix}}Join combines the errors of validating a value against CUE
+// with those of its hand-written validation method.
+func {{.prefix}}Join(err, hook error) error {
+	switch {
+	case hook == nil:
+		return err
+	case err == nil:
+		return hook
+	}
+	return errors.Append(errors.Promote(err, ""), errors.Promote(hook, ""))
+}
+
+{{end -}}
+{{if .errorFunc -}}
+// {{.prefix}}Error converts a non-nil error returned by a generated
+// function using {{.errorFunc}}.
+func {{.prefix}}Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return {{.errorFunc}}(err)
+}
+
+{{end -}}
 // Data size: {{len .data}} bytes.
 var {{.prefix}}InstanceData = []byte({{printf "%+q" .data }})
 `))
@@ -15,6 +15,7 @@
 package pkg1
 
 import (
+	"fmt"
 	"time"
 
 	"cuelang.org/go/encoding/gocode/testdata/pkg2"
@@ -36,6 +37,15 @@ type OtherStruct struct {
 
 type String string
 
+// Validate is a hand-written validation method that is called by the
+// generated ValidateCUE method.
+func (s String) Validate() error {
+	if len(s) < 2 {
+		return fmt.Errorf("string %q is too short", s)
+	}
+	return nil
+}
+
 type Omit int
 
 type Ptr *struct {
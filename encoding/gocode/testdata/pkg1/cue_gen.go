@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/encoding/gocode/gocodec"
 	_ "cuelang.org/go/pkg"
 )
@@ -33,7 +34,7 @@ var cuegenvalString = cuegenMake("String", nil)
 
 // ValidateCUE validates x.
 func (x String) ValidateCUE() error {
-	return cuegenCodec.Validate(cuegenvalString, x)
+	return cuegenJoin(cuegenCodec.Validate(cuegenvalString, x), x.Validate())
 }
 
 var cuegenvalSpecialString = cuegenMake("SpecialString", nil)
@@ -84,5 +85,17 @@ func cuegenMake(name string, x interface{}) cue.Value {
 	return v
 }
 
-// Data size: 603 bytes.
-var cuegenInstanceData = []byte("\x01\x1f\x8b\b\x00\x00\x00\x00\x00\x00\xff\x94RMo\xd3J\x14\x9dq\xf2\xa4\x97Q\u07d3\xde\x0fx\xd2eV\t*v\x12\t\x16V\r\xfd\xa0\xa0.\xdaD\xb4 \x04b1\xb5o\x9cQ'3&\x1e\x97F\xd0\x02\xa5\xf4g\xd7h\x1c\xbb\r\xec\x9a\xd5\u044d\xcf=g\xce=\xff\x94?=\xea\x95\u05c4\x96\xdf\by\xf2\xb5E\xe9\x9a\u0539\x15:\xc6\xe7\xc2\n7\xa6-\xda~e\x8c\xa5\x1e\xa1\xed\xb1\xb0S\xbaF\xe8_/\xa4\u009c\x96W\x84\x90\xff\xcb\x1f\x1e\xa5\xff\xbe\xff\x10\x17\xe8O\xa4\xaa\x99W\x84\x96\x97\x84t\xcb\xef-J\xff\xbe\x9b_\x12\xea\xd1\xf6\x81\x98\xa1[\u052e\x86\x8c\x10r\xd3:*\xaf\x89G)\r\xe2\x02\x95\u0429o\xe6i\x90\x9a\x00ul\x12\xa9\x1d\x8eM\x82\x81\xc5\xdc&\u008a ;I\a\x9b\xa7}J\xe9\x7f\x0e\x06\x8du?.\x90\xdex\xef2\x11\x9f\x88\x14\xc1\xfd\u0258\x9cefn\xa1\xcb:\xfc\x1e\x02C\xce:<\xb7s\xa9\xd3\xdc\xc1\x99\xb0S\xcez\x8c\xed/\x0e\xed\xbc\x88m\b\x9fYg+\x04\u0608\x06}\xd6\xd9\x0e\x01\xa2\v\x1e\v\xcb\xe1\v<\xe4\x89I9\ub31e\x850\xb2S\x9c/9\xac\xb3\x17\x82\xb35\xf4\xf7*W\xfb\xc8\xcea35\xdd\xf5\xd8\xcc2\x85\x16\xa3\x9d\x1a\xf4\xd8\n\xb1\x11\xab\r\xf9;F[!u\xbe\xa5\x17]\xfe\x96\xf7Xg\x1c.\xf7\x8ee|\u2db2\xc3\xea\xd3\x10\xea\u07c3\x88\xf3\x06W\x82\xa7B\xc9DX\x8c\xde\xd4`\xe7\xf5n\x8f\x1df\x18K\xa1\x1art\xc1\xf3\xe5\x84/Yv\x91a\xb4t\xd1c{\xa96s<\x9a\u02bc\x92\x89.\xf8\xc4\x18\xceF3iou\x01\xa4\xb6\x15\xf7Q\x8f\xb1 \x80\x03\xa3w\xcfdn\xa5N\xe1\x93T\n\x8e\x11\xccLZ\x8b\t\x88\x1c\u071b\x11d\x0e\xda\x00~,\xe4\xa9P\xa8-\xbc4\xe0\xa4}\xb6B\xafB\xd9nB\xa9\x83\xacUde\r\n\x8dg.gL\xa0\xd0\n\xf3\x1c\xf0,S2\x96V-\x00\xb58V\x98\xf8lbL\xe8l\xb2\xb1\x9d7I\xbb{\xfb\xfb\x85\xb22S8\x9at\a\xfd\x1e;g\x84x\xeb\xf7)Q]\xd1\xe1\xef\x15\x15+\x05\x1d\xde\x16\xf4\xaem\xac)Gcfc\xd0\xef\xaf<\xf5\x8f\xfb\x8b\xe3\x98;s\xcb\u04c7\xf0\xf41#\xe4W\x00\x00\x00\xff\xff{\xa57\xb4\xe3\x03\x00\x00")
+// cuegenJoin combines the errors of validating a value against CUE
+// with those of its hand-written validation method.
+func cuegenJoin(err, hook error) error {
+	switch {
+	case hook == nil:
+		return err
+	case err == nil:
+		return hook
+	}
+	return errors.Append(errors.Promote(err, ""), errors.Promote(hook, ""))
+}
+
+// Data size: 607 bytes.
+var cuegenInstanceData = []byte("\x01\x1f\x8b\b\x00\x00\x00\x00\x00\x00\xff\x94\x92\xcfn\xd3L\x14\xc5g\x9c|\u0497QA\xe2\x01\x90.\xb3JP\xb1\x93H\xb0\xb0j\xe8\x1f\n\xea\xa2MD\x01!!\x16\x13\xfb\xc6\x19e2c\xecqi\x04-PJ\x1f\xbbFv\xec6\xb0kVG7\xbe\xf7\xfc||\xee\x15\xbf\x1d\xea\x14W\x84\x16?\by\xf6\xbdE\xe9\x86\u0519\x15:\u0117\u008arL[\xb4\xfd\xc6\x18K\x1dB\xdbcagt\x83\xd0\xff^I\x85\x19-.\t!\x0f\x8b_\x0e\xa5\xf7?~\nst\xa7R\u055b\x97\x84\x16\x17\x84t\x8b\x9f-J\xff\xbf\x9d_\x10\xea\xd0\xf6\x91X`y\xa8]\r\x19!\xe4\xba5)\xae\x88C)\xf5\xc2\x1c\x95\u0431k\xd2\u060b\x8d\x87:4\x91\u0525\x0eM\x84\x9e\xc5\xccF\xc2\n/\x99\u01c3\xed\x93>\xa5\xf4A)\xbd\x06\xdd\rs\xa4\xd7\xce,\x11\xe1\\\xc4\b\u57cc\xc9EbR\v]\xd6\xe1w0\x18r\xd6\xe1\x99M\xa5\x8e\xb3R.\x84\x9dq\xd6c\xecpyl\xd3<\xb4>|e\x9d\x1d\x1f`+\x18\xf4Yg\xd7\a\b\xcey(,\x87o\xf0\x98G&\xe6\xac3z\xe1\xc3\xc8\xce0]\xed\xb0\u0381\x0f%\xd6\xd0=\xa8\xa8\x0e\x91\x9d\xc1vl\xba\x9b\xa1Y$\n-\x06{\xb5\u8c75\xc5\u01ac\x06r\xf7\x8c\xb6B\xealG/\xbb\xfc\x03\xef\xb1\xce\xd8_\xdd\x1d\xcbp^^e\xc7\u0563>\u053fG\x01\u736e\fO\x84\x92\x91\xb0\x18\xbc\xaf\xc5\u07bb\xfd\u03591\xf3\x9bA\x8f\x1d'\x18J\xa1\x9aS\xc19\xcfV\x13\xbe\xbaa\x97\t\x06+\xa6\x1e;\x88\xb5I\xf1\xedLf\x95ip\u03a7\xc6p6ZH{C\x01 \xb5\xadv\x9f\xf4\x18\xf3<82z\xffTfV\xea\x18\xbeH\xa5`\x82`\x16\xd2Z\x8c@dP&\x80 3\xd0\x06\xf0s.O\x84Bm\u1d41\xd2\xdaek\xebUD\xbbMDu\xac\xb5\x8b\xac\xd0 \xd7xZ\xa6\x8e\x11\xe4Za\x96\x01\x9e&J\x86\u04aa%\xa0\x16\x13\x85\x91\u02e6\xc6\xf8%&\x1b\u06f4\u027d\xfc\xfa\xeea\xae\xacL\x14\x8e\xa6\xddA\xbf\xc7\xce\x18!\xce\xe6]*U\x17v\xf8wa\xc5Z]\x877u\xbd\xed\x1ek\xaa\xd2\xc0l\r\xfa\xfd\xb5W\xfd\xa7\rb\x12\xf2\x12nU\x04\x1f\x9e?e\x84\xfc\t\x00\x00\xff\xff\xb4\x1f\u02f0\xf1\x03\x00\x00")
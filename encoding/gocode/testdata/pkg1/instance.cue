@@ -18,7 +18,7 @@ OtherStruct: {
 	P: pkg2.PickMe
 }
 
-String: !="" @go(,validate=ValidateCUE)
+String: !="" @go(,validate=ValidateCUE,hook=Validate)
 
 SpecialString: =~"special" @go(,type=string)
 
@@ -26,9 +26,11 @@ import (
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
 	"cuelang.org/go/cue/literal"
 	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/encoding/jsonpointer"
 	"cuelang.org/go/internal/source"
 )
 
@@ -55,6 +57,129 @@ func Validate(b []byte, v cue.Value) error {
 	return v.Validate(cue.Final())
 }
 
+// A Violation describes a value in JSON data that does not satisfy the
+// constraints of a schema.
+type Violation struct {
+	// Pointer is the RFC 6901 JSON Pointer of the value within the data,
+	// such as "/items/0/name". It is "" for the data as a whole, and if the
+	// location cannot be expressed as a JSON Pointer.
+	Pointer string
+
+	// Constraint is the constraint of the schema for the value in CUE
+	// syntax, such as "int & >0". It is "" if the schema does not
+	// constrain the value, as for a field that is not allowed.
+	Constraint string
+
+	// Message describes the violation.
+	Message string
+}
+
+// ValidateAll is like [Validate], but reports all violations of the
+// constraints specified by v rather than a single error, so that they
+// can, for instance, be reported to the client that sent the data.
+// Unlike Validate, it also reports the values that are not concrete after
+// unification with the data, such as required fields that are missing.
+// An error is returned if b is not valid JSON.
+func ValidateAll(b []byte, v cue.Value) ([]Violation, error) {
+	if !json.Valid(b) {
+		return nil, fmt.Errorf("json: invalid JSON")
+	}
+	v2 := v.Context().CompileBytes(b, cue.Filename("json.Validate"))
+	if err := v2.Err(); err != nil {
+		return nil, err
+	}
+	return violations(v, v.Unify(v2), nil), nil
+}
+
+// violations appends the violations of the constraints of schema by the
+// value v to a. As validation does not report values that are not concrete
+// along with other errors, values with such errors are validated field by
+// field.
+func violations(schema, v cue.Value, a []Violation) []Violation {
+	err := v.Validate(cue.Final(), cue.Concrete(true))
+	if err == nil {
+		return a
+	}
+	n := len(a)
+	if v.Validate(cue.Final()) != nil {
+		if iter, err := v.Fields(cue.Optional(true)); err == nil {
+			for iter.Next() {
+				switch sel := iter.Selector(); sel.ConstraintType() {
+				case cue.RequiredConstraint:
+					sels := iter.Value().Path().Selectors()
+					sels[len(sels)-1] = cue.Str(sel.Unquoted())
+					a = append(a, Violation{
+						Pointer:    jsonPointer(sels),
+						Constraint: constraint(schema, sels),
+						Message:    "field is required but not present",
+					})
+				case cue.OptionalConstraint:
+				default:
+					a = violations(schema, iter.Value(), a)
+				}
+			}
+		} else if iter, err := v.List(); err == nil {
+			for iter.Next() {
+				a = violations(schema, iter.Value(), a)
+			}
+		}
+	}
+	if len(a) > n {
+		return a
+	}
+	// The errors are not those of the fields or elements of v.
+	for _, e := range errors.Errors(errors.Sanitize(errors.Promote(err, ""))) {
+		format, args := e.Msg()
+		// The pointer and constraint are left empty if the path of the
+		// error is not a valid CUE path.
+		sels, _ := jsonpointer.ErrorSelectors(e.Path())
+		a = append(a, Violation{
+			Pointer:    jsonPointer(sels),
+			Constraint: constraint(schema, sels),
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+	return a
+}
+
+// jsonPointer returns the JSON Pointer for the CUE path of a value given
+// by sels, or "" if the path cannot be expressed as a JSON Pointer.
+func jsonPointer(sels []cue.Selector) string {
+	p, err := jsonpointer.FromPath(cue.MakePath(sels...))
+	if err != nil {
+		return ""
+	}
+	return p
+}
+
+// constraint returns the constraint of schema for the value at the CUE
+// path given by sels in CUE syntax, taking into account optional and required
+// fields, pattern constraints and the element types of lists.
+func constraint(schema cue.Value, sels []cue.Selector) string {
+	v := schema
+	for _, sel := range sels {
+		candidates := []cue.Selector{sel, cue.AnyIndex}
+		if sel.LabelType() == cue.StringLabel {
+			candidates = []cue.Selector{sel, sel.Optional(), sel.Required(), cue.AnyString}
+		}
+		var next cue.Value
+		for _, c := range candidates {
+			if next = v.LookupPath(cue.MakePath(c)); next.Exists() {
+				break
+			}
+		}
+		if !next.Exists() {
+			return ""
+		}
+		v = next
+	}
+	b, err := format.Node(v.Syntax())
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
 // Extract parses JSON-encoded data to a CUE expression, using path for
 // position information.
 func Extract(path string, data []byte) (ast.Expr, error) {
@@ -0,0 +1,116 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json_test
+
+import (
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/encoding/json"
+)
+
+func TestValidateAll(t *testing.T) {
+	ctx := cuecontext.New()
+	schema := ctx.CompileString(`
+		#Item: {
+			id!:       int & >0
+			name!:     string
+			"a/b~c"?:  string
+		}
+		items: [...#Item]
+		meta: [string]: int
+		kind: "x" | "y"
+	`)
+	qt.Assert(t, qt.IsNil(schema.Err()))
+
+	testCases := []struct {
+		name string
+		data string
+		want []json.Violation
+	}{{
+		name: "valid",
+		data: `{"items": [{"id": 1, "name": "a"}], "meta": {"n": 1}, "kind": "x"}`,
+	}, {
+		name: "all violations",
+		data: `{
+			"items": [
+				{"name": "a", "a/b~c": 1},
+				{"id": 0, "name": "b", "extra": true}
+			],
+			"meta": {"n": "one"},
+			"kind": "x"
+		}`,
+		want: []json.Violation{{
+			Pointer:    "/items/0/id",
+			Constraint: "int & >0",
+			Message:    "field is required but not present",
+		}, {
+			Pointer:    "/items/0/a~1b~0c",
+			Constraint: "string",
+			Message:    "conflicting values 1 and string (mismatched types int and string)",
+		}, {
+			Pointer:    "/items/1/id",
+			Constraint: "int & >0",
+			Message:    "invalid value 0 (out of bound >0)",
+		}, {
+			Pointer: "/items/1/extra",
+			Message: "field not allowed",
+		}, {
+			Pointer:    "/meta/n",
+			Constraint: "int",
+			Message:    `conflicting values "one" and int (mismatched types string and int)`,
+		}},
+	}, {
+		name: "labels that are not identifiers",
+		data: `{"items": [], "meta": {"0": "zero", "a.b": "ab"}, "kind": "x"}`,
+		want: []json.Violation{{
+			Pointer:    "/meta/0",
+			Constraint: "int",
+			Message:    `conflicting values "zero" and int (mismatched types string and int)`,
+		}, {
+			Pointer:    "/meta/a.b",
+			Constraint: "int",
+			Message:    `conflicting values "ab" and int (mismatched types string and int)`,
+		}},
+	}, {
+		name: "missing field",
+		data: `{"items": [], "meta": {}}`,
+		want: []json.Violation{{
+			Pointer:    "/kind",
+			Constraint: `"x" | "y"`,
+			Message:    `incomplete value "x" | "y"`,
+		}},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.ValidateAll([]byte(tc.data), schema)
+			qt.Assert(t, qt.IsNil(err))
+			qt.Assert(t, qt.DeepEquals(got, tc.want))
+		})
+	}
+
+	got, err := json.ValidateAll([]byte(`"a"`), ctx.CompileString("int"))
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.DeepEquals(got, []json.Violation{{
+		Pointer:    "",
+		Constraint: "int",
+		Message:    `conflicting values int and "a" (mismatched types int and string)`,
+	}}))
+
+	_, err = json.ValidateAll([]byte(`{"kind":`), schema)
+	qt.Assert(t, qt.ErrorMatches(err, "json: invalid JSON"))
+}
@@ -137,6 +137,7 @@ func constraintProperties(key string, n cue.Value, s *state) {
 				f.Attrs = append(f.Attrs, internal.NewAttr("deprecated", ""))
 			}
 		}
+		f.Attrs = append(f.Attrs, state.keywordAttrs(s.cfg)...)
 		obj.Elts = append(obj.Elts, f)
 	})
 }
@@ -34,6 +34,7 @@ import (
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
+	internaljson "cuelang.org/go/internal/encoding/json"
 	"cuelang.org/go/mod/module"
 )
 
@@ -514,12 +515,44 @@ type schemaInfo struct {
 	id         *url.URL
 	deprecated bool
 
+	// extensions holds the keywords starting with "x-" and unknown
+	// holds any other keywords that are not recognized, if these
+	// are to be preserved.
+	extensions []keyword
+	unknown    []keyword
+
 	schemaVersion        Version
 	schemaVersionPresent bool
 
 	hasConstraints bool
 }
 
+// A keyword is a preserved keyword along with the JSON encoding of its
+// value.
+type keyword struct {
+	key, value string
+}
+
+// keywordAttrs returns the attributes preserving the keywords as configured
+// by [Config.ExtensionAttr] and [Config.PreserveUnknownKeywords].
+func (s *schemaInfo) keywordAttrs(cfg *Config) (attrs []*ast.Attribute) {
+	if len(s.extensions) > 0 {
+		attrs = append(attrs, keywordAttr(cfg.ExtensionAttr, s.extensions))
+	}
+	if len(s.unknown) > 0 {
+		attrs = append(attrs, keywordAttr("jsonschema", s.unknown))
+	}
+	return attrs
+}
+
+func keywordAttr(name string, a []keyword) *ast.Attribute {
+	args := make([]string, len(a))
+	for i, x := range a {
+		args[i] = x.key + "=" + x.value
+	}
+	return internal.NewAttr(name, strings.Join(args, ", "))
+}
+
 func (s *state) idTag() *ast.Attribute {
 	return &ast.Attribute{Text: fmt.Sprintf("@jsonschema(id=%q)", s.id)}
 }
@@ -788,6 +821,14 @@ func (s0 *state) schemaState(n cue.Value, types cue.Kind) (expr ast.Expr, ingo s
 				// not intended to be a valid keyword, and is explicitly
 				// allowed by OpenAPI. It seems reasonable that
 				// this is not an error even with StrictKeywords enabled.
+				if pass == 0 {
+					switch {
+					case s.cfg.ExtensionAttr != "":
+						s.extensions = s.appendKeyword(s.extensions, key, value)
+					case s.cfg.PreserveUnknownKeywords:
+						s.unknown = s.appendKeyword(s.unknown, key, value)
+					}
+				}
 				return
 			}
 			// Convert each constraint into a either a value or a functor.
@@ -797,6 +838,9 @@ func (s0 *state) schemaState(n cue.Value, types cue.Kind) (expr ast.Expr, ingo s
 					// TODO: value is not the correct position, albeit close. Fix this.
 					s.warnf(value.Pos(), "unknown keyword %q", key)
 				}
+				if pass == 0 && s.cfg.PreserveUnknownKeywords {
+					s.unknown = s.appendKeyword(s.unknown, key, value)
+				}
 				return
 			}
 			if c.phase != pass {
@@ -806,6 +850,9 @@ func (s0 *state) schemaState(n cue.Value, types cue.Kind) (expr ast.Expr, ingo s
 				if s.cfg.StrictKeywords {
 					s.warnf(value.Pos(), "keyword %q is not supported in JSON schema version %v", key, s.schemaVersion)
 				}
+				if s.cfg.PreserveUnknownKeywords {
+					s.unknown = s.appendKeyword(s.unknown, key, value)
+				}
 				return
 			}
 			if pass > 0 && !vfrom(VersionDraft2019_09).contains(s.schemaVersion) && s.hasRefKeyword && key != "$ref" {
@@ -836,6 +883,17 @@ func (s0 *state) schemaState(n cue.Value, types cue.Kind) (expr ast.Expr, ingo s
 	return schemaExpr, s.schemaInfo
 }
 
+// appendKeyword appends the keyword key with value n to a, so that it can
+// be preserved in an attribute.
+func (s *state) appendKeyword(a []keyword, key string, n cue.Value) []keyword {
+	b, err := internaljson.Marshal(n)
+	if err != nil {
+		s.errf(n, "cannot encode %q: %v", key, err)
+		return a
+	}
+	return append(a, keyword{key: key, value: string(b)})
+}
+
 // maybeDefine checks whether we might need a definition
 // for n given its actual schema syntax expression. If
 // it does, it creates the definition as appropriate and returns
@@ -851,7 +909,7 @@ func (s *state) maybeDefine(expr ast.Expr) ast.Expr {
 	def.schema = expr
 	if def.importPath == "" {
 		// It's a local definition that's not at the root.
-		if !s.builder.put(def.path, expr, s.comment()) {
+		if !s.builder.put(def.path, expr, s.comment(), s.keywordAttrs(s.cfg)...) {
 			s.errf(s.pos, "redefinition of schema CUE path %v", def.path)
 			return expr
 		}
@@ -65,6 +65,10 @@ import (
 // The #version: <version> tag selects the default schema version URI to use.
 // As a special case, when this is "openapi", OpenAPI extraction
 // mode is enabled.
+//
+// The #extensionAttr: <name> tag sets [jsonschema.Config.ExtensionAttr] and
+// the #preserveUnknownKeywords tag sets
+// [jsonschema.Config.PreserveUnknownKeywords].
 func TestDecode(t *testing.T) {
 	test := cuetxtar.TxTarTest{
 		Root:   "./testdata/txtar",
@@ -102,6 +106,8 @@ func TestDecode(t *testing.T) {
 		cfg.StrictKeywords = cfg.StrictKeywords || t.HasTag("strictKeywords")
 		cfg.StrictFeatures = t.HasTag("strictFeatures")
 		cfg.PkgName, _ = t.Value("pkgName")
+		cfg.ExtensionAttr, _ = t.Value("extensionAttr")
+		cfg.PreserveUnknownKeywords = t.HasTag("preserveUnknownKeywords")
 
 		ctx := t.CueContext()
 
@@ -244,6 +244,30 @@ type Config struct {
 	// will be used.
 	DefaultVersion Version
 
+	// ExtensionAttr, if non-empty, causes keywords starting with "x-",
+	// such as the vendor extensions of OpenAPI, to be preserved as arguments
+	// of a field attribute with this name. Each argument is of the form
+	// key=value, where value is the JSON encoding of the keyword's value.
+	// For instance, if ExtensionAttr is "openapi", a property with the
+	// keyword "x-go-name": "Foo" results in the attribute
+	//
+	//	@openapi(x-go-name="Foo")
+	//
+	// Only keywords of properties and named schemas are preserved.
+	ExtensionAttr string
+
+	// PreserveUnknownKeywords causes keywords that are not recognized, or
+	// that are not supported by the schema version in use, to be preserved
+	// as arguments of a @jsonschema field attribute, in the same form as
+	// described for ExtensionAttr. For instance, the keyword
+	// "discriminator": "kind" results in
+	//
+	//	@jsonschema(discriminator="kind")
+	//
+	// Keywords starting with "x-" are included too, unless ExtensionAttr
+	// is set.
+	PreserveUnknownKeywords bool
+
 	_ struct{} // prohibit casting from different type.
 }
 
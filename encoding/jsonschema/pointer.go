@@ -1,45 +1,47 @@
 package jsonschema
 
-import "strings"
+import (
+	"fmt"
+	"strings"
 
-// TODO this file contains functionality that mimics the JSON Pointer functionality
-// in https://pkg.go.dev/github.com/go-json-experiment/json/jsontext#Pointer;
-// perhaps use it when it moves into the stdlib as json/v2.
-
-var (
-	jsonPtrEsc   = strings.NewReplacer("~", "~0", "/", "~1")
-	jsonPtrUnesc = strings.NewReplacer("~0", "~", "~1", "/")
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/internal/encoding/jsonpointer"
 )
 
-// TODO(go1.23) func jsonPointerFromTokens(tokens iter.Seq[string]) string
-func jsonPointerFromTokens(tokens func(func(string) bool)) string {
-	var buf strings.Builder
-	// TODO for tok := range tokens {
-	tokens(func(tok string) bool {
-		buf.WriteByte('/')
-		buf.WriteString(jsonPtrEsc.Replace(tok))
-		return true
-	})
-	return buf.String()
+// PointerFromPath returns the JSON Pointer, as defined by RFC 6901, that
+// refers to the same location within a JSON document as p refers to within
+// the CUE value decoded from that document.
+//
+// It returns an error if p contains selectors that cannot occur in data,
+// such as definitions, hidden fields or patterns.
+func PointerFromPath(p cue.Path) (string, error) {
+	return jsonpointer.FromPath(p)
 }
 
-// TODO(go1.23) func jsonPointerTokens(p string) iter.Seq[string]
-func jsonPointerTokens(p string) func(func(string) bool) {
-	return func(yield func(string) bool) {
-		needUnesc := strings.IndexByte(p, '~') >= 0
-		for len(p) > 0 {
-			p = strings.TrimPrefix(p, "/")
-			i := min(uint(strings.IndexByte(p, '/')), uint(len(p)))
-			var ok bool
-			if needUnesc {
-				ok = yield(jsonPtrUnesc.Replace(p[:i]))
-			} else {
-				ok = yield(p[:i])
-			}
-			if !ok {
-				return
-			}
-			p = p[i:]
+// ErrorPointer returns the JSON Pointer of the location of err within the
+// data that failed validation, so that the error can be reported in terms
+// of the original JSON document. The root is the path at which the data was
+// unified with the schema, such as the empty path if the data was unified
+// with a schema at the root of a value.
+//
+// It returns an error if the path of err is not within root or if it
+// cannot be expressed as a JSON Pointer.
+func ErrorPointer(err errors.Error, root cue.Path) (string, error) {
+	elems := err.Path()
+	rootSels := root.Selectors()
+	if len(elems) < len(rootSels) {
+		return "", fmt.Errorf("error path %s is not within %v", strings.Join(elems, "."), root)
+	}
+	for i, sel := range rootSels {
+		if sel.String() != elems[i] {
+			return "", fmt.Errorf("error path %s is not within %v", strings.Join(elems, "."), root)
 		}
 	}
+
+	sels, selErr := jsonpointer.ErrorSelectors(elems[len(rootSels):])
+	if selErr != nil {
+		return "", selErr
+	}
+	return PointerFromPath(cue.MakePath(sels...))
 }
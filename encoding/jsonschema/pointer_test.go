@@ -0,0 +1,78 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/encoding/jsonschema"
+)
+
+func TestPointerFromPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		err  string
+	}{
+		{path: "", want: ""},
+		{path: `a[0]."b-c"`, want: "/a/0/b-c"},
+		{path: `"x/y~z"`, want: "/x~1y~0z"},
+		{path: "a.#b", err: "cannot convert selector #b in a.#b to JSON Pointer"},
+		{path: "a._b", err: "invalid path: hidden label _b not allowed"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			got, err := jsonschema.PointerFromPath(cue.ParsePath(test.path))
+			if test.err != "" {
+				qt.Assert(t, qt.ErrorMatches(err, test.err))
+				return
+			}
+			qt.Assert(t, qt.IsNil(err))
+			qt.Assert(t, qt.Equals(got, test.want))
+		})
+	}
+}
+
+func TestErrorPointer(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+#S: {
+	a?: [...{[string]: int}]
+	[=~"/"]: string
+}
+v: #S & {
+	a: [{"b-c": "foo"}]
+	"x/y~z": 1
+}
+`)
+	err := v.Validate()
+	qt.Assert(t, qt.IsNotNil(err))
+
+	var got []string
+	for _, e := range errors.Errors(err) {
+		ptr, err := jsonschema.ErrorPointer(e, cue.ParsePath("v"))
+		qt.Assert(t, qt.IsNil(err))
+		got = append(got, ptr)
+	}
+	qt.Assert(t, qt.ContentEquals(got, []string{"/a/0/b-c", "/x~1y~0z"}))
+
+	_, err = jsonschema.ErrorPointer(errors.Errors(err)[0], cue.ParsePath("w"))
+	qt.Assert(t, qt.ErrorMatches(err, `error path v\.a\.0\."b-c" is not within w`))
+}
@@ -27,6 +27,7 @@ import (
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/encoding/jsonpointer"
 )
 
 func parseRootRef(str string) (cue.Path, error) {
@@ -44,7 +45,7 @@ func parseRootRef(str string) (cue.Path, error) {
 	// (technically a trailing slash `/` means there's an empty
 	// final element).
 	u.Fragment = strings.TrimSuffix(u.Fragment, "/")
-	fragmentParts := collectSlice(jsonPointerTokens(u.Fragment))
+	fragmentParts := collectSlice(jsonpointer.Tokens(u.Fragment))
 	var selectors []cue.Selector
 	for _, r := range fragmentParts {
 		// Technically this is incorrect because a numeric
@@ -58,8 +59,8 @@ func parseRootRef(str string) (cue.Path, error) {
 var errRefNotFound = errors.New("JSON Pointer reference not found")
 
 func lookupJSONPointer(v cue.Value, p string) (_ cue.Value, _err error) {
-	// TODO(go1.23) for part := range jsonPointerTokens(p)
-	jsonPointerTokens(p)(func(part string) bool {
+	// TODO(go1.23) for part := range jsonpointer.Tokens(p)
+	jsonpointer.Tokens(p)(func(part string) bool {
 		// Note: a JSON Pointer doesn't distinguish between indexing
 		// and struct lookup. We have to use the value itself to decide
 		// which operation is appropriate.
@@ -176,7 +177,7 @@ func defaultMapRef(
 	if len(fragment) > 0 && fragment[0] != '/' {
 		return "", cue.Path{}, fmt.Errorf("anchors (%s) not supported", fragment)
 	}
-	parts := collectSlice(jsonPointerTokens(fragment))
+	parts := collectSlice(jsonpointer.Tokens(fragment))
 	labels, err := mapFn(token.Pos{}, parts)
 	if err != nil {
 		return "", cue.Path{}, err
@@ -203,7 +204,7 @@ func defaultMap(p token.Pos, a []string) ([]ast.Label, error) {
 		// TODO this is needlessly inefficient, as we're putting something
 		// back together that was already joined before defaultMap was
 		// invoked. This does avoid dual implementations though.
-		p := jsonPointerFromTokens(sliceValues(a))
+		p := jsonpointer.FromTokens(sliceValues(a))
 		return []ast.Label{ast.NewIdent("_#defs"), ast.NewString(p)}, nil
 	}
 	name := a[1]
@@ -42,6 +42,9 @@ type structBuilderNode struct {
 	// comment holds any doc comment associated with the value.
 	comment *ast.CommentGroup
 
+	// attrs holds any attributes associated with the field of the value.
+	attrs []*ast.Attribute
+
 	// entries holds the children of this node, keyed by the
 	// name of each child's struct field selector.
 	entries map[cue.Selector]*structBuilderNode
@@ -49,8 +52,9 @@ type structBuilderNode struct {
 
 // put associates value with the given path. It reports whether
 // the value was successfully put, returning false if a value
-// already exists for the path.
-func (b *structBuilder) put(p cue.Path, value ast.Expr, comment *ast.CommentGroup) bool {
+// already exists for the path. Any attributes are added
+// to the field for the value, unless p is the root.
+func (b *structBuilder) put(p cue.Path, value ast.Expr, comment *ast.CommentGroup, attrs ...*ast.Attribute) bool {
 	e := b.entryForPath(p)
 	if e.value != nil {
 		// redefinition
@@ -58,6 +62,7 @@ func (b *structBuilder) put(p cue.Path, value ast.Expr, comment *ast.CommentGrou
 	}
 	e.value = value
 	e.comment = comment
+	e.attrs = attrs
 	return true
 }
 
@@ -191,12 +196,12 @@ func (b *structBuilder) appendDecls(n *structBuilderNode, db *declBuilder) (_err
 				if _err != nil {
 					return
 				}
-				db0.decls, _err = appendField(db0.decls, cue.MakePath(db0.path...), exprFromDecls(db.decls), n.comment)
+				db0.decls, _err = appendField(db0.decls, cue.MakePath(db0.path...), exprFromDecls(db.decls), n.comment, n.attrs)
 			}()
 		}
 		// Note: when the path is empty, we rely on the outer level
 		// to add any doc comment required.
-		db.decls, _err = appendField(db.decls, cue.MakePath(db.path...), n.value, n.comment)
+		db.decls, _err = appendField(db.decls, cue.MakePath(db.path...), n.value, n.comment, n.attrs)
 		if _err != nil {
 			return _err
 		}
@@ -251,7 +256,7 @@ func appendDeclsExpr(decls []ast.Decl, expr ast.Expr) []ast.Decl {
 	return decls
 }
 
-func appendField(decls []ast.Decl, path cue.Path, v ast.Expr, comment *ast.CommentGroup) ([]ast.Decl, error) {
+func appendField(decls []ast.Decl, path cue.Path, v ast.Expr, comment *ast.CommentGroup, attrs []*ast.Attribute) ([]ast.Decl, error) {
 	if len(path.Selectors()) == 0 {
 		return appendDeclsExpr(decls, v), nil
 	}
@@ -266,6 +271,10 @@ func appendField(decls []ast.Decl, path cue.Path, v ast.Expr, comment *ast.Comme
 	if comment != nil {
 		ast.SetComments(elt, []*ast.CommentGroup{comment})
 	}
+	if len(attrs) > 0 {
+		f := elt.(*ast.Field)
+		f.Attrs = append(f.Attrs, attrs...)
+	}
 	ast.SetRelPos(elt, token.NewSection)
 	return append(decls, elt), nil
 }
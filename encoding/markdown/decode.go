@@ -0,0 +1,160 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package markdown converts Markdown files with front matter to CUE.
+//
+// A Markdown file is decoded as a struct with two fields: frontMatter holds
+// the front matter of the file, if any, and body holds the text that
+// follows it as a string. This allows validating the metadata of content
+// repositories, such as those of static site generators like Hugo or
+// Docusaurus, against a CUE schema.
+//
+// The front matter must start at the first line of the file. YAML front
+// matter is delimited by lines consisting of "---" and TOML front matter by
+// lines consisting of "+++". YAML front matter may also be terminated by a
+// line consisting of "...". The front matter must decode to a struct.
+//
+// WARNING: THIS PACKAGE IS EXPERIMENTAL.
+// ITS API MAY CHANGE AT ANY TIME.
+package markdown
+
+import (
+	"bytes"
+	"io"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/encoding/toml"
+	"cuelang.org/go/internal/encoding/yaml"
+)
+
+// NewDecoder creates a decoder from a Markdown file.
+func NewDecoder(filename string, r io.Reader) *Decoder {
+	return &Decoder{r: r, filename: filename}
+}
+
+// Decoder implements the decoding state.
+//
+// Note that a Markdown file never decodes to multiple CUE nodes;
+// subsequent calls to [Decoder.Decode] return [io.EOF].
+type Decoder struct {
+	r io.Reader
+
+	filename string
+
+	decoded bool // whether [Decoder.Decode] has been called already
+}
+
+// Decode reads the Markdown file and converts it to a CUE struct
+// with the fields frontMatter, if the file has front matter, and body.
+// Because a Markdown file only contains a single value,
+// subsequent calls to this method return [io.EOF].
+func (d *Decoder) Decode() (ast.Expr, error) {
+	if d.decoded {
+		return nil, io.EOF
+	}
+	d.decoded = true
+	data, err := io.ReadAll(d.r)
+	if err != nil {
+		return nil, err
+	}
+	tokenFile := token.NewFile(d.filename, 0, len(data))
+	tokenFile.SetLinesForContent(data)
+
+	s := &ast.StructLit{}
+	bodyStart := 0
+	delim, rest, ok := cutLine(data)
+	if ok && (string(delim) == "---" || string(delim) == "+++") {
+		end, next := -1, rest
+		for len(next) > 0 {
+			line, after, _ := cutLine(next)
+			if string(line) == string(delim) || (delim[0] == '-' && string(line) == "...") {
+				end = len(data) - len(next)
+				bodyStart = len(data) - len(after)
+				break
+			}
+			next = after
+		}
+		if end < 0 {
+			return nil, errors.Newf(tokenFile.Pos(0, token.NoRelPos),
+				"front matter starting with %q is not terminated", delim)
+		}
+		fm, err := d.decodeFrontMatter(data[:end], delim[0] == '+')
+		if err != nil {
+			return nil, err
+		}
+		s.Elts = append(s.Elts, &ast.Field{
+			Label: ast.NewIdent("frontMatter"),
+			Value: fm,
+		})
+	}
+
+	body := &ast.BasicLit{
+		ValuePos: tokenFile.Pos(bodyStart, token.NoRelPos),
+		Kind:     token.STRING,
+		Value:    literal.String.WithOptionalTabIndent(1).Quote(string(data[bodyStart:])),
+	}
+	s.Elts = append(s.Elts, &ast.Field{
+		Label: ast.NewIdent("body"),
+		Value: body,
+	})
+	return s, nil
+}
+
+// decodeFrontMatter decodes the front matter in data, which starts with the
+// opening delimiter. The delimiter is decoded along with the front matter,
+// so that the positions of the decoded values match those in the Markdown
+// file: for YAML it starts the document, and for TOML, where it is not
+// valid, it is replaced with spaces in a copy of data.
+func (d *Decoder) decodeFrontMatter(data []byte, isTOML bool) (ast.Expr, error) {
+	var x ast.Expr
+	if isTOML {
+		// The "+++" delimiter is not valid TOML, so blank it out.
+		data = bytes.Clone(data)
+		copy(data, "   ")
+		var err error
+		x, err = toml.NewDecoder(d.filename, bytes.NewReader(data)).Decode()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// The "---" delimiter starts a YAML document.
+		var err error
+		x, err = yaml.Unmarshal(d.filename, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch x := x.(type) {
+	case nil:
+		return ast.NewStruct(), nil
+	case *ast.StructLit:
+		return x, nil
+	case *ast.BasicLit:
+		if x.Kind == token.NULL {
+			return ast.NewStruct(), nil
+		}
+	}
+	return nil, errors.Newf(x.Pos(), "front matter must be a struct")
+}
+
+// cutLine returns the first line of data, without its line terminator and
+// any trailing white space, and the data following it. It reports whether
+// the line was terminated.
+func cutLine(data []byte) (line, rest []byte, ok bool) {
+	line, rest, ok = bytes.Cut(data, []byte("\n"))
+	return bytes.TrimRight(line, " \t\r"), rest, ok
+}
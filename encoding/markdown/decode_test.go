@@ -0,0 +1,107 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/encoding/markdown"
+)
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantJSON string
+		wantErr  string
+	}{{
+		name:     "Empty",
+		input:    "",
+		wantJSON: `{"body":""}`,
+	}, {
+		name:     "NoFrontMatter",
+		input:    "# Title\n\nText.\n",
+		wantJSON: `{"body":"# Title\n\nText.\n"}`,
+	}, {
+		name:     "NotAtStart",
+		input:    "\n---\na: 1\n---\n",
+		wantJSON: `{"body":"\n---\na: 1\n---\n"}`,
+	}, {
+		name:     "YAML",
+		input:    "---\ntitle: Hello\ntags: [a, b]\n---\n# Hello\n",
+		wantJSON: `{"frontMatter":{"title":"Hello","tags":["a","b"]},"body":"# Hello\n"}`,
+	}, {
+		name:     "YAMLDocumentEnd",
+		input:    "---\ntitle: Hello\n...\nText.",
+		wantJSON: `{"frontMatter":{"title":"Hello"},"body":"Text."}`,
+	}, {
+		name:     "YAMLEmpty",
+		input:    "---\n---\nText.\n",
+		wantJSON: `{"frontMatter":{},"body":"Text.\n"}`,
+	}, {
+		name:     "TOML",
+		input:    "+++\ntitle = \"Hello\"\nweight = 3\n+++\n# Hello\n",
+		wantJSON: `{"frontMatter":{"title":"Hello","weight":3},"body":"# Hello\n"}`,
+	}, {
+		name:     "CRLF",
+		input:    "---\r\ntitle: Hello\r\n---\r\nText.\r\n",
+		wantJSON: `{"frontMatter":{"title":"Hello"},"body":"Text.\r\n"}`,
+	}, {
+		name:    "Unterminated",
+		input:   "---\ntitle: Hello\n",
+		wantErr: "front matter starting with \"---\" is not terminated:\n    test.md:1:1",
+	}, {
+		name:    "NotAStruct",
+		input:   "---\n- a\n---\n",
+		wantErr: "front matter must be a struct:\n    test.md:2:1",
+	}, {
+		name:    "InvalidTOML",
+		input:   "+++\n\ntitle = \n+++\n",
+		wantErr: "incomplete number:\n    test.md:3:9",
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dec := markdown.NewDecoder("test.md", strings.NewReader(test.input))
+			node, err := dec.Decode()
+			if test.wantErr != "" {
+				gotErr := strings.TrimSuffix(errors.Details(err, nil), "\n")
+				qt.Assert(t, qt.Equals(gotErr, test.wantErr))
+				qt.Assert(t, qt.IsNil(node))
+				return
+			}
+			qt.Assert(t, qt.IsNil(err))
+
+			node2, err := dec.Decode()
+			qt.Assert(t, qt.IsNil(node2))
+			qt.Assert(t, qt.Equals(err, io.EOF))
+
+			v := cuecontext.New().BuildExpr(node)
+			qt.Assert(t, qt.IsNil(v.Err()))
+			got, err := v.MarshalJSON()
+			qt.Assert(t, qt.IsNil(err))
+			qt.Assert(t, qt.JSONEquals(got, rawJSON(test.wantJSON)))
+		})
+	}
+}
+
+type rawJSON string
+
+func (s rawJSON) MarshalJSON() ([]byte, error) { return []byte(s), nil }
@@ -15,6 +15,7 @@
 package openapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"path"
@@ -26,6 +27,7 @@ import (
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/token"
+	cuejson "cuelang.org/go/encoding/json"
 	"cuelang.org/go/internal"
 	"cuelang.org/go/internal/core/adt"
 )
@@ -42,10 +44,15 @@ type buildContext struct {
 	exclusiveBool bool
 	nameFunc      func(inst cue.Value, path cue.Path) string
 	descFunc      func(v cue.Value) string
+	extFunc       func(v cue.Value) map[string]any
 	fieldFilter   *regexp.Regexp
 
 	schemas *orderedMap
 
+	// sources maps the names of generated schemas to the CUE path of the
+	// value from which they were generated.
+	sources map[string]cue.Path
+
 	// Track external schemas.
 	externalRefs map[string]*externalType
 
@@ -67,13 +74,13 @@ type externalType struct {
 
 type typeFunc func(b *builder, a cue.Value)
 
-func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, err error) {
+func schemas(g *Generator, inst cue.InstanceOrValue) (schemas, paths *ast.StructLit, sources map[string]cue.Path, err error) {
 	val := inst.Value()
 	var fieldFilter *regexp.Regexp
 	if g.FieldFilter != "" {
 		fieldFilter, err = regexp.Compile(g.FieldFilter)
 		if err != nil {
-			return nil, errors.Newf(token.NoPos, "invalid field filter: %v", err)
+			return nil, nil, nil, errors.Newf(token.NoPos, "invalid field filter: %v", err)
 		}
 
 		// verify that certain elements are still passed.
@@ -81,7 +88,7 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 			"version,title,allOf,anyOf,not,enum,Schema/properties,Schema/items"+
 				"nullable,type", ",") {
 			if fieldFilter.MatchString(f) {
-				return nil, errors.Newf(token.NoPos, "field filter may not exclude %q", f)
+				return nil, nil, nil, errors.Newf(token.NoPos, "field filter may not exclude %q", f)
 			}
 		}
 	}
@@ -98,7 +105,9 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 		structural:   g.ExpandReferences,
 		nameFunc:     g.NameFunc,
 		descFunc:     g.DescriptionFunc,
+		extFunc:      g.ExtensionFunc,
 		schemas:      &orderedMap{},
+		sources:      map[string]cue.Path{},
 		externalRefs: map[string]*externalType{},
 		fieldFilter:  fieldFilter,
 	}
@@ -108,7 +117,7 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 		c.exclusiveBool = true
 	case "3.1.0":
 	default:
-		return nil, errors.Newf(token.NoPos, "unsupported version %s", g.Version)
+		return nil, nil, nil, errors.Newf(token.NoPos, "unsupported version %s", g.Version)
 	}
 
 	defer func() {
@@ -121,11 +130,9 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 		}
 	}()
 
-	// Although paths is empty for now, it makes it valid OpenAPI spec.
-
 	i, err := inst.Value().Fields(cue.Definitions(true))
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	for i.Next() {
 		sel := i.Selector()
@@ -143,6 +150,16 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 		c.schemas.setExpr(ref, c.build(sel, i.Value()))
 	}
 
+	// Operations may refer to external schemas, so generate the paths
+	// before resolving them. An empty paths section keeps the document a
+	// valid OpenAPI document.
+	paths = ast.NewStruct()
+	for i, _ := val.Fields(); i.Next(); {
+		if topLevelLabel(i) == "paths" {
+			paths = c.paths(i.Value())
+		}
+	}
+
 	// keep looping until a fixed point is reached.
 	for done := 0; len(c.externalRefs) != done; {
 		done = len(c.externalRefs)
@@ -172,7 +189,54 @@ func schemas(g *Generator, inst cue.InstanceOrValue) (schemas *ast.StructLit, er
 		return x < y
 	})
 
-	return (*ast.StructLit)(c.schemas), c.errs
+	return (*ast.StructLit)(c.schemas), paths, c.sources, c.errs
+}
+
+// paths generates the paths section of an OpenAPI document from v, which
+// maps paths to Path Item Objects as defined by the OpenAPI specification.
+// The values of schema fields, as found in Parameter, Header and Media
+// Type Objects, are converted to OpenAPI schemas. In particular, a
+// reference to a definition results in a reference to the schema generated
+// for it. All other values must be concrete.
+func (c *buildContext) paths(v cue.Value) *ast.StructLit {
+	s, ok := c.operations(v).(*ast.StructLit)
+	if !ok {
+		newRootBuilder(c).failf(v, "paths must be a struct")
+	}
+	return s
+}
+
+func (c *buildContext) operations(v cue.Value) ast.Expr {
+	switch v.IncompleteKind() {
+	case cue.StructKind:
+		m := &orderedMap{}
+		for i, _ := v.Fields(); i.Next(); {
+			sel := i.Selector()
+			if sel.Unquoted() != "schema" {
+				m.setExpr(sel.Unquoted(), c.operations(i.Value()))
+				continue
+			}
+			oldPath := c.path
+			c.path = v.Path().Selectors()
+			m.setExpr("schema", c.build(sel, i.Value()))
+			c.path = oldPath
+		}
+		return (*ast.StructLit)(m)
+
+	case cue.ListKind:
+		a := []ast.Expr{}
+		for i, _ := v.List(); i.Next(); {
+			a = append(a, c.operations(i.Value()))
+		}
+		return ast.NewList(a...)
+	}
+
+	c.path = v.Path().Selectors()
+	if err := v.Validate(cue.Concrete(true)); err != nil {
+		newRootBuilder(c).failf(v, "operation field must be concrete: %v", err)
+	}
+	c.path = nil
+	return v.Syntax(cue.Final(), cue.Concrete(true)).(ast.Expr)
 }
 
 func (c *buildContext) build(name cue.Selector, v cue.Value) *ast.StructLit {
@@ -243,6 +307,70 @@ func (b *builder) getDoc(v cue.Value) {
 	}
 }
 
+// getExtensions adds the vendor extensions for v, as determined by
+// Config.ExtensionFunc or the @openapi attributes of v. It also adds the
+// keywords preserved in @jsonschema attributes of v, such as those added by
+// jsonschema.Config.PreserveUnknownKeywords when extracting a schema.
+func (b *builder) getExtensions(v cue.Value) {
+	if b.ctx.extFunc != nil {
+		m := b.ctx.extFunc(v)
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !strings.HasPrefix(k, "x-") {
+				b.failf(v, "invalid extension %q: must start with x-", k)
+			}
+			x, err := toCUE(k, m[k])
+			if err != nil {
+				b.failf(v, "%v", err)
+			}
+			b.setSingle(k, x, true)
+		}
+	}
+
+	for _, a := range v.Attributes(cue.FieldAttr) {
+		switch a.Name() {
+		case "openapi":
+			if b.ctx.extFunc != nil {
+				continue
+			}
+			for i := 0; i < a.NumArgs(); i++ {
+				if key, _ := a.Arg(i); strings.HasPrefix(key, "x-") {
+					b.setSingle(key, b.attrValue(v, a, i), true)
+				}
+			}
+
+		case "jsonschema":
+			for i := 0; i < a.NumArgs(); i++ {
+				// Keywords that are generated take precedence.
+				if key, _ := a.Arg(i); b.singleFields == nil || !b.singleFields.exists(key) {
+					b.setSingle(key, b.attrValue(v, a, i), true)
+				}
+			}
+		}
+	}
+}
+
+// attrValue returns the value of the i'th argument of a, which is a JSON
+// value or, if it is not valid JSON, a string.
+func (b *builder) attrValue(v cue.Value, a cue.Attribute, i int) ast.Expr {
+	key, value := a.Arg(i)
+	// Use the raw argument to distinguish quoted from unquoted values, such
+	// as "1" and 1.
+	_, raw, _ := strings.Cut(a.RawArg(i), "=")
+	if raw = strings.TrimSpace(raw); !json.Valid([]byte(raw)) {
+		return ast.NewString(value)
+	}
+	x, err := cuejson.Extract(key, []byte(raw))
+	if err != nil {
+		b.failf(v, "invalid value for %q: %v", key, err)
+	}
+	return x
+}
+
 func (b *builder) fillSchema(v cue.Value) *ast.StructLit {
 	if b.filled != nil {
 		return b.filled
@@ -263,6 +391,8 @@ func (b *builder) fillSchema(v cue.Value) *ast.StructLit {
 		}
 	}
 
+	b.getExtensions(v)
+
 	schema := b.finish()
 	s := (*ast.StructLit)(schema)
 
@@ -883,7 +1013,7 @@ func (b *builder) listCap(v cue.Value) {
 		// must be type, so okay.
 	case cue.NotEqualOp:
 		i := b.int(a[0])
-		b.setNot("allOff", ast.NewList(
+		b.setNot("allOf", ast.NewList(
 			b.kv("minItems", i),
 			b.kv("maxItems", i),
 		))
@@ -923,7 +1053,7 @@ func (b *builder) number(v cue.Value) {
 
 	case cue.NotEqualOp:
 		i := b.big(a[0])
-		b.setNot("allOff", ast.NewList(
+		b.setNot("allOf", ast.NewList(
 			b.kv("minimum", i),
 			b.kv("maximum", i),
 		))
@@ -1240,6 +1370,14 @@ func (b *builder) addRef(v cue.Value, inst cue.Value, ref cue.Path) {
 }
 
 func (b *buildContext) makeRef(inst cue.Value, ref cue.Path) string {
+	name := b.refName(inst, ref)
+	if _, ok := b.sources[name]; name != "" && !ok {
+		b.sources[name] = ref
+	}
+	return name
+}
+
+func (b *buildContext) refName(inst cue.Value, ref cue.Path) string {
 	if b.nameFunc != nil {
 		return b.nameFunc(inst, ref)
 	}
@@ -79,10 +79,15 @@ func Extract(data cue.InstanceOrValue, c *Config) (*ast.File, error) {
 		add(cg)
 	}
 
+	var extensionAttr string
+	if c.PreserveExtensions {
+		extensionAttr = "openapi"
+	}
 	js, err := jsonschema.Extract(data, &jsonschema.Config{
 		Root:           oapiSchemas,
-		Map:            openAPIMapping,
+		Map:            c.mapping,
 		DefaultVersion: schemaVersion,
+		ExtensionAttr:  extensionAttr,
 		StrictFeatures: c.StrictFeatures,
 		// OpenAPI 3.0 is stricter than JSON Schema about allowed keywords.
 		StrictKeywords: schemaVersion == jsonschema.VersionOpenAPI || c.StrictKeywords,
@@ -150,13 +155,18 @@ const oapiSchemas = "#/components/schemas/"
 // TODO: find something more principled.
 const rootDefs = "#SchemaMap"
 
-func openAPIMapping(pos token.Pos, a []string) ([]ast.Label, error) {
+func (c *Config) mapping(pos token.Pos, a []string) ([]ast.Label, error) {
 	if len(a) != 3 || a[0] != "components" || a[1] != "schemas" {
 		return nil, errors.Newf(pos,
 			`openapi: reference must be of the form %q; found "#/%s"`,
 			oapiSchemas, strings.Join(a, "/"))
 	}
 	name := a[2]
+	if c.DefinitionFunc != nil {
+		if p := c.DefinitionFunc(name); len(p.Selectors()) > 0 {
+			return pathLabels(pos, name, p)
+		}
+	}
 	if ast.IsValidIdent(name) &&
 		name != rootDefs[1:] &&
 		!internal.IsDefOrHidden(name) {
@@ -164,3 +174,28 @@ func openAPIMapping(pos token.Pos, a []string) ([]ast.Label, error) {
 	}
 	return []ast.Label{ast.NewIdent(rootDefs), ast.NewString(name)}, nil
 }
+
+// pathLabels converts the path p returned by Config.DefinitionFunc for the
+// schema name to labels.
+func pathLabels(pos token.Pos, name string, p cue.Path) ([]ast.Label, error) {
+	if err := p.Err(); err != nil {
+		return nil, errors.Wrapf(err, pos, "openapi: invalid path for schema %q", name)
+	}
+	var labels []ast.Label
+	for _, sel := range p.Selectors() {
+		switch s := sel.String(); sel.LabelType() {
+		case cue.DefinitionLabel:
+			labels = append(labels, ast.NewIdent(s))
+		case cue.StringLabel:
+			if ast.IsValidIdent(s) {
+				labels = append(labels, ast.NewIdent(s))
+			} else {
+				labels = append(labels, ast.NewString(sel.Unquoted()))
+			}
+		default:
+			return nil, errors.Newf(pos,
+				"openapi: invalid path %v for schema %q: unsupported selector %v", p, name, sel)
+		}
+	}
+	return labels, nil
+}
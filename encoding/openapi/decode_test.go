@@ -20,6 +20,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -58,7 +59,12 @@ func TestDecode(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			cfg := &openapi.Config{PkgName: "foo"}
+			cfg := &openapi.Config{
+				PkgName: "foo",
+				// Tests can enable the preservation of vendor extensions
+				// with a #preserveExtensions line in the archive comment.
+				PreserveExtensions: slices.Contains(strings.Fields(string(a.Comment)), "#preserveExtensions"),
+			}
 
 			var inFile *ast.File
 			var out, errout []byte
@@ -133,3 +139,40 @@ func TestDecode(t *testing.T) {
 	})
 	qt.Assert(t, qt.IsNil(err))
 }
+
+func TestExtractDefinitionFunc(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+openapi: "3.0.0"
+info: title: "Test"
+components: schemas: {
+	"io.k8s.Pod": {
+		type: "object"
+		properties: spec: $ref: "#/components/schemas/io.k8s.PodSpec"
+	}
+	"io.k8s.PodSpec": type: "object"
+}
+`)
+	qt.Assert(t, qt.IsNil(v.Err()))
+	f, err := openapi.Extract(v, &openapi.Config{
+		DefinitionFunc: func(name string) cue.Path {
+			return cue.MakePath(cue.Def(strings.TrimPrefix(name, "io.k8s.")))
+		},
+	})
+	qt.Assert(t, qt.IsNil(err))
+	b, err := format.Node(f, format.Simplify())
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(b), `// Test
+
+info: title: *"Test" | string
+
+#Pod: {
+	spec?: #PodSpec
+	...
+}
+
+#PodSpec: {
+	...
+}
+`))
+}
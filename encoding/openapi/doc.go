@@ -15,7 +15,18 @@
 // Package openapi provides functionality for mapping CUE to and from
 // OpenAPI v3.0.0.
 //
-// It currently handles OpenAPI Schema components only.
+// Definitions are mapped to OpenAPI Schema components. In addition, the
+// top-level fields info, servers, paths, security, tags and externalDocs
+// define the corresponding sections of the generated document. Within
+// paths, the value of each schema field is converted to a Schema Object,
+// so that operations may refer to definitions:
+//
+//	paths: "/pets/{id}": get: responses: "200": {
+//		description: "The requested pet."
+//		content: "application/json": schema: #Pet
+//	}
+//
+// All other values in these sections must be concrete.
 //
 // See https://github.com/OAI/OpenAPI-Specification/blob/main/versions/3.0.0.md#schema-object
 package openapi
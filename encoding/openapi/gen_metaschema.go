@@ -0,0 +1,120 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+
+// This command generates metaschema_v3_0.cue from metaschema_v3_0.yaml,
+// the JSON Schema for OpenAPI 3.0 documents.
+//
+// metaschema_v3_0.yaml is a verbatim copy of _archive_/schemas/v3.0/schema.yaml
+// of https://github.com/OAI/OpenAPI-Specification at commit c9f8f040e825.
+// It is the source of the schemas published at
+// https://spec.openapis.org/oas/3.0/schema/. To update it, replace the file
+// with a newer copy and run go generate.
+//
+// The definitions of the schema are placed in a struct named v3_0, and the
+// root schema is placed in its #Document definition.
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/encoding/jsonschema"
+	"cuelang.org/go/encoding/yaml"
+)
+
+const header = `// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_metaschema.go; DO NOT EDIT.
+
+`
+
+const (
+	in  = "metaschema_v3_0.yaml"
+	out = "metaschema_v3_0.cue"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	data, err := os.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	expr, err := yaml.Extract(in, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	v := cuecontext.New().BuildFile(expr)
+	if err := v.Err(); err != nil {
+		log.Fatal(err)
+	}
+	schema, err := jsonschema.Extract(v, &jsonschema.Config{
+		DefaultVersion: jsonschema.VersionDraft4,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f := &ast.File{}
+	var root, defs []ast.Decl
+	for _, d := range schema.Decls {
+		switch d := d.(type) {
+		case *ast.Package, *ast.Attribute:
+		case *ast.ImportDecl:
+			f.Decls = append(f.Decls, d)
+		case *ast.Field:
+			if name, _, _ := ast.LabelName(d.Label); strings.HasPrefix(name, "#") {
+				defs = append(defs, d)
+				break
+			}
+			root = append(root, d)
+		default:
+			root = append(root, d)
+		}
+	}
+	doc := &ast.Field{
+		Label: ast.NewIdent("#Document"),
+		Value: &ast.StructLit{Elts: root},
+	}
+	f.Decls = append(f.Decls, &ast.Field{
+		Label: ast.NewIdent("v3_0"),
+		Value: &ast.StructLit{Elts: append([]ast.Decl{doc}, defs...)},
+	})
+
+	b, err := format.Node(f, format.Simplify())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, append([]byte(header), b...), 0o666); err != nil {
+		log.Fatal(err)
+	}
+}
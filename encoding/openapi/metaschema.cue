@@ -0,0 +1,127 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file holds the parts of the OpenAPI 3.1 meta-schema
+// (https://spec.openapis.org/oas/) that apply to the documents produced
+// by this package. It is used when Config.Validate is set. The OpenAPI 3.0
+// meta-schema is generated in metaschema_v3_0.cue.
+
+#Extensions: [=~"^x-"]: _
+
+#Info: {
+	title!:          string
+	description?:    string
+	termsOfService?: string
+	contact?: {
+		name?:  string
+		url?:   string
+		email?: string
+		#Extensions
+	}
+	license?: {
+		name!:       string
+		url?:        string
+		identifier?: string
+		#Extensions
+	}
+	summary?: string
+	version!: string
+	#Extensions
+}
+
+#ExternalDocs: {
+	description?: string
+	url!:         string
+	#Extensions
+}
+
+#NonNegInt: int & >=0
+
+#SchemaName: =~"^[a-zA-Z0-9.\\-_]+$"
+
+#DocumentBase: {
+	info!: #Info
+	servers?: [...{...}]
+	paths?: {
+		[=~"^/"]: {...}
+		#Extensions
+	}
+	components?: {
+		schemas?: [#SchemaName]: _
+		responses?: {...}
+		parameters?: {...}
+		examples?: {...}
+		requestBodies?: {...}
+		headers?: {...}
+		securitySchemes?: {...}
+		links?: {...}
+		callbacks?: {...}
+		pathItems?: {...}
+		#Extensions
+	}
+	security?: [...{...}]
+	tags?: [...{
+		name!: string
+		...
+	}]
+	externalDocs?: #ExternalDocs
+	#Extensions
+}
+
+// v3_1 corresponds to https://spec.openapis.org/oas/3.1/schema/2022-10-07.
+// Schema objects are JSON Schema 2020-12 documents, which allow arbitrary
+// keywords, so only the types of the well-known keywords are checked.
+v3_1: {
+	#Document: {
+		#DocumentBase
+		openapi!: =~"^3\\.1\\.\\d+(-.+)?$"
+		components?: schemas?: [#SchemaName]: #Schema
+	}
+
+	#Schema: bool | {
+		$ref?:             string
+		title?:            string
+		multipleOf?:       number & >0
+		maximum?:          number
+		exclusiveMaximum?: number
+		minimum?:          number
+		exclusiveMinimum?: number
+		maxLength?:        #NonNegInt
+		minLength?:        #NonNegInt
+		pattern?:          string
+		maxItems?:         #NonNegInt
+		minItems?:         #NonNegInt
+		uniqueItems?:      bool
+		maxProperties?:    #NonNegInt
+		minProperties?:    #NonNegInt
+		required?: [...string]
+		enum?: [...]
+		type?: #Type | [...#Type]
+		not?: #Schema
+		allOf?: [#Schema, ...#Schema]
+		oneOf?: [#Schema, ...#Schema]
+		anyOf?: [#Schema, ...#Schema]
+		items?: #Schema
+		properties?: [string]: #Schema
+		additionalProperties?: #Schema
+		description?:          string
+		format?:               string
+		deprecated?:           bool
+		readOnly?:             bool
+		writeOnly?:            bool
+		...
+	}
+
+	#Type: "array" | "boolean" | "integer" | "null" | "number" | "object" | "string"
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/token"
+)
+
+//go:generate go run gen_metaschema.go
+
+var (
+	//go:embed metaschema.cue
+	metaSchemaData string
+
+	//go:embed metaschema_v3_0.cue
+	metaSchemaV3_0Data string
+)
+
+// validate checks the generated document doc against the meta-schema
+// for c.Version. Errors are mapped back to the CUE values in inst from
+// which the offending schemas were generated, using the sources
+// recorded during generation.
+func (c *Config) validate(inst, doc cue.Value, sources map[string]cue.Path) error {
+	var sel, data, file string
+	switch {
+	case strings.HasPrefix(c.Version, "3.0."):
+		sel, data, file = "v3_0", metaSchemaV3_0Data, "metaschema_v3_0.cue"
+	case strings.HasPrefix(c.Version, "3.1."):
+		sel, data, file = "v3_1", metaSchemaData, "metaschema.cue"
+	default:
+		return errors.Newf(token.NoPos, "openapi: no meta-schema for version %s", c.Version)
+	}
+	meta := doc.Context().CompileString(data, cue.Filename("cuelang.org/go/encoding/openapi/"+file))
+	schemaPath := cue.MakePath(cue.Str(sel), cue.Def("Document"))
+	schema := meta.LookupPath(schemaPath)
+	// Only check the selected schema: some definitions of the generated
+	// OpenAPI 3.0 meta-schema, such as those using oneOf, only evaluate
+	// correctly when applied to a concrete value.
+	if err := schema.Err(); err != nil {
+		return err
+	}
+
+	err := schema.Unify(doc).Validate(cue.Concrete(true))
+	if err == nil {
+		return nil
+	}
+	var errs errors.Error
+	for _, e := range errors.Errors(err) {
+		// Errors are reported relative to the meta-schema definition.
+		docPath := e.Path()
+		if n := len(schemaPath.Selectors()); len(docPath) >= n {
+			docPath = docPath[n:]
+		}
+		path, pos := sourceOf(inst, docPath, sources)
+		format, args := e.Msg()
+		errs = errors.Append(errs, &openapiError{
+			Message: errors.NewMessagef("openapi: invalid document at %s: %s",
+				strings.Join(docPath, "."), fmt.Sprintf(format, args...)),
+			path: path,
+			pos:  pos,
+		})
+	}
+	return errs
+}
+
+// sourceOf maps a path within the generated document to the path and
+// position of the CUE value it was generated from. Only paths within
+// components.schemas can be mapped; the zero path is returned otherwise.
+func sourceOf(inst cue.Value, docPath []string, sources map[string]cue.Path) (cue.Path, token.Pos) {
+	if len(docPath) < 3 || docPath[0] != "components" || docPath[1] != "schemas" {
+		return cue.Path{}, token.NoPos
+	}
+	base, ok := sources[unquote(docPath[2])]
+	if !ok {
+		return cue.Path{}, token.NoPos
+	}
+	sels := base.Selectors()
+	pos := inst.LookupPath(base).Pos()
+	rest := docPath[3:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case "properties":
+			if len(rest) < 2 {
+				return cue.MakePath(sels...), pos
+			}
+			sels = append(sels, cue.Str(unquote(rest[1])))
+			rest = rest[2:]
+		case "items":
+			sels = append(sels, cue.AnyIndex)
+			rest = rest[1:]
+		case "additionalProperties":
+			sels = append(sels, cue.AnyString)
+			rest = rest[1:]
+		default:
+			return cue.MakePath(sels...), pos
+		}
+		if v := inst.LookupPath(cue.MakePath(sels...)); v.Exists() {
+			pos = v.Pos()
+		}
+	}
+	return cue.MakePath(sels...), pos
+}
+
+// unquote returns the field name for a label as reported in an error path.
+func unquote(label string) string {
+	if s, err := strconv.Unquote(label); err == nil {
+		return s
+	}
+	return label
+}
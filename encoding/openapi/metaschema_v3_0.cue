@@ -0,0 +1,567 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by gen_metaschema.go; DO NOT EDIT.
+
+import (
+	"list"
+	"struct"
+	"net"
+)
+
+v3_0: {
+	#Document: {
+		close({
+			@jsonschema(id="https://spec.openapis.org/oas/3.0/schema/WORK-IN-PROGRESS")
+			openapi!:      =~"^3\\.0\\.\\d(-.+)?$"
+			info!:         #Info
+			externalDocs?: #ExternalDocumentation
+			servers?: [...#Server]
+			security?: [...#SecurityRequirement]
+			tags?: list.UniqueItems() & [...#Tag]
+			paths!:      #Paths
+			components?: #Components
+
+			{[=~"^x-" & !~"^(openapi|info|externalDocs|servers|security|tags|paths|components)$"]: _}
+		})
+	}
+
+	#APIKeySecurityScheme: close({
+		type!:        "apiKey"
+		name!:        string
+		in!:          "header" | "query" | "cookie"
+		description?: string
+
+		{[=~"^x-" & !~"^(type|name|in|description)$"]: _}
+	})
+
+	#AuthorizationCodeOAuthFlow: close({
+		authorizationUrl!: string
+		tokenUrl!:         string
+		refreshUrl?:       string
+		scopes!: close({
+			[string]: string
+		})
+
+		{[=~"^x-" & !~"^(authorizationUrl|tokenUrl|refreshUrl|scopes)$"]: _}
+	})
+
+	#Callback: close({
+		{[=~"^x-"]: _}
+		{[!~"^x-" & !~"^()$"]: #PathItem}
+	})
+
+	#ClientCredentialsFlow: close({
+		tokenUrl!:   string
+		refreshUrl?: string
+		scopes!: close({
+			[string]: string
+		})
+
+		{[=~"^x-" & !~"^(tokenUrl|refreshUrl|scopes)$"]: _}
+	})
+
+	#Components: close({
+		schemas?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Schema, #Reference])}
+			...
+		}
+		responses?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Response])}
+			...
+		}
+		parameters?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Parameter])}
+			...
+		}
+		examples?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Example])}
+			...
+		}
+		requestBodies?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #RequestBody])}
+			...
+		}
+		headers?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Header])}
+			...
+		}
+		securitySchemes?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #SecurityScheme])}
+			...
+		}
+		links?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Link])}
+			...
+		}
+		callbacks?: {
+			{[=~"^[a-zA-Z0-9\\.\\-_]+$"]: matchN(1, [#Reference, #Callback])}
+			...
+		}
+
+		{[=~"^x-" & !~"^(schemas|responses|parameters|examples|requestBodies|headers|securitySchemes|links|callbacks)$"]: _}
+	})
+
+	#Contact: close({
+		name?:  string
+		url?:   string
+		email?: string
+
+		{[=~"^x-" & !~"^(name|url|email)$"]: _}
+	})
+
+	// Parameter in cookie
+	#CookieParameter: null | bool | number | string | [...] | {
+		in?:    "cookie"
+		style?: "form" | *"form"
+		...
+	}
+
+	#Discriminator: {
+		propertyName!: string
+		mapping?: close({
+			[string]: string
+		})
+		...
+	}
+
+	#Encoding: close({
+		contentType?: string
+		headers?: close({
+			[string]: matchN(1, [#Header, #Reference])
+		})
+		style?:         "form" | "spaceDelimited" | "pipeDelimited" | "deepObject"
+		explode?:       bool
+		allowReserved?: bool | *false
+
+		{[=~"^x-" & !~"^(contentType|headers|style|explode|allowReserved)$"]: _}
+	})
+
+	#Example: close({
+		summary?:       string
+		description?:   string
+		value?:         _
+		externalValue?: string
+
+		{[=~"^x-" & !~"^(summary|description|value|externalValue)$"]: _}
+	})
+
+	// Example and examples are mutually exclusive
+	#ExampleXORExamples: matchN(0, [null | bool | number | string | [...] | {
+		example!:  _
+		examples!: _
+		...
+	}])
+
+	#ExternalDocumentation: close({
+		description?: string
+		url!:         string
+
+		{[=~"^x-" & !~"^(description|url)$"]: _}
+	})
+
+	#HTTPSecurityScheme: matchN(1, [{
+		scheme?: =~"^[Bb][Ee][Aa][Rr][Ee][Rr]$"
+		...
+	}, matchN(0, [null | bool | number | string | [...] | {
+		bearerFormat!: _
+		...
+	}]) & {
+		scheme?: matchN(0, [=~"^[Bb][Ee][Aa][Rr][Ee][Rr]$"])
+		...
+	}]) & close({
+		scheme!:       string
+		bearerFormat?: string
+		description?:  string
+		type!:         "http"
+
+		{[=~"^x-" & !~"^(scheme|bearerFormat|description|type)$"]: _}
+	})
+
+	#Header: matchN(2, [#ExampleXORExamples & {
+		...
+	}, #SchemaXORContent & {
+		...
+	}]) & close({
+		description?:     string
+		required?:        bool | *false
+		deprecated?:      bool | *false
+		allowEmptyValue?: bool | *false
+		style?:           "simple" | *"simple"
+		explode?:         bool
+		allowReserved?:   bool | *false
+		schema?: matchN(1, [#Schema, #Reference])
+		content?: close({
+			[string]: #MediaType
+		}) & struct.MaxFields(
+			1) & struct.MinFields(
+			1)
+		example?: _
+		examples?: close({
+			[string]: matchN(1, [#Example, #Reference])
+		})
+
+		{[=~"^x-" & !~"^(description|required|deprecated|allowEmptyValue|style|explode|allowReserved|schema|content|example|examples)$"]: _}
+	})
+
+	// Parameter in header
+	#HeaderParameter: null | bool | number | string | [...] | {
+		in?:    "header"
+		style?: "simple" | *"simple"
+		...
+	}
+
+	#ImplicitOAuthFlow: close({
+		authorizationUrl!: string
+		refreshUrl?:       string
+		scopes!: close({
+			[string]: string
+		})
+
+		{[=~"^x-" & !~"^(authorizationUrl|refreshUrl|scopes)$"]: _}
+	})
+
+	#Info: close({
+		title!:          string
+		description?:    string
+		termsOfService?: string
+		contact?:        #Contact
+		license?:        #License
+		version!:        string
+
+		{[=~"^x-" & !~"^(title|description|termsOfService|contact|license|version)$"]: _}
+	})
+
+	#License: close({
+		name!: string
+		url?:  string
+
+		{[=~"^x-" & !~"^(name|url)$"]: _}
+	})
+
+	#Link: matchN(0, [null | bool | number | string | [...] | {
+		operationId!:  _
+		operationRef!: _
+		...
+	}]) & close({
+		operationId?:  string
+		operationRef?: string
+		parameters?: close({
+			...
+		})
+		requestBody?: _
+		description?: string
+		server?:      #Server
+
+		{[=~"^x-" & !~"^(operationId|operationRef|parameters|requestBody|description|server)$"]: _}
+	})
+
+	#MediaType: #ExampleXORExamples & {
+		...
+	} & close({
+		schema?: matchN(1, [#Schema, #Reference])
+		example?: _
+		examples?: close({
+			[string]: matchN(1, [#Example, #Reference])
+		})
+		encoding?: close({
+			[string]: #Encoding
+		})
+
+		{[=~"^x-" & !~"^(schema|example|examples|encoding)$"]: _}
+	})
+
+	#OAuth2SecurityScheme: close({
+		type!:        "oauth2"
+		flows!:       #OAuthFlows
+		description?: string
+
+		{[=~"^x-" & !~"^(type|flows|description)$"]: _}
+	})
+
+	#OAuthFlows: close({
+		implicit?:          #ImplicitOAuthFlow
+		password?:          #PasswordOAuthFlow
+		clientCredentials?: #ClientCredentialsFlow
+		authorizationCode?: #AuthorizationCodeOAuthFlow
+
+		{[=~"^x-" & !~"^(implicit|password|clientCredentials|authorizationCode)$"]: _}
+	})
+
+	#OpenIdConnectSecurityScheme: close({
+		type!:             "openIdConnect"
+		openIdConnectUrl!: string
+		description?:      string
+
+		{[=~"^x-" & !~"^(type|openIdConnectUrl|description)$"]: _}
+	})
+
+	#Operation: close({
+		tags?: [...string]
+		summary?:      string
+		description?:  string
+		externalDocs?: #ExternalDocumentation
+		operationId?:  string
+		parameters?: list.UniqueItems() & [...matchN(1, [#Parameter, #Reference])]
+		requestBody?: matchN(1, [#RequestBody, #Reference])
+		responses!: #Responses
+		callbacks?: close({
+			[string]: matchN(1, [#Callback, #Reference])
+		})
+		deprecated?: bool | *false
+		security?: [...#SecurityRequirement]
+		servers?: [...#Server]
+
+		{[=~"^x-" & !~"^(tags|summary|description|externalDocs|operationId|parameters|requestBody|responses|callbacks|deprecated|security|servers)$"]: _}
+	})
+
+	#Parameter: matchN(2, [#ExampleXORExamples & {
+		...
+	}, #SchemaXORContent & {
+		...
+	}]) & matchN(1, [#PathParameter & {
+		...
+	}, #QueryParameter & {
+		...
+	}, #HeaderParameter & {
+		...
+	}, #CookieParameter & {
+		...
+	}]) & close({
+		name!:            string
+		in!:              string
+		description?:     string
+		required?:        bool | *false
+		deprecated?:      bool | *false
+		allowEmptyValue?: bool | *false
+		style?:           string
+		explode?:         bool
+		allowReserved?:   bool | *false
+		schema?: matchN(1, [#Schema, #Reference])
+		content?: close({
+			[string]: #MediaType
+		}) & struct.MaxFields(
+			1) & struct.MinFields(
+			1)
+		example?: _
+		examples?: close({
+			[string]: matchN(1, [#Example, #Reference])
+		})
+
+		{[=~"^x-" & !~"^(name|in|description|required|deprecated|allowEmptyValue|style|explode|allowReserved|schema|content|example|examples)$"]: _}
+	})
+
+	#PasswordOAuthFlow: close({
+		tokenUrl!:   string
+		refreshUrl?: string
+		scopes!: close({
+			[string]: string
+		})
+
+		{[=~"^x-" & !~"^(tokenUrl|refreshUrl|scopes)$"]: _}
+	})
+
+	#PathItem: close({
+		$ref?:        string
+		summary?:     string
+		description?: string
+		get?:         #Operation
+		put?:         #Operation
+		post?:        #Operation
+		delete?:      #Operation
+		options?:     #Operation
+		head?:        #Operation
+		patch?:       #Operation
+		trace?:       #Operation
+		servers?: [...#Server]
+		parameters?: list.UniqueItems() & [...matchN(1, [#Parameter, #Reference])]
+
+		{[=~"^x-" & !~"^(\\$ref|summary|description|get|put|post|delete|options|head|patch|trace|servers|parameters)$"]: _}
+	})
+
+	// Parameter in path
+	#PathParameter: null | bool | number | string | [...] | {
+		in?:       "path"
+		style?:    "matrix" | "label" | "simple" | *"simple"
+		required!: true
+		...
+	}
+
+	#Paths: close({
+		{[=~"^\\/"]: #PathItem}
+
+		{[=~"^x-"]: _}
+	})
+
+	// Parameter in query
+	#QueryParameter: null | bool | number | string | [...] | {
+		in?:    "query"
+		style?: "form" | "spaceDelimited" | "pipeDelimited" | "deepObject" | *"form"
+		...
+	}
+
+	#Reference: {
+		$ref!: _
+
+		{[=~"^\\$ref$" & !~"^(\\$ref)$"]: string}
+		...
+	}
+
+	#RequestBody: close({
+		description?: string
+		content!: close({
+			[string]: #MediaType
+		})
+		required?: bool | *false
+
+		{[=~"^x-" & !~"^(description|content|required)$"]: _}
+	})
+
+	#Response: close({
+		description!: string
+		headers?: close({
+			[string]: matchN(1, [#Header, #Reference])
+		})
+		content?: close({
+			[string]: #MediaType
+		})
+		links?: close({
+			[string]: matchN(1, [#Link, #Reference])
+		})
+
+		{[=~"^x-" & !~"^(description|headers|content|links)$"]: _}
+	})
+
+	#Responses: close({
+		default?: matchN(1, [#Response, #Reference])
+
+		{[=~"^[1-5](?:\\d{2}|XX)$" & !~"^(default)$"]: matchN(1, [#Response, #Reference])}
+
+		{[=~"^x-" & !~"^(default)$"]: _}
+	}) & struct.MinFields(
+		1)
+
+	#Schema: close({
+		title?:            string
+		multipleOf?:       >0
+		maximum?:          number
+		exclusiveMaximum?: bool | *false
+		minimum?:          number
+		exclusiveMinimum?: bool | *false
+		maxLength?:        int & >=0
+		minLength?:        int & >=0 | *0
+		pattern?:          string
+		maxItems?:         int & >=0
+		minItems?:         int & >=0 | *0
+		uniqueItems?:      bool | *false
+		maxProperties?:    int & >=0
+		minProperties?:    int & >=0 | *0
+		required?: list.UniqueItems() & [...string] & [_, ...]
+		enum?: [...] & [_, ...]
+		type?: "array" | "boolean" | "integer" | "number" | "object" | "string"
+		not?: matchN(1, [#Schema, #Reference])
+		allOf?: [...matchN(1, [#Schema, #Reference])]
+		oneOf?: [...matchN(1, [#Schema, #Reference])]
+		anyOf?: [...matchN(1, [#Schema, #Reference])]
+		items?: matchN(1, [#Schema, #Reference])
+		properties?: close({
+			[string]: matchN(1, [#Schema, #Reference])
+		})
+		additionalProperties?: matchN(1, [#Schema, #Reference, bool]) | *true
+		description?:   string
+		format?:        string
+		default?:       _
+		nullable?:      bool | *false
+		discriminator?: #Discriminator
+		readOnly?:      bool | *false
+		writeOnly?:     bool | *false
+		example?:       _
+		externalDocs?:  #ExternalDocumentation
+		deprecated?:    bool | *false
+		xml?:           #XML
+
+		{[=~"^x-" & !~"^(title|multipleOf|maximum|exclusiveMaximum|minimum|exclusiveMinimum|maxLength|minLength|pattern|maxItems|minItems|uniqueItems|maxProperties|minProperties|required|enum|type|not|allOf|oneOf|anyOf|items|properties|additionalProperties|description|format|default|nullable|discriminator|readOnly|writeOnly|example|externalDocs|deprecated|xml)$"]: _}
+	})
+
+	// Schema and content are mutually exclusive, at least one is
+	// required
+	#SchemaXORContent: matchN(0, [null | bool | number | string | [...] | {
+		schema!:  _
+		content!: _
+		...
+	}]) & matchN(1, [null | bool | number | string | [...] | {
+		schema!: _
+		...
+	}, matchN(5, [matchN(0, [null | bool | number | string | [...] | {
+		style!: _
+		...
+	}]), matchN(0, [null | bool | number | string | [...] | {
+		explode!: _
+		...
+	}]), matchN(0, [null | bool | number | string | [...] | {
+		allowReserved!: _
+		...
+	}]), matchN(0, [null | bool | number | string | [...] | {
+		example!: _
+		...
+	}]), matchN(0, [null | bool | number | string | [...] | {
+		examples!: _
+		...
+	}])]) & (null | bool | number | string | [...] | {
+		content!: _
+		...
+	})])
+
+	#SecurityRequirement: close({
+		[string]: [...string]
+	})
+
+	#SecurityScheme: matchN(1, [#APIKeySecurityScheme, #HTTPSecurityScheme, #OAuth2SecurityScheme, #OpenIdConnectSecurityScheme])
+
+	#Server: close({
+		url!:         string
+		description?: string
+		variables?: close({
+			[string]: #ServerVariable
+		})
+
+		{[=~"^x-" & !~"^(url|description|variables)$"]: _}
+	})
+
+	#ServerVariable: close({
+		enum?: [...string]
+		default!:     string
+		description?: string
+
+		{[=~"^x-" & !~"^(enum|default|description)$"]: _}
+	})
+
+	#Tag: close({
+		name!:         string
+		description?:  string
+		externalDocs?: #ExternalDocumentation
+
+		{[=~"^x-" & !~"^(name|description|externalDocs)$"]: _}
+	})
+
+	#XML: close({
+		name?:      string
+		namespace?: net.AbsURL
+		prefix?:    string
+		attribute?: bool | *false
+		wrapped?:   bool | *false
+
+		{[=~"^x-" & !~"^(name|namespace|prefix|attribute|wrapped)$"]: _}
+	})
+}
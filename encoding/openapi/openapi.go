@@ -46,12 +46,43 @@ type Config struct {
 	// reference.
 	NameFunc func(val cue.Value, path cue.Path) string
 
+	// DefinitionFunc is used by Extract to determine the path of the CUE
+	// definition generated for the schema with the given name in
+	// #/components/schemas. It thus performs the inverse mapping of NameFunc.
+	// References to the schema are updated accordingly.
+	//
+	// If DefinitionFunc is nil or returns an empty path, a schema named foo
+	// maps to #foo if foo is a valid identifier and to #SchemaMap."foo"
+	// otherwise.
+	DefinitionFunc func(name string) cue.Path
+
 	// DescriptionFunc allows rewriting a description associated with a certain
 	// field. A typical implementation compiles the description from the
 	// comments obtains from the Doc method. No description field is added if
 	// the empty string is returned.
 	DescriptionFunc func(v cue.Value) string
 
+	// ExtensionFunc allows specifying the vendor extensions for the schema
+	// generated for a value. It returns a map from keys, which must start
+	// with "x-", to values that marshal to JSON.
+	//
+	// If ExtensionFunc is nil, extensions are taken from the arguments of
+	// the form x-key=value of @openapi attributes of a field, where value
+	// is a JSON value or, if it is not valid JSON, a string. For instance,
+	//
+	//	name: string @openapi(x-go-name=Name, x-order=1)
+	//
+	// adds "x-go-name": "Name" and "x-order": 1 to the schema of name.
+	// Extract preserves vendor extensions in the same form if
+	// PreserveExtensions is set.
+	ExtensionFunc func(v cue.Value) map[string]any
+
+	// PreserveExtensions causes Extract to preserve the vendor extensions
+	// of properties and named schemas, the keywords starting with "x-", as
+	// arguments of @openapi attributes of the generated fields. Otherwise,
+	// vendor extensions are ignored.
+	PreserveExtensions bool
+
 	// SelfContained causes all non-expanded external references to be included
 	// in this document.
 	SelfContained bool
@@ -81,6 +112,12 @@ type Config struct {
 	// true, as that specification explicitly prohibits unknown keywords
 	// other than "x-" prefixed keywords.
 	StrictKeywords bool
+
+	// Validate checks the generated document against the OpenAPI
+	// meta-schema for the selected Version. Any violation is reported as
+	// an error positioned at the CUE value from which the offending
+	// schema was generated.
+	Validate bool
 }
 
 type Generator = Config
@@ -91,11 +128,11 @@ func Gen(inst cue.InstanceOrValue, c *Config) ([]byte, error) {
 	if c == nil {
 		c = defaultConfig
 	}
-	all, err := schemas(c, inst)
+	all, paths, sources, err := schemas(c, inst)
 	if err != nil {
 		return nil, err
 	}
-	top, err := c.compose(inst, all)
+	top, err := c.compose(inst, all, paths)
 	if err != nil {
 		return nil, err
 	}
@@ -103,25 +140,40 @@ func Gen(inst cue.InstanceOrValue, c *Config) ([]byte, error) {
 	if err := topValue.Err(); err != nil {
 		return nil, err
 	}
+	if c.Validate {
+		if err := c.validate(inst.Value(), topValue, sources); err != nil {
+			return nil, err
+		}
+	}
 	return internaljson.Marshal(topValue)
 }
 
 // Generate generates the set of OpenAPI schema for all top-level types of the
-// given instance.
+// given instance, along with the document sections, such as paths, defined
+// by its regular top-level fields.
 //
 // Note: only a limited number of top-level types are supported so far.
 func Generate(inst cue.InstanceOrValue, c *Config) (*ast.File, error) {
 	if c == nil {
 		c = defaultConfig
 	}
-	all, err := schemas(c, inst)
+	all, paths, sources, err := schemas(c, inst)
 	if err != nil {
 		return nil, err
 	}
-	top, err := c.compose(inst, all)
+	top, err := c.compose(inst, all, paths)
 	if err != nil {
 		return nil, err
 	}
+	if c.Validate {
+		topValue := inst.Value().Context().BuildExpr(top)
+		if err := topValue.Err(); err != nil {
+			return nil, err
+		}
+		if err := c.validate(inst.Value(), topValue, sources); err != nil {
+			return nil, err
+		}
+	}
 	return &ast.File{Decls: top.Elts}, nil
 }
 
@@ -138,22 +190,40 @@ func toCUE(name string, x interface{}) (v ast.Expr, err error) {
 
 }
 
-func (c *Config) compose(inst cue.InstanceOrValue, schemas *ast.StructLit) (x *ast.StructLit, err error) {
+// topLevelLabel returns the name of the section of the OpenAPI document
+// defined by the top-level field at i. It may be overridden by the first
+// argument of an @openapi attribute.
+func topLevelLabel(i *cue.Iterator) string {
+	label := i.Selector().Unquoted()
+	attr := i.Value().Attribute("openapi")
+	if s, _ := attr.String(0); s != "" {
+		label = s
+	}
+	return label
+}
+
+func (c *Config) compose(inst cue.InstanceOrValue, schemas, paths *ast.StructLit) (x *ast.StructLit, err error) {
 	val := inst.Value()
 	var errs errors.Error
 
 	var title, version string
 	var info *ast.StructLit
+	sections := map[string]ast.Expr{}
 
 	for i, _ := val.Fields(); i.Next(); {
-		label := i.Selector().Unquoted()
-		attr := i.Value().Attribute("openapi")
-		if s, _ := attr.String(0); s != "" {
-			label = s
-		}
+		label := topLevelLabel(i)
 		switch label {
 		case "$version":
 		case "-":
+		case "paths":
+			// Generated together with the schemas.
+		case "servers", "security", "tags", "externalDocs":
+			v := i.Value()
+			if err := v.Validate(cue.Concrete(true)); err != nil {
+				errs = errors.Append(errs, errors.Promote(err, ""))
+				continue
+			}
+			sections[label] = v.Syntax(cue.Final(), cue.Concrete(true)).(ast.Expr)
 		case "info":
 			info, _ = i.Value().Syntax().(*ast.StructLit)
 			if info == nil {
@@ -212,12 +282,22 @@ func (c *Config) compose(inst cue.InstanceOrValue, schemas *ast.StructLit) (x *a
 		}
 	}
 
-	return ast.NewStruct(
-		"openapi", ast.NewString(c.Version),
-		"info", info,
-		"paths", ast.NewStruct(),
-		"components", ast.NewStruct("schemas", schemas),
-	), errs
+	// Sections are emitted in the order of the OpenAPI specification.
+	doc := &orderedMap{}
+	add := func(name string, x ast.Expr) {
+		if x != nil {
+			doc.setExpr(name, x)
+		}
+	}
+	add("openapi", ast.NewString(c.Version))
+	add("info", info)
+	add("servers", sections["servers"])
+	add("paths", paths)
+	add("components", ast.NewStruct("schemas", schemas))
+	add("security", sections["security"])
+	add("tags", sections["tags"])
+	add("externalDocs", sections["externalDocs"])
+	return (*ast.StructLit)(doc), errs
 }
 
 var defaultConfig = &Config{}
@@ -27,6 +27,7 @@ import (
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
 	"cuelang.org/go/cue/load"
 	"cuelang.org/go/encoding/openapi"
 	"cuelang.org/go/internal/cuetest"
@@ -45,6 +46,10 @@ func TestParseDefinitions(t *testing.T) {
 		variant string
 		config  *openapi.Config
 		err     string
+
+		// metaErr is the expected error when validating the result
+		// against the OpenAPI meta-schema.
+		metaErr string
 	}{{
 		in:     "structural.cue",
 		out:    "structural.json",
@@ -176,6 +181,30 @@ func TestParseDefinitions(t *testing.T) {
 		in:     "omitvalue.cue",
 		out:    "omitvalue.json",
 		config: defaultConfig,
+	}, {
+		in:     "extensions.cue",
+		out:    "extensions.json",
+		config: defaultConfig,
+	}, {
+		in:     "keywords.cue",
+		out:    "keywords.json",
+		config: defaultConfig,
+	}, {
+		in:  "extensions.cue",
+		out: "extensions-funcs.json",
+		config: &openapi.Config{
+			Info: info,
+			ExtensionFunc: func(v cue.Value) map[string]any {
+				if _, ok := v.Label(); !ok {
+					return nil
+				}
+				return map[string]any{"x-path": v.Path().String()}
+			},
+		},
+	}, {
+		in:     "paths.cue",
+		out:    "paths.json",
+		config: defaultConfig,
 	}}
 	for _, tc := range testCases {
 		t.Run(tc.out+tc.variant, func(t *testing.T) {
@@ -195,6 +224,17 @@ func TestParseDefinitions(t *testing.T) {
 					if err != nil {
 						t.Fatal(err)
 					}
+					validate := *tc.config
+					validate.Validate = true
+					_, err = openapi.Gen(inst, &validate)
+					switch {
+					case tc.metaErr == "" && err != nil:
+						t.Fatal("invalid document:", errors.Details(err, nil))
+					case tc.metaErr != "" && err == nil:
+						t.Fatal("unexpected valid document:", tc.metaErr)
+					case tc.metaErr != "" && !strings.Contains(errors.Details(err, nil), tc.metaErr):
+						t.Fatalf("unexpected error %v; want %q", errors.Details(err, nil), tc.metaErr)
+					}
 				}
 
 				var out = &bytes.Buffer{}
@@ -245,6 +285,54 @@ func TestIssue1234(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	// OpenAPI 3.0 does not allow tuple-style items.
+	val := cuecontext.New().CompileString(`
+#Foo: [1, 2, 3]
+`)
+	if err := val.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &openapi.Config{}
+	_, err := openapi.Gen(val, config)
+	if err != nil {
+		t.Fatal(errors.Details(err, nil))
+	}
+
+	config.Validate = true
+	_, err = openapi.Gen(val, config)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	errs := errors.Errors(err)
+	if len(errs) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(errs), errors.Details(err, nil))
+	}
+	if got, want := strings.Join(errs[0].Path(), "."), "#Foo"; got != want {
+		t.Errorf("got path %q; want %q", got, want)
+	}
+	if !errs[0].Position().IsValid() {
+		t.Errorf("missing position in error: %v", errors.Details(err, nil))
+	}
+}
+
+// The OpenAPI 3.1 meta-schema is maintained by hand; check that it stays
+// formatted.
+func TestMetaSchemaFormatted(t *testing.T) {
+	b, err := os.ReadFile("metaschema.cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := format.Source(b, format.Simplify())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, b) {
+		t.Error("metaschema.cue is not formatted; run cue fmt")
+	}
+}
+
 // This is for debugging purposes. Do not remove.
 func TestX(t *testing.T) {
 	t.Skip()
@@ -0,0 +1,14 @@
+// Vendor extensions.
+
+$version: "v1"
+
+#User: {
+	id:    int    @openapi(x-order=1)
+	name?: string @openapi(x-go-name=Name, x-nullable=false, x-label="1")
+	tags?: [...string] @openapi(x-tags=["a", "b,c"])
+	address?: #Address @openapi(x-go-name=Addr)
+} @openapi(x-go-type=User)
+
+#Address: {
+	street?: string
+}
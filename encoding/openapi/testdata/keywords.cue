@@ -0,0 +1,8 @@
+// Keywords preserved in @jsonschema attributes.
+
+$version: "v1"
+
+#Pet: {
+	kind: string
+	age?: int @jsonschema(example=3)
+} @jsonschema(discriminator={"propertyName":"kind"}, x-go-type=Pet)
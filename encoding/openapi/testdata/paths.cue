@@ -0,0 +1,49 @@
+// A pet store.
+package petstore
+
+info: {
+	title:   "Pet Store"
+	version: "v1"
+}
+
+servers: [{url: "https://pets.example.com/v1"}]
+
+tags: [{name: "pets"}]
+
+paths: "/pets/{id}": {
+	parameters: [{
+		name:     "id"
+		in:       "path"
+		required: true
+		schema:   #ID
+	}]
+	get: {
+		operationId: "getPet"
+		tags: ["pets"]
+		responses: {
+			"200": {
+				description: "The requested pet."
+				content: "application/json": schema: #Pet
+			}
+			"404": description: "No such pet."
+		}
+	}
+	put: {
+		operationId: "updatePet"
+		parameters: [{
+			name:   "dryRun"
+			in:     "query"
+			schema: bool
+		}]
+		requestBody: content: "application/json": schema: #Pet
+		responses: "204": description: "The pet was updated."
+	}
+}
+
+#ID: int & >0
+
+#Pet: {
+	id:   #ID
+	name: string
+	tag?: string
+}
@@ -319,6 +319,9 @@ func (p *protoConverter) doImport(v *proto.Import) error {
 	}
 
 	if !p.mapBuiltinPackage(v.Position, v.Filename, filename == "") {
+		for name, m := range p.state.wellKnown {
+			p.scope[0][name] = m
+		}
 		return nil
 	}
 
@@ -491,7 +494,7 @@ func (p *protoConverter) messageField(s *ast.StructLit, i int, v proto.Visitee)
 		s.Elts = append(s.Elts, comment(x, true))
 
 	case *proto.NormalField:
-		f := p.parseField(s, i, x.Field)
+		f := p.parseField(s, i, x.Field, x)
 
 		if x.Repeated {
 			f.Value = &ast.ListLit{
@@ -701,7 +704,30 @@ func (p *protoConverter) enum(x *proto.Enum) {
 // optional, we define oneOfs as all required fields, but add one more
 // disjunction allowing no fields. This makes it easier to constrain the
 // result to include at least one of the values.
+//
+// With the optional oneof mode, the fields are added to the message as
+// optional fields instead.
 func (p *protoConverter) oneOf(x *proto.Oneof) {
+	if p.state.oneofMode == "optional" {
+		s := p.current
+		doc := comment(x.Comment, true)
+		for i, v := range x.Elements {
+			switch x := v.(type) {
+			case *proto.OneOfField:
+				f := p.parseField(s, i, x.Field, nil)
+				f.Optional = token.NoSpace.Pos()
+				if doc != nil {
+					// Document the oneof with its first field.
+					f.SetComments(append([]*ast.CommentGroup{doc}, f.Comments()...))
+					doc = nil
+				}
+			default:
+				p.messageField(s, i, v)
+			}
+		}
+		return
+	}
+
 	s := ast.NewStruct()
 	ast.SetRelPos(s, token.Newline)
 	embed := &ast.EmbedDecl{Expr: s}
@@ -720,7 +746,7 @@ func (p *protoConverter) oneOf(x *proto.Oneof) {
 		switch x := v.(type) {
 		case *proto.OneOfField:
 			newStruct()
-			oneOf := p.parseField(s, 0, x.Field)
+			oneOf := p.parseField(s, 0, x.Field, nil)
 			oneOf.Optional = token.NoPos
 
 		case *proto.Comment:
@@ -736,7 +762,9 @@ func (p *protoConverter) oneOf(x *proto.Oneof) {
 	}
 }
 
-func (p *protoConverter) parseField(s *ast.StructLit, i int, x *proto.Field) *ast.Field {
+// parseField converts a field to CUE. The normal field nf, if any, holds
+// the label of the field.
+func (p *protoConverter) parseField(s *ast.StructLit, i int, x *proto.Field, nf *proto.NormalField) *ast.Field {
 	defer func(saved []string) { p.path = saved }(p.path)
 	p.path = append(p.path, x.Name)
 
@@ -756,6 +784,10 @@ func (p *protoConverter) parseField(s *ast.StructLit, i int, x *proto.Field) *as
 	if x.Name != name.Name {
 		o.tags += ",name=" + x.Name
 	}
+	if nf != nil && nf.Optional && p.proto3 {
+		// The field has explicit presence.
+		o.tags += ",optional"
+	}
 	o.parse(x.Options)
 	p.addTag(f, o.tags)
 
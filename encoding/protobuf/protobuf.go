@@ -64,6 +64,11 @@
 //	Timestamp      time.Time        See struct.proto.
 //	Duration       time.Duration    See struct.proto.
 //
+// The mappings of well-known types can be changed with
+// [Config.WellKnownTypes]. Fields are optional in CUE unless they are marked
+// as required. Fields of proto3 that are marked optional, and thus have
+// explicit presence, have an optional flag in their @protobuf attribute.
+//
 // # Annotations
 //
 // Protobuf definitions can be annotated with CUE constraints that are included
@@ -147,6 +152,31 @@ type Config struct {
 	//            disjunction of the enum to interpret strings.
 	//
 	EnumMode string
+
+	// OneofMode defines how the fields of a oneof are converted.
+	//
+	//    disjunction  the message embeds a disjunction of structs that each
+	//                 define one of the fields, and an empty struct
+	//                 allowing none of them (default).
+	//
+	//    optional     the fields become optional fields of the message.
+	//                 That at most one of them may be set is not enforced.
+	//
+	OneofMode string
+
+	// WellKnownTypes replaces the mapping of well-known types, such as
+	// google.protobuf.Timestamp, by the given CUE expressions. Keys are
+	// fully qualified type names in the google.protobuf package. The
+	// expressions may refer to one of the builtin packages time and
+	// struct. For instance:
+	//
+	//	map[string]string{
+	//		"google.protobuf.Timestamp": "string",
+	//		"google.protobuf.Duration":  `=~"^-?[0-9]+(\\.[0-9]+)?s$"`,
+	//		"google.protobuf.Struct":    "{...}",
+	//	}
+	//
+	WellKnownTypes map[string]string
 }
 
 // An Extractor converts a collection of proto files, typically belonging to one
@@ -159,12 +189,14 @@ type Config struct {
 // All other imported files are assigned to the CUE pkg dir ($Root/pkg)
 // according to their Go package import path.
 type Extractor struct {
-	root     string
-	cwd      string
-	module   string
-	paths    []string
-	pkgName  string
-	enumMode string
+	root      string
+	cwd       string
+	module    string
+	paths     []string
+	pkgName   string
+	enumMode  string
+	oneofMode string
+	wellKnown map[string]mapping
 
 	fileCache map[string]result
 	imports   map[string]*build.Instance
@@ -204,10 +236,33 @@ func NewExtractor(c *Config) *Extractor {
 		pkgName:   c.PkgName,
 		module:    modulePath,
 		enumMode:  c.EnumMode,
+		oneofMode: c.OneofMode,
+		wellKnown: map[string]mapping{},
 		fileCache: map[string]result{},
 		imports:   map[string]*build.Instance{},
 	}
 
+	switch c.OneofMode {
+	case "", "disjunction", "optional":
+	default:
+		b.errs = errors.Append(b.errs,
+			errors.Newf(token.NoPos, "unknown oneof mode %q", c.OneofMode))
+	}
+	for name, src := range c.WellKnownTypes {
+		if !strings.HasPrefix(name, "google.protobuf.") {
+			b.errs = errors.Append(b.errs,
+				errors.Newf(token.NoPos, "%s is not a well-known type", name))
+			continue
+		}
+		m, err := wellKnownMapping(src)
+		if err != nil {
+			b.errs = errors.Append(b.errs,
+				errors.Newf(token.NoPos, "invalid mapping for %s: %v", name, err))
+			continue
+		}
+		b.wellKnown[name] = m
+	}
+
 	if b.root == "" {
 		b.root = b.cwd
 	}
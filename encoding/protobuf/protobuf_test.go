@@ -73,6 +73,48 @@ func TestExtractDefinitions(t *testing.T) {
 	}
 }
 
+func TestExtractOptions(t *testing.T) {
+	c := &Config{
+		Paths:     []string{"testdata"},
+		OneofMode: "optional",
+		WellKnownTypes: map[string]string{
+			"google.protobuf.Timestamp": "string",
+			"google.protobuf.Duration":  `time.Duration & =~"s$"`,
+			"google.protobuf.Struct":    "{[string]: string}",
+		},
+	}
+	out := &bytes.Buffer{}
+	if f, err := Extract("testdata/options/options.proto", nil, c); err != nil {
+		fmt.Fprintln(out, err)
+	} else {
+		b, _ := format.Node(f, format.Simplify())
+		out.Write(b)
+	}
+
+	wantFile := filepath.Join("testdata", "options.proto.out.cue")
+	if cuetest.UpdateGoldenFiles {
+		_ = os.WriteFile(wantFile, out.Bytes(), 0666)
+		return
+	}
+	b, err := os.ReadFile(wantFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(out.String(), string(b)); diff != "" {
+		t.Error(diff)
+	}
+
+	for _, c := range []*Config{
+		{OneofMode: "none"},
+		{WellKnownTypes: map[string]string{"acme.Time": "string"}},
+		{WellKnownTypes: map[string]string{"google.protobuf.Struct": "{"}},
+	} {
+		if err := NewExtractor(c).Err(); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+}
+
 func TestBuild(t *testing.T) {
 	cwd, _ := os.Getwd()
 	root := filepath.Join(cwd, "testdata/istio.io/api")
@@ -0,0 +1,17 @@
+package options
+
+import "time"
+
+#Job: {
+	// name has no presence.
+	name?: string @protobuf(1,string)
+
+	// priority has explicit presence.
+	priority?: int32                  @protobuf(2,int32,optional)
+	start?:    string                 @protobuf(3,google.protobuf.Timestamp)
+	timeout?:  time.Duration & =~"s$" @protobuf(4,google.protobuf.Duration)
+	labels?: {[string]: string} @protobuf(5,google.protobuf.Struct)
+	// target selects where the job runs.
+	host?: string @protobuf(6,string)
+	pool?: string @protobuf(7,string)
+}
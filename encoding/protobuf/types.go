@@ -77,6 +77,58 @@ func (p *protoConverter) setBuiltinParse(from, to string, pkg *protoConverter) {
 	p.scope[0][from] = mapping{f, pkg}
 }
 
+// wellKnownMapping returns a mapping to the CUE expression src, for use in
+// place of the default mapping of a well-known type. The expression may
+// refer to one of the builtin packages time and struct.
+func wellKnownMapping(src string) (mapping, error) {
+	parse := func() (ast.Expr, *protoConverter, error) {
+		expr, err := parser.ParseExpr("", src)
+		if err != nil {
+			return nil, nil, err
+		}
+		var pkg *protoConverter
+		ast.Walk(expr, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			x, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			switch x.Name {
+			case "time":
+				x.Node = importTime
+				if pkg == nil || pkg == pkgTime {
+					pkg = pkgTime
+				} else {
+					err = fmt.Errorf("%q refers to more than one package", src)
+				}
+			case "struct":
+				x.Node = importStruct
+				if pkg == nil || pkg == pkgStruct {
+					pkg = pkgStruct
+				} else {
+					err = fmt.Errorf("%q refers to more than one package", src)
+				}
+			}
+			return true
+		}, nil)
+		return expr, pkg, err
+	}
+	_, pkg, err := parse()
+	if err != nil {
+		return mapping{}, err
+	}
+	return mapping{
+		cue: func() ast.Expr {
+			expr, _, _ := parse()
+			return expr
+		},
+		pkg: pkg,
+	}, nil
+}
+
 var (
 	pkgTime      = &protoConverter{cuePkgPath: "time"}
 	pkgStruct    = &protoConverter{cuePkgPath: "struct"}
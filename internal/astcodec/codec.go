@@ -0,0 +1,925 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package astcodec implements a compact binary encoding of CUE syntax
+// trees, including their positions and comments, that is considerably
+// cheaper to decode than parsing the corresponding source.
+//
+// The encoding is only meant to be read by the same version of this
+// package that wrote it; it is used for caches that are keyed by the
+// version of CUE.
+package astcodec
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/token"
+)
+
+// Version identifies the encoding. It must be changed whenever the
+// encoding or the syntax tree produced by the parser changes.
+const Version = 1
+
+// Node kinds. kindNil and kindRef are not nodes: the former encodes a nil
+// node and the latter a node that was encoded before.
+const (
+	kindNil = iota
+	kindRef
+	kindAlias
+	kindAttribute
+	kindBadDecl
+	kindBadExpr
+	kindBasicLit
+	kindBinaryExpr
+	kindBottomLit
+	kindCallExpr
+	kindComment
+	kindCommentGroup
+	kindComprehension
+	kindEllipsis
+	kindEmbedDecl
+	kindField
+	kindFile
+	kindForClause
+	kindFunc
+	kindIdent
+	kindIfClause
+	kindImportDecl
+	kindImportSpec
+	kindIndexExpr
+	kindInterpolation
+	kindLetClause
+	kindListLit
+	kindPackage
+	kindParenExpr
+	kindSelectorExpr
+	kindSliceExpr
+	kindStructLit
+	kindUnaryExpr
+)
+
+// Encode returns the encoding of f, which must be a file as returned by
+// the parser: all its positions must be in the same token.File, if any.
+func Encode(f *ast.File) (data []byte, err error) {
+	e := &encoder{
+		nodes:   map[ast.Node]int{},
+		strings: map[string]int{},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			ee, ok := r.(encodeError)
+			if !ok {
+				panic(r)
+			}
+			err = ee
+		}
+	}()
+	e.node(f)
+	e.resolved(f)
+
+	// The header holds the line table and the number of nodes, strings,
+	// and identifiers, so that the decoder can allocate them at once.
+	body := e.buf
+	e.buf = []byte{Version}
+	var lines []int
+	if e.file != nil {
+		lines = e.file.Lines()
+	}
+	e.uint(uint64(len(lines)))
+	prev := 0
+	for _, l := range lines {
+		e.uint(uint64(l - prev))
+		prev = l
+	}
+	e.uint(uint64(len(e.nodes)))
+	e.uint(uint64(len(e.strings)))
+	e.uint(uint64(len(e.idents)))
+	return append(e.buf, body...), nil
+}
+
+type encodeError struct{ msg string }
+
+func (e encodeError) Error() string { return e.msg }
+
+type encoder struct {
+	buf     []byte
+	file    *token.File
+	nodes   map[ast.Node]int
+	strings map[string]int
+	idents  []*ast.Ident
+}
+
+func (e *encoder) fail(format string, args ...any) {
+	panic(encodeError{fmt.Sprintf("astcodec: "+format, args...)})
+}
+
+func (e *encoder) uint(x uint64) {
+	e.buf = binary.AppendUvarint(e.buf, x)
+}
+
+func (e *encoder) int(x int) {
+	e.buf = binary.AppendVarint(e.buf, int64(x))
+}
+
+func (e *encoder) bool(b bool) {
+	if b {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) token(t token.Token) {
+	e.uint(uint64(t))
+}
+
+// string writes s as a reference to an earlier occurrence, or as 0
+// followed by its contents.
+func (e *encoder) string(s string) {
+	if i, ok := e.strings[s]; ok {
+		e.uint(uint64(i))
+		return
+	}
+	e.strings[s] = len(e.strings) + 1
+	e.uint(0)
+	e.uint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) pos(p token.Pos) {
+	if f := p.File(); f != nil {
+		if e.file == nil {
+			e.file = f
+		} else if f != e.file {
+			e.fail("positions in multiple files")
+		}
+		e.uint(uint64(f.Offset(p)+1)<<4 | uint64(p.RelPos()))
+		return
+	}
+	e.uint(uint64(p.RelPos()))
+}
+
+// ident writes x, which may be nil.
+func (e *encoder) ident(x *ast.Ident) {
+	if x == nil {
+		e.uint(kindNil)
+		return
+	}
+	e.node(x)
+}
+
+func (e *encoder) node(n ast.Node) {
+	if n == nil {
+		e.uint(kindNil)
+		return
+	}
+	if i, ok := e.nodes[n]; ok {
+		e.uint(kindRef)
+		e.uint(uint64(i))
+		return
+	}
+	e.nodes[n] = len(e.nodes)
+
+	switch x := n.(type) {
+	case *ast.Alias:
+		e.uint(kindAlias)
+		e.ident(x.Ident)
+		e.pos(x.Equal)
+		e.node(x.Expr)
+	case *ast.Attribute:
+		e.uint(kindAttribute)
+		e.pos(x.At)
+		e.string(x.Text)
+	case *ast.BadDecl:
+		e.uint(kindBadDecl)
+		e.pos(x.From)
+		e.pos(x.To)
+	case *ast.BadExpr:
+		e.uint(kindBadExpr)
+		e.pos(x.From)
+		e.pos(x.To)
+	case *ast.BasicLit:
+		e.uint(kindBasicLit)
+		e.pos(x.ValuePos)
+		e.token(x.Kind)
+		e.string(x.Value)
+	case *ast.BinaryExpr:
+		e.uint(kindBinaryExpr)
+		e.node(x.X)
+		e.pos(x.OpPos)
+		e.token(x.Op)
+		e.node(x.Y)
+	case *ast.BottomLit:
+		e.uint(kindBottomLit)
+		e.pos(x.Bottom)
+	case *ast.CallExpr:
+		e.uint(kindCallExpr)
+		e.node(x.Fun)
+		e.pos(x.Lparen)
+		e.exprs(x.Args)
+		e.pos(x.Rparen)
+	case *ast.Comment:
+		e.uint(kindComment)
+		e.pos(x.Slash)
+		e.string(x.Text)
+	case *ast.CommentGroup:
+		e.uint(kindCommentGroup)
+		e.bool(x.Doc)
+		e.bool(x.Line)
+		e.int(int(x.Position))
+		e.uint(uint64(len(x.List)))
+		for _, c := range x.List {
+			e.node(c)
+		}
+	case *ast.Comprehension:
+		e.uint(kindComprehension)
+		e.uint(uint64(len(x.Clauses)))
+		for _, c := range x.Clauses {
+			e.node(c)
+		}
+		e.node(x.Value)
+	case *ast.Ellipsis:
+		e.uint(kindEllipsis)
+		e.pos(x.Ellipsis)
+		e.node(x.Type)
+	case *ast.EmbedDecl:
+		e.uint(kindEmbedDecl)
+		e.node(x.Expr)
+	case *ast.Field:
+		e.uint(kindField)
+		e.node(x.Label)
+		e.pos(x.Optional)
+		e.token(x.Constraint)
+		e.pos(x.TokenPos)
+		e.token(x.Token)
+		e.node(x.Value)
+		e.uint(uint64(len(x.Attrs)))
+		for _, a := range x.Attrs {
+			e.node(a)
+		}
+	case *ast.File:
+		e.uint(kindFile)
+		e.decls(x.Decls)
+		e.uint(uint64(len(x.Imports)))
+		for _, s := range x.Imports {
+			e.node(s)
+		}
+	case *ast.ForClause:
+		e.uint(kindForClause)
+		e.pos(x.For)
+		e.ident(x.Key)
+		e.pos(x.Colon)
+		e.ident(x.Value)
+		e.pos(x.In)
+		e.node(x.Source)
+	case *ast.Func:
+		e.uint(kindFunc)
+		e.pos(x.Func)
+		e.exprs(x.Args)
+		e.node(x.Ret)
+	case *ast.Ident:
+		e.uint(kindIdent)
+		e.pos(x.NamePos)
+		e.string(x.Name)
+		e.idents = append(e.idents, x)
+	case *ast.IfClause:
+		e.uint(kindIfClause)
+		e.pos(x.If)
+		e.node(x.Condition)
+	case *ast.ImportDecl:
+		e.uint(kindImportDecl)
+		e.pos(x.Import)
+		e.pos(x.Lparen)
+		e.uint(uint64(len(x.Specs)))
+		for _, s := range x.Specs {
+			e.node(s)
+		}
+		e.pos(x.Rparen)
+	case *ast.ImportSpec:
+		e.uint(kindImportSpec)
+		e.ident(x.Name)
+		if x.Path == nil {
+			e.uint(kindNil)
+		} else {
+			e.node(x.Path)
+		}
+		e.pos(x.EndPos)
+	case *ast.IndexExpr:
+		e.uint(kindIndexExpr)
+		e.node(x.X)
+		e.pos(x.Lbrack)
+		e.node(x.Index)
+		e.pos(x.Rbrack)
+	case *ast.Interpolation:
+		e.uint(kindInterpolation)
+		e.exprs(x.Elts)
+	case *ast.LetClause:
+		e.uint(kindLetClause)
+		e.pos(x.Let)
+		e.ident(x.Ident)
+		e.pos(x.Equal)
+		e.node(x.Expr)
+	case *ast.ListLit:
+		e.uint(kindListLit)
+		e.pos(x.Lbrack)
+		e.exprs(x.Elts)
+		e.pos(x.Rbrack)
+	case *ast.Package:
+		e.uint(kindPackage)
+		e.pos(x.PackagePos)
+		e.ident(x.Name)
+	case *ast.ParenExpr:
+		e.uint(kindParenExpr)
+		e.pos(x.Lparen)
+		e.node(x.X)
+		e.pos(x.Rparen)
+	case *ast.SelectorExpr:
+		e.uint(kindSelectorExpr)
+		e.node(x.X)
+		e.node(x.Sel)
+	case *ast.SliceExpr:
+		e.uint(kindSliceExpr)
+		e.node(x.X)
+		e.pos(x.Lbrack)
+		e.node(x.Low)
+		e.node(x.High)
+		e.pos(x.Rbrack)
+	case *ast.StructLit:
+		e.uint(kindStructLit)
+		e.pos(x.Lbrace)
+		e.decls(x.Elts)
+		e.pos(x.Rbrace)
+	case *ast.UnaryExpr:
+		e.uint(kindUnaryExpr)
+		e.pos(x.OpPos)
+		e.token(x.Op)
+		e.node(x.X)
+	default:
+		e.fail("unsupported node type %T", n)
+	}
+
+	switch n.(type) {
+	case *ast.Comment, *ast.CommentGroup:
+		// These cannot have comments.
+	default:
+		cgs := ast.Comments(n)
+		e.uint(uint64(len(cgs)))
+		for _, cg := range cgs {
+			e.node(cg)
+		}
+	}
+}
+
+// resolved writes the scopes and nodes to which the identifiers of f
+// resolve, and its unresolved identifiers, so that these need not be
+// computed again when decoding. Identifiers are written in the order in
+// which they were encoded and refer to nodes by their index.
+func (e *encoder) resolved(f *ast.File) {
+	ref := func(n ast.Node) {
+		if n == nil {
+			e.uint(0)
+			return
+		}
+		i, ok := e.nodes[n]
+		if !ok {
+			e.fail("identifier resolves to node outside file")
+		}
+		e.uint(uint64(i) + 1)
+	}
+	for _, x := range e.idents {
+		ref(x.Scope)
+		ref(x.Node)
+	}
+	e.uint(uint64(len(f.Unresolved)))
+	for _, x := range f.Unresolved {
+		ref(x)
+	}
+}
+
+func (e *encoder) exprs(a []ast.Expr) {
+	e.bool(a == nil)
+	e.uint(uint64(len(a)))
+	for _, x := range a {
+		e.node(x)
+	}
+}
+
+func (e *encoder) decls(a []ast.Decl) {
+	e.bool(a == nil)
+	e.uint(uint64(len(a)))
+	for _, x := range a {
+		e.node(x)
+	}
+}
+
+// Decode decodes a file encoded by Encode. The positions of the returned
+// file refer to a new token.File named filename for src, the source from
+// which the encoded file was parsed. As with the parser, identifiers in
+// the file are resolved.
+func Decode(filename string, src, data []byte) (f *ast.File, err error) {
+	if len(data) == 0 || data[0] != Version {
+		return nil, fmt.Errorf("astcodec: unsupported encoding")
+	}
+	d := &decoder{
+		buf:  data[1:],
+		file: token.NewFile(filename, -1, len(src)),
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			de, ok := r.(decodeError)
+			if !ok {
+				panic(r)
+			}
+			f, err = nil, de
+		}
+	}()
+
+	lines := make([]int, d.len())
+	prev := 0
+	for i := range lines {
+		prev += int(d.uint())
+		lines[i] = prev
+	}
+	if len(lines) > 0 && !d.file.SetLines(lines) {
+		d.fail()
+	}
+	if n := len(src); n > 0 && src[n-1] == '\n' {
+		// Record that a line starts at the end of the file, as the
+		// scanner does.
+		d.file.AddLine(n)
+	}
+	d.nodes = make([]ast.Node, 0, d.len())
+	d.strings = make([]string, 0, d.len())
+	d.idents = make([]*ast.Ident, 0, d.len())
+	f, ok := d.node().(*ast.File)
+	if !ok {
+		d.fail()
+	}
+	d.resolved(f)
+	if len(d.buf) != 0 {
+		d.fail()
+	}
+	f.Filename = filename
+	return f, nil
+}
+
+type decodeError struct{}
+
+func (decodeError) Error() string { return "astcodec: invalid encoding" }
+
+type decoder struct {
+	buf     []byte
+	file    *token.File
+	nodes   []ast.Node
+	strings []string
+	idents  []*ast.Ident
+}
+
+func (d *decoder) fail() {
+	panic(decodeError{})
+}
+
+func (d *decoder) uint() uint64 {
+	x, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail()
+	}
+	d.buf = d.buf[n:]
+	return x
+}
+
+func (d *decoder) len() int {
+	n := d.uint()
+	if n > uint64(len(d.buf)) {
+		// Every element takes at least one byte.
+		d.fail()
+	}
+	return int(n)
+}
+
+func (d *decoder) int() int {
+	x, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail()
+	}
+	d.buf = d.buf[n:]
+	return int(x)
+}
+
+func (d *decoder) bool() bool {
+	return d.uint() != 0
+}
+
+func (d *decoder) token() token.Token {
+	return token.Token(d.uint())
+}
+
+func (d *decoder) string() string {
+	i := d.uint()
+	if i > 0 {
+		if i > uint64(len(d.strings)) {
+			d.fail()
+		}
+		return d.strings[i-1]
+	}
+	n := d.len()
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	d.strings = append(d.strings, s)
+	return s
+}
+
+func (d *decoder) pos() token.Pos {
+	x := d.uint()
+	rel := token.RelPos(x & 0xf)
+	offset := x >> 4
+	if offset == 0 {
+		return rel.Pos()
+	}
+	if offset-1 > uint64(d.file.Size()) {
+		d.fail()
+	}
+	return d.file.Pos(int(offset-1), rel)
+}
+
+func (d *decoder) resolved(f *ast.File) {
+	ref := func() ast.Node {
+		i := d.uint()
+		if i == 0 {
+			return nil
+		}
+		if i > uint64(len(d.nodes)) {
+			d.fail()
+		}
+		return d.nodes[i-1]
+	}
+	for _, x := range d.idents {
+		x.Scope = ref()
+		x.Node = ref()
+	}
+	if n := d.len(); n > 0 {
+		f.Unresolved = make([]*ast.Ident, n)
+		for i := range f.Unresolved {
+			x, ok := ref().(*ast.Ident)
+			if !ok {
+				d.fail()
+			}
+			f.Unresolved[i] = x
+		}
+	}
+}
+
+func (d *decoder) ident() *ast.Ident {
+	n := d.node()
+	if n == nil {
+		return nil
+	}
+	x, ok := n.(*ast.Ident)
+	if !ok {
+		d.fail()
+	}
+	return x
+}
+
+func (d *decoder) expr() ast.Expr {
+	n := d.node()
+	if n == nil {
+		return nil
+	}
+	x, ok := n.(ast.Expr)
+	if !ok {
+		d.fail()
+	}
+	return x
+}
+
+func (d *decoder) label() ast.Label {
+	n := d.node()
+	if n == nil {
+		return nil
+	}
+	x, ok := n.(ast.Label)
+	if !ok {
+		d.fail()
+	}
+	return x
+}
+
+func (d *decoder) decl() ast.Decl {
+	x, ok := d.node().(ast.Decl)
+	if !ok {
+		d.fail()
+	}
+	return x
+}
+
+func (d *decoder) exprs() []ast.Expr {
+	isNil := d.bool()
+	n := d.len()
+	if isNil {
+		return nil
+	}
+	a := make([]ast.Expr, n)
+	for i := range a {
+		a[i] = d.expr()
+	}
+	return a
+}
+
+func (d *decoder) decls() []ast.Decl {
+	isNil := d.bool()
+	n := d.len()
+	if isNil {
+		return nil
+	}
+	a := make([]ast.Decl, n)
+	for i := range a {
+		a[i] = d.decl()
+	}
+	return a
+}
+
+func (d *decoder) node() ast.Node {
+	kind := d.uint()
+	switch kind {
+	case kindNil:
+		return nil
+	case kindRef:
+		i := d.uint()
+		if i >= uint64(len(d.nodes)) {
+			d.fail()
+		}
+		return d.nodes[i]
+	}
+
+	// Nodes are registered before their children are decoded, in the
+	// same order as they were encoded.
+	var n ast.Node
+	register := func(x ast.Node) {
+		n = x
+		d.nodes = append(d.nodes, x)
+	}
+
+	switch kind {
+	case kindAlias:
+		x := &ast.Alias{}
+		register(x)
+		x.Ident = d.ident()
+		x.Equal = d.pos()
+		x.Expr = d.expr()
+	case kindAttribute:
+		x := &ast.Attribute{}
+		register(x)
+		x.At = d.pos()
+		x.Text = d.string()
+	case kindBadDecl:
+		x := &ast.BadDecl{}
+		register(x)
+		x.From = d.pos()
+		x.To = d.pos()
+	case kindBadExpr:
+		x := &ast.BadExpr{}
+		register(x)
+		x.From = d.pos()
+		x.To = d.pos()
+	case kindBasicLit:
+		x := &ast.BasicLit{}
+		register(x)
+		x.ValuePos = d.pos()
+		x.Kind = d.token()
+		x.Value = d.string()
+	case kindBinaryExpr:
+		x := &ast.BinaryExpr{}
+		register(x)
+		x.X = d.expr()
+		x.OpPos = d.pos()
+		x.Op = d.token()
+		x.Y = d.expr()
+	case kindBottomLit:
+		x := &ast.BottomLit{}
+		register(x)
+		x.Bottom = d.pos()
+	case kindCallExpr:
+		x := &ast.CallExpr{}
+		register(x)
+		x.Fun = d.expr()
+		x.Lparen = d.pos()
+		x.Args = d.exprs()
+		x.Rparen = d.pos()
+	case kindComment:
+		x := &ast.Comment{}
+		register(x)
+		x.Slash = d.pos()
+		x.Text = d.string()
+		return x
+	case kindCommentGroup:
+		x := &ast.CommentGroup{}
+		register(x)
+		x.Doc = d.bool()
+		x.Line = d.bool()
+		x.Position = int8(d.int())
+		x.List = make([]*ast.Comment, d.len())
+		for i := range x.List {
+			c, ok := d.node().(*ast.Comment)
+			if !ok {
+				d.fail()
+			}
+			x.List[i] = c
+		}
+		return x
+	case kindComprehension:
+		x := &ast.Comprehension{}
+		register(x)
+		x.Clauses = make([]ast.Clause, d.len())
+		for i := range x.Clauses {
+			c, ok := d.node().(ast.Clause)
+			if !ok {
+				d.fail()
+			}
+			x.Clauses[i] = c
+		}
+		x.Value = d.expr()
+	case kindEllipsis:
+		x := &ast.Ellipsis{}
+		register(x)
+		x.Ellipsis = d.pos()
+		x.Type = d.expr()
+	case kindEmbedDecl:
+		x := &ast.EmbedDecl{}
+		register(x)
+		x.Expr = d.expr()
+	case kindField:
+		x := &ast.Field{}
+		register(x)
+		x.Label = d.label()
+		x.Optional = d.pos()
+		x.Constraint = d.token()
+		x.TokenPos = d.pos()
+		x.Token = d.token()
+		x.Value = d.expr()
+		if n := d.len(); n > 0 {
+			x.Attrs = make([]*ast.Attribute, n)
+			for i := range x.Attrs {
+				a, ok := d.node().(*ast.Attribute)
+				if !ok {
+					d.fail()
+				}
+				x.Attrs[i] = a
+			}
+		}
+	case kindFile:
+		x := &ast.File{}
+		register(x)
+		x.Decls = d.decls()
+		if n := d.len(); n > 0 {
+			x.Imports = make([]*ast.ImportSpec, n)
+			for i := range x.Imports {
+				s, ok := d.node().(*ast.ImportSpec)
+				if !ok {
+					d.fail()
+				}
+				x.Imports[i] = s
+			}
+		}
+	case kindForClause:
+		x := &ast.ForClause{}
+		register(x)
+		x.For = d.pos()
+		x.Key = d.ident()
+		x.Colon = d.pos()
+		x.Value = d.ident()
+		x.In = d.pos()
+		x.Source = d.expr()
+	case kindFunc:
+		x := &ast.Func{}
+		register(x)
+		x.Func = d.pos()
+		x.Args = d.exprs()
+		x.Ret = d.expr()
+	case kindIdent:
+		x := &ast.Ident{}
+		register(x)
+		x.NamePos = d.pos()
+		x.Name = d.string()
+		d.idents = append(d.idents, x)
+	case kindIfClause:
+		x := &ast.IfClause{}
+		register(x)
+		x.If = d.pos()
+		x.Condition = d.expr()
+	case kindImportDecl:
+		x := &ast.ImportDecl{}
+		register(x)
+		x.Import = d.pos()
+		x.Lparen = d.pos()
+		x.Specs = make([]*ast.ImportSpec, d.len())
+		for i := range x.Specs {
+			s, ok := d.node().(*ast.ImportSpec)
+			if !ok {
+				d.fail()
+			}
+			x.Specs[i] = s
+		}
+		x.Rparen = d.pos()
+	case kindImportSpec:
+		x := &ast.ImportSpec{}
+		register(x)
+		x.Name = d.ident()
+		if p := d.node(); p != nil {
+			lit, ok := p.(*ast.BasicLit)
+			if !ok {
+				d.fail()
+			}
+			x.Path = lit
+		}
+		x.EndPos = d.pos()
+	case kindIndexExpr:
+		x := &ast.IndexExpr{}
+		register(x)
+		x.X = d.expr()
+		x.Lbrack = d.pos()
+		x.Index = d.expr()
+		x.Rbrack = d.pos()
+	case kindInterpolation:
+		x := &ast.Interpolation{}
+		register(x)
+		x.Elts = d.exprs()
+	case kindLetClause:
+		x := &ast.LetClause{}
+		register(x)
+		x.Let = d.pos()
+		x.Ident = d.ident()
+		x.Equal = d.pos()
+		x.Expr = d.expr()
+	case kindListLit:
+		x := &ast.ListLit{}
+		register(x)
+		x.Lbrack = d.pos()
+		x.Elts = d.exprs()
+		x.Rbrack = d.pos()
+	case kindPackage:
+		x := &ast.Package{}
+		register(x)
+		x.PackagePos = d.pos()
+		x.Name = d.ident()
+	case kindParenExpr:
+		x := &ast.ParenExpr{}
+		register(x)
+		x.Lparen = d.pos()
+		x.X = d.expr()
+		x.Rparen = d.pos()
+	case kindSelectorExpr:
+		x := &ast.SelectorExpr{}
+		register(x)
+		x.X = d.expr()
+		x.Sel = d.label()
+	case kindSliceExpr:
+		x := &ast.SliceExpr{}
+		register(x)
+		x.X = d.expr()
+		x.Lbrack = d.pos()
+		x.Low = d.expr()
+		x.High = d.expr()
+		x.Rbrack = d.pos()
+	case kindStructLit:
+		x := &ast.StructLit{}
+		register(x)
+		x.Lbrace = d.pos()
+		x.Elts = d.decls()
+		x.Rbrace = d.pos()
+	case kindUnaryExpr:
+		x := &ast.UnaryExpr{}
+		register(x)
+		x.OpPos = d.pos()
+		x.Op = d.token()
+		x.X = d.expr()
+	default:
+		d.fail()
+	}
+
+	if k := d.len(); k > 0 {
+		cgs := make([]*ast.CommentGroup, k)
+		for i := range cgs {
+			cg, ok := d.node().(*ast.CommentGroup)
+			if !ok {
+				d.fail()
+			}
+			cgs[i] = cg
+		}
+		ast.SetComments(n, cgs)
+	}
+	return n
+}
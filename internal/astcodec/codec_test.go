@@ -0,0 +1,148 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package astcodec_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/txtar"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/internal/astcodec"
+	"cuelang.org/go/internal/astinternal"
+)
+
+func TestRoundTrip(t *testing.T) {
+	inputs := map[string][]byte{
+		"empty.cue":   nil,
+		"newline.cue": []byte("\n"),
+		"all.cue": []byte(`// Package doc.
+
+@attr(file)
+
+package foo
+
+import (
+	"strings"
+	l "list"
+)
+
+#A: {
+	a?: int @go(A)
+	b!: string // line comment
+	c: *1 | 2
+	[=~"x"]: _
+	(d): e
+	"\(c)": 3
+	...
+}
+e: "d"
+X=f: [1, 2, ...int] & [...] & X
+g: {for k, v in #A if v != _|_ let x = v {"\(k)": x}}
+h: strings.Join(["a"], "") + l.Repeat([1], 2)[0:1][0]
+i: -1 & !=2 & >=3 & =~"x" & !~"y"
+j: 'bytes' + """
+	multi
+	line
+	"""
+k: [Y=string]: Y
+l: a.b
+m: (1 + 2) * 3
+n: 1.5e3 | 0x1f | 1Ki | null | true
+o: #"raw \#(e)"#
+`),
+	}
+	files, err := filepath.Glob("../../cue/format/testdata/*.txtar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		a, err := txtar.ParseFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range a.Files {
+			if strings.HasSuffix(f.Name, ".input") {
+				inputs[filepath.Base(file)+"/"+f.Name] = f.Data
+			}
+		}
+	}
+
+	for name, src := range inputs {
+		t.Run(name, func(t *testing.T) {
+			f, err := parser.ParseFile(name, src, parser.ParseComments)
+			if err != nil {
+				t.Skip("parse error")
+			}
+			data, err := astcodec.Encode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g, err := astcodec.Decode(name, src, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg := astinternal.DebugConfig{IncludeNodeRefs: true}
+			want := string(astinternal.AppendDebug(nil, f, cfg))
+			got := string(astinternal.AppendDebug(nil, g, cfg))
+			if got != want {
+				t.Errorf("decoded file differs:\ngot:\n%s\nwant:\n%s", got, want)
+			}
+			if got, want := lines(g), lines(f); !slices.Equal(got, want) {
+				t.Errorf("lines: got %v; want %v", got, want)
+			}
+			b1, err1 := format.Node(f)
+			b2, err2 := format.Node(g)
+			if string(b1) != string(b2) || (err1 == nil) != (err2 == nil) {
+				t.Errorf("formatted file differs:\ngot:\n%s\nwant:\n%s", b2, b1)
+			}
+		})
+	}
+}
+
+func lines(f *ast.File) []int {
+	for _, d := range f.Decls {
+		if tf := d.Pos().File(); tf != nil {
+			return tf.Lines()
+		}
+	}
+	return nil
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	src := []byte("a: b: [1, 2]\n")
+	f, err := parser.ParseFile("x.cue", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := astcodec.Encode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Truncated or corrupted encodings must be reported, not panic.
+	for i := range data {
+		if _, err := astcodec.Decode("x.cue", src, data[:i]); err == nil {
+			t.Errorf("Decode(data[:%d]): got no error", i)
+		}
+		corrupt := slices.Clone(data)
+		corrupt[i] ^= 0xff
+		_, _ = astcodec.Decode("x.cue", src, corrupt)
+	}
+}
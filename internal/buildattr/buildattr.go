@@ -4,6 +4,7 @@ package buildattr
 
 import (
 	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/cue/token"
@@ -30,18 +31,63 @@ func ShouldBuildFile(f *ast.File, tagIsSet func(key string) bool) (bool, *ast.At
 		return true, nil, nil
 	}
 
+	include, err := evalIf(a, tagIsSet)
+	if err != nil {
+		return false, a, err
+	}
+	return include, a, nil
+}
+
+// FilterDecls removes the fields from f, at any depth, that have an
+// @if attribute whose expression does not hold. It uses tagIsSet to
+// determine whether a given attribute key should be treated as set.
+//
+// File-level attributes are not considered; use [ShouldBuildFile] to
+// decide whether to include f as a whole.
+func FilterDecls(f *ast.File, tagIsSet func(key string) bool) (errs errors.Error) {
+	astutil.Apply(f, func(c astutil.Cursor) bool {
+		field, ok := c.Node().(*ast.Field)
+		if !ok {
+			return true
+		}
+		var a *ast.Attribute
+		for _, x := range field.Attrs {
+			if key, _ := x.Split(); key != "if" {
+				continue
+			}
+			if a != nil {
+				errs = errors.Append(errs, errors.Newf(x.Pos(), "multiple @if attributes"))
+				errs = errors.Append(errs, errors.Newf(a.Pos(), "previous declaration here"))
+				return false
+			}
+			a = x
+		}
+		if a == nil {
+			return true
+		}
+		include, err := evalIf(a, tagIsSet)
+		if err != nil {
+			errs = errors.Append(errs, err)
+			return false
+		}
+		if !include {
+			c.Delete()
+			return false
+		}
+		return true
+	}, nil)
+	return errs
+}
+
+// evalIf evaluates the expression of the @if attribute a.
+func evalIf(a *ast.Attribute, tagIsSet func(key string) bool) (bool, errors.Error) {
 	_, body := a.Split()
 
 	expr, parseErr := parser.ParseExpr("", body)
 	if parseErr != nil {
-		return false, a, errors.Promote(parseErr, "")
+		return false, errors.Promote(parseErr, "")
 	}
-
-	include, err := shouldInclude(expr, tagIsSet)
-	if err != nil {
-		return false, a, err
-	}
-	return include, a, nil
+	return shouldInclude(expr, tagIsSet)
 }
 
 func getBuildAttr(f *ast.File) (ignore bool, a *ast.Attribute, err errors.Error) {
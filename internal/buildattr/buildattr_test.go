@@ -359,3 +359,44 @@ func TestShouldBuildFile(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterDecls(t *testing.T) {
+	f, err := parser.ParseFile("testfile.cue", `
+@if(other)
+
+package p
+
+a: 1 @if(foo)
+b: 2 @if(!foo)
+c: {
+	d: 3 @if(foo && bar)
+	e: 4 @if(foo || bar)
+	f: 5
+}
+`)
+	qt.Assert(t, qt.IsNil(err))
+	tagsUsed := make(map[string]bool)
+	err = FilterDecls(f, func(tag string) bool {
+		tagsUsed[tag] = true
+		return tag == "foo"
+	})
+	qt.Assert(t, qt.IsNil(err))
+	b, err := format.Node(f)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(b), `@if(other)
+
+package p
+
+a: 1 @if(foo)
+c: {
+	e: 4 @if(foo || bar)
+	f: 5
+}
+`))
+	qt.Assert(t, qt.DeepEquals(tagsUsed, map[string]bool{"foo": true, "bar": true}))
+
+	f, err = parser.ParseFile("testfile.cue", `a: 1 @if(foo) @if(bar)`)
+	qt.Assert(t, qt.IsNil(err))
+	err = FilterDecls(f, func(string) bool { return true })
+	qt.Assert(t, qt.ErrorMatches(err, `multiple @if attributes.*`))
+}
@@ -26,6 +26,7 @@ import (
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/format"
 	"cuelang.org/go/cue/load"
@@ -45,8 +46,15 @@ usage of cue-ast:
 
   cue-ast join [flags] [inputs]
 
-    Join the input package instance as a single file.
-    Joining multiple package instances is not supported yet.
+    Join the input package instances as a single file.
+
+    When joining multiple instances, such as a package and its tests or
+    all packages in a directory, their declarations are unified as if
+    they belonged to the same package. Let declarations and aliases at
+    the top level are renamed if another file declares the same name,
+    and imports are renamed as needed to avoid collisions.
+    Top-level fields declared by more than one instance are reported
+    as conflicts, as their values are unified in the joint file.
 
 See 'cue help inputs' as well.
 `[1:])
@@ -84,26 +92,16 @@ See 'cue help inputs' as well.
 		// TODO: add a flag drop comments, which is useful when reducing bug reproducers.
 		flag.CommandLine.Parse(args)
 
-		var jointImports []*ast.ImportSpec
-		var jointFields []ast.Decl
 		insts := load.Instances(flag.Args(), &load.Config{})
-		if len(insts) != 1 {
-			log.Fatal("joining multiple instances is not possible yet")
+		for _, inst := range insts {
+			if err := inst.Err; err != nil {
+				log.Fatal(errors.Details(err, nil))
+			}
 		}
-		inst := insts[0]
-		if err := inst.Err; err != nil {
-			log.Fatal(errors.Details(err, nil))
+		joint, conflicts := join(insts)
+		for _, c := range conflicts {
+			fmt.Fprintln(os.Stderr, c)
 		}
-		for _, file := range inst.Files {
-			jointImports = slices.Concat(jointImports, file.Imports)
-
-			fields := file.Decls[len(file.Preamble()):]
-			jointFields = slices.Concat(jointFields, fields)
-		}
-		// TODO: we should sort and deduplicate imports.
-		joint := &ast.File{Decls: slices.Concat([]ast.Decl{
-			&ast.ImportDecl{Specs: jointImports},
-		}, jointFields)}
 
 		// Sanitize the resulting file so that, for example,
 		// multiple packages imported as the same name avoid collisions.
@@ -120,3 +118,106 @@ See 'cue help inputs' as well.
 		flag.Usage()
 	}
 }
+
+// join joins the files of insts into a single file. It returns the joint
+// file and a description of each conflict between the top-level
+// declarations of the files.
+func join(insts []*build.Instance) (joint *ast.File, conflicts []string) {
+	var jointImports []*ast.ImportSpec
+	var jointFields []ast.Decl
+
+	// names records the file that declared each let or alias name, as
+	// these are only visible within their file.
+	names := map[string]*ast.File{}
+	// fields records the instance that first declared each top-level field.
+	fields := map[string]*build.Instance{}
+	reported := map[string]bool{}
+	for _, inst := range insts {
+		for _, file := range inst.Files {
+			jointImports = slices.Concat(jointImports, file.Imports)
+
+			decls := file.Decls[len(file.Preamble()):]
+			for _, d := range decls {
+				for _, n := range topLevelNames(d) {
+					name := n.ident.Name
+					if other, ok := names[name]; ok && other != file {
+						newName := uniqueName(name, names)
+						rename(file, n, newName)
+						conflicts = append(conflicts, fmt.Sprintf(
+							"renamed %s in %s to %s, as it is also declared in %s",
+							name, file.Filename, newName, other.Filename))
+						name = newName
+					}
+					names[name] = file
+				}
+				f, ok := d.(*ast.Field)
+				if !ok {
+					continue
+				}
+				name, _, err := ast.LabelName(f.Label)
+				if err != nil {
+					continue
+				}
+				switch other, ok := fields[name]; {
+				case !ok:
+					fields[name] = inst
+				case other != inst && !reported[name]:
+					reported[name] = true
+					conflicts = append(conflicts, fmt.Sprintf(
+						"field %s is declared in both %s and %s; their values are unified",
+						name, other.DisplayPath, inst.DisplayPath))
+				}
+			}
+			jointFields = slices.Concat(jointFields, decls)
+		}
+	}
+	// TODO: we should sort and deduplicate imports.
+	joint = &ast.File{Decls: slices.Concat([]ast.Decl{
+		&ast.ImportDecl{Specs: jointImports},
+	}, jointFields)}
+	return joint, conflicts
+}
+
+// A declName is a name declared by a top-level declaration.
+type declName struct {
+	ident *ast.Ident
+	decl  ast.Node // the node that references to ident resolve to
+}
+
+// topLevelNames returns the names declared by d that are only visible
+// within its file: the names of let declarations and aliases.
+func topLevelNames(d ast.Decl) []declName {
+	switch d := d.(type) {
+	case *ast.LetClause:
+		return []declName{{d.Ident, d}}
+	case *ast.Alias:
+		return []declName{{d.Ident, d}}
+	case *ast.Field:
+		if a, ok := d.Label.(*ast.Alias); ok {
+			return []declName{{a.Ident, d}}
+		}
+	}
+	return nil
+}
+
+// uniqueName returns a variant of name that is not in names.
+func uniqueName(name string, names map[string]*ast.File) string {
+	for i := 1; ; i++ {
+		s := fmt.Sprintf("%s_%d", name, i)
+		if _, ok := names[s]; !ok {
+			return s
+		}
+	}
+}
+
+// rename renames the declared name n of file, along with all the
+// references to it.
+func rename(file *ast.File, n declName, name string) {
+	ast.Walk(file, func(x ast.Node) bool {
+		if x, ok := x.(*ast.Ident); ok && x.Node == n.decl && x.Name == n.ident.Name {
+			x.Name = name
+		}
+		return true
+	}, nil)
+	n.ident.Name = name
+}
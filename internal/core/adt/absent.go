@@ -0,0 +1,65 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+import "sync"
+
+// absentCacheSize is the maximum number of entries of an AbsentCache.
+const absentCacheSize = 4096
+
+// An AbsentCache memoizes lookups of features that are not present in
+// finalized vertices, as validators often probe for many optional fields.
+//
+// Entries are keyed by Vertex, so that copies of a Vertex, which may differ in
+// arcs or closedness, do not share them. The cache is cleared once it holds
+// absentCacheSize entries, so that it does not keep vertices alive
+// indefinitely.
+//
+// The zero value is an empty cache ready for use. An AbsentCache is safe for
+// concurrent use.
+type AbsentCache struct {
+	mu      sync.RWMutex
+	entries map[absentKey]*Bottom
+}
+
+type absentKey struct {
+	v *Vertex
+	f Feature
+}
+
+// Lookup returns the error recorded with [AbsentCache.Set] for looking up f
+// in v, or nil if no such lookup was recorded.
+func (c *AbsentCache) Lookup(v *Vertex, f Feature) *Bottom {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.entries[absentKey{v, f}]
+}
+
+// Set records that looking up f in v, which has no arc for f, results in
+// err. The result is only recorded if v is finalized, as arcs may still be
+// added otherwise.
+func (c *AbsentCache) Set(v *Vertex, f Feature, err *Bottom) {
+	if v.status != finalized {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil || len(c.entries) >= absentCacheSize {
+		c.entries = map[absentKey]*Bottom{}
+	}
+	c.entries[absentKey{v, f}] = err
+}
@@ -65,5 +65,5 @@ func verifyArc2(ctx *OpContext, f Feature, v *Vertex, isClosed bool) (found bool
 		s.AddPositions(ctx)
 	}
 
-	return false, ctx.NewErrf("field not allowed")
+	return false, ctx.newNotAllowedError(v)
 }
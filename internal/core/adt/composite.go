@@ -1305,6 +1305,7 @@ func (v *Vertex) GetArc(c *OpContext, f Feature, t ArcType) (arc *Vertex, isNew
 		anonymous: v.anonymous || v.Label.IsLet(),
 	}
 	v.Arcs = append(v.Arcs, arc)
+	c.allocated(vertexSize)
 	if t == ArcPending {
 		v.hasPendingArc = true
 	}
@@ -233,6 +233,7 @@ func (n *nodeContext) scheduleConjunct(c Conjunct, id CloseInfo) {
 	}
 
 	n.ctx.stats.Conjuncts++
+	n.ctx.allocated(conjunctSize)
 }
 
 // scheduleStruct records all elements of this conjunct in the structure and
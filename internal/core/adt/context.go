@@ -174,6 +174,8 @@ type OpContext struct {
 	cuedebug.Config
 	Version  internal.EvaluatorVersion // Copied from Runtime
 	TopoSort bool                      // Copied from Runtime
+	MaxDepth int                       // Copied from Runtime
+	Memory   *MemoryBudget             // Copied from Runtime
 
 	taskContext
 
@@ -215,6 +217,15 @@ type OpContext struct {
 	// detect structural cycles and their severity.s
 	evalDepth int
 
+	// unifyDepth is the number of nested calls to unify. Evaluation
+	// of a vertex fails once it reaches MaxDepth, if set, so that deeply
+	// nested inputs result in an error rather than exhausting the stack.
+	//
+	// TODO: evaluate vertices from an explicit work list rather than
+	// recursively, so that the nesting of values is no longer bounded by
+	// the Go stack and MaxDepth only guards against runaway evaluation.
+	unifyDepth int
+
 	// optionalMark indicates the evalDepth at which the last optional field,
 	// pattern constraint or other construct that may contain errors was
 	// encountered. A value of 0 indicates we are not within such field.
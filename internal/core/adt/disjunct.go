@@ -107,7 +107,78 @@ type envDisjunct struct {
 	childDefaultUsed  bool
 }
 
+// hasAtomDisjunction reports whether x consists of scalar values only and a
+// disjunction with the same disjuncts was already added to n.
+//
+// Unifying such a disjunction with itself does not change the result,
+// regardless of the environment and closedness information with which it was
+// added. Templates commonly apply the same disjunction to a node more than
+// once, for instance through a pattern constraint and a reference to the
+// same definition, or through distinct definitions that spell out the same
+// set of values. Skipping the duplicates avoids evaluating the cross product
+// of identical disjunctions only to eliminate the resulting duplicates again.
+func (n *nodeContext) hasAtomDisjunction(x *DisjunctionExpr) bool {
+	if len(n.disjunctions) == 0 || !isAtomDisjunction(x) {
+		return false
+	}
+	for _, d := range n.disjunctions {
+		switch {
+		case d.expr == x:
+			return true
+		case d.expr == nil, !n.ctx.isDevVersion():
+			// The old evaluator resolves structural cycles differently
+			// depending on the number of disjuncts, so it only skips
+			// repeated occurrences of the same expression.
+		case sameAtomDisjunction(n.ctx, d.expr, x):
+			return true
+		}
+	}
+	return false
+}
+
+// isAtomDisjunction reports whether all disjuncts of x are scalar values.
+// The result of unifying with such values does not depend on their
+// environment.
+func isAtomDisjunction(x *DisjunctionExpr) bool {
+	for _, d := range x.Values {
+		v, ok := d.Val.(Value)
+		if !ok || v.Kind()&^ScalarKinds != 0 {
+			return false
+		}
+		if _, ok := v.(*Vertex); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sameAtomDisjunction reports whether x has the same disjuncts, in the same
+// order and with the same defaults, as y, which must consist of scalar
+// values only.
+func sameAtomDisjunction(ctx *OpContext, x, y *DisjunctionExpr) bool {
+	if len(x.Values) != len(y.Values) {
+		return false
+	}
+	for i, a := range x.Values {
+		b := y.Values[i]
+		if a.Default != b.Default {
+			return false
+		}
+		v, ok := a.Val.(Value)
+		w := b.Val.(Value)
+		// Compare kinds first, as 1 and 1.0 are equal, but int and float
+		// values unify differently.
+		if !ok || v.Kind() != w.Kind() || !Equal(ctx, v, w, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 func (n *nodeContext) addDisjunction(env *Environment, x *DisjunctionExpr, cloneID CloseInfo) {
+	if n.hasAtomDisjunction(x) {
+		return
+	}
 
 	// TODO: precompute
 	numDefaults := 0
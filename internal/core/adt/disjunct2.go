@@ -213,6 +213,10 @@ type disjunctHole struct {
 }
 
 func (n *nodeContext) scheduleDisjunction(d envDisjunct) {
+	if d.expr != nil && n.hasAtomDisjunction(d.expr) {
+		return
+	}
+
 	if len(n.disjunctions) == 0 {
 		// This processes all disjunctions in a single pass.
 		n.scheduleTask(handleDisjunctions, nil, nil, CloseInfo{})
@@ -297,9 +297,16 @@ type ValueError struct {
 	v      *Vertex
 	pos    token.Pos
 	auxpos []token.Pos
+	code   errors.Code
 	errors.Message
 }
 
+// Code reports the code classifying the error, if it is more specific than
+// the ErrorCode of the Bottom value it is part of.
+func (v *ValueError) Code() errors.Code {
+	return v.code
+}
+
 func (v *ValueError) AddPosition(n Node) {
 	if n == nil {
 		return
@@ -161,6 +161,12 @@ func (c *OpContext) unify(v *Vertex, flags combinedFlags) {
 		return
 	}
 
+	if c.limitExceeded(v) {
+		return
+	}
+	c.unifyDepth++
+	defer func() { c.unifyDepth-- }()
+
 	// defer c.PopVertex(c.PushVertex(v))
 	if c.LogEval > 0 {
 		c.nest++
@@ -1286,6 +1292,7 @@ func (c *OpContext) newNodeContext(node *Vertex) *nodeContext {
 		return n
 	}
 	c.stats.Allocs++
+	c.allocated(nodeContextSize)
 
 	n := &nodeContext{
 		scheduler: scheduler{
@@ -1376,6 +1383,7 @@ func (n *nodeContext) reportConflict(
 			"conflicting values %s and %s (mismatched types %s and %s)",
 			v1, v2, k1, k2)
 	}
+	err.code = errors.Conflict
 
 	err.AddPosition(v1)
 	err.AddPosition(v2)
@@ -1446,9 +1454,11 @@ func (n *nodeContext) updateNodeType(k Kind, v Expr, id CloseInfo) bool {
 		n.reportConflict(n.kindExpr, v, n.kind, k, n.kindID, id)
 
 	default:
-		n.addErr(ctx.Newf(
+		err := ctx.Newf(
 			"conflicting value %s (mismatched types %s and %s)",
-			v, n.kind, k))
+			v, n.kind, k)
+		err.code = errors.Conflict
+		n.addErr(err)
 	}
 
 	if n.kind != kind || n.kindExpr == nil {
@@ -1676,6 +1686,7 @@ func (n *nodeContext) addExprConjunct(v Conjunct, state vertexStatus) {
 		n.evalExpr(v, state)
 	}
 	n.ctx.stats.Conjuncts++
+	n.ctx.allocated(conjunctSize)
 }
 
 // evalExpr is only called by addExprConjunct. If an error occurs, it records
@@ -661,6 +661,7 @@ func (x *BoundValue) validate(c *OpContext, y Value) *Bottom {
 		// TODO(errors): use "invalid value %v (not an %s)" if x is a
 		// predeclared identifier such as `int`.
 		err := c.Newf("invalid value %v (out of bound %s)", y, x)
+		err.code = errors.OutOfBound
 		err.AddPosition(y)
 		return &Bottom{
 			Src:  c.src,
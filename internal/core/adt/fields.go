@@ -360,6 +360,7 @@ func (n *nodeContext) getArc(f Feature, mode ArcType) (arc *Vertex, isNew bool)
 		arc.ArcType = ArcNotPresent
 	}
 	v.Arcs = append(v.Arcs, arc)
+	n.ctx.allocated(vertexSize)
 	return arc, true
 }
 
@@ -1099,7 +1100,7 @@ func (ctx *OpContext) notAllowedError(arc *Vertex) {
 
 	// TODO: setting arc instead of n.node eliminates subfields. This may be
 	// desirable or not, but it differs, at least from <=v0.6 behavior.
-	arc.SetValue(ctx, ctx.NewErrf("field not allowed"))
+	arc.SetValue(ctx, ctx.newNotAllowedError(arc))
 	if arc.state != nil {
 		arc.state.kind = 0
 	}
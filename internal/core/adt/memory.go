@@ -0,0 +1,90 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// This file contains logic for limiting the resources used by evaluation.
+
+// A MemoryBudget limits the memory allocated by evaluation.
+//
+// Rather than measuring heap usage, which is shared by the entire process,
+// it accounts for an estimate of the size of the data structures allocated
+// by the evaluator, such as vertices, node contexts, and conjuncts. Memory
+// is never returned to the budget, even if it is freed later on.
+//
+// A MemoryBudget may be shared by multiple OpContexts and is safe for
+// concurrent use.
+type MemoryBudget struct {
+	// Limit is the number of bytes that evaluation may allocate.
+	Limit int64
+
+	used atomic.Int64
+}
+
+// Used reports the number of bytes allocated so far.
+func (b *MemoryBudget) Used() int64 {
+	return b.used.Load()
+}
+
+func (b *MemoryBudget) exceeded() bool {
+	return b.used.Load() > b.Limit
+}
+
+// Estimated sizes of the data structures accounted for by a MemoryBudget.
+const (
+	vertexSize      = int64(unsafe.Sizeof(Vertex{}))
+	nodeContextSize = int64(unsafe.Sizeof(nodeContext{}))
+	conjunctSize    = int64(unsafe.Sizeof(Conjunct{}))
+)
+
+// allocated records that evaluation allocated size bytes.
+func (c *OpContext) allocated(size int64) {
+	if c.Memory != nil {
+		c.Memory.used.Add(size)
+	}
+}
+
+// limitExceeded reports whether unifying v would exceed the maximum
+// evaluation depth or the memory budget, in which case v is set to an
+// error. This prevents excessively nested or large values from exhausting
+// the Go stack or the memory of the process.
+func (c *OpContext) limitExceeded(v *Vertex) bool {
+	var msg string
+	var arg any
+	switch {
+	case c.MaxDepth > 0 && c.unifyDepth >= c.MaxDepth:
+		msg, arg = "evaluation depth limit of %d exceeded", c.MaxDepth
+	case c.Memory != nil && c.Memory.exceeded():
+		msg, arg = "memory limit of %d bytes exceeded", c.Memory.Limit
+	default:
+		return false
+	}
+
+	saved := c.vertex
+	c.vertex = v
+	err := c.Newf(msg, arg)
+	c.vertex = saved
+
+	v.SetValue(c, &Bottom{
+		Code: EvalError,
+		Err:  err,
+		Node: v,
+	})
+	return true
+}
@@ -149,6 +149,7 @@ func (ctx *overlayContext) cloneVertex(x *Vertex) *Vertex {
 	*v = *x
 
 	ctx.vertices = append(ctx.vertices, v)
+	ctx.ctx.allocated(vertexSize)
 
 	v._cc = ctx.allocCC(x.cc())
 
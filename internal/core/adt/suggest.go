@@ -0,0 +1,162 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue/errors"
+)
+
+// maxSuggestions is the maximum number of field names suggested for a field
+// that is not allowed.
+const maxSuggestions = 3
+
+// newNotAllowedError returns a "field not allowed" error for arc. If the
+// parent of arc has fields with labels that are similar to that of arc, the
+// error suggests these as alternatives.
+func (c *OpContext) newNotAllowedError(arc *Vertex) *Bottom {
+	err := c.Newf("field not allowed")
+	err.code = errors.NotAllowed
+	return &Bottom{
+		Src:  c.src,
+		Err:  &notAllowedError{ValueError: err, r: c.Runtime, arc: arc},
+		Code: EvalError,
+		Node: c.vertex,
+	}
+}
+
+// A notAllowedError is a "field not allowed" error. Many of these errors
+// are discarded, for instance for disjuncts that fail, so the labels to
+// suggest are only computed when the error is reported.
+type notAllowedError struct {
+	*ValueError
+	r   StringIndexer
+	arc *Vertex
+
+	once        sync.Once
+	suggestions []string
+}
+
+// Suggestions reports the labels of existing fields that are similar to
+// the label of the field that is not allowed.
+func (e *notAllowedError) Suggestions() []string {
+	e.once.Do(func() {
+		e.suggestions = suggestLabels(e.r, e.arc)
+	})
+	return e.suggestions
+}
+
+func (e *notAllowedError) Msg() (format string, args []interface{}) {
+	if s := e.Suggestions(); len(s) > 0 {
+		return "field not allowed (did you mean %s?)", []interface{}{joinAlternatives(s)}
+	}
+	return "field not allowed", nil
+}
+
+func (e *notAllowedError) Error() string {
+	return errors.String(e)
+}
+
+// suggestLabels returns the labels of the fields of the parent of arc that
+// are closest to the label of arc in terms of edit distance, if they are
+// close enough to be plausibly misspelled.
+//
+// Fields that are not present or that are themselves erroneous, such as
+// other fields that are not allowed, are not considered.
+func suggestLabels(r StringIndexer, arc *Vertex) []string {
+	f := arc.Label
+	if arc.Parent == nil || !f.IsRegular() || !f.IsString() {
+		return nil
+	}
+	name := f.StringValue(r)
+	// Allow about one edit for every three characters, so that short
+	// labels do not match arbitrary other short labels.
+	best := max(1, len(name)/3)
+	if best >= len(name) {
+		best = len(name) - 1
+	}
+
+	var labels []string
+	for _, a := range arc.Parent.Arcs {
+		g := a.Label
+		if a == arc || g == f || !g.IsRegular() || !g.IsString() ||
+			a.ArcType > ArcOptional {
+			continue
+		}
+		if _, ok := a.BaseValue.(*Bottom); ok {
+			continue
+		}
+		s := g.StringValue(r)
+		switch d := editDistance(name, s); {
+		case d < best:
+			best = d
+			labels = labels[:0]
+			fallthrough
+		case d == best:
+			labels = append(labels, g.SelectorString(r))
+		}
+	}
+	slices.Sort(labels)
+	labels = slices.Compact(labels)
+	if len(labels) > maxSuggestions {
+		labels = labels[:maxSuggestions]
+	}
+	return labels
+}
+
+// joinAlternatives formats a list of alternatives as "a", "a or b", or
+// "a, b, or c".
+func joinAlternatives(a []string) string {
+	switch len(a) {
+	case 1:
+		return a[0]
+	case 2:
+		return a[0] + " or " + a[1]
+	}
+	return strings.Join(a[:len(a)-1], ", ") + ", or " + a[len(a)-1]
+}
+
+// editDistance returns the number of single character insertions,
+// deletions, substitutions, and transpositions of adjacent characters
+// needed to transform a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// Keep the last three rows of the distance matrix.
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(t)]
+}
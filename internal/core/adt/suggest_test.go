@@ -0,0 +1,108 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adt
+
+import (
+	"slices"
+	"testing"
+
+	"cuelang.org/go/cue/errors"
+)
+
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"abc", "abd", 1},
+		{"abc", "ab", 1},
+		{"abc", "acb", 1},
+		{"action", "ction", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+	for _, tc := range testCases {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.want)
+		}
+		if got := editDistance(tc.b, tc.a); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tc.b, tc.a, got, tc.want)
+		}
+	}
+}
+
+func TestJoinAlternatives(t *testing.T) {
+	testCases := []struct {
+		in   []string
+		want string
+	}{
+		{[]string{"a"}, "a"},
+		{[]string{"a", "b"}, "a or b"},
+		{[]string{"a", "b", "c"}, "a, b, or c"},
+	}
+	for _, tc := range testCases {
+		if got := joinAlternatives(tc.in); got != tc.want {
+			t.Errorf("joinAlternatives(%q) = %q; want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+type testIndexer []string
+
+func (x *testIndexer) StringToIndex(s string) int64 {
+	if i := slices.Index(*x, s); i >= 0 {
+		return int64(i + 1)
+	}
+	*x = append(*x, s)
+	return int64(len(*x))
+}
+
+func (x *testIndexer) IndexToString(i int64) string { return (*x)[i-1] }
+
+func (x *testIndexer) NextUniqueID() uint64 { return 0 }
+
+func TestNotAllowedErrorSuggestions(t *testing.T) {
+	r := &testIndexer{}
+	parent := &Vertex{}
+	for _, name := range []string{"name", "kind", "names"} {
+		parent.Arcs = append(parent.Arcs, &Vertex{
+			Parent: parent,
+			Label:  MakeStringLabel(r, name),
+		})
+	}
+	arc := &Vertex{Parent: parent, Label: MakeStringLabel(r, "nmae")}
+	e := &notAllowedError{
+		ValueError: &ValueError{Message: errors.NewMessagef("field not allowed")},
+		r:          r,
+		arc:        arc,
+	}
+	if e.suggestions != nil {
+		t.Fatalf("suggestions computed before the error is reported")
+	}
+	// Fields added after the error was created are considered.
+	parent.Arcs = append(parent.Arcs, &Vertex{Parent: parent, Label: MakeStringLabel(r, "nme")})
+
+	format, args := e.Msg()
+	msg := errors.NewMessagef(format, args...)
+	if got, want := msg.Error(), "field not allowed (did you mean name or nme?)"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	if got := e.Suggestions(); !slices.Equal(got, []string{"name", "nme"}) {
+		t.Errorf("got suggestions %q", got)
+	}
+}
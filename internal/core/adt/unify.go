@@ -147,6 +147,12 @@ func (v *Vertex) unify(c *OpContext, needs condition, mode runMode) bool {
 		return false
 	}
 
+	if c.limitExceeded(v) {
+		return false
+	}
+	c.unifyDepth++
+	defer func() { c.unifyDepth-- }()
+
 	// Note that the state of a node can be removed before the node is.
 	// This happens with the close builtin, for instance.
 	// See TestFromAPI in pkg export.
@@ -578,6 +584,8 @@ func (n *nodeContext) completeAllArcs(needs condition, mode runMode) bool {
 		// TODO: harmonize this error with "cannot combine"
 		switch {
 		case a.ArcType > ArcRequired, !a.Label.IsString():
+		case n.kind == BottomKind:
+			// The conflicting types have already been reported.
 		case n.kind&StructKind == 0:
 			if !n.node.IsErr() && !a.IsErr() {
 				n.reportFieldMismatch(pos(a.Value()), nil, a.Label, n.node.Value())
@@ -775,6 +783,7 @@ func (v *Vertex) lookup(c *OpContext, pos token.Pos, f Feature, flags combinedFl
 	default:
 		arc = &Vertex{Parent: state.node, Label: f, ArcType: ArcPending}
 		v.Arcs = append(v.Arcs, arc)
+		c.allocated(vertexSize)
 		arcState = arc.getState(c) // TODO: consider using getBareState.
 	}
 
@@ -15,6 +15,7 @@
 package compile
 
 import (
+	"maps"
 	"strings"
 
 	"cuelang.org/go/cue/ast"
@@ -60,7 +61,41 @@ type Config struct {
 func Files(cfg *Config, r adt.Runtime, pkgID string, files ...*ast.File) (*adt.Vertex, errors.Error) {
 	c := newCompiler(cfg, pkgID, r)
 
-	v := c.compileFiles(files)
+	v := c.compileFiles(files, nil)
+
+	if c.errs != nil {
+		return v, c.errs
+	}
+	return v, nil
+}
+
+// Update compiles the given files as a single instance, like Files, but
+// reuses the compiled form of the files that prev, the result of an earlier
+// call to Files or Update for the same package, was compiled from. Files are
+// identified by pointer, so a changed file must be passed as a new
+// *ast.File. As the top-level declarations of a package determine how
+// references are resolved, nothing is reused if they differ from those of
+// prev.
+func Update(cfg *Config, r adt.Runtime, pkgID string, prev *adt.Vertex, files ...*ast.File) (*adt.Vertex, errors.Error) {
+	c := newCompiler(cfg, pkgID, r)
+
+	var old []*ast.File
+	compiled := map[*ast.File]adt.Conjunct{}
+	if prev != nil {
+		for _, x := range prev.Conjuncts {
+			if s, ok := x.Elem().(*adt.StructLit); ok {
+				if f, ok := s.Src.(*ast.File); ok {
+					old = append(old, f)
+					compiled[f] = x
+				}
+			}
+		}
+	}
+	if !maps.Equal(c.packageScope(old), c.packageScope(files)) {
+		compiled = nil
+	}
+
+	v := c.compileFiles(files, compiled)
 
 	if c.errs != nil {
 		return v, c.errs
@@ -261,19 +296,14 @@ func (c *compiler) popScope() {
 	c.stack = c.stack[:k]
 }
 
-func (c *compiler) compileFiles(a []*ast.File) *adt.Vertex { // Or value?
-	c.fileScope = map[adt.Feature]bool{}
-	c.upCountOffset = 1
-
-	// TODO(resolve): this is also done in the runtime package, do we need both?
-
-	// Populate file scope to handle unresolved references.
-	// Excluded from cross-file resolution are:
-	// - import specs
-	// - aliases
-	// - let declarations
-	// - anything in an anonymous file
-	//
+// packageScope returns the labels of the top-level fields of the given files
+// that may be referenced from other files of the same package. Excluded are:
+//   - import specs
+//   - aliases
+//   - let declarations
+//   - anything in an anonymous file
+func (c *compiler) packageScope(a []*ast.File) map[adt.Feature]bool {
+	scope := map[adt.Feature]bool{}
 	for _, f := range a {
 		if f.PackageName() == "" {
 			continue
@@ -281,11 +311,23 @@ func (c *compiler) compileFiles(a []*ast.File) *adt.Vertex { // Or value?
 		for _, d := range f.Decls {
 			if f, ok := d.(*ast.Field); ok {
 				if id, ok := f.Label.(*ast.Ident); ok {
-					c.fileScope[c.label(id)] = true
+					scope[c.label(id)] = true
 				}
 			}
 		}
 	}
+	return scope
+}
+
+// compileFiles compiles the files in a into a single Vertex. Files for which
+// compiled holds a conjunct are not compiled again.
+func (c *compiler) compileFiles(a []*ast.File, compiled map[*ast.File]adt.Conjunct) *adt.Vertex { // Or value?
+	c.upCountOffset = 1
+
+	// TODO(resolve): this is also done in the runtime package, do we need both?
+
+	// Populate file scope to handle unresolved references.
+	c.fileScope = c.packageScope(a)
 
 	// TODO: set doc.
 	res := &adt.Vertex{}
@@ -302,6 +344,10 @@ func (c *compiler) compileFiles(a []*ast.File) *adt.Vertex { // Or value?
 	}
 
 	for _, file := range a {
+		if x, ok := compiled[file]; ok {
+			res.InsertConjunct(x)
+			continue
+		}
 		c.pushScope(nil, 0, file) // File scope
 		v := &adt.StructLit{Src: file}
 		c.addDecls(v, file.Decls)
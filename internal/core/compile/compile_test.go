@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/internal/core/compile"
@@ -101,3 +102,45 @@ func TestX(t *testing.T) {
 	}
 	t.Error(debug.NodeString(r, arc.ConjunctAt(0).Elem(), nil))
 }
+
+func TestUpdate(t *testing.T) {
+	parse := func(name, src string) *ast.File {
+		f, err := parser.ParseFile(name, "package p\n"+src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	r := runtime.New()
+	a := parse("a.cue", "a: b")
+	b := parse("b.cue", "b: 1")
+
+	prev, err := compile.Files(nil, r, "p", a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing b.cue without changing the top-level fields reuses a.cue.
+	b2 := parse("b.cue", "b: 2")
+	v, err := compile.Update(nil, r, "p", prev, a, b2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Conjuncts[0].Elem() != prev.Conjuncts[0].Elem() {
+		t.Error("a.cue was compiled again")
+	}
+	if v.Conjuncts[1].Elem() == prev.Conjuncts[1].Elem() {
+		t.Error("b.cue was not compiled again")
+	}
+
+	// Adding a top-level field compiles all files again, as references may
+	// now resolve differently.
+	b3 := parse("b.cue", "b: 2, c: 3")
+	v2, err := compile.Update(nil, r, "p", v, a, b3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.Conjuncts[0].Elem() == v.Conjuncts[0].Elem() {
+		t.Error("a.cue was not compiled again")
+	}
+}
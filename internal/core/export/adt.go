@@ -429,6 +429,11 @@ func (e *exporter) resolve(env *adt.Environment, r adt.Resolver) ast.Expr {
 			// TODO: note that env.Vertex should never be nil; investigate and replace the nil check below.
 			if v := env.Vertex; v != nil && !v.IsDynamic {
 				if v = v.Lookup(x.Label); v != nil {
+					if c := e.pivotter; c != nil {
+						if alt := c.recursiveRef(v); alt != nil {
+							return alt
+						}
+					}
 					e.linkIdentifier(v, ident)
 				}
 			}
@@ -71,6 +71,12 @@ type Profile struct {
 
 	// InlineImports expands references to non-builtin packages.
 	InlineImports bool
+
+	// SortFields, if non-nil, orders the fields of structs of evaluated
+	// values. It compares two fields of the same struct as in
+	// [slices.SortStableFunc]; fields for which it returns 0 keep their
+	// default, topological, order.
+	SortFields func(a, b *adt.Vertex) int
 }
 
 var Simplified = &Profile{
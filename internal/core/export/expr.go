@@ -269,7 +269,7 @@ func (x *exporter) mergeValues(label adt.Feature, src *adt.Vertex, a []conjunct,
 		internal.SetConstraint(d, field.arcType.Token())
 		if x.cfg.ShowDocs {
 			v := &adt.Vertex{Conjuncts: a}
-			docs := extractDocs(v)
+			docs := ExtractDoc(v)
 			ast.SetComments(d, docs)
 		}
 		if x.cfg.ShowAttributes {
@@ -29,17 +29,47 @@ import (
 //	// comment
 //	foo: bar: 2
 func ExtractDoc(v *adt.Vertex) (docs []*ast.CommentGroup) {
-	return extractDocs(v)
+	for _, d := range extractDocs(v, true) {
+		docs = append(docs, d.Comment)
+	}
+	return docs
+}
+
+// A Doc is a documentation comment along with the declaration it is
+// attached to.
+type Doc struct {
+	Comment *ast.CommentGroup
+
+	// Decl is the declaration from which Comment originates. It is either an
+	// *ast.Field or, for package documentation, an *ast.File.
+	Decl ast.Node
 }
 
-func extractDocs(v *adt.Vertex) (docs []*ast.CommentGroup) {
+// ExtractDocOrigins is like ExtractDoc, but reports the declaration from
+// which each comment originates. Unlike ExtractDoc, it does not remove
+// comments with identical text that originate from distinct declarations.
+func ExtractDocOrigins(v *adt.Vertex) []Doc {
+	return extractDocs(v, false)
+}
+
+// extractDocs collects the documentation comments for v. If byText is true,
+// comments are considered to be duplicates if they have the same text.
+func extractDocs(v *adt.Vertex, byText bool) (docs []Doc) {
 	fields := []*ast.Field{}
 
+	addField := func(f *ast.Field) {
+		for _, cg := range f.Comments() {
+			if !containsDoc(docs, cg, byText) && cg.Doc {
+				docs = append(docs, Doc{Comment: cg, Decl: f})
+			}
+		}
+	}
+
 	// Collect docs directly related to this Vertex.
 	v.VisitLeafConjuncts(func(x adt.Conjunct) bool {
 		// TODO: Is this still being used?
 		if v, ok := x.Elem().(*adt.Vertex); ok {
-			docs = append(docs, extractDocs(v)...)
+			docs = append(docs, extractDocs(v, byText)...)
 			return true
 		}
 
@@ -49,15 +79,15 @@ func extractDocs(v *adt.Vertex) (docs []*ast.CommentGroup) {
 				return true
 			}
 			fields = append(fields, f)
-			for _, cg := range f.Comments() {
-				if !containsDoc(docs, cg) && cg.Doc {
-					docs = append(docs, cg)
-				}
-			}
+			addField(f)
 
 		case *ast.File:
 			fdocs, _ := internal.FileComments(f)
-			docs = append(docs, fdocs...)
+			for _, cg := range fdocs {
+				if byText || !containsDoc(docs, cg, false) {
+					docs = append(docs, Doc{Comment: cg, Decl: f})
+				}
+			}
 		}
 
 		return true
@@ -78,11 +108,7 @@ func extractDocs(v *adt.Vertex) (docs []*ast.CommentGroup) {
 			for _, child := range fields {
 				if nested == child {
 					newFields = append(newFields, f)
-					for _, cg := range f.Comments() {
-						if !containsDoc(docs, cg) && cg.Doc {
-							docs = append(docs, cg)
-						}
-					}
+					addField(f)
 				}
 			}
 			return true
@@ -125,15 +151,21 @@ func nestedField(f *ast.Field) *ast.Field {
 	return f
 }
 
-func containsDoc(a []*ast.CommentGroup, cg *ast.CommentGroup) bool {
+// containsDoc reports whether cg is already in a. If byText is true, it also
+// reports whether a contains a comment with the same text as cg.
+func containsDoc(a []Doc, cg *ast.CommentGroup, byText bool) bool {
 	for _, c := range a {
-		if c == cg {
+		if c.Comment == cg {
 			return true
 		}
 	}
 
+	if !byText {
+		return false
+	}
+
 	for _, c := range a {
-		if c.Text() == cg.Text() {
+		if c.Comment.Text() == cg.Text() {
 			return true
 		}
 	}
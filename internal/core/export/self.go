@@ -56,11 +56,16 @@ func (e *exporter) completePivot(f *ast.File) {
 	if s == nil || f == nil {
 		return
 	}
-	for _, d := range s.deps {
-		if !d.isExternalRoot() {
-			continue
+	// Exporting a hoisted value may require others to be hoisted as well.
+	for added := true; added; {
+		added = false
+		for _, d := range s.deps {
+			if !d.isExternalRoot() || !d.needTopLevel || d.added {
+				continue
+			}
+			s.addExternal(d)
+			added = true
 		}
-		s.addExternal(d)
 	}
 	f.Decls = append(f.Decls, s.decls...)
 }
@@ -82,6 +87,10 @@ type pivotter struct {
 	refMap map[adt.Resolver]*refData
 
 	decls []ast.Decl
+
+	// hoisting is the node whose value is currently being exported by
+	// addExternal, if any.
+	hoisting *depData
 }
 
 type depData struct {
@@ -95,6 +104,7 @@ type depData struct {
 	useCount     int // Other reference using this vertex
 	included     bool
 	needTopLevel bool
+	added        bool // the top-level declaration has been added
 }
 
 // isExternalRoot reports whether d is an external node (a node referenced
@@ -426,13 +436,40 @@ func (p *pivotter) refExpr(r adt.Resolver) ast.Expr {
 	return nil
 }
 
+// recursiveRef returns a substituted expression for a reference to v that
+// was not recorded as a dependency, or nil if there are no changes. This
+// happens for references to a hoisted or inlined value from within the
+// value itself, such as for a recursive definition, which would otherwise
+// be left dangling.
+func (p *pivotter) recursiveRef(v *adt.Vertex) ast.Expr {
+	d, ok := p.depsMap[v]
+	if !ok || d.included || d.parent != nil || len(d.path) != 2 {
+		// References with a path of one element cannot be resolved from
+		// within the let clause that declares them.
+		return nil
+	}
+	if p.hoisting == d {
+		// Within the let clause of d, the definition is in scope.
+		return p.x.ident(d.path[1])
+	}
+	d.needTopLevel = true
+	return &ast.SelectorExpr{
+		X:   p.x.ident(d.path[0]),
+		Sel: p.x.stringLabel(d.path[1]),
+	}
+}
+
 // addExternal converts a vertex for an external reference.
 func (p *pivotter) addExternal(d *depData) {
 	if !d.needTopLevel {
 		return
 	}
+	d.added = true
 
+	saved := p.hoisting
+	p.hoisting = d
 	expr := p.x.expr(nil, d.node())
+	p.hoisting = saved
 
 	if len(d.path) > 1 {
 		expr = ast.NewStruct(&ast.Field{
@@ -191,3 +191,18 @@ outer:
 
 	return counts
 }
+
+// sortFeatures sorts the features of v using cmp to compare the arcs
+// corresponding to these features. Features for which there is no arc keep
+// their relative position.
+func sortFeatures(v *adt.Vertex, features []adt.Feature, cmp func(a, b *adt.Vertex) int) []adt.Feature {
+	features = slices.Clone(features)
+	slices.SortStableFunc(features, func(a, b adt.Feature) int {
+		x, y := v.LookupRaw(a), v.LookupRaw(b)
+		if x == nil || y == nil {
+			return 0
+		}
+		return cmp(x, y)
+	})
+	return features
+}
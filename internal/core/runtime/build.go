@@ -15,6 +15,7 @@
 package runtime
 
 import (
+	"slices"
 	"strings"
 
 	"cuelang.org/go/cue/ast"
@@ -43,6 +44,9 @@ func (x *Runtime) Build(cfg *Config, b *build.Instance) (v *adt.Vertex, errs err
 	if err := b.Complete(); err != nil {
 		return nil, b.Err
 	}
+	if err := b.LoadSyntax(); err != nil {
+		return nil, err
+	}
 	if v := x.getNodeFromInstance(b); v != nil {
 		return v, b.Err
 	}
@@ -91,6 +95,86 @@ func (x *Runtime) Build(cfg *Config, b *build.Instance) (v *adt.Vertex, errs err
 	return v, errs
 }
 
+// Rebuild builds a new version of b, which must have been built before, in
+// which the given files replace the files of b with the same name. Files
+// with a name that is not yet part of b are added. It returns the new
+// instance, which can in turn be rebuilt again, while b can no longer be
+// rebuilt.
+//
+// The compiled form of the files that did not change is reused, unless the
+// top-level declarations of the package changed, as are all the packages
+// that b imports. Files may only import packages that b already imports.
+func (x *Runtime) Rebuild(cfg *Config, b *build.Instance, files ...*ast.File) (*build.Instance, *adt.Vertex, errors.Error) {
+	prev := x.getNodeFromInstance(b)
+	if prev == nil {
+		return b, nil, errors.Newf(token.NoPos, "instance %s was not built", b.ID())
+	}
+
+	// Instances with errors from loading are not built, so any errors of b
+	// stem from building it and must not carry over to the new instance.
+	old := b
+	nb := *b
+	nb.Err = nil
+	nb.Files = slices.Clone(b.Files)
+	for _, f := range files {
+		i := slices.IndexFunc(nb.Files, func(g *ast.File) bool {
+			return g.Filename == f.Filename
+		})
+		if i < 0 {
+			nb.Files = append(nb.Files, f)
+		} else {
+			nb.Files[i] = f
+		}
+	}
+	b = &nb
+
+	errs := b.Err
+	for _, file := range files {
+		file.VisitImports(func(d *ast.ImportDecl) {
+			for _, s := range d.Specs {
+				errs = errors.Append(errs, x.buildSpec(cfg, b, s))
+			}
+		})
+	}
+
+	errs = errors.Append(errs, x.ResolveFiles(b))
+
+	var cc *compile.Config
+	if cfg != nil {
+		cc = &cfg.Config
+	}
+	v, err := compile.Update(cc, x, b.ID(), prev, b.Files...)
+	errs = errors.Append(errs, err)
+
+	// Implementations were already injected into the files that were
+	// reused, so only inject them into the files that were compiled again.
+	reused := map[adt.Elem]bool{}
+	for _, c := range prev.Conjuncts {
+		reused[c.Elem()] = true
+	}
+	var compiled []*ast.File
+	for _, c := range v.Conjuncts {
+		if s, ok := c.Elem().(*adt.StructLit); ok && !reused[s] {
+			if f, ok := s.Src.(*ast.File); ok {
+				compiled = append(compiled, f)
+			}
+		}
+	}
+	errs = errors.Append(errs, x.injectImplementations(b, compiled, v))
+
+	if errs != nil {
+		v = adt.ToVertex(&adt.Bottom{Err: errs})
+		b.Err = errs
+	}
+
+	// Forget about the old instance to avoid retaining every version of a
+	// frequently rebuilt instance.
+	x.removeInst(prev, old)
+	x.AddInst(b.ImportPath, v, b)
+
+	return b, v, errs
+}
+
 func dummyLoad(token.Pos, string) *build.Instance { return nil }
 
 func (r *Runtime) Compile(cfg *Config, source interface{}) (*adt.Vertex, *build.Instance) {
@@ -131,15 +215,16 @@ func (x *Runtime) buildSpec(cfg *Config, b *build.Instance, spec *ast.ImportSpec
 
 	pkg := b.LookupImport(info.ID)
 	if pkg == nil {
+		if x.index.builtinPaths[info.ID] != nil {
+			return nil
+		}
 		if strings.Contains(info.ID, ".") {
 			return errors.Newf(spec.Pos(),
 				"package %q imported but not defined in %s",
 				info.ID, b.ImportPath)
-		} else if x.index.builtinPaths[info.ID] == nil {
-			return errors.Newf(spec.Pos(),
-				"builtin package %q undefined", info.ID)
 		}
-		return nil
+		return errors.Newf(spec.Pos(),
+			"builtin package %q undefined", info.ID)
 	}
 
 	if v := x.getNodeFromInstance(pkg); v != nil {
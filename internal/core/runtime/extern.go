@@ -64,13 +64,18 @@ type Compiler interface {
 // TODO(mvdan): unexport again once cue.Instance.Build is no longer used by `cue cmd`
 // and can be removed entirely.
 func (r *Runtime) InjectImplementations(b *build.Instance, v *adt.Vertex) (errs errors.Error) {
+	return r.injectImplementations(b, b.Files, v)
+}
 
+// injectImplementations is like InjectImplementations, but only considers
+// the attributes in the given files of b.
+func (r *Runtime) injectImplementations(b *build.Instance, files []*ast.File, v *adt.Vertex) (errs errors.Error) {
 	d := &externDecorator{
 		runtime: r,
 		pkg:     b,
 	}
 
-	for _, f := range b.Files {
+	for _, f := range files {
 		d.errs = errors.Append(d.errs, d.addFile(f))
 	}
 
@@ -15,7 +15,9 @@
 package runtime
 
 import (
+	"maps"
 	"path"
+	"slices"
 	"sync"
 
 	"cuelang.org/go/cue/build"
@@ -44,6 +46,43 @@ func (x *index) RegisterBuiltin(importPath string, f PackageFunc) {
 	x.builtinShort[base] = importPath
 }
 
+// AddBuiltin makes the builtin package implemented by f available to r
+// under importPath, in addition to the builtins registered with
+// RegisterBuiltin, which are shared by all runtimes. It must be called
+// before r is used.
+func (r *Runtime) AddBuiltin(importPath string, f PackageFunc) {
+	x := r.index
+	if !x.ownBuiltins {
+		// Do not modify the builtins shared with other runtimes.
+		x.builtinPaths = maps.Clone(x.builtinPaths)
+		x.builtinShort = maps.Clone(x.builtinShort)
+		x.ownBuiltins = true
+	}
+	x.RegisterBuiltin(importPath, f)
+}
+
+// HasBuiltin reports whether a builtin package is available to r for
+// importPath.
+func (r *Runtime) HasBuiltin(importPath string) bool {
+	return r.index.builtinPaths[importPath] != nil
+}
+
+// IsBuiltin reports whether a builtin package is registered for importPath.
+func IsBuiltin(importPath string) bool {
+	return sharedIndex.builtinPaths[importPath] != nil
+}
+
+// BuiltinPaths returns the sorted import paths of the builtin packages
+// registered with RegisterBuiltin.
+func BuiltinPaths() []string {
+	paths := make([]string, 0, len(sharedIndex.builtinPaths))
+	for p := range sharedIndex.builtinPaths {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
 // We use a sync.OnceValue below so that cueexperiment.Init is only called
 // the first time that the API is used, letting the user set $CUE_EXPERIMENT globally
 // as part of their package init if they want to.
@@ -96,6 +135,10 @@ type index struct {
 	builtinPaths map[string]PackageFunc // Full path
 	builtinShort map[string]string      // Commandline shorthand
 
+	// ownBuiltins reports whether builtinPaths and builtinShort are owned
+	// by this index, rather than shared with sharedIndex.
+	ownBuiltins bool
+
 	typeCache sync.Map // map[reflect.Type]evaluated
 }
 
@@ -139,6 +182,16 @@ func (r *Runtime) AddInst(path string, key *adt.Vertex, p *build.Instance) {
 	}
 }
 
+// removeInst removes the instance p, built as key, from the index.
+func (r *Runtime) removeInst(key *adt.Vertex, p *build.Instance) {
+	r.index.lock.Lock()
+	defer r.index.lock.Unlock()
+
+	x := r.index
+	delete(x.imports, key)
+	delete(x.importsByBuild, p)
+}
+
 func (r *Runtime) GetInstanceFromNode(key *adt.Vertex) *build.Instance {
 	r.index.lock.RLock()
 	defer r.index.lock.RUnlock()
@@ -15,6 +15,7 @@
 package runtime
 
 import (
+	"strings"
 	"sync"
 
 	"cuelang.org/go/internal"
@@ -60,9 +61,10 @@ func (r *Runtime) Label(s string, isIdent bool) adt.Feature {
 
 // TODO: move to Runtime as fields.
 var (
-	labelMap = map[string]int{}
-	labels   = make([]string, 0, 1000)
-	mutex    sync.RWMutex
+	labelMap   = map[string]int{}
+	labels     = make([]string, 0, 1000)
+	labelBytes int
+	mutex      sync.RWMutex
 )
 
 func init() {
@@ -83,12 +85,41 @@ func getKey(s string) int64 {
 	if ok {
 		return int64(p)
 	}
+	// Labels are often substrings of a larger source. Clone s so that the
+	// table does not keep that source alive.
+	s = strings.Clone(s)
 	p = len(labels)
 	labels = append(labels, s)
 	labelMap[s] = p
+	labelBytes += len(s)
 	return int64(p)
 }
 
+// RegisterLabels adds the given labels to the label table, if they are not
+// already present.
+func RegisterLabels(labels ...string) {
+	for _, s := range labels {
+		getKey(s)
+	}
+}
+
+// LabelStats describes the size of the label table, which is shared by all
+// runtimes and never shrinks.
+type LabelStats struct {
+	// Labels is the number of distinct labels in the table.
+	Labels int
+
+	// Bytes is the total length of all labels in the table.
+	Bytes int
+}
+
+// ReadLabelStats reports the current size of the label table.
+func ReadLabelStats() LabelStats {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return LabelStats{Labels: len(labels), Bytes: labelBytes}
+}
+
 func (x *index) IndexToString(i int64) string {
 	mutex.RLock()
 	s := labels[i]
@@ -34,6 +34,10 @@ type Runtime struct {
 
 	version  internal.EvaluatorVersion
 	topoSort bool
+	maxDepth int
+	memory   *adt.MemoryBudget
+
+	absent adt.AbsentCache
 
 	flags cuedebug.Config
 }
@@ -45,6 +49,8 @@ func (r *Runtime) Settings() (internal.EvaluatorVersion, cuedebug.Config) {
 func (r *Runtime) ConfigureOpCtx(ctx *adt.OpContext) {
 	ctx.Version = r.version
 	ctx.TopoSort = r.topoSort
+	ctx.MaxDepth = r.maxDepth
+	ctx.Memory = r.memory
 	ctx.Config = r.flags
 }
 
@@ -89,6 +95,45 @@ func (r *Runtime) SetTopologicalSort(b bool) {
 	r.topoSort = b
 }
 
+// DefaultMaxDepth is the default maximum depth of evaluation. It is well
+// beyond the nesting of any practical configuration, yet low enough that
+// evaluation fails with an error before exhausting the Go stack.
+const DefaultMaxDepth = 10_000
+
+// SetMaxDepth sets the maximum depth of evaluation for the Runtime. Values
+// whose evaluation nests deeper result in an error. A value of zero or
+// less disables the check. This should only be set before first use.
+func (r *Runtime) SetMaxDepth(n int) {
+	r.maxDepth = n
+}
+
+// SetMemoryLimit limits the memory that evaluation may allocate over the
+// lifetime of the Runtime to about n bytes. Evaluation of values beyond
+// this limit results in an error. A value of zero or less removes the
+// limit. This should only be set before first use.
+func (r *Runtime) SetMemoryLimit(n int64) {
+	if n <= 0 {
+		r.memory = nil
+		return
+	}
+	r.memory = &adt.MemoryBudget{Limit: n}
+}
+
+// Absent returns the cache of lookups of absent fields for values of the
+// Runtime.
+func (r *Runtime) Absent() *adt.AbsentCache {
+	return &r.absent
+}
+
+// MemoryUsed reports an estimate of the memory allocated by evaluation
+// since the memory limit was set. It returns 0 if there is no limit.
+func (r *Runtime) MemoryUsed() int64 {
+	if r.memory == nil {
+		return 0
+	}
+	return r.memory.Used()
+}
+
 // SetDebugOptions sets the debug flags to use for the Runtime. This should only
 // be set before first use.
 func (r *Runtime) SetDebugOptions(flags *cuedebug.Config) {
@@ -121,6 +166,7 @@ func (r *Runtime) Init() {
 		r.version = internal.DefaultVersion
 	}
 	r.topoSort = cueexperiment.Flags.TopoSort
+	r.maxDepth = DefaultMaxDepth
 
 	// By default we follow the environment's CUE_DEBUG settings,
 	// which can be overriden via [Runtime.SetDebugOptions],
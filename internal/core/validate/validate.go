@@ -16,6 +16,7 @@
 package validate
 
 import (
+	"cuelang.org/go/cue/errors"
 	"cuelang.org/go/internal/core/adt"
 )
 
@@ -32,6 +33,15 @@ type Config struct {
 	// AllErrors continues descending into a Vertex, even if errors are found.
 	AllErrors bool
 
+	// RequireRegular, if true, reports regular fields outside of
+	// definitions that do not have a concrete value as missing required
+	// fields.
+	RequireRegular bool
+
+	// MaxErrors, if positive, stops validation once at least this many
+	// errors have been found. It only applies if AllErrors is true.
+	MaxErrors int
+
 	// TODO: omitOptional, if this is becomes relevant.
 }
 
@@ -50,6 +60,7 @@ type validator struct {
 	Config
 	ctx          *adt.OpContext
 	err          *adt.Bottom
+	numErrs      int
 	inDefinition int
 }
 
@@ -64,9 +75,18 @@ func (v *validator) add(b *adt.Bottom) {
 	}
 	if !b.ChildError {
 		v.err = adt.CombineErrors(nil, v.err, b)
+		v.numErrs += max(1, len(errors.Errors(b.Err)))
 	}
 }
 
+// done reports whether validation can stop as enough errors were found.
+func (v *validator) done() bool {
+	if !v.AllErrors {
+		return v.err != nil
+	}
+	return v.MaxErrors > 0 && v.numErrs >= v.MaxErrors
+}
+
 func (v *validator) validate(x *adt.Vertex) {
 	defer v.ctx.PopArc(v.ctx.PushArc(x))
 
@@ -104,6 +124,10 @@ func (v *validator) validate(x *adt.Vertex) {
 		}
 	}
 
+	if _, shared := x.BaseValue.(*adt.Vertex); shared && v.RequireRegular && v.inDefinition == 0 {
+		v.checkShared(x)
+	}
+
 	for _, a := range x.Arcs {
 		if a.ArcType == adt.ArcRequired && v.Final && v.inDefinition == 0 {
 			v.add(adt.NewRequiredNotPresentError(v.ctx, a))
@@ -113,9 +137,14 @@ func (v *validator) validate(x *adt.Vertex) {
 		if a.Label.IsLet() || !a.IsDefined(v.ctx) {
 			continue
 		}
-		if !v.AllErrors && v.err != nil {
+		if v.done() {
 			break
 		}
+		if v.RequireRegular && a.ArcType == adt.ArcMember && v.inDefinition == 0 &&
+			a.Label.IsRegular() && v.isMissing(a) {
+			v.add(v.requiredError(a, a))
+			continue
+		}
 		if a.Label.IsRegular() {
 			v.validate(a)
 		} else {
@@ -125,3 +154,50 @@ func (v *validator) validate(x *adt.Vertex) {
 		}
 	}
 }
+
+// isMissing reports whether a field has a non-concrete value, which
+// indicates that its value was to be supplied by the data the
+// containing schema is applied to.
+func (v *validator) isMissing(a *adt.Vertex) bool {
+	x := a.DerefNonShared()
+	if x.Bottom() != nil {
+		// Leave errors to the usual checks.
+		return false
+	}
+	return !adt.IsConcrete(x.Default())
+}
+
+// requiredError reports that field a, located at the given arc, is missing.
+func (v *validator) requiredError(arc, a *adt.Vertex) *adt.Bottom {
+	defer v.ctx.PopArc(v.ctx.PushArc(arc))
+	err := v.ctx.Newf("field is required but not present")
+	a.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		err.AddPosition(c.Field())
+		return true
+	})
+	return &adt.Bottom{
+		Code: adt.IncompleteError,
+		Err:  err,
+		Node: a,
+	}
+}
+
+// checkShared reports missing regular fields within the structure-shared
+// value of x, which is otherwise not descended into to avoid processing it
+// more than once. Errors are reported at their location within x.
+func (v *validator) checkShared(x *adt.Vertex) {
+	for _, a := range x.DerefValue().Arcs {
+		if v.done() {
+			break
+		}
+		if a.ArcType != adt.ArcMember || !a.Label.IsRegular() || !a.IsDefined(v.ctx) {
+			continue
+		}
+		arc := &adt.Vertex{Parent: x, Label: a.Label, BaseValue: a}
+		if v.isMissing(a) {
+			v.add(v.requiredError(arc, a))
+			continue
+		}
+		v.checkShared(arc)
+	}
+}
@@ -94,6 +94,21 @@ func TestValidate(t *testing.T) {
 x: conflicting values 2 and 1:
     test:2:6
     test:2:10
+y: conflicting values 4 and 2:
+    test:3:6
+    test:3:10`,
+	}, {
+		name: "max errors",
+		cfg:  &validate.Config{AllErrors: true, MaxErrors: 2},
+		in: `
+		x: 1 & 2
+		y: 2 & 4
+		z: 3 & 4
+		`,
+		out: `eval
+x: conflicting values 2 and 1:
+    test:2:6
+    test:2:10
 y: conflicting values 4 and 2:
     test:3:6
     test:3:10`,
@@ -241,6 +256,21 @@ y: conflicting values 4 and 2:
 		// 		x: {bar: 2}
 		// 		x: string | {foo!: string}
 		// 	`,
+	}, {
+		name: "regular fields required",
+		cfg:  &validate.Config{RequireRegular: true, AllErrors: true},
+		in: `
+			#S: {a: int, b: *1 | int, c?: int, d: e: string}
+			x: #S
+		`,
+		out: "incomplete\nx.a: field is required but not present:\n    test:2:9\nx.d.e: field is required but not present:\n    test:2:42",
+	}, {
+		name: "regular fields required ignores definitions",
+		cfg:  &validate.Config{RequireRegular: true},
+		in: `
+			#S: {a: int}
+		`,
+		out: "",
 	}}
 
 	cuetdtest.Run(t, testCases, func(t *cuetdtest.T, tc *testCase) {
@@ -42,6 +42,11 @@ type Config struct {
 	// lexicographically.
 	SortFields bool
 
+	// SortErrors forces the individual errors reported for a value to be
+	// sorted by position and path, rather than appearing in the order
+	// in which the evaluator encountered them.
+	SortErrors bool
+
 	// OpenInline permits disallowed fields to be selected into literal structs
 	// that would normally result in a close error. For instance,
 	//
@@ -117,19 +117,15 @@ func (d *differ) diffValue(x, y cue.Value) (Kind, *EditScript) {
 		case cue.ListKind:
 			return d.diffList(x, y)
 		}
-		fallthrough
 
-	default:
-		// In concrete mode we do not care about non-concrete values.
-		if d.cfg.Concrete {
-			return Identity, nil
-		}
-
-		if !x.Equals(y) {
-			return Modified, nil
-		}
+	// In concrete mode we do not care about non-concrete values.
+	case d.cfg.Concrete:
+		return Identity, nil
 	}
 
+	if !x.Equals(y) {
+		return Modified, nil
+	}
 	return Identity, nil
 }
 
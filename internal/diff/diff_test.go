@@ -398,6 +398,23 @@ a: x: "hello"
 		x:       `{a: 1, _hidden1: 1, _hidden: 1}`,
 		y:       `{a: 1, _hidden2: 1, _hidden: 2}`,
 		profile: &Profile{SkipHidden: true, Concrete: true},
+	}, {
+		name:    "modified value in data",
+		x:       `{a: *1 | int, b: "foo"}`,
+		y:       `{a: 1, b: "bar"}`,
+		profile: Final,
+		kind:    Modified,
+		diff: `  {
+      a: 1
+-     b: "foo"
++     b: "bar"
+  }
+`,
+	}, {
+		name:    "default value in data",
+		x:       `{a: *1 | int, b: string}`,
+		y:       `{a: 1, b: string}`,
+		profile: Final,
 	}, {
 		name: "all errors are equal",
 		x:    `1 & 3`,
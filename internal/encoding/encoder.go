@@ -122,6 +122,9 @@ func NewEncoder(ctx *cue.Context, f *build.File, cfg *Config) (*Encoder, error)
 			cue.DisallowCycles(!fi.Cycles),
 			cue.InlineImports(cfg.InlineImports),
 		)
+		if cfg.FieldOrder != nil {
+			synOpts = append(synOpts, cue.FieldOrder(cfg.FieldOrder))
+		}
 
 		opts := []format.Option{}
 		opts = append(opts, cfg.Format...)
@@ -246,9 +249,31 @@ func NewEncoder(ctx *cue.Context, f *build.File, cfg *Config) (*Encoder, error)
 		return nil, fmt.Errorf("unsupported encoding %q", f.Encoding)
 	}
 
+	switch f.Encoding {
+	case build.JSON, build.JSONL, build.YAML, build.TOML:
+		if cfg.FieldOrder != nil {
+			encValue := e.encValue
+			e.encValue = func(v cue.Value) error {
+				v, err := e.orderFields(v)
+				if err != nil {
+					return err
+				}
+				return encValue(v)
+			}
+		}
+	}
+
 	return e, nil
 }
 
+// orderFields returns a copy of the concrete value v of which the fields are
+// ordered according to e.cfg.FieldOrder.
+func (e *Encoder) orderFields(v cue.Value) (cue.Value, error) {
+	n := v.Syntax(cue.Final(), cue.Concrete(true), cue.FieldOrder(e.cfg.FieldOrder))
+	v = e.ctx.BuildFile(internal.ToFile(n))
+	return v, v.Err()
+}
+
 func (e *Encoder) EncodeFile(f *ast.File) error {
 	e.autoSimplify = false
 	return e.encodeFile(f, e.interpret)
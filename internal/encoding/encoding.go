@@ -32,6 +32,7 @@ import (
 	"cuelang.org/go/cue/token"
 	"cuelang.org/go/encoding/json"
 	"cuelang.org/go/encoding/jsonschema"
+	"cuelang.org/go/encoding/markdown"
 	"cuelang.org/go/encoding/openapi"
 	"cuelang.org/go/encoding/protobuf"
 	"cuelang.org/go/encoding/protobuf/jsonpb"
@@ -149,6 +150,10 @@ type Config struct {
 	ProtoPath     []string
 	Format        []format.Option
 	ParseFile     func(name string, src interface{}) (*ast.File, error)
+
+	// FieldOrder, if non-nil, determines the order of struct fields in the
+	// output. See [cue.FieldOrder].
+	FieldOrder func(a, b cue.Value) int
 }
 
 // NewDecoder returns a stream of non-rooted data expressions. The encoding
@@ -257,6 +262,9 @@ func NewDecoder(ctx *cue.Context, f *build.File, cfg *Config) *Decoder {
 	case build.TOML:
 		i.next = toml.NewDecoder(path, r).Decode
 		i.Next()
+	case build.Markdown:
+		i.next = markdown.NewDecoder(path, r).Decode
+		i.Next()
 	case build.Text:
 		b, err := io.ReadAll(r)
 		i.err = err
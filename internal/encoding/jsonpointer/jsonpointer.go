@@ -0,0 +1,124 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpointer converts between JSON Pointers, as defined by RFC 6901,
+// and the paths of CUE values decoded from JSON.
+package jsonpointer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue"
+)
+
+// TODO this package mimics the JSON Pointer functionality in
+// https://pkg.go.dev/github.com/go-json-experiment/json/jsontext#Pointer;
+// perhaps use it when it moves into the stdlib as json/v2.
+
+var (
+	esc   = strings.NewReplacer("~", "~0", "/", "~1")
+	unesc = strings.NewReplacer("~0", "~", "~1", "/")
+)
+
+// FromTokens returns the JSON Pointer made of the given unescaped tokens.
+//
+// TODO(go1.23) func FromTokens(tokens iter.Seq[string]) string
+func FromTokens(tokens func(func(string) bool)) string {
+	var buf strings.Builder
+	// TODO for tok := range tokens {
+	tokens(func(tok string) bool {
+		buf.WriteByte('/')
+		buf.WriteString(esc.Replace(tok))
+		return true
+	})
+	return buf.String()
+}
+
+// Tokens returns the unescaped tokens of the JSON Pointer p.
+//
+// TODO(go1.23) func Tokens(p string) iter.Seq[string]
+func Tokens(p string) func(func(string) bool) {
+	return func(yield func(string) bool) {
+		needUnesc := strings.IndexByte(p, '~') >= 0
+		for len(p) > 0 {
+			p = strings.TrimPrefix(p, "/")
+			i := min(uint(strings.IndexByte(p, '/')), uint(len(p)))
+			var ok bool
+			if needUnesc {
+				ok = yield(unesc.Replace(p[:i]))
+			} else {
+				ok = yield(p[:i])
+			}
+			if !ok {
+				return
+			}
+			p = p[i:]
+		}
+	}
+}
+
+// FromPath returns the JSON Pointer that refers to the same location within
+// a JSON document as p refers to within the CUE value decoded from that
+// document.
+//
+// It returns an error if p contains selectors that cannot occur in data,
+// such as definitions, hidden fields or patterns.
+func FromPath(p cue.Path) (string, error) {
+	if err := p.Err(); err != nil {
+		return "", err
+	}
+	sels := p.Selectors()
+	tokens := make([]string, len(sels))
+	for i, sel := range sels {
+		switch {
+		case sel.ConstraintType() == cue.PatternConstraint:
+			return "", fmt.Errorf("cannot convert pattern selector %v in %v to JSON Pointer", sel, p)
+		case sel.LabelType() == cue.StringLabel:
+			tokens[i] = sel.Unquoted()
+		case sel.LabelType() == cue.IndexLabel:
+			tokens[i] = strconv.Itoa(sel.Index())
+		default:
+			return "", fmt.Errorf("cannot convert selector %v in %v to JSON Pointer", sel, p)
+		}
+	}
+	return FromTokens(func(yield func(string) bool) {
+		for _, tok := range tokens {
+			if !yield(tok) {
+				return
+			}
+		}
+	}), nil
+}
+
+// ErrorSelectors returns the selectors of the path of an error, as returned
+// by the Path method of cuelang.org/go/cue/errors.Error.
+func ErrorSelectors(elems []string) ([]cue.Selector, error) {
+	// Error paths are made of selector strings, with list indices as plain
+	// numbers.
+	var sels []cue.Selector
+	for _, elem := range elems {
+		if i, err := strconv.Atoi(elem); err == nil && i >= 0 {
+			sels = append(sels, cue.Index(i))
+			continue
+		}
+		p := cue.ParsePath(elem)
+		if err := p.Err(); err != nil {
+			return nil, err
+		}
+		sels = append(sels, p.Selectors()...)
+	}
+	return sels, nil
+}
@@ -161,6 +161,8 @@ modes: [string]: {
 		".yml":       tagInfo.yaml
 		".toml":      tagInfo.toml
 		".txt":       tagInfo.text
+		".md":        tagInfo.markdown
+		".markdown":  tagInfo.markdown
 		".go":        tagInfo.go
 		".wasm":      tagInfo.binary
 		".proto":     tagInfo.proto
@@ -209,6 +211,11 @@ modes: [string]: {
 		stream: false
 	}
 
+	encodings: markdown: {
+		forms.data
+		stream: false
+	}
+
 	encodings: proto: {
 		forms.schema
 		encoding: "proto"
@@ -329,6 +336,7 @@ tagInfo: {
 	jsonl: encoding:     "jsonl"
 	yaml: encoding:      "yaml"
 	toml: encoding:      "toml"
+	markdown: encoding:  "markdown"
 	proto: encoding:     "proto"
 	textproto: encoding: "textproto"
 	// "binpb":  encodings.binproto
@@ -84,6 +84,7 @@ const (
 	TemplateError            DiagnosticSource = "template"
 	WorkFileError            DiagnosticSource = "go.work file"
 	ConsistencyInfo          DiagnosticSource = "consistency"
+	TagInjection             DiagnosticSource = "tag injection"
 )
 
 // A SuggestedFix represents a suggested fix (for a diagnostic)
@@ -8,7 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -414,16 +414,17 @@ func bestView[V viewDefiner](ctx context.Context, fs file.Source, fh file.Handle
 
 	v := views[0]
 
+	// The module root of the file is the closest directory containing it that
+	// has a cue.mod/module.cue file.
 	fileDir := fh.URI().Dir()
-	fileBase := path.Base(string(fh.URI()))
-	modRoot, err := findRootPattern(ctx, fileDir, fileBase, fs)
+	modFile, err := findRootPattern(ctx, fileDir, filepath.FromSlash("cue.mod/module.cue"), fs)
 	if err != nil {
 		return zero, err
 	}
 
 	// Only if the module root corresponds to that of the view (workspace folder)
 	// do we match.
-	if modRoot == v.definition().root {
+	if modFile != "" && modFile.Dir().Dir() == v.definition().root {
 		return v, nil
 	}
 
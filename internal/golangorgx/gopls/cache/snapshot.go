@@ -160,6 +160,46 @@ type Snapshot struct {
 
 	// pkgIndex is an index of package IDs, for efficient storage of typerefs.
 	pkgIndex *typerefs.PackageIndex
+
+	// memos holds the values computed by Memoize, keyed by the key passed to
+	// it. It is not carried over when the snapshot is cloned.
+	memos map[any]*memo
+}
+
+// A memo holds a value computed by Snapshot.Memoize.
+type memo struct {
+	mu    sync.Mutex
+	done  bool
+	value any
+	err   error
+}
+
+// Memoize returns the result of compute for the given key, calling it at
+// most once per snapshot unless it is interrupted by the cancellation of
+// ctx. It is intended for values that are derived from the files of the
+// whole workspace, which are invalidated by any change.
+func (s *Snapshot) Memoize(ctx context.Context, key any, compute func(context.Context) (any, error)) (any, error) {
+	s.mu.Lock()
+	m := s.memos[key]
+	if m == nil {
+		if s.memos == nil {
+			s.memos = make(map[any]*memo)
+		}
+		m = &memo{}
+		s.memos[key] = m
+	}
+	s.mu.Unlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.done {
+		value, err := compute(ctx)
+		if ctx.Err() != nil {
+			return value, err
+		}
+		m.done, m.value, m.err = true, value, err
+	}
+	return m.value, m.err
 }
 
 var _ memoize.RefCounted = (*Snapshot)(nil) // snapshots are reference-counted
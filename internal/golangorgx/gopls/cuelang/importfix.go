@@ -0,0 +1,170 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cuelang
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/internal/golangorgx/gopls/cache"
+	"cuelang.org/go/internal/golangorgx/gopls/file"
+	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	"cuelang.org/go/internal/golangorgx/tools/event"
+)
+
+// ImportFixes returns quick fixes that add an import for the unresolved
+// references in rng that are used as package qualifiers, such as strings
+// in strings.Join. There is a fix for each package with that qualifier
+// among those offered by import path completion.
+func ImportFixes(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range) ([]protocol.CodeAction, error) {
+	ctx, done := event.Start(ctx, "source.ImportFixes")
+	defer done()
+
+	src, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	filename := fh.URI().Path()
+	f, err := parser.ParseFile(filename, src)
+	if err != nil {
+		return nil, nil
+	}
+	mapper := protocol.NewMapper(fh.URI(), src)
+
+	// Fields declared at the top level of other files of the package are
+	// not resolved by the parser.
+	declared := packageFields(filename, f.PackageName())
+
+	var names []string
+	ast.Walk(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		x, ok := sel.X.(*ast.Ident)
+		if !ok || x.Node != nil || x.Scope != nil || declared[x.Name] || slices.Contains(names, x.Name) {
+			return true
+		}
+		xrng, err := mapper.OffsetRange(x.Pos().Offset(), x.End().Offset())
+		if err != nil {
+			return true
+		}
+		if protocol.ComparePosition(xrng.End, rng.Start) >= 0 &&
+			protocol.ComparePosition(rng.End, xrng.Start) >= 0 {
+			names = append(names, x.Name)
+		}
+		return true
+	}, nil)
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var actions []protocol.CodeAction
+	for _, c := range importCandidates(fh) {
+		if !slices.Contains(names, c.qualifier()) {
+			continue
+		}
+		edit, err := addImportEdit(mapper, f, c.path)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, protocol.CodeAction{
+			Title: fmt.Sprintf("Add import %s", literal.String.Quote(c.path)),
+			Kind:  protocol.QuickFix,
+			Edit: &protocol.WorkspaceEdit{
+				DocumentChanges: protocol.TextEditsToDocumentChanges(fh.URI(), fh.Version(), []protocol.TextEdit{edit}),
+			},
+		})
+	}
+	return actions, nil
+}
+
+// packageFields returns the names of the fields declared at the top level
+// of the files of package pkg in the directory of filename, other than
+// filename itself.
+func packageFields(filename, pkg string) map[string]bool {
+	names := map[string]bool{}
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".cue") || path == filename {
+			continue
+		}
+		f, err := parser.ParseFile(path, nil)
+		if err != nil || f.PackageName() != pkg {
+			continue
+		}
+		for _, d := range f.Decls {
+			if field, ok := d.(*ast.Field); ok {
+				if name, _, err := ast.LabelName(field.Label); err == nil {
+					names[name] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+// addImportEdit returns the edit that adds an import of importPath to f:
+// to its last import declaration if it has parentheses, and in a new
+// import declaration otherwise.
+func addImportEdit(mapper *protocol.Mapper, f *ast.File, importPath string) (protocol.TextEdit, error) {
+	spec := literal.String.Quote(importPath)
+	var offset int
+	var text string
+	var last *ast.ImportDecl
+	var pkg *ast.Package
+	for _, d := range f.Decls {
+		switch x := d.(type) {
+		case *ast.ImportDecl:
+			last = x
+		case *ast.Package:
+			pkg = x
+		}
+	}
+	switch {
+	case last != nil && last.Rparen.IsValid():
+		offset = last.Rparen.Offset()
+		if offset > 0 && mapper.Content[offset-1] == '\n' {
+			text = "\t" + spec + "\n"
+		} else {
+			text = ", " + spec
+		}
+	case last != nil:
+		offset = last.End().Offset()
+		text = "\nimport " + spec
+	case pkg != nil:
+		offset = pkg.End().Offset()
+		text = "\n\nimport " + spec
+	default:
+		text = "import " + spec + "\n\n"
+	}
+	rng, err := mapper.OffsetRange(offset, offset)
+	if err != nil {
+		return protocol.TextEdit{}, err
+	}
+	return protocol.TextEdit{Range: rng, NewText: text}, nil
+}
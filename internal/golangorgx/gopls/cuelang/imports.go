@@ -0,0 +1,324 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cuelang
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/scanner"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/runtime"
+	"cuelang.org/go/internal/cueconfig"
+	"cuelang.org/go/internal/golangorgx/gopls/cache"
+	"cuelang.org/go/internal/golangorgx/gopls/file"
+	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	"cuelang.org/go/internal/golangorgx/tools/event"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/module"
+	_ "cuelang.org/go/pkg" // register the builtin packages
+)
+
+// ImportCompletion returns the completions of the import path at pos, if
+// pos is within the import path of an import declaration. The candidates
+// are the packages of the standard library, those of the module of the
+// file, and those of the dependencies of the module that are present in
+// the module cache. Dependencies that are not in the module cache are
+// completed up to their module path.
+func ImportCompletion(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position) (*protocol.CompletionList, error) {
+	ctx, done := event.Start(ctx, "source.ImportCompletion")
+	defer done()
+
+	src, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	mapper := protocol.NewMapper(fh.URI(), src)
+	offset, err := mapper.PositionOffset(pos)
+	if err != nil {
+		return nil, err
+	}
+	start, ok := importPathAt(fh.URI().Path(), src, offset)
+	if !ok {
+		return nil, nil
+	}
+	prefix := string(src[start:offset])
+	rng, err := mapper.OffsetRange(start, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &protocol.CompletionList{}
+	for _, c := range importCandidates(fh) {
+		if !strings.HasPrefix(c.path, prefix) {
+			continue
+		}
+		list.Items = append(list.Items, protocol.CompletionItem{
+			Label:  c.path,
+			Kind:   protocol.ModuleCompletion,
+			Detail: c.detail,
+			TextEdit: &protocol.TextEdit{
+				Range:   rng,
+				NewText: c.path,
+			},
+		})
+	}
+	return list, nil
+}
+
+// An importCandidate is a package that may be imported.
+type importCandidate struct {
+	path   string
+	detail string // where the package comes from
+}
+
+// qualifier returns the name by which the package is referred to when it
+// is imported without an alias.
+func (c importCandidate) qualifier() string {
+	return module.ParseImportPath(c.path).Qualifier
+}
+
+// importCandidates returns the packages that may be imported by the file
+// fh, sorted by import path: those of the standard library, those of the
+// module of the file, and those of the dependencies of the module that are
+// present in the module cache. Dependencies that are not in the module
+// cache are represented by their module path.
+func importCandidates(fh file.Handle) []importCandidate {
+	var candidates []importCandidate
+	seen := map[string]bool{}
+	add := func(importPath, detail string) {
+		if !seen[importPath] {
+			seen[importPath] = true
+			candidates = append(candidates, importCandidate{importPath, detail})
+		}
+	}
+
+	isTool := strings.HasSuffix(fh.URI().Path(), "_tool.cue")
+	for _, p := range runtime.BuiltinPaths() {
+		// Tool packages can only be used in tool files.
+		if (p == "tool" || strings.HasPrefix(p, "tool/")) && !isTool {
+			continue
+		}
+		add(p, "standard library")
+	}
+
+	fileDir := filepath.Dir(fh.URI().Path())
+	root, mf := findModule(fileDir)
+	if mf != nil {
+		modPath := mf.ModulePath()
+		for _, p := range modulePackages(root, modPath, fileDir) {
+			add(p, modPath)
+		}
+
+		// TODO: cache the packages of dependencies, which do not change.
+		cacheDir, _ := cueconfig.CacheDir(os.Getenv)
+		deps := make([]string, 0, len(mf.Deps))
+		for p := range mf.Deps {
+			deps = append(deps, p)
+		}
+		slices.Sort(deps)
+		for _, p := range deps {
+			mv, err := module.NewVersion(p, mf.Deps[p].Version)
+			if err != nil {
+				continue
+			}
+			detail := mv.String()
+			if dir := extractedModuleDir(cacheDir, mv); dir != "" {
+				for _, p := range modulePackages(dir, mv.BasePath(), "") {
+					add(p, detail)
+				}
+			}
+			add(mv.BasePath(), detail)
+		}
+	}
+	slices.SortFunc(candidates, func(a, b importCandidate) int {
+		return strings.Compare(a.path, b.path)
+	})
+	return candidates
+}
+
+// importPathAt reports whether offset is within the import path of an
+// import declaration in src, in which case it returns the offset at which
+// the import path starts, just after the opening quote. The import path
+// may not yet be terminated.
+func importPathAt(filename string, src []byte, offset int) (start int, ok bool) {
+	var s scanner.Scanner
+	s.Init(token.NewFile(filename, -1, len(src)), src, nil, scanner.DontInsertCommas)
+
+	// Import declarations have the form
+	//
+	//	import "path"
+	//	import name "path"
+	//	import ( "path1", name "path2" )
+	inImport, inBlock := false, false
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF || pos.Offset() >= offset {
+			return 0, false
+		}
+		switch {
+		case tok == token.IDENT && lit == "import" && !inImport:
+			inImport, inBlock = true, false
+			continue
+		case !inImport:
+			continue
+		case tok == token.LPAREN && !inBlock:
+			inBlock = true
+			continue
+		case tok == token.RPAREN && inBlock:
+			inImport = false
+			continue
+		case tok == token.IDENT, tok == token.COMMA && inBlock:
+			continue
+		case tok != token.STRING:
+			inImport = false
+			continue
+		}
+
+		start := pos.Offset() + 1
+		if !strings.HasPrefix(lit, `"`) || strings.HasPrefix(lit, `"""`) {
+			return 0, false
+		}
+		end := pos.Offset() + len(lit)
+		if len(lit) > 1 && strings.HasSuffix(lit, `"`) {
+			end-- // the cursor must be before the closing quote
+		}
+		if offset <= end {
+			return start, !strings.ContainsAny(string(src[start:offset]), "\"\n")
+		}
+		if !inBlock {
+			inImport = false
+		}
+	}
+}
+
+// findModule returns the root directory and module file of the module
+// containing dir, if any.
+func findModule(dir string) (string, *modfile.File) {
+	for {
+		filename := filepath.Join(dir, "cue.mod", "module.cue")
+		if data, err := os.ReadFile(filename); err == nil {
+			mf, err := modfile.ParseNonStrict(data, filename)
+			if err != nil {
+				return "", nil
+			}
+			return dir, mf
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// extractedModuleDir returns the directory holding the contents of the
+// module mv in the module cache rooted at cacheDir, or "" if the module is
+// not completely extracted there.
+//
+// This follows the layout of the cache of package modcache.
+func extractedModuleDir(cacheDir string, mv module.Version) string {
+	if cacheDir == "" {
+		return ""
+	}
+	enc, err := module.EscapePath(mv.BasePath())
+	if err != nil {
+		return ""
+	}
+	encVer, err := module.EscapeVersion(mv.Version())
+	if err != nil {
+		return ""
+	}
+	dir := filepath.Join(cacheDir, "mod", "extract", enc+"@"+encVer)
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	partial := filepath.Join(cacheDir, "mod", "download", enc, "@v", encVer+".partial")
+	if _, err := os.Stat(partial); err == nil {
+		return ""
+	}
+	return dir
+}
+
+// modulePackages returns the import paths of the packages of the module
+// with the given path rooted at root, except for those in the directory
+// exclude. Packages whose name differs from the last element of their
+// directory are qualified with their name.
+func modulePackages(root, modPath, exclude string) []string {
+	var paths []string
+	filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if p != root {
+			name := d.Name()
+			if name == "cue.mod" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(p, "cue.mod")); err == nil {
+				return filepath.SkipDir // a nested module
+			}
+		}
+		if p == exclude {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return nil
+		}
+		dirPath := path.Join(modPath, filepath.ToSlash(rel))
+		implied := module.ParseImportPath(dirPath).Qualifier
+		for _, name := range packageNames(p) {
+			if name == implied {
+				paths = append(paths, dirPath)
+			} else {
+				paths = append(paths, dirPath+":"+name)
+			}
+		}
+		return nil
+	})
+	return paths
+}
+
+// packageNames returns the sorted names of the packages of the CUE files
+// in dir.
+func packageNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".cue") {
+			continue
+		}
+		filename := filepath.Join(dir, e.Name())
+		f, err := parser.ParseFile(filename, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		if name := f.PackageName(); name != "" && name != "_" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,378 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cuelang
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal"
+	"cuelang.org/go/internal/golangorgx/gopls/cache"
+	"cuelang.org/go/internal/golangorgx/gopls/file"
+	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	"cuelang.org/go/internal/golangorgx/tools/event"
+)
+
+// Tag values are injected with the -t flag of the cue command, which is
+// typically run by scripts and tools that the language server knows
+// nothing about. The known invocations of the cue command are those in
+// //cue:generate comments and those in the commands of _tool.cue files,
+// such as
+//
+//	exec.Run & {cmd: ["cue", "export", "-t", "env=prod"]}
+
+// TagDefinition returns the locations at which the tag of the field at
+// pos is injected by a known invocation of the cue command.
+func TagDefinition(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position) ([]protocol.Location, error) {
+	ctx, done := event.Start(ctx, "source.TagDefinition")
+	defer done()
+
+	decl, err := tagAt(fh, pos)
+	if decl == nil || err != nil {
+		return nil, err
+	}
+	sites, err := tagInjections(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return sites[decl.name], nil
+}
+
+// TagHover returns the documentation of the tag of the field at pos.
+func TagHover(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, pos protocol.Position) (*protocol.Hover, error) {
+	ctx, done := event.Start(ctx, "source.TagHover")
+	defer done()
+
+	decl, err := tagAt(fh, pos)
+	if decl == nil || err != nil {
+		return nil, err
+	}
+	sites, err := tagInjections(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "```cue\n%s\n```\n\n", decl.text)
+	fmt.Fprintf(b, "The value of this field is injected with `cue -t %s=value`.", decl.name)
+	if decl.short != "" {
+		fmt.Fprintf(b, " The values `%s` may also be injected by name, as in `-t value`.", decl.short)
+	}
+	switch n := len(sites[decl.name]); n {
+	case 0:
+		b.WriteString("\n\nNo known invocation of the cue command injects this tag.")
+	case 1:
+		b.WriteString("\n\nInjected by 1 known invocation of the cue command.")
+	default:
+		fmt.Fprintf(b, "\n\nInjected by %d known invocations of the cue command.", n)
+	}
+	b.WriteString(" See `cue help injection` for details.")
+
+	return &protocol.Hover{
+		Contents: protocol.MarkupContent{
+			Kind:  protocol.Markdown,
+			Value: b.String(),
+		},
+		Range: decl.rng,
+	}, nil
+}
+
+// TagDiagnostics reports the tags declared in the file fh that are not
+// injected by any known invocation of the cue command. No diagnostics are
+// reported if there are no known invocations at all, as the tags are then
+// presumably injected by tools outside of the module.
+func TagDiagnostics(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle) ([]*cache.Diagnostic, error) {
+	ctx, done := event.Start(ctx, "source.TagDiagnostics")
+	defer done()
+
+	decls, err := fileTags(fh)
+	if len(decls) == 0 || err != nil {
+		return nil, err
+	}
+	sites, err := tagInjections(ctx, snapshot)
+	if len(sites) == 0 || err != nil {
+		return nil, err
+	}
+	var diags []*cache.Diagnostic
+	for _, decl := range decls {
+		if len(sites[decl.name]) > 0 {
+			continue
+		}
+		diags = append(diags, &cache.Diagnostic{
+			URI:      fh.URI(),
+			Range:    decl.rng,
+			Severity: protocol.SeverityInformation,
+			Source:   cache.TagInjection,
+			Message:  fmt.Sprintf("tag %q is never injected by a known invocation of the cue command", decl.name),
+		})
+	}
+	return diags, nil
+}
+
+// A tagDecl is a @tag attribute of a field.
+type tagDecl struct {
+	name  string
+	short string
+	text  string
+	rng   protocol.Range
+	field protocol.Range
+}
+
+// tagAt returns the tag attribute of the field at pos, if any.
+func tagAt(fh file.Handle, pos protocol.Position) (*tagDecl, error) {
+	decls, err := fileTags(fh)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range decls {
+		if protocol.ComparePosition(d.field.Start, pos) <= 0 &&
+			protocol.ComparePosition(pos, d.field.End) <= 0 {
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+// fileTags returns the tag attributes declared in the file fh.
+// Files that cannot be parsed have no tags.
+func fileTags(fh file.Handle) ([]tagDecl, error) {
+	src, err := fh.Content()
+	if err != nil {
+		return nil, err
+	}
+	f, err := parser.ParseFile(fh.URI().Path(), src)
+	if err != nil {
+		return nil, nil
+	}
+	mapper := protocol.NewMapper(fh.URI(), src)
+
+	var decls []tagDecl
+	ast.Walk(f, func(n ast.Node) bool {
+		field, ok := n.(*ast.Field)
+		if !ok {
+			return true
+		}
+		for _, a := range field.Attrs {
+			key, body := a.Split()
+			if key != "tag" {
+				continue
+			}
+			attr := internal.ParseAttrBody(a.Pos().Add(len("@tag(")), body)
+			name, err := attr.String(0)
+			if err != nil || name == "" {
+				continue
+			}
+			short, _, _ := attr.Lookup(1, "short")
+			rng, err := mapper.OffsetRange(a.Pos().Offset(), a.End().Offset())
+			if err != nil {
+				continue
+			}
+			fieldRng, err := mapper.OffsetRange(field.Pos().Offset(), field.End().Offset())
+			if err != nil {
+				continue
+			}
+			decls = append(decls, tagDecl{
+				name:  name,
+				short: short,
+				text:  a.Text,
+				rng:   rng,
+				field: fieldRng,
+			})
+		}
+		return true
+	}, nil)
+	return decls, nil
+}
+
+// tagInjections returns the locations of the tags injected by the known
+// invocations of the cue command within the module, keyed by tag name.
+// The result is computed once per snapshot.
+func tagInjections(ctx context.Context, snapshot *cache.Snapshot) (map[string][]protocol.Location, error) {
+	type tagInjectionsKey struct{}
+	sites, err := snapshot.Memoize(ctx, tagInjectionsKey{}, func(ctx context.Context) (any, error) {
+		return findTagInjections(ctx, snapshot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sites.(map[string][]protocol.Location), nil
+}
+
+// findTagInjections walks the module to find the tags injected by the
+// known invocations of the cue command. See tagInjections.
+func findTagInjections(ctx context.Context, snapshot *cache.Snapshot) (map[string][]protocol.Location, error) {
+	sites := map[string][]protocol.Location{}
+	root := snapshot.View().Root().Path()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (name == "cue.mod" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".cue") {
+			return nil
+		}
+		fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(path))
+		if err != nil {
+			return err
+		}
+		src, err := fh.Content()
+		if err != nil {
+			return nil
+		}
+		f, err := parser.ParseFile(path, src, parser.ParseComments)
+		if err != nil {
+			return nil
+		}
+		mapper := protocol.NewMapper(fh.URI(), src)
+		for _, cmd := range commandLines(f, strings.HasSuffix(name, "_tool.cue")) {
+			for _, w := range injectedTags(cmd) {
+				loc, err := mapper.OffsetLocation(w.offset, w.offset+len(w.text))
+				if err != nil {
+					continue
+				}
+				sites[w.text] = append(sites[w.text], loc)
+			}
+		}
+		return nil
+	})
+	return sites, err
+}
+
+// A word is a command line argument along with its offset in a file.
+type word struct {
+	text   string
+	offset int
+}
+
+// commandLines returns the command lines in the //cue:generate comments
+// of f and, if f is a tool file, in its string literals and lists of string
+// literals.
+func commandLines(f *ast.File, isTool bool) [][]word {
+	var lines [][]word
+	inList := map[*ast.BasicLit]bool{}
+	ast.Walk(f, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CommentGroup:
+			for _, c := range x.List {
+				if text, ok := strings.CutPrefix(c.Text, "//cue:generate "); ok {
+					offset := c.Pos().Offset() + len(c.Text) - len(text)
+					lines = append(lines, fields(text, offset))
+				}
+			}
+
+		case *ast.ListLit:
+			if !isTool {
+				break
+			}
+			var line []word
+			for _, e := range x.Elts {
+				lit, ok := e.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				s, err := literal.Unquote(lit.Value)
+				if err != nil {
+					return true
+				}
+				// Skip the opening quote.
+				offset := lit.Pos().Offset() + strings.IndexAny(lit.Value, `"'`) + 1
+				line = append(line, word{s, offset})
+			}
+			for _, e := range x.Elts {
+				inList[e.(*ast.BasicLit)] = true
+			}
+			lines = append(lines, line)
+
+		case *ast.BasicLit:
+			if !isTool || x.Kind != token.STRING || inList[x] {
+				break
+			}
+			if s, err := literal.Unquote(x.Value); err == nil && !strings.Contains(x.Value, "\\") {
+				offset := x.Pos().Offset() + strings.Index(x.Value, s)
+				lines = append(lines, fields(s, offset))
+			}
+		}
+		return true
+	}, nil)
+	return lines
+}
+
+// fields splits s, which starts at the given offset, around white space.
+func fields(s string, offset int) []word {
+	var words []word
+	for len(s) > 0 {
+		trimmed := strings.TrimLeft(s, " \t\n")
+		offset += len(s) - len(trimmed)
+		s = trimmed
+		n := strings.IndexAny(s, " \t\n")
+		if n < 0 {
+			n = len(s)
+		}
+		if n > 0 {
+			words = append(words, word{s[:n], offset})
+		}
+		s, offset = s[n:], offset+n
+	}
+	return words
+}
+
+// injectedTags returns the names of the tags injected by the cue command
+// line args. Lines that do not run the cue command are ignored.
+func injectedTags(args []word) []word {
+	var tags []word
+	isCUE := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !isCUE {
+			isCUE = arg.text == "cue" || strings.HasSuffix(arg.text, "/cue")
+			continue
+		}
+		var value word
+		switch {
+		case arg.text == "-t" || arg.text == "--inject":
+			if i+1 == len(args) {
+				continue
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg.text, "--inject="):
+			value = word{arg.text[len("--inject="):], arg.offset + len("--inject=")}
+		case strings.HasPrefix(arg.text, "-t="):
+			value = word{arg.text[len("-t="):], arg.offset + len("-t=")}
+		case strings.HasPrefix(arg.text, "-t"):
+			value = word{arg.text[len("-t"):], arg.offset + len("-t")}
+		default:
+			continue
+		}
+		value.text, _, _ = strings.Cut(value.text, "=")
+		if value.text != "" {
+			tags = append(tags, value)
+		}
+	}
+	return tags
+}
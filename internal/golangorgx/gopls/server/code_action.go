@@ -5,12 +5,40 @@
 package server
 
 import (
+	"context"
+	"slices"
+
+	"cuelang.org/go/internal/golangorgx/gopls/cuelang"
 	"cuelang.org/go/internal/golangorgx/gopls/file"
 	"cuelang.org/go/internal/golangorgx/gopls/protocol"
+	"cuelang.org/go/internal/golangorgx/tools/event"
+	"cuelang.org/go/internal/golangorgx/tools/event/tag"
 )
 
 type unit = struct{}
 
+func (s *server) CodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
+	ctx, done := event.Start(ctx, "lsp.Server.codeAction", tag.URI.Of(params.TextDocument.URI))
+	defer done()
+
+	if only := params.Context.Only; len(only) > 0 && !slices.Contains(only, protocol.QuickFix) {
+		return nil, nil
+	}
+
+	fh, snapshot, release, err := s.fileOf(ctx, params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	switch snapshot.FileKind(fh) {
+	case file.CUE:
+		// For now, the only code actions add missing imports.
+		return cuelang.ImportFixes(ctx, snapshot, fh, params.Range)
+	}
+	return nil, nil
+}
+
 func documentChanges(fh file.Handle, edits []protocol.TextEdit) []protocol.DocumentChanges {
 	return protocol.TextEditsToDocumentChanges(fh.URI(), fh.Version(), edits)
 }
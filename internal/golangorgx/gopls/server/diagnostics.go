@@ -18,6 +18,7 @@ import (
 
 	"cuelang.org/go/internal/golangorgx/gopls/cache"
 	"cuelang.org/go/internal/golangorgx/gopls/cache/metadata"
+	"cuelang.org/go/internal/golangorgx/gopls/cuelang"
 	"cuelang.org/go/internal/golangorgx/gopls/file"
 	"cuelang.org/go/internal/golangorgx/gopls/protocol"
 	"cuelang.org/go/internal/golangorgx/gopls/settings"
@@ -275,6 +276,17 @@ func (s *server) diagnose(ctx context.Context, snapshot *cache.Snapshot) (diagMa
 		diagnostics = make(diagMap)
 	)
 
+	for _, o := range snapshot.Overlays() {
+		if snapshot.FileKind(o) != file.CUE {
+			continue
+		}
+		diags, err := cuelang.TagDiagnostics(ctx, snapshot, o)
+		if err != nil {
+			return nil, err
+		}
+		diagnostics[o.URI()] = diags
+	}
+
 	return diagnostics, nil
 }
 
@@ -14,10 +14,6 @@ import (
 	"cuelang.org/go/internal/golangorgx/tools/jsonrpc2"
 )
 
-func (s *server) CodeAction(ctx context.Context, params *protocol.CodeActionParams) ([]protocol.CodeAction, error) {
-	return nil, notImplemented("CodeAction")
-}
-
 func (s *server) CodeLens(ctx context.Context, params *protocol.CodeLensParams) ([]protocol.CodeLens, error) {
 	return nil, notImplemented("CodeLens")
 }
@@ -26,18 +22,10 @@ func (s *server) ColorPresentation(context.Context, *protocol.ColorPresentationP
 	return nil, notImplemented("ColorPresentation")
 }
 
-func (s *server) Completion(ctx context.Context, params *protocol.CompletionParams) (_ *protocol.CompletionList, rerr error) {
-	return nil, notImplemented("Completion")
-}
-
 func (s *server) Declaration(context.Context, *protocol.DeclarationParams) (*protocol.Or_textDocument_declaration, error) {
 	return nil, notImplemented("Declaration")
 }
 
-func (s *server) Definition(ctx context.Context, params *protocol.DefinitionParams) (_ []protocol.Location, rerr error) {
-	return nil, notImplemented("Definition")
-}
-
 func (s *server) Diagnostic(context.Context, *string) (*string, error) {
 	return nil, notImplemented("Diagnostic")
 }
@@ -98,10 +86,6 @@ func (s *server) FoldingRange(ctx context.Context, params *protocol.FoldingRange
 	return nil, notImplemented("FoldingRange")
 }
 
-func (s *server) Hover(ctx context.Context, params *protocol.HoverParams) (_ *protocol.Hover, rerr error) {
-	return nil, notImplemented("Hover")
-}
-
 func (s *server) Implementation(ctx context.Context, params *protocol.ImplementationParams) (_ []protocol.Location, rerr error) {
 	return nil, notImplemented("Implementation")
 }
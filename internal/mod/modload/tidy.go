@@ -152,10 +152,11 @@ func modfileFromRequirements(old *modfile.File, rs *modrequirements.Requirements
 	// want to just copy the entirety of old because that includes
 	// private fields too.
 	mf := &modfile.File{
-		Module:   old.Module,
-		Language: old.Language,
-		Deps:     make(map[string]*modfile.Dep),
-		Source:   old.Source,
+		Module:     old.Module,
+		Language:   old.Language,
+		Deps:       make(map[string]*modfile.Dep),
+		Source:     old.Source,
+		Signatures: old.Signatures,
 	}
 	defaults := rs.DefaultMajorVersions()
 	for _, v := range rs.RootModules() {
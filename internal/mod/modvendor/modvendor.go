@@ -0,0 +1,206 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modvendor implements the vendor directory of a module, which
+// holds a copy of all the module's dependencies so that they can be loaded
+// without access to a registry.
+//
+// The vendor directory is cue.mod/vendor. It holds one directory per
+// module version, named after the module path and version, such as
+// "example.com/foo@v0.1.0", and a manifest file named modules.txt that
+// lists all the vendored module versions, one per line.
+package modvendor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"cuelang.org/go/internal/mod/semver"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/module"
+)
+
+// Dir holds the name of the vendor directory inside cue.mod.
+const Dir = "vendor"
+
+// ManifestFile holds the name of the manifest file inside the vendor
+// directory.
+const ManifestFile = "modules.txt"
+
+const manifestHeader = "# Code generated by cue mod vendor. DO NOT EDIT.\n"
+
+// Fetcher is used to fetch the contents of the modules to vendor.
+type Fetcher interface {
+	Fetch(ctx context.Context, m module.Version) (module.SourceLoc, error)
+}
+
+// Write replaces the contents of the vendor directory dir with the
+// contents of the given module versions, as fetched from reg.
+func Write(ctx context.Context, dir string, reg Fetcher, mods []module.Version) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	var manifest bytes.Buffer
+	manifest.WriteString(manifestHeader)
+	for _, mv := range mods {
+		loc, err := reg.Fetch(ctx, mv)
+		if err != nil {
+			return err
+		}
+		if err := copyModule(filepath.Join(dir, filepath.FromSlash(mv.String())), loc); err != nil {
+			return fmt.Errorf("cannot vendor %v: %v", mv, err)
+		}
+		fmt.Fprintln(&manifest, mv)
+	}
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), manifest.Bytes(), 0o666)
+}
+
+// copyModule copies all the files of the module at loc to the directory dir.
+func copyModule(dir string, loc module.SourceLoc) error {
+	return fs.WalkDir(loc.FS, loc.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, loc.Dir), "/")
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0o777)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		src, err := loc.FS.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o666)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, src); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// Registry implements a module registry that serves the module versions
+// in a vendor directory. It never accesses the network.
+type Registry struct {
+	fsys fs.FS
+	mods map[module.Version]bool
+}
+
+// NewRegistry returns a registry that serves the module versions
+// vendored in fsys, which holds the contents of a vendor directory.
+func NewRegistry(fsys fs.FS) (*Registry, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read vendor manifest: %w", err)
+	}
+	r := &Registry{
+		fsys: fsys,
+		mods: make(map[module.Version]bool),
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mv, err := module.ParseVersion(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module version %q in vendor manifest", line)
+		}
+		r.mods[mv] = true
+	}
+	return r, nil
+}
+
+// CheckDeps checks that the vendored module versions are exactly deps.
+func (r *Registry) CheckDeps(deps []module.Version) error {
+	for _, mv := range deps {
+		if !r.mods[mv] {
+			return fmt.Errorf("dependency %v is not vendored; run 'cue mod vendor'", mv)
+		}
+	}
+	if len(deps) != len(r.mods) {
+		return fmt.Errorf("vendor directory does not match module.cue; run 'cue mod vendor'")
+	}
+	return nil
+}
+
+// Requirements implements [modrequirements.Registry.Requirements] by
+// reading the module file of the vendored module.
+//
+// The dependencies of a tidy module include all the module versions in
+// its build list, so versions that have not been vendored cannot be
+// selected. They are reported as having no requirements.
+func (r *Registry) Requirements(ctx context.Context, mv module.Version) ([]module.Version, error) {
+	if !r.mods[mv] {
+		return nil, nil
+	}
+	loc, err := r.Fetch(ctx, mv)
+	if err != nil {
+		return nil, err
+	}
+	name := path.Join(loc.Dir, "cue.mod", "module.cue")
+	data, err := fs.ReadFile(loc.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := modfile.Parse(data, mv.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse module file from %v: %v", mv, err)
+	}
+	return mf.DepVersions(), nil
+}
+
+// Fetch implements [modpkgload.Registry.Fetch]. It reports an error if mv
+// has not been vendored.
+func (r *Registry) Fetch(ctx context.Context, mv module.Version) (module.SourceLoc, error) {
+	if !r.mods[mv] {
+		return module.SourceLoc{}, fmt.Errorf("module %v is not vendored; run 'cue mod vendor'", mv)
+	}
+	return module.SourceLoc{
+		FS:  r.fsys,
+		Dir: mv.String(),
+	}, nil
+}
+
+// ModuleVersions implements [modload.Registry.ModuleVersions] by
+// returning the vendored versions of the module with the given path.
+func (r *Registry) ModuleVersions(ctx context.Context, mpath string) ([]string, error) {
+	var versions []string
+	for mv := range r.mods {
+		if mv.Path() == mpath || mv.BasePath() == mpath {
+			versions = append(versions, mv.Version())
+		}
+	}
+	semver.Sort(versions)
+	return versions, nil
+}
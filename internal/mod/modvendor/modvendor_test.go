@@ -0,0 +1,85 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modvendor
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/mod/module"
+)
+
+type mapFetcher fstest.MapFS
+
+func (f mapFetcher) Fetch(ctx context.Context, mv module.Version) (module.SourceLoc, error) {
+	return module.SourceLoc{FS: fstest.MapFS(f), Dir: mv.String()}, nil
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	ctx := context.Background()
+	src := mapFetcher{
+		"foo.com@v0.1.0/cue.mod/module.cue": {Data: []byte(`
+module: "foo.com@v0"
+language: version: "v0.8.0"
+deps: "bar.com@v0": v: "v0.2.0"
+`)},
+		"foo.com@v0.1.0/foo.cue": {Data: []byte("package foo\n")},
+		"bar.com@v0.2.0/cue.mod/module.cue": {Data: []byte(`
+module: "bar.com@v0"
+language: version: "v0.8.0"
+`)},
+		"bar.com@v0.2.0/x/x.cue": {Data: []byte("package x\n")},
+	}
+	foo := module.MustParseVersion("foo.com@v0.1.0")
+	bar := module.MustParseVersion("bar.com@v0.2.0")
+	other := module.MustParseVersion("bar.com@v0.1.0")
+
+	dir := t.TempDir()
+	err := Write(ctx, dir, src, []module.Version{bar, foo})
+	qt.Assert(t, qt.IsNil(err))
+
+	fsys := os.DirFS(dir)
+	data, err := fs.ReadFile(fsys, "bar.com@v0.2.0/x/x.cue")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(data), "package x\n"))
+
+	r, err := NewRegistry(fsys)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.IsNil(r.CheckDeps([]module.Version{foo, bar})))
+	qt.Assert(t, qt.ErrorMatches(r.CheckDeps([]module.Version{foo}),
+		`vendor directory does not match module.cue; run 'cue mod vendor'`))
+	qt.Assert(t, qt.ErrorMatches(r.CheckDeps([]module.Version{foo, other}),
+		`dependency bar.com@v0.1.0 is not vendored; run 'cue mod vendor'`))
+
+	reqs, err := r.Requirements(ctx, foo)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.DeepEquals(reqs, []module.Version{bar}))
+
+	reqs, err = r.Requirements(ctx, other)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.HasLen(reqs, 0))
+
+	_, err = r.Fetch(ctx, other)
+	qt.Assert(t, qt.ErrorMatches(err, `module bar.com@v0.1.0 is not vendored; run 'cue mod vendor'`))
+
+	versions, err := r.ModuleVersions(ctx, "bar.com@v0")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.DeepEquals(versions, []string{"v0.2.0"}))
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/cockroachdb/apd/v3"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/internal/core/adt"
+)
+
+// FuncBuiltin returns a Builtin with the given name that calls the Go
+// function fn. The signature of the builtin is derived from the type
+// of fn, which must not be variadic and must return a single value,
+// optionally followed by an error.
+//
+// Parameters may be of any of the following types:
+//
+//	bool, string, []byte, []string
+//	int, int8, int16, int32, int64
+//	uint, uint8, uint16, uint32, uint64
+//	float32, float64, *big.Int, *big.Float, *apd.Decimal
+//	cue.Value, []cue.Value
+//
+// as well as types with one of the above basic types as their underlying
+// type, and any other struct, map, slice, array, pointer or interface
+// type that a cue.Value can be decoded into.
+//
+// The result may be of any type that can be converted to CUE with
+// cue.Context.Encode.
+func FuncBuiltin(name string, fn any) (*Builtin, error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func {
+		return nil, fmt.Errorf("%s: implementation is %T, not a function", name, fn)
+	}
+	if f.IsNil() {
+		return nil, fmt.Errorf("%s: implementation is a nil function", name)
+	}
+	t := f.Type()
+	if t.IsVariadic() {
+		return nil, fmt.Errorf("%s: variadic functions are not supported", name)
+	}
+
+	b := &Builtin{Name: name}
+	getters := make([]argFunc, t.NumIn())
+	for i := range getters {
+		p, ok := paramFor(t.In(i))
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported type %s for parameter %d", name, t.In(i), i+1)
+		}
+		b.Params = append(b.Params, Param{Kind: p.kind})
+		getters[i] = p.get
+	}
+
+	switch {
+	case t.NumOut() == 1 && t.Out(0) != errorType:
+	case t.NumOut() == 2 && t.Out(1) == errorType:
+	default:
+		return nil, fmt.Errorf("%s: must return a single value, optionally followed by an error", name)
+	}
+	kind, ok := resultKind(t.Out(0))
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported result type %s", name, t.Out(0))
+	}
+	b.Result = kind
+
+	b.Func = func(c *CallCtxt) {
+		args := make([]reflect.Value, len(getters))
+		for i, get := range getters {
+			args[i] = get(c, i)
+			if !c.Do() {
+				return
+			}
+		}
+		out := f.Call(args)
+		c.Ret = out[0].Interface()
+		if len(out) == 2 && !out[1].IsNil() {
+			c.Err = out[1].Interface()
+		}
+	}
+	return b, nil
+}
+
+var (
+	errorType = reflect.TypeFor[error]()
+	valueType = reflect.TypeFor[cue.Value]()
+)
+
+// An argFunc returns argument i of a call as a Go value.
+type argFunc func(c *CallCtxt, i int) reflect.Value
+
+// A funcParam describes how to obtain an argument of a given Go type.
+type funcParam struct {
+	kind adt.Kind
+	get  argFunc
+}
+
+func getter[T any](get func(c *CallCtxt, i int) T) argFunc {
+	return func(c *CallCtxt, i int) reflect.Value {
+		return reflect.ValueOf(get(c, i))
+	}
+}
+
+// funcParams holds the parameter types that are converted by a dedicated
+// method of CallCtxt.
+var funcParams = map[reflect.Type]funcParam{
+	reflect.TypeFor[bool]():     {adt.BoolKind, getter((*CallCtxt).Bool)},
+	reflect.TypeFor[string]():   {adt.StringKind, getter((*CallCtxt).String)},
+	reflect.TypeFor[[]byte]():   {adt.BytesKind | adt.StringKind, getter((*CallCtxt).Bytes)},
+	reflect.TypeFor[[]string](): {adt.ListKind, getter((*CallCtxt).StringList)},
+
+	reflect.TypeFor[int]():   {adt.IntKind, getter((*CallCtxt).Int)},
+	reflect.TypeFor[int8]():  {adt.IntKind, getter((*CallCtxt).Int8)},
+	reflect.TypeFor[int16](): {adt.IntKind, getter((*CallCtxt).Int16)},
+	reflect.TypeFor[int32](): {adt.IntKind, getter((*CallCtxt).Int32)},
+	reflect.TypeFor[int64](): {adt.IntKind, getter((*CallCtxt).Int64)},
+
+	reflect.TypeFor[uint]():   {adt.IntKind, getter((*CallCtxt).Uint)},
+	reflect.TypeFor[uint8]():  {adt.IntKind, getter((*CallCtxt).Uint8)},
+	reflect.TypeFor[uint16](): {adt.IntKind, getter((*CallCtxt).Uint16)},
+	reflect.TypeFor[uint32](): {adt.IntKind, getter((*CallCtxt).Uint32)},
+	reflect.TypeFor[uint64](): {adt.IntKind, getter((*CallCtxt).Uint64)},
+
+	reflect.TypeFor[float64]():      {adt.NumberKind, getter((*CallCtxt).Float64)},
+	reflect.TypeFor[*big.Int]():     {adt.IntKind, getter((*CallCtxt).BigInt)},
+	reflect.TypeFor[*big.Float]():   {adt.NumberKind, getter((*CallCtxt).BigFloat)},
+	reflect.TypeFor[*apd.Decimal](): {adt.NumberKind, getter((*CallCtxt).Decimal)},
+
+	valueType:                      {adt.TopKind, getter((*CallCtxt).Value)},
+	reflect.TypeFor[[]cue.Value](): {adt.ListKind, getter((*CallCtxt).List)},
+}
+
+// paramFor reports how to obtain an argument of type t, and whether
+// arguments of that type are supported at all.
+func paramFor(t reflect.Type) (funcParam, bool) {
+	if p, ok := funcParams[t]; ok {
+		return p, true
+	}
+	var kind adt.Kind
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// Named and other basic types are converted from the value for
+		// their underlying type.
+		p := funcParams[basicTypes[t.Kind()]]
+		return funcParam{p.kind, func(c *CallCtxt, i int) reflect.Value {
+			return p.get(c, i).Convert(t)
+		}}, true
+	case reflect.Struct, reflect.Map:
+		kind = adt.StructKind
+	case reflect.Slice, reflect.Array:
+		kind = adt.ListKind
+	case reflect.Pointer, reflect.Interface:
+		kind = adt.TopKind
+	default:
+		return funcParam{}, false
+	}
+	return funcParam{kind, func(c *CallCtxt, i int) reflect.Value {
+		p := reflect.New(t)
+		if err := c.Value(i).Decode(p.Interface()); err != nil {
+			c.Err = err
+		}
+		return p.Elem()
+	}}, true
+}
+
+// basicTypes maps the kinds of basic types to the type in funcParams
+// that values of that kind are converted from.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeFor[bool](),
+	reflect.String:  reflect.TypeFor[string](),
+	reflect.Int:     reflect.TypeFor[int](),
+	reflect.Int8:    reflect.TypeFor[int8](),
+	reflect.Int16:   reflect.TypeFor[int16](),
+	reflect.Int32:   reflect.TypeFor[int32](),
+	reflect.Int64:   reflect.TypeFor[int64](),
+	reflect.Uint:    reflect.TypeFor[uint](),
+	reflect.Uint8:   reflect.TypeFor[uint8](),
+	reflect.Uint16:  reflect.TypeFor[uint16](),
+	reflect.Uint32:  reflect.TypeFor[uint32](),
+	reflect.Uint64:  reflect.TypeFor[uint64](),
+	reflect.Float32: reflect.TypeFor[float64](),
+	reflect.Float64: reflect.TypeFor[float64](),
+}
+
+// resultKind reports the kind of the CUE value that a result of type t
+// converts to, and whether t can be converted at all.
+func resultKind(t reflect.Type) (adt.Kind, bool) {
+	if p, ok := funcParams[t]; ok {
+		return p.kind, true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return adt.BoolKind, true
+	case reflect.String:
+		return adt.StringKind, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return adt.IntKind, true
+	case reflect.Float32, reflect.Float64:
+		return adt.NumberKind, true
+	case reflect.Complex64, reflect.Complex128,
+		reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return 0, false
+	}
+	// Maps, structs, slices, pointers and interfaces may be converted to
+	// a variety of values, including null.
+	return adt.TopKind, true
+}
@@ -22,10 +22,23 @@ import (
 )
 
 func Register(importPath string, p *Package) {
-	f := func(r adt.Runtime) (*adt.Vertex, errors.Error) {
+	runtime.RegisterBuiltin(importPath, PackageFunc(importPath, p))
+}
+
+// PackageFunc returns a function that compiles p as the builtin package
+// with the given import path.
+func PackageFunc(importPath string, p *Package) runtime.PackageFunc {
+	return func(r adt.Runtime) (*adt.Vertex, errors.Error) {
 		ctx := eval.NewContext(r, nil)
+		// Builtin packages are shared by all evaluations and must always
+		// compile, so do not account for them in any memory budget.
+		ctx.Memory = nil
 
 		return p.MustCompile(ctx, importPath), nil
 	}
-	runtime.RegisterBuiltin(importPath, f)
 }
+
+// FromPublic converts a package of type *cuelang.org/go/pkg.Package to
+// a Package for the builtin package with the given import path. It is set
+// by package cuelang.org/go/pkg, which defines that type.
+var FromPublic func(importPath string, p any) (*Package, error)
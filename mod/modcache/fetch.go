@@ -44,9 +44,26 @@ func New(registry *modregistry.Client, dir string) (modload.Registry, error) {
 	}, nil
 }
 
+// NewWithVerifier is like [New] except that verify is called for each
+// module whose contents are downloaded, before they are added to the
+// cache. If verify returns an error, the module is not added to the cache
+// and the error is returned from Fetch.
+//
+// Note that modules that are already present in the cache are not
+// verified again.
+func NewWithVerifier(registry *modregistry.Client, dir string, verify func(ctx context.Context, m *modregistry.Module) error) (modload.Registry, error) {
+	r, err := New(registry, dir)
+	if err != nil {
+		return nil, err
+	}
+	r.(*cache).verify = verify
+	return r, nil
+}
+
 type cache struct {
 	dir              string // typically ${CUE_CACHE_DIR}/mod
 	reg              *modregistry.Client
+	verify           func(ctx context.Context, m *modregistry.Module) error
 	downloadZipCache par.ErrCache[module.Version, string]
 	modFileCache     par.ErrCache[string, []byte]
 }
@@ -223,6 +240,11 @@ func (c *cache) downloadZip1(ctx context.Context, mod module.Version, zipfile st
 	if err != nil {
 		return err
 	}
+	if c.verify != nil {
+		if err := c.verify(ctx, m); err != nil {
+			return fmt.Errorf("cannot verify %v: %v", mod, err)
+		}
+	}
 	r, err := m.GetZip(ctx)
 	if err != nil {
 		return err
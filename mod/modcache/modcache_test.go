@@ -148,6 +148,48 @@ package x
 	fetch(nil)
 }
 
+func TestFetchWithVerifier(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() {
+		RemoveAll(dir)
+	})
+	ctx := context.Background()
+	registryFS, err := txtar.FS(txtar.Parse([]byte(`
+-- example.com_foo_v0.0.1/cue.mod/module.cue --
+module: "example.com/foo@v0"
+language: version: "v0.8.0"
+-- example.com_foo_v0.0.1/example.cue --
+package example
+`)))
+	qt.Assert(t, qt.IsNil(err))
+	client := modregistry.NewClient(newRegistry(t, registryFS))
+	mv := module.MustNewVersion("example.com/foo", "v0.0.1")
+
+	var verified []module.Version
+	verifyErr := fmt.Errorf("bad signature")
+	verify := func(ctx context.Context, m *modregistry.Module) error {
+		qt.Check(t, qt.Not(qt.Equals(m.ManifestDigest(), "")))
+		verified = append(verified, m.Version())
+		return verifyErr
+	}
+	cr, err := NewWithVerifier(client, dir, verify)
+	qt.Assert(t, qt.IsNil(err))
+	_, err = cr.Fetch(ctx, mv)
+	qt.Assert(t, qt.ErrorMatches(err, `cannot verify example.com/foo@v0.0.1: bad signature`))
+	qt.Assert(t, qt.DeepEquals(verified, []module.Version{mv}))
+
+	// Once verified, the module is added to the cache
+	// and not verified again.
+	verifyErr = nil
+	cr, err = NewWithVerifier(client, dir, verify)
+	qt.Assert(t, qt.IsNil(err))
+	_, err = cr.Fetch(ctx, mv)
+	qt.Assert(t, qt.IsNil(err))
+	_, err = cr.Fetch(ctx, mv)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.DeepEquals(verified, []module.Version{mv, mv}))
+}
+
 func fsSub(fsys fs.FS, sub string) fs.FS {
 	fsys, err := fs.Sub(fsys, sub)
 	if err != nil {
@@ -0,0 +1,134 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"cuelabs.dev/go/oci/ociregistry/ociauth"
+)
+
+// A CredentialHelper provides credentials for registry hosts on demand,
+// for example by fetching short-lived tokens from a cloud provider.
+type CredentialHelper interface {
+	// Credentials returns the credentials to use for the given registry
+	// host, such as "registry.example.com" or "localhost:5000".
+	// If it has no credentials for the host, it should return the zero
+	// Credentials and a nil error.
+	Credentials(host string) (Credentials, error)
+}
+
+// Credentials holds the credentials for a registry. At most one of
+// AccessToken, RefreshToken, or Username and Password should be set.
+type Credentials struct {
+	// AccessToken holds a bearer token to send to the registry.
+	AccessToken string
+
+	// RefreshToken holds a token that can be exchanged for an access
+	// token with the registry's token server.
+	RefreshToken string
+
+	// Username and Password hold credentials for basic auth.
+	Username string
+	Password string
+}
+
+// ExecCredentialHelper returns a [CredentialHelper] that runs the given
+// program to obtain credentials, using the same protocol as the Docker
+// credential helpers, so that any of those can be used directly.
+//
+// The program is run with the single argument "get" and the registry
+// host on its standard input. It must print a JSON object with the
+// fields "Username" and "Secret". A username of "<token>" means that the
+// secret is a refresh token; otherwise the secret is a password.
+// If the program fails with the message "credentials not found in native
+// keychain", there are no credentials for the host.
+//
+// The program is run with the environment variables in env, or the
+// current process's environment if env is nil.
+func ExecCredentialHelper(program string, env []string) CredentialHelper {
+	return &execCredentialHelper{
+		program: program,
+		env:     env,
+	}
+}
+
+type execCredentialHelper struct {
+	program string
+	env     []string
+}
+
+func (h *execCredentialHelper) Credentials(host string) (Credentials, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(h.program, "get")
+	cmd.Stdin = strings.NewReader(host)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = h.env
+	if err := cmd.Run(); err != nil {
+		if !errors.As(err, new(*exec.ExitError)) {
+			return Credentials{}, fmt.Errorf("cannot run credential helper: %v", err)
+		}
+		// Docker credential helpers print their errors to stdout,
+		// but others print them to stderr.
+		msg := strings.TrimSpace(stdout.String() + stderr.String())
+		if msg == "credentials not found in native keychain" {
+			return Credentials{}, nil
+		}
+		return Credentials{}, fmt.Errorf("credential helper %s failed for %s: %s", h.program, host, msg)
+	}
+	var creds struct {
+		Username string
+		Secret   string
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return Credentials{}, fmt.Errorf("invalid output from credential helper %s: %v", h.program, err)
+	}
+	if creds.Username == "<token>" {
+		return Credentials{RefreshToken: creds.Secret}, nil
+	}
+	return Credentials{
+		Username: creds.Username,
+		Password: creds.Secret,
+	}, nil
+}
+
+// helperAuthConfig implements [ociauth.Config] by consulting a credential
+// helper before falling back to another configuration.
+type helperAuthConfig struct {
+	helper CredentialHelper
+	base   ociauth.Config
+}
+
+func (c *helperAuthConfig) EntryForRegistry(host string) (ociauth.ConfigEntry, error) {
+	creds, err := c.helper.Credentials(host)
+	if err != nil {
+		return ociauth.ConfigEntry{}, err
+	}
+	if creds != (Credentials{}) {
+		return ociauth.ConfigEntry{
+			AccessToken:  creds.AccessToken,
+			RefreshToken: creds.RefreshToken,
+			Username:     creds.Username,
+			Password:     creds.Password,
+		}, nil
+	}
+	return c.base.EntryForRegistry(host)
+}
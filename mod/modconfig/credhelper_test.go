@@ -0,0 +1,102 @@
+package modconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-quicktest/qt"
+	"golang.org/x/tools/txtar"
+
+	"cuelang.org/go/internal/registrytest"
+	"cuelang.org/go/mod/modcache"
+	"cuelang.org/go/mod/module"
+)
+
+type credentialHelperFunc func(host string) (Credentials, error)
+
+func (f credentialHelperFunc) Credentials(host string) (Credentials, error) {
+	return f(host)
+}
+
+func TestCredentialHelper(t *testing.T) {
+	fsys, err := txtar.FS(txtar.Parse([]byte(`
+-- auth.json --
+{
+	"username": "bob",
+	"password": "somePassword"
+}
+-- foo.example_v0.0.1/cue.mod/module.cue --
+module: "foo.example@v0"
+language: version: "v0.8.0"
+`)))
+	qt.Assert(t, qt.IsNil(err))
+	r, err := registrytest.New(fsys, "")
+	qt.Assert(t, qt.IsNil(err))
+	defer r.Close()
+
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("CUE_CONFIG_DIR", dir)
+	t.Setenv("CUE_REGISTRY", r.Host()+"+insecure")
+	cacheDir := filepath.Join(dir, "cache")
+	t.Setenv("CUE_CACHE_DIR", cacheDir)
+	t.Cleanup(func() {
+		modcache.RemoveAll(cacheDir)
+	})
+
+	var hosts []string
+	reg, err := NewRegistry(&Config{
+		CredentialHelper: credentialHelperFunc(func(host string) (Credentials, error) {
+			hosts = append(hosts, host)
+			return Credentials{Username: "bob", Password: "somePassword"}, nil
+		}),
+	})
+	qt.Assert(t, qt.IsNil(err))
+	_, err = reg.Requirements(context.Background(), module.MustNewVersion("foo.example@v0", "v0.0.1"))
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Not(qt.HasLen(hosts, 0)))
+	qt.Assert(t, qt.Equals(hosts[0], r.Host()))
+
+	// Without credentials, access is denied.
+	modcache.RemoveAll(cacheDir)
+	reg, err = NewRegistry(nil)
+	qt.Assert(t, qt.IsNil(err))
+	_, err = reg.Requirements(context.Background(), module.MustNewVersion("foo.example@v0", "v0.0.1"))
+	qt.Assert(t, qt.ErrorMatches(err, `.*401 Unauthorized.*`))
+}
+
+func TestExecCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on Windows")
+	}
+	dir := t.TempDir()
+	helper := filepath.Join(dir, "helper")
+	err := os.WriteFile(helper, []byte(`#!/bin/sh
+read host
+case "$host" in
+token.example) echo '{"Username": "<token>", "Secret": "tok"}' ;;
+basic.example) echo '{"Username": "bob", "Secret": "pw"}' ;;
+*) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`), 0o777)
+	qt.Assert(t, qt.IsNil(err))
+
+	h := ExecCredentialHelper(helper, nil)
+	creds, err := h.Credentials("token.example")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(creds, Credentials{RefreshToken: "tok"}))
+
+	creds, err = h.Credentials("basic.example")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(creds, Credentials{Username: "bob", Password: "pw"}))
+
+	creds, err = h.Credentials("other.example")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(creds, Credentials{}))
+
+	_, err = ExecCredentialHelper(filepath.Join(dir, "missing"), nil).Credentials("other.example")
+	qt.Assert(t, qt.ErrorMatches(err, `cannot run credential helper: .*`))
+}
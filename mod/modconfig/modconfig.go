@@ -78,6 +78,25 @@ type Config struct {
 	// that's added in each outgoing HTTP request.
 	// If it's empty, it defaults to "cuelang.org/go".
 	ClientType string
+
+	// CredentialHelper, if non-nil, is used to obtain credentials for
+	// registry hosts that have no login from "cue login".
+	// Its credentials take precedence over those in Docker's
+	// configuration.
+	//
+	// If it's nil and $CUE_CREDENTIAL_HELPER is set, the program it names
+	// is used via [ExecCredentialHelper].
+	CredentialHelper CredentialHelper
+
+	// VerifyModule, if non-nil, is called by the registry returned by
+	// [NewRegistry] for each module that it downloads, before the module
+	// is added to the cache. It is passed the location of the module in
+	// its registry and the digest of the module's manifest, which
+	// together identify the artifact that was published.
+	// If it returns an error, the download fails.
+	//
+	// Modules that are already in the cache are not verified again.
+	VerifyModule func(ctx context.Context, mv module.Version, loc HostLocation, manifestDigest ociregistry.Digest) error
 }
 
 // NewResolver returns an implementation of [modregistry.Resolver]
@@ -298,13 +317,26 @@ func (t *cueLoginsTransport) init() error {
 
 func (t *cueLoginsTransport) _init() error {
 	// If a registry was authenticated via `cue login`, use that.
-	// If not, fall back to authentication via Docker's config.json.
+	// If not, fall back to the credential helper, if any,
+	// and then to authentication via Docker's config.json.
 	// Note that the order below is backwards, since we layer interfaces.
 
-	config, err := ociauth.LoadWithEnv(nil, t.cfg.Env)
+	configFile, err := ociauth.LoadWithEnv(nil, t.cfg.Env)
 	if err != nil {
 		return fmt.Errorf("cannot load OCI auth configuration: %v", err)
 	}
+	// config is an interface, as it may be wrapped below.
+	config := ociauth.Config(configFile)
+	helper := t.cfg.CredentialHelper
+	if program := t.getenv("CUE_CREDENTIAL_HELPER"); helper == nil && program != "" {
+		helper = ExecCredentialHelper(program, t.cfg.Env)
+	}
+	if helper != nil {
+		config = &helperAuthConfig{
+			helper: helper,
+			base:   config,
+		}
+	}
 	t.transport = ociauth.NewStdTransport(ociauth.StdTransportParams{
 		Config:    config,
 		Transport: t.cfg.Transport,
@@ -342,7 +374,18 @@ func NewRegistry(cfg *Config) (Registry, error) {
 	if err != nil {
 		return nil, err
 	}
-	return modcache.New(modregistry.NewClientWithResolver(resolver), cacheDir)
+	client := modregistry.NewClientWithResolver(resolver)
+	if cfg.VerifyModule == nil {
+		return modcache.New(client, cacheDir)
+	}
+	return modcache.NewWithVerifier(client, cacheDir, func(ctx context.Context, m *modregistry.Module) error {
+		mv := m.Version()
+		loc, ok := resolver.ResolveToLocation(mv.BasePath(), mv.Version())
+		if !ok {
+			return fmt.Errorf("cannot resolve %v to registry", mv)
+		}
+		return cfg.VerifyModule(ctx, mv, loc, m.ManifestDigest())
+	})
 }
 
 func getenvFunc(env []string) func(string) string {
@@ -53,12 +53,13 @@ type File struct {
 	// Use the [File.QualifiedModule] method to obtain a module
 	// path that's always qualified. See also the
 	// [File.ModulePath] and [File.MajorVersion] methods.
-	Module   string                    `json:"module"`
-	Language *Language                 `json:"language,omitempty"`
-	Source   *Source                   `json:"source,omitempty"`
-	Deps     map[string]*Dep           `json:"deps,omitempty"`
-	Custom   map[string]map[string]any `json:"custom,omitempty"`
-	versions []module.Version
+	Module     string                    `json:"module"`
+	Language   *Language                 `json:"language,omitempty"`
+	Source     *Source                   `json:"source,omitempty"`
+	Deps       map[string]*Dep           `json:"deps,omitempty"`
+	Signatures []*SignaturePolicy        `json:"signatures,omitempty"`
+	Custom     map[string]map[string]any `json:"custom,omitempty"`
+	versions   []module.Version
 	// defaultMajorVersions maps from module base path to the
 	// major version default for that path.
 	defaultMajorVersions map[string]string
@@ -125,6 +126,43 @@ func (src *Source) Validate() error {
 	return fmt.Errorf("unrecognized source kind %q", src.Kind)
 }
 
+// SignaturePolicy describes the signatures that are
+// required of a set of modules.
+type SignaturePolicy struct {
+	// Modules holds the module paths, without major version,
+	// that the policy applies to. Each path also matches all
+	// module paths below it.
+	Modules []string `json:"modules"`
+	// Identity holds the identity of the signer.
+	Identity string `json:"identity"`
+	// Issuer holds the OpenID Connect issuer that authenticated
+	// the signer.
+	Issuer string `json:"issuer"`
+}
+
+// Matches reports whether the policy applies to the module
+// with the given path, which should not contain a major version.
+func (p *SignaturePolicy) Matches(mpath string) bool {
+	for _, m := range p.Modules {
+		if rest, ok := strings.CutPrefix(mpath, m); ok && (rest == "" || rest[0] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+// SignaturePolicy returns the first signature policy in f that
+// applies to the module with the given path, which should not contain
+// a major version, or nil if there is none.
+func (f *File) SignaturePolicy(mpath string) *SignaturePolicy {
+	for _, p := range f.Signatures {
+		if p.Matches(mpath) {
+			return p
+		}
+	}
+	return nil
+}
+
 // Format returns a formatted representation of f
 // in CUE syntax.
 func (f *File) Format() ([]byte, error) {
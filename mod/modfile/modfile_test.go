@@ -140,6 +140,49 @@ language: version: "v0.8.6"
 source: kind: "git"
 `,
 	wantError: `invalid module.cue file: source field is not allowed at this language version; need at least v0.9.0-alpha.0`,
+}, {
+	testName: "WithSignatures",
+	parse:    Parse,
+	data: `
+module: "foo.com/bar@v0"
+language: version: "v0.12.0"
+source: kind: "self"
+signatures: [{
+	modules: ["example.com/org"]
+	identity: "release@example.com"
+	issuer: "https://accounts.google.com"
+}]
+`,
+	want: &File{
+		Language: &Language{
+			Version: "v0.12.0",
+		},
+		Module: "foo.com/bar@v0",
+		Source: &Source{
+			Kind: "self",
+		},
+		Signatures: []*SignaturePolicy{{
+			Modules:  []string{"example.com/org"},
+			Identity: "release@example.com",
+			Issuer:   "https://accounts.google.com",
+		}},
+	},
+	wantDefaults: map[string]string{
+		"foo.com/bar": "v0",
+	},
+}, {
+	testName: "WithEarlierVersionAndSignatures",
+	parse:    Parse,
+	data: `
+module: "foo.com/bar@v0"
+language: version: "v0.11.0"
+signatures: [{
+	modules: ["example.com/org"]
+	identity: "release@example.com"
+	issuer: "https://accounts.google.com"
+}]
+`,
+	wantError: `invalid module.cue file: signatures field is not allowed at this language version; need at least v0.12.0`,
 }, {
 	testName: "AmbiguousDefaults",
 	parse:    Parse,
@@ -544,6 +587,24 @@ func TestEarliestClosedSchemaVersion(t *testing.T) {
 	qt.Assert(t, qt.Equals(EarliestClosedSchemaVersion(), "v0.8.0-alpha.0"))
 }
 
+func TestSignaturePolicy(t *testing.T) {
+	f := &File{
+		Signatures: []*SignaturePolicy{{
+			Modules:  []string{"example.com/org", "other.com"},
+			Identity: "a",
+		}, {
+			Modules:  []string{"example.com"},
+			Identity: "b",
+		}},
+	}
+	qt.Assert(t, qt.Equals(f.SignaturePolicy("example.com/org").Identity, "a"))
+	qt.Assert(t, qt.Equals(f.SignaturePolicy("example.com/org/foo").Identity, "a"))
+	qt.Assert(t, qt.Equals(f.SignaturePolicy("other.com").Identity, "a"))
+	qt.Assert(t, qt.Equals(f.SignaturePolicy("example.com/organization").Identity, "b"))
+	qt.Assert(t, qt.IsNil(f.SignaturePolicy("example.comx")))
+	qt.Assert(t, qt.IsNil(f.SignaturePolicy("unrelated.org/foo")))
+}
+
 func parseVersions(vs ...string) []module.Version {
 	vvs := make([]module.Version, 0, len(vs))
 	for _, v := range vs {
@@ -51,6 +51,13 @@ versions: "v0.8.0-alpha.0": {
 }
 
 versions: "v0.9.0-alpha.0": {
+	versions["v0.12.0"]
+
+	// The signatures field was added in v0.12.0.
+	#File: signatures?: _errorSignaturesFieldRequiredVersion
+}
+
+versions: "v0.12.0": {
 	#File: {
 		// module indicates the module's path.
 		module?: #Module | ""
@@ -72,6 +79,12 @@ versions: "v0.9.0-alpha.0": {
 		// deps holds dependency information for modules, keyed by module path.
 		deps?: [#Module]: #Dep
 
+		// signatures holds policies for verifying the signatures of
+		// dependencies when they are downloaded. A dependency matched by
+		// a policy must have been signed with sigstore by the given
+		// identity, for example with "cue mod publish --sign".
+		signatures?: [...#SignaturePolicy]
+
 		// custom holds arbitrary data intended for use by third-party tools.
 		// Each field at the top level represents a tooling namespace,
 		// conventionally a module or domain name. Data migrated from legacy
@@ -116,6 +129,25 @@ versions: "v0.9.0-alpha.0": {
 		#Dep: v!: #Semver
 	}
 
+	// #SignaturePolicy describes the signatures required of a set of
+	// modules.
+	#SignaturePolicy: {
+		// modules holds the module paths that the policy applies to,
+		// without major version suffixes. A path also matches all
+		// module paths below it, so "example.com/org" applies to
+		// "example.com/org/foo".
+		modules!: [...string]
+
+		// identity holds the identity of the signer recorded in the
+		// signing certificate, such as an email address or the URL of
+		// a CI workflow.
+		identity!: string
+
+		// issuer holds the OpenID Connect issuer that authenticated
+		// the signer, such as "https://accounts.google.com".
+		issuer!: string
+	}
+
 	// #Source describes a source of truth for a module's content.
 	#Source: {
 		// kind specifies the kind of source.
@@ -145,3 +177,6 @@ versions: "v0.9.0-alpha.0": {
 
 //error: source field is not allowed at this language version; need at least v0.9.0-alpha.0
 let _errorSourceFieldRequiredVersion = 1 & 2
+
+//error: signatures field is not allowed at this language version; need at least v0.12.0
+let _errorSignaturesFieldRequiredVersion = 1 & 2
@@ -0,0 +1,210 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package modoci provides a module registry that reads CUE modules
+// directly from OCI artifacts referenced by the digest of their manifest.
+//
+// Unlike the registry returned by [modconfig.NewRegistry], the contents
+// of a module are never extracted to the module cache: they are read into
+// memory, checked against their digests and presented to the loader as is.
+// This makes loads hermetic and content-addressed, as is typically
+// required by build systems such as Bazel.
+//
+// WARNING: THIS PACKAGE IS EXPERIMENTAL.
+// ITS API MAY CHANGE AT ANY TIME.
+package modoci
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"cuelabs.dev/go/oci/ociregistry"
+
+	"cuelang.org/go/internal/mod/semver"
+	"cuelang.org/go/internal/par"
+	"cuelang.org/go/mod/modconfig"
+	"cuelang.org/go/mod/modfile"
+	"cuelang.org/go/mod/modregistry"
+	"cuelang.org/go/mod/module"
+	"cuelang.org/go/mod/modzip"
+)
+
+// Artifact holds the location of the OCI artifact containing
+// a module version.
+type Artifact struct {
+	// Registry holds the registry containing the artifact.
+	Registry ociregistry.Interface
+
+	// Repository holds the repository within Registry.
+	Repository string
+
+	// Digest holds the digest of the artifact's manifest.
+	Digest ociregistry.Digest
+}
+
+// NewRegistry returns a registry that provides exactly the given module
+// versions, each read from its associated artifact. The result can be
+// used as the Registry field of [cuelang.org/go/cue/load.Config].
+//
+// The contents of each artifact are verified against the digests recorded
+// in its manifest, and the manifest itself is verified against the digest
+// in the artifact, so the loaded modules cannot differ from the ones that
+// were pinned.
+func NewRegistry(modules map[module.Version]Artifact) modconfig.Registry {
+	return &registry{
+		modules: modules,
+	}
+}
+
+type registry struct {
+	modules   map[module.Version]Artifact
+	manifests par.ErrCache[module.Version, *ociregistry.Manifest]
+	zips      par.ErrCache[module.Version, *zip.Reader]
+}
+
+// Requirements implements [modconfig.Registry.Requirements].
+func (r *registry) Requirements(ctx context.Context, mv module.Version) ([]module.Version, error) {
+	m, err := r.manifest(ctx, mv)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.readBlob(ctx, mv, m.Layers[1])
+	if err != nil {
+		return nil, err
+	}
+	mf, err := modfile.Parse(data, mv.String())
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse module file from %v: %v", mv, err)
+	}
+	return mf.DepVersions(), nil
+}
+
+// Fetch implements [modconfig.Registry.Fetch]. The file system of the
+// returned location is backed by the module's zip archive held in memory.
+func (r *registry) Fetch(ctx context.Context, mv module.Version) (module.SourceLoc, error) {
+	z, err := r.zips.Do(mv, func() (*zip.Reader, error) {
+		m, err := r.manifest(ctx, mv)
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.readBlob(ctx, mv, m.Layers[0])
+		if err != nil {
+			return nil, err
+		}
+		z, _, _, err := modzip.CheckZip(mv, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("module %v: %v", mv, err)
+		}
+		return z, nil
+	})
+	if err != nil {
+		return module.SourceLoc{}, err
+	}
+	return module.SourceLoc{
+		FS:  z,
+		Dir: ".",
+	}, nil
+}
+
+// ModuleVersions implements [modconfig.Registry.ModuleVersions].
+// It only reports the versions that were passed to [NewRegistry].
+func (r *registry) ModuleVersions(ctx context.Context, mpath string) ([]string, error) {
+	var versions []string
+	for mv := range r.modules {
+		if mv.Path() == mpath || mv.BasePath() == mpath {
+			versions = append(versions, mv.Version())
+		}
+	}
+	semver.Sort(versions)
+	return slices.Compact(versions), nil
+}
+
+// manifest returns the verified manifest of the artifact holding mv.
+func (r *registry) manifest(ctx context.Context, mv module.Version) (*ociregistry.Manifest, error) {
+	return r.manifests.Do(mv, func() (*ociregistry.Manifest, error) {
+		a, ok := r.modules[mv]
+		if !ok {
+			return nil, fmt.Errorf("module %v: %w", mv, modregistry.ErrNotFound)
+		}
+		if err := a.Digest.Validate(); err != nil {
+			return nil, fmt.Errorf("module %v: invalid digest %q: %v", mv, a.Digest, err)
+		}
+		rd, err := a.Registry.GetManifest(ctx, a.Repository, a.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("module %v: %w", mv, err)
+		}
+		defer rd.Close()
+		data, err := io.ReadAll(rd)
+		if err != nil {
+			return nil, fmt.Errorf("module %v: %v", mv, err)
+		}
+		if got := a.Digest.Algorithm().FromBytes(data); got != a.Digest {
+			return nil, fmt.Errorf("module %v: manifest has digest %s, want %s", mv, got, a.Digest)
+		}
+
+		// Let the registry client check that the manifest describes a
+		// module. The location is only used for reading blobs, which
+		// we do ourselves.
+		client := modregistry.NewClientWithResolver(resolver{a})
+		if _, err := client.GetModuleWithManifest(mv, data, rd.Descriptor().MediaType); err != nil {
+			return nil, err
+		}
+		var m ociregistry.Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("module %v: cannot decode manifest: %v", mv, err)
+		}
+		return &m, nil
+	})
+}
+
+// readBlob reads the blob described by desc from the artifact holding mv
+// and checks that its contents match the digest in desc.
+func (r *registry) readBlob(ctx context.Context, mv module.Version, desc ociregistry.Descriptor) ([]byte, error) {
+	a := r.modules[mv]
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("module %v: invalid blob digest %q: %v", mv, desc.Digest, err)
+	}
+	rd, err := a.Registry.GetBlob(ctx, a.Repository, desc.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("module %v: %w", mv, err)
+	}
+	defer rd.Close()
+	data, err := io.ReadAll(rd)
+	if err != nil {
+		return nil, fmt.Errorf("module %v: %v", mv, err)
+	}
+	if got := desc.Digest.Algorithm().FromBytes(data); got != desc.Digest {
+		return nil, fmt.Errorf("module %v: blob has digest %s, want %s", mv, got, desc.Digest)
+	}
+	return data, nil
+}
+
+// resolver implements [modregistry.Resolver] by resolving all modules
+// to a single artifact.
+type resolver struct {
+	a Artifact
+}
+
+func (r resolver) ResolveToRegistry(mpath, vers string) (modregistry.RegistryLocation, error) {
+	return modregistry.RegistryLocation{
+		Registry:   r.a.Registry,
+		Repository: r.a.Repository,
+		Tag:        vers,
+	}, nil
+}
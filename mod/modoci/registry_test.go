@@ -0,0 +1,155 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modoci_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cuelabs.dev/go/oci/ociregistry"
+	"cuelabs.dev/go/oci/ociregistry/ocimem"
+	"github.com/go-quicktest/qt"
+	"golang.org/x/tools/txtar"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/internal/registrytest"
+	"cuelang.org/go/mod/modoci"
+	"cuelang.org/go/mod/module"
+)
+
+const testModules = `
+-- example.com_v0.1.0/cue.mod/module.cue --
+module: "example.com@v0"
+language: version: "v0.8.0"
+deps: "dep.org@v0": v: "v0.2.0"
+-- example.com_v0.1.0/x/x.cue --
+package x
+
+import "dep.org/y"
+
+x: y.y + 1
+-- dep.org_v0.2.0/cue.mod/module.cue --
+module: "dep.org@v0"
+language: version: "v0.8.0"
+-- dep.org_v0.2.0/y/y.cue --
+package y
+
+y: 41
+`
+
+const testMain = `
+-- cue.mod/module.cue --
+module: "main.org@v0"
+language: version: "v0.8.0"
+deps: {
+	"example.com@v0": v: "v0.1.0"
+	"dep.org@v0": v: "v0.2.0"
+}
+-- main.cue --
+package main
+
+import "example.com/x"
+
+out: x.x
+`
+
+// upload uploads the test modules to a new in-memory registry and returns
+// the artifacts holding them.
+func upload(t *testing.T) map[module.Version]modoci.Artifact {
+	ctx := context.Background()
+	r := ocimem.New()
+	fsys, err := txtar.FS(txtar.Parse([]byte(testModules)))
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.IsNil(registrytest.Upload(ctx, r, fsys)))
+
+	modules := map[module.Version]modoci.Artifact{}
+	for _, v := range []string{"example.com@v0.1.0", "dep.org@v0.2.0"} {
+		mv := module.MustParseVersion(v)
+		rd, err := r.GetTag(ctx, mv.BasePath(), mv.Version())
+		qt.Assert(t, qt.IsNil(err))
+		rd.Close()
+		modules[mv] = modoci.Artifact{
+			Registry:   r,
+			Repository: mv.BasePath(),
+			Digest:     rd.Descriptor().Digest,
+		}
+	}
+	return modules
+}
+
+func loadMain(t *testing.T, modules map[module.Version]modoci.Artifact) (string, error) {
+	dir := t.TempDir()
+	cacheDir := t.TempDir()
+	cfg := &load.Config{
+		Dir:      dir,
+		Overlay:  map[string]load.Source{},
+		Registry: modoci.NewRegistry(modules),
+		Env:      []string{"CUE_CACHE_DIR=" + cacheDir},
+	}
+	for _, f := range txtar.Parse([]byte(testMain)).Files {
+		cfg.Overlay[filepath.Join(dir, f.Name)] = load.FromBytes(f.Data)
+	}
+	insts := load.Instances([]string{"."}, cfg)
+	qt.Assert(t, qt.HasLen(insts, 1))
+
+	// Nothing may have been written to the module cache.
+	entries, err := os.ReadDir(cacheDir)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.HasLen(entries, 0))
+
+	if err := insts[0].Err; err != nil {
+		return "", err
+	}
+	v := cuecontext.New().BuildInstance(insts[0])
+	return fmt.Sprint(v.LookupPath(cue.ParsePath("out"))), v.Err()
+}
+
+func TestLoad(t *testing.T) {
+	got, err := loadMain(t, upload(t))
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(got, "42"))
+}
+
+func TestDigestMismatch(t *testing.T) {
+	modules := upload(t)
+	other := modules[module.MustParseVersion("example.com@v0.1.0")]
+	mv := module.MustParseVersion("dep.org@v0.2.0")
+	a := modules[mv]
+	r := a.Registry
+	// Serve the manifest of another module in place of the requested one.
+	a.Registry = &ociregistry.Funcs{
+		GetManifest_: func(ctx context.Context, repo string, _ ociregistry.Digest) (ociregistry.BlobReader, error) {
+			return r.GetManifest(ctx, other.Repository, other.Digest)
+		},
+	}
+	modules[mv] = a
+	_, err := loadMain(t, modules)
+	qt.Assert(t, qt.ErrorMatches(err, `(?s).*cannot fetch dep.org@v0.2.0: module dep.org@v0.2.0: manifest has digest sha256:[0-9a-f]+, want `+string(a.Digest)))
+}
+
+func TestModuleVersions(t *testing.T) {
+	reg := modoci.NewRegistry(upload(t))
+	versions, err := reg.ModuleVersions(context.Background(), "dep.org@v0")
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.DeepEquals(versions, []string{"v0.2.0"}))
+
+	_, err = reg.Fetch(context.Background(), module.MustParseVersion("dep.org@v0.3.0"))
+	qt.Assert(t, qt.ErrorMatches(err, `module dep.org@v0.3.0: module not found`))
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/internal/core/eval"
+	"cuelang.org/go/internal/core/runtime"
+	internalpkg "cuelang.org/go/internal/pkg"
+	"cuelang.org/go/mod/module"
+)
+
+// A Package describes a builtin package implemented in Go, for use with
+// [Register] or [cuelang.org/go/cue/cuecontext.BuiltinPackage].
+type Package struct {
+	// Funcs maps the names of the functions of the package to their Go
+	// implementations. Names must be valid exported CUE identifiers.
+	//
+	// Each implementation must be a Go function that is not variadic.
+	// Its parameters determine the CUE signature of the builtin and must
+	// each have one of the following types:
+	//
+	//	bool
+	//	string, []byte, []string
+	//	int, int8, int16, int32, int64
+	//	uint, uint8, uint16, uint32, uint64
+	//	float32, float64, *big.Int, *big.Float, *apd.Decimal
+	//	cue.Value, []cue.Value
+	//
+	// Types whose underlying type is one of the above basic types are
+	// allowed as well, as are other struct, map, slice, array, pointer and
+	// interface types that a [cuelang.org/go/cue.Value] can be decoded
+	// into.
+	//
+	// The function must return a single value, optionally followed by an
+	// error. The value is converted to CUE in the same way as
+	// [cuelang.org/go/cue.Context.Encode] does.
+	//
+	// Functions must be pure: their result must only depend on their
+	// arguments.
+	Funcs map[string]any
+
+	// CUE holds optional CUE declarations to add to the package, such as
+	// definitions, written as the body of a struct.
+	CUE string
+}
+
+// Register makes p available as a builtin package that CUE code can import
+// using importPath.
+//
+// The first element of importPath must contain a dot, such as in
+// "example.com/internal/funcs", as other paths are reserved for the
+// standard library. The package name is the last element of the path.
+// Register reports an error if importPath is not valid, if a package is
+// already registered for it, or if p is not a valid package.
+//
+// The package is available to all contexts and loaders in the current
+// process. Register must be called before any of them are used, typically
+// from an init function, and must not be called concurrently.
+//
+// To make a package available to a single context only, use
+// [cuelang.org/go/cue/cuecontext.BuiltinPackage] instead.
+func Register(importPath string, p *Package) error {
+	if err := checkBuiltinPath(importPath); err != nil {
+		return err
+	}
+	if runtime.IsBuiltin(importPath) {
+		return fmt.Errorf("builtin package %q already registered", importPath)
+	}
+	ip, err := newPackage(importPath, p)
+	if err != nil {
+		return err
+	}
+	internalpkg.Register(importPath, ip)
+	return nil
+}
+
+func init() {
+	internalpkg.FromPublic = func(importPath string, p any) (*internalpkg.Package, error) {
+		if err := checkBuiltinPath(importPath); err != nil {
+			return nil, err
+		}
+		return newPackage(importPath, p.(*Package))
+	}
+}
+
+// newPackage checks that p is a valid builtin package and converts it to
+// its internal representation.
+func newPackage(importPath string, p *Package) (*internalpkg.Package, error) {
+	names := make([]string, 0, len(p.Funcs))
+	for name := range p.Funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ip := &internalpkg.Package{}
+	for _, name := range names {
+		b, err := makeBuiltin(name, p.Funcs[name])
+		if err != nil {
+			return nil, fmt.Errorf("builtin package %q: %v", importPath, err)
+		}
+		ip.Native = append(ip.Native, b)
+	}
+	if p.CUE != "" {
+		ip.CUE = "{\n" + p.CUE + "\n}"
+	}
+
+	// Compile the package once to report any errors now rather than when
+	// it is first imported.
+	if err := tryCompile(ip, importPath); err != nil {
+		return nil, fmt.Errorf("builtin package %q: %v", importPath, err)
+	}
+	return ip, nil
+}
+
+func checkBuiltinPath(importPath string) error {
+	if strings.ContainsAny(importPath, "@:") {
+		return fmt.Errorf("builtin import path %q must not have a version or qualifier", importPath)
+	}
+	if err := module.CheckImportPath(importPath); err != nil {
+		return err
+	}
+	first, _, _ := strings.Cut(importPath, "/")
+	if !strings.Contains(first, ".") {
+		return fmt.Errorf("builtin import path %q is reserved for the standard library", importPath)
+	}
+	if name := path.Base(importPath); !ast.IsValidIdent(name) || isHidden(name) {
+		return fmt.Errorf("builtin import path %q does not end in a valid package name", importPath)
+	}
+	return nil
+}
+
+func isHidden(name string) bool {
+	return strings.HasPrefix(name, "_") || strings.HasPrefix(name, "#")
+}
+
+func tryCompile(p *internalpkg.Package, importPath string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	ctx := eval.NewContext(runtime.New(), nil)
+	p.MustCompile(ctx, importPath)
+	return nil
+}
+
+// makeBuiltin checks that fn is a valid implementation of the builtin
+// function with the given name and converts it to a Builtin.
+func makeBuiltin(name string, fn any) (*internalpkg.Builtin, error) {
+	if !ast.IsValidIdent(name) || isHidden(name) {
+		return nil, fmt.Errorf("invalid function name %q", name)
+	}
+	return internalpkg.FuncBuiltin(name, fn)
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkg_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/load"
+	"cuelang.org/go/pkg"
+)
+
+func init() {
+	err := pkg.Register("example.com/internal/funcs", &pkg.Package{
+		Funcs: map[string]any{
+			"Repeat": strings.Repeat,
+			"Half": func(x int) (int, error) {
+				if x%2 != 0 {
+					return 0, errors.New("odd number")
+				}
+				return x / 2, nil
+			},
+			"Keys": func(v cue.Value) ([]string, error) {
+				var keys []string
+				iter, err := v.Fields()
+				if err != nil {
+					return nil, err
+				}
+				for iter.Next() {
+					keys = append(keys, iter.Selector().String())
+				}
+				return keys, nil
+			},
+			"Area": func(r struct{ W, H float64 }) float64 {
+				return r.W * r.H
+			},
+		},
+		CUE: `#Name: =~"^[a-z]+$"`,
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctx := cuecontext.New()
+	v := ctx.CompileString(`
+import "example.com/internal/funcs"
+
+a: funcs.Repeat("ab", 3)
+b: funcs.Half(10)
+c: funcs.Keys({x: 1, y: 2})
+d: funcs.#Name & "foo"
+e: funcs.Area({W: 2, H: 3})
+`)
+	got := fmt.Sprint(v)
+	want := `{
+	a: "ababab"
+	b: 5
+	c: ["x", "y"]
+	d: "foo"
+	e: 6.0
+}`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	v = ctx.CompileString(`
+import "example.com/internal/funcs"
+
+a: funcs.Half(3)
+`)
+	if err := v.Validate(); err == nil || !strings.Contains(err.Error(), "odd number") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRegisterLoad(t *testing.T) {
+	dir := t.TempDir()
+	insts := load.Instances([]string{"."}, &load.Config{
+		Dir: dir,
+		Overlay: map[string]load.Source{
+			filepath.Join(dir, "cue.mod", "module.cue"): load.FromString(`
+module: "mod.test/x"
+language: version: "v0.9.0"
+`),
+			filepath.Join(dir, "x.cue"): load.FromString(`
+package x
+
+import "example.com/internal/funcs"
+
+a: funcs.Repeat("x", 2)
+`),
+		},
+	})
+	if err := insts[0].Err; err != nil {
+		t.Fatal(err)
+	}
+	v := cuecontext.New().BuildInstance(insts[0])
+	if err := v.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := v.LookupPath(cue.ParsePath("a")).String(); got != "xx" {
+		t.Errorf("got %q; want %q", got, "xx")
+	}
+}
+
+func TestRegisterErrors(t *testing.T) {
+	testCases := []struct {
+		path string
+		pkg  pkg.Package
+		err  string
+	}{{
+		path: "strings2",
+		err:  `builtin import path "strings2" is reserved for the standard library`,
+	}, {
+		path: "example.com/foo@v1",
+		err:  `builtin import path "example.com/foo@v1" must not have a version or qualifier`,
+	}, {
+		path: "example.com/foo-bar",
+		err:  `builtin import path "example.com/foo-bar" does not end in a valid package name`,
+	}, {
+		path: "example.com/internal/funcs",
+		err:  `builtin package "example.com/internal/funcs" already registered`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"_f": strings.ToUpper}},
+		err:  `builtin package "example.com/bad": invalid function name "_f"`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"F": 1}},
+		err:  `builtin package "example.com/bad": F: implementation is int, not a function`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"F": func(c chan int) int { return 0 }}},
+		err:  `builtin package "example.com/bad": F: unsupported type chan int for parameter 1`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"F": func(string) {}}},
+		err:  `builtin package "example.com/bad": F: must return a single value, optionally followed by an error`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"F": func(...string) int { return 0 }}},
+		err:  `builtin package "example.com/bad": F: variadic functions are not supported`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{Funcs: map[string]any{"F": func() func() { return nil }}},
+		err:  `builtin package "example.com/bad": F: unsupported result type func()`,
+	}, {
+		path: "example.com/bad",
+		pkg:  pkg.Package{CUE: `a: b`},
+		err:  `builtin package "example.com/bad": `,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			err := pkg.Register(tc.path, &tc.pkg)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.HasPrefix(err.Error(), tc.err) {
+				t.Errorf("got error:\n%v\nwant prefix:\n%s", err, tc.err)
+			}
+		})
+	}
+}
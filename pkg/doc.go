@@ -35,6 +35,9 @@
 // would not be possible or practical. The cue "cmd" command can be used to mix
 // in non-hermetic influences into configurations by using packages defined
 // in the tool subdirectory.
+//
+// Programs embedding CUE can make additional builtin packages implemented
+// in Go available with [Register].
 package pkg
 
 //go:generate go run gen.go
@@ -466,7 +466,8 @@ func (g *generator) adtKind(typ types.Type) string {
 	// Some builtin functions return custom types, like [cuelang.org/go/pkg/time.Split].
 	// TODO: we can simplify this once the CUE API declarations in ./pkg/...
 	// use CUE function signatures to validate their parameters and results.
-	case "*cuelang.org/go/pkg/time.Parts":
+	case "*cuelang.org/go/pkg/time.Parts", "*cuelang.org/go/pkg/net.URLParts",
+		"*cuelang.org/go/pkg/strings.CutParts":
 		return "adt.StructKind"
 	}
 	log.Fatal("adtKind: unhandled Go type ", typ.String())
@@ -309,5 +309,29 @@ var p = &pkg.Package{
 				c.Ret, c.Err = AbsURL(s)
 			}
 		},
+	}, {
+		Name: "ParseURL",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StructKind,
+		Func: func(c *pkg.CallCtxt) {
+			s := c.String(0)
+			if c.Do() {
+				c.Ret, c.Err = ParseURL(s)
+			}
+		},
+	}, {
+		Name: "BuildURL",
+		Params: []pkg.Param{
+			{Kind: adt.TopKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *pkg.CallCtxt) {
+			parts := c.Value(0)
+			if c.Do() {
+				c.Ret, c.Err = BuildURL(parts)
+			}
+		},
 	}},
 }
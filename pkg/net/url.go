@@ -16,7 +16,12 @@ package net
 
 import (
 	"errors"
+	"fmt"
+	"net"
 	"net/url"
+	"strings"
+
+	"cuelang.org/go/cue"
 )
 
 // PathEscape escapes the string so it can be safely placed inside a URL path
@@ -69,3 +74,155 @@ func AbsURL(s string) (bool, error) {
 	}
 	return true, nil
 }
+
+// URLParts holds the individual parts of a parsed URL.
+type URLParts struct {
+	Scheme string `json:"scheme"`
+
+	// User holds the user name and, if present, password, as in
+	// "user:password".
+	User string `json:"user"`
+
+	// Host holds the host name or IP address, without port or brackets.
+	Host string `json:"host"`
+	Port string `json:"port"`
+	Path string `json:"path"`
+
+	// Query maps each query parameter to its values, in order.
+	Query    map[string][]string `json:"query"`
+	Fragment string              `json:"fragment"`
+}
+
+// ParseURL parses s as a relative or absolute URL and returns its parts as
+// a struct with the fields scheme, user, host, port, path, query and
+// fragment. Parts that are absent are returned as empty strings. The query
+// maps each parameter to the list of its values.
+//
+// For instance, ParseURL("https://example.com:8443/a%20b?x=1&x=2") returns
+//
+//	scheme:   "https"
+//	user:     ""
+//	host:     "example.com"
+//	port:     "8443"
+//	path:     "/a b"
+//	query:    {x: ["1", "2"]}
+//	fragment: ""
+func ParseURL(s string) (*URLParts, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, err
+	}
+	user := ""
+	if u.User != nil {
+		user = u.User.String()
+	}
+	return &URLParts{
+		Scheme:   u.Scheme,
+		User:     user,
+		Host:     u.Hostname(),
+		Port:     u.Port(),
+		Path:     u.Path,
+		Query:    query,
+		Fragment: u.Fragment,
+	}, nil
+}
+
+// BuildURL returns the URL for the given parts, which is a struct with the
+// same fields as returned by ParseURL. All fields are optional. The port
+// may be given as a string or an integer, and each query parameter may have
+// a single string value or a list of them. Query parameters are sorted by
+// name.
+//
+// For instance, BuildURL({scheme: "https", host: "example.com", port: 8443})
+// returns "https://example.com:8443".
+func BuildURL(parts cue.Value) (string, error) {
+	str := func(name string) (string, error) {
+		v := parts.LookupPath(cue.MakePath(cue.Str(name)))
+		if !v.Exists() {
+			return "", nil
+		}
+		if v.Kind() == cue.IntKind && name == "port" {
+			i, err := v.Int64()
+			return fmt.Sprint(i), err
+		}
+		return v.String()
+	}
+
+	var u url.URL
+	var err error
+	if u.Scheme, err = str("scheme"); err != nil {
+		return "", err
+	}
+	user, err := str("user")
+	if err != nil {
+		return "", err
+	}
+	if user != "" {
+		name, password, ok := strings.Cut(user, ":")
+		if ok {
+			u.User = url.UserPassword(name, password)
+		} else {
+			u.User = url.User(name)
+		}
+	}
+	host, err := str("host")
+	if err != nil {
+		return "", err
+	}
+	port, err := str("port")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+	if u.Path, err = str("path"); err != nil {
+		return "", err
+	}
+	if u.Host != "" && u.Path != "" && !strings.HasPrefix(u.Path, "/") {
+		return "", fmt.Errorf("path %q must be absolute if a host is given", u.Path)
+	}
+	if u.Fragment, err = str("fragment"); err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	iter, err := parts.LookupPath(cue.MakePath(cue.Str("query"))).Fields()
+	if err == nil {
+		for iter.Next() {
+			name := iter.Selector().Unquoted()
+			v := iter.Value()
+			if v.Kind() != cue.ListKind {
+				s, err := v.String()
+				if err != nil {
+					return "", err
+				}
+				query.Add(name, s)
+				continue
+			}
+			list, err := v.List()
+			if err != nil {
+				return "", err
+			}
+			for list.Next() {
+				s, err := list.Value().String()
+				if err != nil {
+					return "", err
+				}
+				query.Add(name, s)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -22,6 +22,7 @@ import (
 	_ "cuelang.org/go/pkg/net"
 	_ "cuelang.org/go/pkg/path"
 	_ "cuelang.org/go/pkg/regexp"
+	_ "cuelang.org/go/pkg/semver"
 	_ "cuelang.org/go/pkg/strconv"
 	_ "cuelang.org/go/pkg/strings"
 	_ "cuelang.org/go/pkg/struct"
@@ -30,6 +31,7 @@ import (
 	_ "cuelang.org/go/pkg/time"
 	_ "cuelang.org/go/pkg/tool"
 	_ "cuelang.org/go/pkg/tool/cli"
+	_ "cuelang.org/go/pkg/tool/docker"
 	_ "cuelang.org/go/pkg/tool/exec"
 	_ "cuelang.org/go/pkg/tool/file"
 	_ "cuelang.org/go/pkg/tool/http"
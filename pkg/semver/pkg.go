@@ -0,0 +1,68 @@
+// Code generated by cuelang.org/go/pkg/gen. DO NOT EDIT.
+
+package semver
+
+import (
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/pkg"
+)
+
+func init() {
+	pkg.Register("semver", p)
+}
+
+var _ = adt.TopKind // in case the adt package isn't used
+
+var p = &pkg.Package{
+	Native: []*pkg.Builtin{{
+		Name: "Valid",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+		},
+		Result: adt.BottomKind,
+		Func: func(c *pkg.CallCtxt) {
+			v := c.String(0)
+			if c.Do() {
+				c.Ret = Valid(v)
+			}
+		},
+	}, {
+		Name: "Compare",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.IntKind,
+		Func: func(c *pkg.CallCtxt) {
+			a, b := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = Compare(a, b)
+			}
+		},
+	}, {
+		Name: "Sort",
+		Params: []pkg.Param{
+			{Kind: adt.ListKind},
+		},
+		Result: adt.ListKind,
+		Func: func(c *pkg.CallCtxt) {
+			versions := c.StringList(0)
+			if c.Do() {
+				c.Ret, c.Err = Sort(versions)
+			}
+		},
+	}, {
+		Name: "MatchRange",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.BoolKind,
+		Func: func(c *pkg.CallCtxt) {
+			v, r := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret, c.Err = MatchRange(v, r)
+			}
+		},
+	}},
+}
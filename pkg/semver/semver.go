@@ -0,0 +1,323 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package semver defines functionality for semantic versions as defined by
+// https://semver.org, such as the versions of container images or Helm
+// charts.
+//
+// A version has the form MAJOR.MINOR.PATCH, optionally followed by a
+// prerelease suffix, such as -rc.1, and build metadata, such as +build.5.
+// All functions also accept versions that are preceded by a "v", as is
+// common for tags.
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cuelang.org/go/internal/mod/semver"
+)
+
+// Valid ensures that v is a valid semantic version.
+func Valid(v string) error {
+	_, err := parse(v)
+	return err
+}
+
+// Compare returns an integer comparing two versions according to semantic
+// version precedence. The result will be 0 if a == b, -1 if a < b, or
+// +1 if a > b. Build metadata is ignored.
+//
+// It is an error if a or b is not a valid semantic version.
+func Compare(a, b string) (int, error) {
+	va, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return semver.Compare(va, vb), nil
+}
+
+// Sort returns the given versions sorted in increasing order of semantic
+// version precedence. Versions with the same precedence, such as versions
+// that only differ in build metadata, retain their relative order.
+//
+// It is an error if any of the versions is not a valid semantic version.
+func Sort(versions []string) ([]string, error) {
+	type version struct {
+		orig, v string
+	}
+	a := make([]version, len(versions))
+	for i, s := range versions {
+		v, err := parse(s)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = version{s, v}
+	}
+	slices.SortStableFunc(a, func(x, y version) int {
+		return semver.Compare(x.v, y.v)
+	})
+	sorted := make([]string, len(a))
+	for i, x := range a {
+		sorted[i] = x.orig
+	}
+	return sorted, nil
+}
+
+// MatchRange reports whether version v is within range r.
+//
+// A range is a list of comparisons separated by commas or spaces, all of
+// which must hold, such as ">=2, <3". Such lists may be combined with "||",
+// in which case at least one of them must hold. A comparison consists of
+// an operator followed by a version:
+//
+//	=1.2.3   equal to 1.2.3; the "=" may be omitted
+//	!=1.2.3  not equal to 1.2.3
+//	>1.2.3   greater than 1.2.3
+//	>=1.2.3  greater than or equal to 1.2.3
+//	<1.2.3   less than 1.2.3
+//	<=1.2.3  less than or equal to 1.2.3
+//	~1.2.3   at least 1.2.3, but less than 1.3.0
+//	^1.2.3   at least 1.2.3, but less than 2.0.0. For versions
+//	         starting with 0, only the first non-zero component
+//	         must be equal: ^0.2.3 is less than 0.3.0.
+//
+// The version of a comparison may omit its trailing components, or use x
+// or * to denote them, such as in "1.2", "1.2.x" or "1.x". A comparison
+// with such a version applies to all versions that it matches: for
+// instance, "1.2" is equivalent to ">=1.2.0, <1.3.0", ">2" to ">=3.0.0",
+// and "^0" to ">=0.0.0, <1.0.0". A lone "*" matches any version.
+//
+// A prerelease version is only within a range if at least one of the
+// comparisons that must hold for it mentions a prerelease version, so
+// that ranges do not unexpectedly match unstable versions.
+//
+// It is an error if v is not a valid semantic version or r is not a valid
+// range.
+func MatchRange(v, r string) (bool, error) {
+	cv, err := parse(v)
+	if err != nil {
+		return false, err
+	}
+	sets, err := parseRange(r)
+	if err != nil {
+		return false, err
+	}
+	isPrerelease := semver.Prerelease(cv) != ""
+outer:
+	for _, set := range sets {
+		if isPrerelease && !slices.ContainsFunc(set, comparison.isPrerelease) {
+			continue
+		}
+		for _, c := range set {
+			if !c.match(cv) {
+				continue outer
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// parse checks that s is a valid semantic version and returns it in the
+// form expected by package semver: with a "v" prefix.
+func parse(s string) (string, error) {
+	v := "v" + strings.TrimPrefix(s, "v")
+	// semver.IsValid also accepts shorthands like v1.2, which are not
+	// valid semantic versions.
+	core, _, _ := strings.Cut(v, "-")
+	core, _, _ = strings.Cut(core, "+")
+	if !semver.IsValid(v) || strings.Count(core, ".") != 2 {
+		return "", fmt.Errorf("invalid semantic version %q", s)
+	}
+	return v, nil
+}
+
+// A comparison compares a version against the version v using op.
+type comparison struct {
+	op string // one of =, !=, >, >=, <, <=
+	v  string
+}
+
+func (c comparison) isPrerelease() bool {
+	return semver.Prerelease(c.v) != ""
+}
+
+func (c comparison) match(v string) bool {
+	cmp := semver.Compare(v, c.v)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default: // "<="
+		return cmp <= 0
+	}
+}
+
+// parseRange parses a range into lists of comparisons of which all must
+// hold for at least one list.
+func parseRange(r string) ([][]comparison, error) {
+	var sets [][]comparison
+	for _, s := range strings.Split(r, "||") {
+		fields := strings.FieldsFunc(s, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid range %q: empty list of comparisons", r)
+		}
+		set := []comparison{}
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			// Allow for space between an operator and a version.
+			if strings.Trim(f, "=!<>~^") == "" && i+1 < len(fields) {
+				i++
+				f += fields[i]
+			}
+			a, err := parseComparison(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %q: %v", r, err)
+			}
+			set = append(set, a...)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// parseComparison converts a single comparison to the equivalent list of
+// comparisons against full versions.
+func parseComparison(s string) ([]comparison, error) {
+	v := strings.TrimLeft(s, "=!<>~^")
+	op := s[:len(s)-len(v)]
+	switch op {
+	case "", "=", "!=", ">", ">=", "<", "<=", "~", "^":
+	default:
+		return nil, fmt.Errorf("invalid operator %q", op)
+	}
+
+	// Split off the prerelease and build metadata, so that the remaining
+	// components can be partial.
+	v = strings.TrimPrefix(v, "v")
+	core, suffix := v, ""
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		core, suffix = v[:i], v[i:]
+	}
+	var nums []uint64
+	wildcard := false
+	for _, p := range strings.Split(core, ".") {
+		switch {
+		case p == "x" || p == "X" || p == "*":
+			wildcard = true
+			continue
+		case wildcard:
+			return nil, fmt.Errorf("invalid version %q: number after wildcard", v)
+		}
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil || (len(p) > 1 && p[0] == '0') {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		nums = append(nums, n)
+	}
+	if len(strings.Split(core, ".")) > 3 || (len(nums) < 3 && suffix != "") {
+		return nil, fmt.Errorf("invalid version %q", v)
+	}
+
+	lower := version(nums, suffix)
+	if _, err := parse(lower); err != nil {
+		return nil, err
+	}
+	if len(nums) == 0 {
+		switch op {
+		case "", "=", ">=", "<=", "~", "^":
+			return nil, nil // any version
+		}
+		return nil, fmt.Errorf("operator %q cannot be used with a wildcard", op)
+	}
+	// next returns the first version after those matching the first i+1
+	// components of the version.
+	next := func(i int) string {
+		a := slices.Clone(nums[:i+1])
+		a[i]++
+		return version(a, "")
+	}
+	last := len(nums) - 1
+	full := len(nums) == 3
+	switch op {
+	case "", "=":
+		if full {
+			return []comparison{{"=", lower}}, nil
+		}
+		return []comparison{{">=", lower}, {"<", next(last)}}, nil
+	case "!=":
+		if !full {
+			return nil, fmt.Errorf("operator %q requires a full version", op)
+		}
+		return []comparison{{"!=", lower}}, nil
+	case ">":
+		if full {
+			return []comparison{{">", lower}}, nil
+		}
+		return []comparison{{">=", next(last)}}, nil
+	case ">=":
+		return []comparison{{">=", lower}}, nil
+	case "<":
+		return []comparison{{"<", lower}}, nil
+	case "<=":
+		if full {
+			return []comparison{{"<=", lower}}, nil
+		}
+		return []comparison{{"<", next(last)}}, nil
+	case "~":
+		return []comparison{{">=", lower}, {"<", next(min(last, 1))}}, nil
+	}
+	// ^: the first non-zero component may not change.
+	i := 0
+	for i < last && nums[i] == 0 {
+		i++
+	}
+	return []comparison{{">=", lower}, {"<", next(i)}}, nil
+}
+
+// version returns a version in the form expected by package semver,
+// with missing components set to 0.
+func version(nums []uint64, suffix string) string {
+	var b strings.Builder
+	b.WriteString("v")
+	for i := range 3 {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		var n uint64
+		if i < len(nums) {
+			n = nums[i]
+		}
+		b.WriteString(strconv.FormatUint(n, 10))
+	}
+	b.WriteString(suffix)
+	return b.String()
+}
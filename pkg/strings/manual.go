@@ -29,6 +29,8 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+
+	"cuelang.org/go/cue"
 )
 
 // ByteAt reports the ith byte of the underlying strings or byte.
@@ -118,3 +120,132 @@ func SliceRunes(s string, start, end int) (string, error) {
 	}
 	return string(runes[start:end]), nil
 }
+
+// CutParts holds the result of Cut.
+type CutParts struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+	Found  bool   `json:"found"`
+}
+
+// Cut slices s around the first instance of sep, returning the text before
+// and after sep. The found field reports whether sep appears in s. If sep
+// does not appear in s, before is s and after is the empty string.
+//
+// For instance
+//
+//	strings.Cut("key=value", "=")
+//
+// results in
+//
+//	{before: "key", after: "value", found: true}
+func Cut(s, sep string) *CutParts {
+	before, after, found := strings.Cut(s, sep)
+	return &CutParts{Before: before, After: after, Found: found}
+}
+
+// FieldsN is like Fields, but returns at most n substrings:
+//
+//	n > 0: at most n substrings; the last substring is the remainder of s,
+//	       starting at the nth field, without trailing white space.
+//	n == 0: the result is the empty list
+//	n < 0: all substrings, as with Fields
+func FieldsN(s string, n int) []string {
+	if n < 0 {
+		return strings.Fields(s)
+	}
+	a := []string{}
+	for len(a) < n {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		if s == "" {
+			break
+		}
+		if len(a) == n-1 {
+			a = append(a, strings.TrimRightFunc(s, unicode.IsSpace))
+			break
+		}
+		i := strings.IndexFunc(s, unicode.IsSpace)
+		if i < 0 {
+			i = len(s)
+		}
+		a = append(a, s[:i])
+		s = s[i:]
+	}
+	return a
+}
+
+// Template substitutes the fields of a struct into a template string.
+// A placeholder of the form {path} is replaced with the value at the given
+// CUE path within fields, where path is for instance a field name or a
+// sequence of selectors such as a.b. Use {{ and }} to produce literal braces.
+//
+// Values must be concrete strings, bytes, numbers, or booleans. They are
+// converted to text as in string interpolation.
+//
+// For instance
+//
+//	strings.Template("{name}:{version}", {name: "app", version: 2})
+//
+// results in
+//
+//	"app:2"
+func Template(template string, fields cue.Value) (string, error) {
+	var b strings.Builder
+	for s := template; s != ""; {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		c := s[i]
+		s = s[i+1:]
+		if s != "" && s[0] == c {
+			// Escaped brace.
+			b.WriteByte(c)
+			s = s[1:]
+			continue
+		}
+		if c == '}' {
+			return "", fmt.Errorf("unmatched '}' in template")
+		}
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in template")
+		}
+		str, err := templateValue(fields, s[:end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(str)
+		s = s[end+1:]
+	}
+	return b.String(), nil
+}
+
+func templateValue(fields cue.Value, name string) (string, error) {
+	p := cue.ParsePath(strings.TrimSpace(name))
+	if err := p.Err(); err != nil {
+		return "", fmt.Errorf("invalid placeholder {%s}: %v", name, err)
+	}
+	v := fields.LookupPath(p)
+	if !v.Exists() {
+		return "", fmt.Errorf("placeholder {%s}: field not found", name)
+	}
+	v, _ = v.Default()
+	switch v.IncompleteKind() {
+	case cue.StringKind:
+		return v.String()
+	case cue.BytesKind:
+		b, err := v.Bytes()
+		return string(b), err
+	case cue.IntKind, cue.FloatKind, cue.NumberKind, cue.BoolKind:
+		if err := v.Validate(cue.Concrete(true)); err != nil {
+			return "", err
+		}
+		return fmt.Sprint(v), nil
+	case cue.BottomKind:
+		return "", v.Err()
+	}
+	return "", fmt.Errorf("placeholder {%s}: cannot use value of type %v in template", name, v.IncompleteKind())
+}
@@ -117,6 +117,45 @@ var p = &pkg.Package{
 				c.Ret, c.Err = SliceRunes(s, start, end)
 			}
 		},
+	}, {
+		Name: "Cut",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.StringKind},
+		},
+		Result: adt.StructKind,
+		Func: func(c *pkg.CallCtxt) {
+			s, sep := c.String(0), c.String(1)
+			if c.Do() {
+				c.Ret = Cut(s, sep)
+			}
+		},
+	}, {
+		Name: "FieldsN",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.IntKind},
+		},
+		Result: adt.ListKind,
+		Func: func(c *pkg.CallCtxt) {
+			s, n := c.String(0), c.Int(1)
+			if c.Do() {
+				c.Ret = FieldsN(s, n)
+			}
+		},
+	}, {
+		Name: "Template",
+		Params: []pkg.Param{
+			{Kind: adt.StringKind},
+			{Kind: adt.TopKind},
+		},
+		Result: adt.StringKind,
+		Func: func(c *pkg.CallCtxt) {
+			template, fields := c.String(0), c.Value(1)
+			if c.Do() {
+				c.Ret, c.Err = Template(template, fields)
+			}
+		},
 	}, {
 		Name: "Compare",
 		Params: []pkg.Param{
@@ -72,7 +72,7 @@ func LastIndexAny(s, chars string) int {
 // The count determines the number of substrings to return:
 //
 //	n > 0: at most n substrings; the last substring will be the unsplit remainder.
-//	n == 0: the result is nil (zero substrings)
+//	n == 0: the result is the empty list (zero substrings)
 //	n < 0: all substrings
 //
 // Edge cases for s and sep (for example, empty strings) are handled
@@ -87,7 +87,7 @@ func SplitN(s, sep string, n int) []string {
 // The count determines the number of substrings to return:
 //
 //	n > 0: at most n substrings; the last substring will be the unsplit remainder.
-//	n == 0: the result is nil (zero substrings)
+//	n == 0: the result is the empty list (zero substrings)
 //	n < 0: all substrings
 //
 // Edge cases for s and sep (for example, empty strings) are handled
@@ -18,8 +18,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"os"
+	"slices"
 	"strings"
 
+	"golang.org/x/term"
+
 	"cuelang.org/go/cue"
 	"cuelang.org/go/internal/task"
 )
@@ -66,11 +70,100 @@ func (r *oneByteReader) Read(p []byte) (int, error) {
 
 func (c *askCmd) Run(ctx *task.Context) (res interface{}, err error) {
 	str := ctx.String("prompt")
+	v := ctx.Lookup("response")
 	if ctx.Err != nil {
 		return nil, ctx.Err
 	}
-	if str != "" {
-		fmt.Fprint(ctx.Stdout, str+" ")
+	// A response that is already set, for instance through an injected
+	// tag, takes precedence over prompting.
+	if v.IsConcrete() {
+		return nil, nil
+	}
+
+	var choices []string
+	if x := ctx.Obj.LookupPath(cue.ParsePath("choices")); x.Exists() {
+		if err := x.Decode(&choices); err != nil {
+			return nil, err
+		}
+	}
+	secret := false
+	if x := ctx.Obj.LookupPath(cue.ParsePath("secret")); x.Exists() {
+		if secret, err = x.Bool(); err != nil {
+			return nil, err
+		}
+	}
+
+	var response string
+	if name := envName(ctx.Obj); name != "" && os.Getenv(name) != "" {
+		response = os.Getenv(name)
+		if !validChoice(choices, response) {
+			return nil, fmt.Errorf("invalid value %q for environment variable %s; must be one of: %s",
+				response, name, strings.Join(choices, ", "))
+		}
+	} else {
+		for {
+			if str != "" {
+				fmt.Fprint(ctx.Stdout, str+" ")
+			}
+			line, ok, err := readLine(ctx, secret)
+			if err != nil {
+				return nil, err
+			}
+			if len(choices) > 0 && !ok {
+				return nil, fmt.Errorf("no response; must be one of: %s", strings.Join(choices, ", "))
+			}
+			response = line
+			if validChoice(choices, response) {
+				break
+			}
+			fmt.Fprintf(ctx.Stdout, "invalid response %q; must be one of: %s\n",
+				response, strings.Join(choices, ", "))
+		}
+	}
+
+	update := map[string]interface{}{"response": response}
+
+	switch v.IncompleteKind() {
+	case cue.BoolKind:
+		update["response"] = strings.ToLower(response) == "yes"
+	case cue.StringKind:
+		// already set above
+	}
+	return update, nil
+}
+
+// envName reports the name of the environment variable that provides a
+// response for the Ask task v, or "" if there is none.
+func envName(v cue.Value) string {
+	x := v.LookupPath(cue.ParsePath("env"))
+	if !x.Exists() {
+		return ""
+	}
+	name, _ := x.String()
+	return name
+}
+
+// validChoice reports whether response is one of choices, or whether there
+// are no choices to select from.
+func validChoice(choices []string, response string) bool {
+	return len(choices) == 0 || slices.Contains(choices, response)
+}
+
+// readLine reads a single line from the task's standard input. If secret is
+// true and the input is a terminal, the input is not echoed. It reports
+// false if no line could be read.
+func readLine(ctx *task.Context, secret bool) (line string, ok bool, err error) {
+	if f, isFile := ctx.Stdin.(*os.File); secret && isFile && term.IsTerminal(int(f.Fd())) {
+		b, err := term.ReadPassword(int(f.Fd()))
+		// The newline typed by the user was not echoed either.
+		fmt.Fprintln(ctx.Stdout)
+		switch {
+		case err == io.EOF:
+			return "", false, nil
+		case err != nil:
+			return "", false, err
+		}
+		return string(b), true, nil
 	}
 
 	// Roger is convinced that bufio.Scanner will only issue as many reads
@@ -82,21 +175,11 @@ func (c *askCmd) Run(ctx *task.Context) (res interface{}, err error) {
 	// TODO(mvdan): come back to remove this notice once Roger's CL is
 	// approved, or to rewrite the code if it is rejected.
 	scanner := bufio.NewScanner(&oneByteReader{ctx.Stdin})
-	var response string
 	if scanner.Scan() {
-		response = scanner.Text()
+		line, ok = scanner.Text(), true
 	}
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return "", false, err
 	}
-
-	update := map[string]interface{}{"response": response}
-
-	switch v := ctx.Lookup("response"); v.IncompleteKind() {
-	case cue.BoolKind:
-		update["response"] = strings.ToLower(response) == "yes"
-	case cue.StringKind:
-		// already set above
-	}
-	return update, nil
+	return line, ok, nil
 }
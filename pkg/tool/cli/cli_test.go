@@ -0,0 +1,116 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/internal/task"
+	"cuelang.org/go/pkg/internal"
+)
+
+func TestAsk(t *testing.T) {
+	t.Setenv("CUECLITESTANSWER", "blue")
+
+	testCases := []struct {
+		desc   string
+		val    string
+		stdin  string
+		want   interface{}
+		stdout string
+		err    string
+	}{{
+		desc:   "string",
+		val:    `prompt: "Name?", response: string`,
+		stdin:  "gopher\nrest\n",
+		want:   map[string]interface{}{"response": "gopher"},
+		stdout: "Name? ",
+	}, {
+		desc:   "bool",
+		val:    `prompt: "Okay?", response: bool`,
+		stdin:  "Yes\n",
+		want:   map[string]interface{}{"response": true},
+		stdout: "Okay? ",
+	}, {
+		desc:   "secret from non-terminal",
+		val:    `prompt: "Password:", response: string, secret: true`,
+		stdin:  "hunter2\n",
+		want:   map[string]interface{}{"response": "hunter2"},
+		stdout: "Password: ",
+	}, {
+		desc:   "choices",
+		val:    `prompt: "Color?", response: string, choices: ["red", "blue"]`,
+		stdin:  "green\nred\n",
+		want:   map[string]interface{}{"response": "red"},
+		stdout: "Color? invalid response \"green\"; must be one of: red, blue\nColor? ",
+	}, {
+		desc:   "choices without valid response",
+		val:    `prompt: "Color?", response: string, choices: ["red", "blue"]`,
+		stdin:  "green\n",
+		stdout: "Color? invalid response \"green\"; must be one of: red, blue\nColor? ",
+		err:    "no response; must be one of: red, blue",
+	}, {
+		desc: "env",
+		val:  `prompt: "Color?", response: string, env: "CUECLITESTANSWER"`,
+		want: map[string]interface{}{"response": "blue"},
+	}, {
+		desc: "invalid env",
+		val:  `prompt: "Color?", response: string, env: "CUECLITESTANSWER", choices: ["red"]`,
+		err:  `invalid value "blue" for environment variable CUECLITESTANSWER; must be one of: red`,
+	}, {
+		desc:   "unset env",
+		val:    `prompt: "Color?", response: string, env: "CUECLITESTUNSET"`,
+		stdin:  "red\n",
+		want:   map[string]interface{}{"response": "red"},
+		stdout: "Color? ",
+	}, {
+		desc: "concrete response",
+		val:  `prompt: "Color?", response: "red"`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := internal.NewContext()
+			v := ctx.CompileString(tc.val, cue.Filename(tc.desc))
+			if err := v.Err(); err != nil {
+				t.Fatal(err)
+			}
+			var stdout strings.Builder
+			got, err := (&askCmd{}).Run(&task.Context{
+				Context: context.Background(),
+				Stdin:   strings.NewReader(tc.stdin),
+				Stdout:  &stdout,
+				Obj:     v,
+			})
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Errorf("got error %v; want %s", err, tc.err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Error(diff)
+			}
+			if got := stdout.String(); got != tc.stdout {
+				t.Errorf("got stdout %q; want %q", got, tc.stdout)
+			}
+		})
+	}
+}
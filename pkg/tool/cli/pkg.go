@@ -19,6 +19,13 @@
 //	//         prompt:   "Are you okay?"
 //	//         response: bool
 //	//     })
+//	//
+//	//     task: password: cli.Ask({
+//	//         prompt:   "Password:"
+//	//         response: string
+//	//         secret:   true
+//	//         env:      "APP_PASSWORD"
+//	//     })
 //	Ask: {
 //		$id: "tool/cli.Ask"
 //
@@ -27,7 +34,23 @@
 //
 //		// response holds the user's response. If it is a boolean expression it
 //		// will interpret the answer using textual yes/ no.
+//		//
+//		// No prompt is shown if response is already concrete, for instance
+//		// because it was set with an injected tag.
 //		response: string | bool
+//
+//		// secret indicates that the response should not be echoed when it is
+//		// read from a terminal, for instance when asking for a password.
+//		secret: *false | bool
+//
+//		// choices, if non-empty, lists the accepted responses. The prompt is
+//		// repeated until one of them is entered.
+//		choices: [...string]
+//
+//		// env optionally names an environment variable that, if set and not
+//		// empty, provides the response without prompting. This allows using
+//		// the task non-interactively.
+//		env?: string
 //	}
 package cli
 
@@ -53,6 +76,9 @@ var p = &pkg.Package{
 		$id:      "tool/cli.Ask"
 		prompt:   string
 		response: string | bool
+		secret:   *false | bool
+		choices: [...string]
+		env?: string
 	}
 }`,
 }
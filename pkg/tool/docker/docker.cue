@@ -0,0 +1,93 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+// Build builds an image from a build context directory.
+Build: {
+	$id: "tool/docker.Build"
+
+	// dir is the build context directory.
+	dir: string
+
+	// dockerfile is the path of the Dockerfile to use. The default is
+	// the file named Dockerfile in dir.
+	dockerfile?: string
+
+	// tags holds the names under which the built image is tagged,
+	// in the form name:tag.
+	tags: [...string]
+
+	// buildArgs sets build-time variables.
+	buildArgs: [string]: string
+
+	// target selects the build stage to build in a multi-stage Dockerfile.
+	target?: string
+
+	// platform sets the target platform of the image, such as linux/amd64.
+	platform?: string
+
+	// config is the directory holding the docker client configuration,
+	// including registry credentials. The default is the standard
+	// configuration directory used by the docker command.
+	config?: string
+
+	// id is set to the ID of the built image.
+	id: string
+}
+
+// Tag creates a tag target that refers to the image source.
+Tag: {
+	$id: "tool/docker.Tag"
+
+	source: string
+	target: string
+
+	config?: string
+}
+
+// Push pushes an image to its registry.
+Push: {
+	$id: "tool/docker.Push"
+
+	// image is the name of the image to push, in the form name:tag.
+	image: string
+
+	config?: string
+
+	// digest is set to the content digest of the pushed image,
+	// such as "sha256:...". The image can be referred to
+	// reproducibly as name@digest.
+	digest: string
+}
+
+// Inspect reports information about a local image.
+Inspect: {
+	$id: "tool/docker.Inspect"
+
+	// image is the name or ID of the image to inspect.
+	image: string
+
+	config?: string
+
+	// id is set to the ID of the image.
+	id: string
+
+	// repoTags lists the tags which refer to the image.
+	repoTags: [...string]
+
+	// repoDigests lists the image's digests in the registries it was
+	// pushed to or pulled from, in the form name@digest.
+	repoDigests: [...string]
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/internal/task"
+)
+
+func init() {
+	task.Register("tool/docker.Build", newBuildCmd)
+	task.Register("tool/docker.Tag", newTagCmd)
+	task.Register("tool/docker.Push", newPushCmd)
+	task.Register("tool/docker.Inspect", newInspectCmd)
+}
+
+type cmdBuild struct{}
+type cmdTag struct{}
+type cmdPush struct{}
+type cmdInspect struct{}
+
+func newBuildCmd(v cue.Value) (task.Runner, error)   { return &cmdBuild{}, nil }
+func newTagCmd(v cue.Value) (task.Runner, error)     { return &cmdTag{}, nil }
+func newPushCmd(v cue.Value) (task.Runner, error)    { return &cmdPush{}, nil }
+func newInspectCmd(v cue.Value) (task.Runner, error) { return &cmdInspect{}, nil }
+
+// docker is the name of the command run by the tasks in this package.
+var docker = "docker"
+
+func (c *cmdBuild) Run(ctx *task.Context) (res interface{}, err error) {
+	dir, err := os.MkdirTemp("", "cue-docker-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	idFile := filepath.Join(dir, "id")
+
+	args, err := buildArgs(ctx, idFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := run(ctx, args, ctx.Stdout); err != nil {
+		return nil, err
+	}
+	id, err := os.ReadFile(idFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading image ID: %v", err)
+	}
+	return map[string]interface{}{"id": strings.TrimSpace(string(id))}, nil
+}
+
+// buildArgs returns the arguments for a docker build command which
+// writes the ID of the resulting image to idFile.
+func buildArgs(ctx *task.Context, idFile string) ([]string, error) {
+	args := append(configArgs(ctx), "build", "--iidfile", idFile)
+	if s, ok := optString(ctx, "dockerfile"); ok {
+		args = append(args, "--file", s)
+	}
+	if s, ok := optString(ctx, "target"); ok {
+		args = append(args, "--target", s)
+	}
+	if s, ok := optString(ctx, "platform"); ok {
+		args = append(args, "--platform", s)
+	}
+	for iter, _ := ctx.Lookup("tags").List(); iter.Next(); {
+		s, err := iter.Value().String()
+		if err != nil {
+			return nil, errors.Wrapf(err, iter.Value().Pos(), "invalid tag")
+		}
+		args = append(args, "--tag", s)
+	}
+	for iter, _ := ctx.Lookup("buildArgs").Fields(); iter.Next(); {
+		s, err := iter.Value().String()
+		if err != nil {
+			return nil, errors.Wrapf(err, iter.Value().Pos(), "invalid build argument")
+		}
+		args = append(args, "--build-arg", iter.Selector().Unquoted()+"="+s)
+	}
+	args = append(args, ctx.String("dir"))
+	if ctx.Err != nil {
+		return nil, ctx.Err
+	}
+	return args, nil
+}
+
+func (c *cmdTag) Run(ctx *task.Context) (res interface{}, err error) {
+	var (
+		args   = configArgs(ctx)
+		source = ctx.String("source")
+		target = ctx.String("target")
+	)
+	if ctx.Err != nil {
+		return nil, ctx.Err
+	}
+	args = append(args, "tag", source, target)
+	return nil, run(ctx, args, ctx.Stdout)
+}
+
+func (c *cmdPush) Run(ctx *task.Context) (res interface{}, err error) {
+	var (
+		args  = configArgs(ctx)
+		image = ctx.String("image")
+	)
+	if ctx.Err != nil {
+		return nil, ctx.Err
+	}
+	var out bytes.Buffer
+	args = append(args, "push", image)
+	if err := run(ctx, args, io.MultiWriter(ctx.Stdout, &out)); err != nil {
+		return nil, err
+	}
+	digest := pushDigest(out.Bytes())
+	if digest == "" {
+		return nil, fmt.Errorf("no digest reported when pushing %s", image)
+	}
+	return map[string]interface{}{"digest": digest}, nil
+}
+
+var digestRE = regexp.MustCompile(`digest: (sha256:[0-9a-f]{64})`)
+
+// pushDigest returns the digest reported by the output of docker push,
+// or the empty string if there is none.
+func pushDigest(out []byte) string {
+	m := digestRE.FindAllSubmatch(out, -1)
+	if len(m) == 0 {
+		return ""
+	}
+	// Only the last line refers to the pushed tag.
+	return string(m[len(m)-1][1])
+}
+
+func (c *cmdInspect) Run(ctx *task.Context) (res interface{}, err error) {
+	var (
+		args  = configArgs(ctx)
+		image = ctx.String("image")
+	)
+	if ctx.Err != nil {
+		return nil, ctx.Err
+	}
+	var out bytes.Buffer
+	args = append(args, "image", "inspect", "--format", "{{json .}}", image)
+	if err := run(ctx, args, &out); err != nil {
+		return nil, err
+	}
+	return parseInspect(out.Bytes())
+}
+
+// parseInspect returns the task results for the JSON output of
+// docker image inspect.
+func parseInspect(out []byte) (map[string]interface{}, error) {
+	var info struct {
+		ID          string   `json:"Id"`
+		RepoTags    []string `json:"RepoTags"`
+		RepoDigests []string `json:"RepoDigests"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("invalid output from docker image inspect: %v", err)
+	}
+	if info.RepoTags == nil {
+		info.RepoTags = []string{}
+	}
+	if info.RepoDigests == nil {
+		info.RepoDigests = []string{}
+	}
+	return map[string]interface{}{
+		"id":          info.ID,
+		"repoTags":    info.RepoTags,
+		"repoDigests": info.RepoDigests,
+	}, nil
+}
+
+// configArgs returns the global docker arguments selecting the client
+// configuration directory, if the task sets one.
+func configArgs(ctx *task.Context) []string {
+	if dir, ok := optString(ctx, "config"); ok {
+		return []string{"--config", dir}
+	}
+	return nil
+}
+
+// optString returns the value of an optional string field.
+func optString(ctx *task.Context, field string) (string, bool) {
+	v := ctx.Obj.LookupPath(cue.MakePath(cue.Str(field)))
+	if !v.Exists() {
+		return "", false
+	}
+	return ctx.String(field), true
+}
+
+// run runs docker with the given arguments, writing its standard output
+// to stdout.
+func run(ctx *task.Context, args []string, stdout io.Writer) error {
+	cmd := exec.CommandContext(ctx.Context, docker, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = ctx.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %q failed: %v", append([]string{docker}, args...), err)
+	}
+	return nil
+}
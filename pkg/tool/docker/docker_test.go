@@ -0,0 +1,139 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/internal/task"
+	"cuelang.org/go/pkg/internal"
+)
+
+func TestBuildArgs(t *testing.T) {
+	testCases := []struct {
+		desc string
+		val  string
+		args []string
+	}{{
+		desc: "minimal",
+		val: `
+		dir: "."
+		tags: []
+		buildArgs: {}
+		`,
+		args: []string{"build", "--iidfile", "id", "."},
+	}, {
+		desc: "full",
+		val: `
+		dir:        "app"
+		dockerfile: "app/Dockerfile.prod"
+		tags: ["example.com/app:v1", "example.com/app:latest"]
+		buildArgs: {
+			VERSION: "v1"
+			DEBUG:   "false"
+		}
+		target:   "release"
+		platform: "linux/amd64"
+		config:   "/tmp/docker"
+		`,
+		args: []string{
+			"--config", "/tmp/docker",
+			"build", "--iidfile", "id",
+			"--file", "app/Dockerfile.prod",
+			"--target", "release",
+			"--platform", "linux/amd64",
+			"--tag", "example.com/app:v1",
+			"--tag", "example.com/app:latest",
+			"--build-arg", "VERSION=v1",
+			"--build-arg", "DEBUG=false",
+			"app",
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := internal.NewContext()
+			v := ctx.CompileString(tc.val, cue.Filename(tc.desc))
+			if err := v.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			args, err := buildArgs(&task.Context{
+				Context: context.Background(),
+				Obj:     v,
+			}, "id")
+			if err != nil {
+				t.Fatalf("buildArgs error = %v", err)
+			}
+
+			if diff := cmp.Diff(args, tc.args); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPushDigest(t *testing.T) {
+	const (
+		d1 = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		d2 = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	testCases := []struct {
+		out    string
+		digest string
+	}{{
+		out: `The push refers to repository [example.com/app]
+5f70bf18a086: Pushed
+v1: digest: ` + d1 + ` size: 528
+`,
+		digest: d1,
+	}, {
+		out: `5f70bf18a086: Layer already exists
+v1: digest: ` + d1 + ` size: 528
+latest: digest: ` + d2 + ` size: 528
+`,
+		digest: d2,
+	}, {
+		out:    "Everything up-to-date\n",
+		digest: "",
+	}}
+	for _, tc := range testCases {
+		if got := pushDigest([]byte(tc.out)); got != tc.digest {
+			t.Errorf("pushDigest(%q) = %q; want %q", tc.out, got, tc.digest)
+		}
+	}
+}
+
+func TestParseInspect(t *testing.T) {
+	got, err := parseInspect([]byte(`{"Id":"sha256:abc","RepoTags":["app:v1"],"RepoDigests":null,"Size":1234}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"id":          "sha256:abc",
+		"repoTags":    []string{"app:v1"},
+		"repoDigests": []string{},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := parseInspect([]byte("Error: No such image")); err == nil {
+		t.Error("expected error for invalid output")
+	}
+}
@@ -0,0 +1,138 @@
+// Code generated by cuelang.org/go/pkg/gen. DO NOT EDIT.
+
+// Package docker provides tasks for building, tagging and pushing
+// container images.
+//
+// The tasks run the docker command, which must be available in the PATH.
+// Registry credentials are taken from the standard docker configuration,
+// as set up by "docker login" or a credential helper; the config field
+// may be used to select a different configuration directory.
+//
+// These are the supported tasks:
+//
+//	// Build builds an image from a build context directory.
+//	Build: {
+//		$id: "tool/docker.Build"
+//
+//		// dir is the build context directory.
+//		dir: string
+//
+//		// dockerfile is the path of the Dockerfile to use. The default is
+//		// the file named Dockerfile in dir.
+//		dockerfile?: string
+//
+//		// tags holds the names under which the built image is tagged,
+//		// in the form name:tag.
+//		tags: [...string]
+//
+//		// buildArgs sets build-time variables.
+//		buildArgs: [string]: string
+//
+//		// target selects the build stage to build in a multi-stage Dockerfile.
+//		target?: string
+//
+//		// platform sets the target platform of the image, such as linux/amd64.
+//		platform?: string
+//
+//		// config is the directory holding the docker client configuration,
+//		// including registry credentials. The default is the standard
+//		// configuration directory used by the docker command.
+//		config?: string
+//
+//		// id is set to the ID of the built image.
+//		id: string
+//	}
+//
+//	// Tag creates a tag target that refers to the image source.
+//	Tag: {
+//		$id: "tool/docker.Tag"
+//
+//		source: string
+//		target: string
+//
+//		config?: string
+//	}
+//
+//	// Push pushes an image to its registry.
+//	Push: {
+//		$id: "tool/docker.Push"
+//
+//		// image is the name of the image to push, in the form name:tag.
+//		image: string
+//
+//		config?: string
+//
+//		// digest is set to the content digest of the pushed image,
+//		// such as "sha256:...". The image can be referred to
+//		// reproducibly as name@digest.
+//		digest: string
+//	}
+//
+//	// Inspect reports information about a local image.
+//	Inspect: {
+//		$id: "tool/docker.Inspect"
+//
+//		// image is the name or ID of the image to inspect.
+//		image: string
+//
+//		config?: string
+//
+//		// id is set to the ID of the image.
+//		id: string
+//
+//		// repoTags lists the tags which refer to the image.
+//		repoTags: [...string]
+//
+//		// repoDigests lists the image's digests in the registries it was
+//		// pushed to or pulled from, in the form name@digest.
+//		repoDigests: [...string]
+//	}
+package docker
+
+import (
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/pkg"
+)
+
+func init() {
+	pkg.Register("tool/docker", p)
+}
+
+var _ = adt.TopKind // in case the adt package isn't used
+
+var p = &pkg.Package{
+	Native: []*pkg.Builtin{},
+	CUE: `{
+	Build: {
+		$id:         "tool/docker.Build"
+		dir:         string
+		dockerfile?: string
+		tags: [...string]
+		buildArgs: [string]: string
+		target?:   string
+		platform?: string
+		config?:   string
+		id:        string
+	}
+	Tag: {
+		$id:     "tool/docker.Tag"
+		source:  string
+		target:  string
+		config?: string
+	}
+	Push: {
+		$id:     "tool/docker.Push"
+		image:   string
+		config?: string
+		digest:  string
+	}
+	Inspect: {
+		$id:     "tool/docker.Inspect"
+		image:   string
+		config?: string
+		id:      string
+		repoTags: [...string]
+		repoDigests: [...string]
+	}
+}`,
+}
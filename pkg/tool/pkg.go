@@ -53,6 +53,18 @@
 //		// $long is a longer description that spans multiple lines and
 //		// likely contain examples of usage of the command.
 //		$long?: string
+//
+//		// $flags declares the flags accepted by the command, which may be
+//		// referred to by its tasks. The type of each field determines the type
+//		// of the flag, its default the default of the flag, and its doc comment
+//		// the usage of the flag. Required fields declare required flags.
+//		//
+//		// Example:
+//		//     $flags: {
+//		//         // env is the target environment.
+//		//         env: *"staging" | "prod"
+//		//     }
+//		$flags?: [string]: string | int | bool
 //	}
 //
 //	// TODO:
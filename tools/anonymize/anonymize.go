@@ -0,0 +1,304 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package anonymize rewrites CUE files so that they can be shared, for
+// instance to report a bug, without revealing the names and values of a
+// proprietary configuration.
+//
+// Field names and other identifiers declared in the files are replaced by
+// meaningless names, string literals by meaningless strings, and comments
+// are removed. Replacements are consistent across all files: the same name
+// or string is always replaced by the same value, and a string equal to a
+// field name is replaced by the new name of that field. This preserves the
+// structure of a configuration, but not necessarily the results of
+// operations on strings, such as matching regular expressions or
+// computing their length.
+//
+// For example,
+//
+//	#Service: {
+//		name: string
+//		kind: *"web" | "db"
+//	}
+//	services: frontend: #Service & {name: "frontend"}
+//
+// is rewritten as
+//
+//	#f1: {
+//		f2: string
+//		f3: *"s4" | "s5"
+//	}
+//	f6: f7: #f1 & {f2: "f7"}
+package anonymize
+
+import (
+	"strconv"
+	"strings"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/literal"
+	"cuelang.org/go/cue/token"
+)
+
+// Config configures anonymization.
+type Config struct {
+	// IsLocal reports whether the package with the given import path is
+	// anonymized along with the files, in which case the names selected
+	// from it are replaced as well. Names selected from other packages,
+	// such as those of the standard library, are kept. If IsLocal is nil,
+	// no imported package is local.
+	IsLocal func(importPath string) bool
+}
+
+// Files anonymizes the given files in place. The files must have been
+// parsed with identifier resolution, as done by the parser by default.
+//
+// Import paths, package names, attributes, bytes literals, the text of
+// string interpolations and the operands of the regular expression
+// operators =~ and !~ are kept.
+func Files(files []*ast.File, cfg *Config) error {
+	a := &anonymizer{
+		cfg:      *cfg,
+		names:    map[string]string{},
+		topLevel: map[string]bool{},
+		used:     map[string]bool{},
+	}
+	for _, f := range files {
+		a.declare(f)
+	}
+	for _, f := range files {
+		a.rewrite(f)
+	}
+	return nil
+}
+
+type anonymizer struct {
+	cfg Config
+
+	// names maps declared names and strings to their replacement, or to
+	// "" if no replacement has been chosen yet.
+	names map[string]string
+
+	// topLevel holds the names of the fields declared at the top level
+	// of a file. As these are visible in all files of a package,
+	// references to them from other files are not resolved by the parser.
+	topLevel map[string]bool
+
+	// used holds all identifiers and strings, to avoid clashes with the
+	// replacements.
+	used map[string]bool
+
+	n int // number of replacements chosen so far
+}
+
+// declare records the names declared in f.
+func (a *anonymizer) declare(f *ast.File) {
+	for _, d := range f.Decls {
+		// Let declarations and aliases are only visible within their file.
+		if x, ok := d.(*ast.Field); ok {
+			if name, _, err := ast.LabelName(x.Label); err == nil {
+				a.topLevel[name] = true
+			}
+		}
+	}
+	ast.Walk(f, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.Ident:
+			a.used[x.Name] = true
+		case *ast.BasicLit:
+			if s, ok := stringValue(x); ok {
+				a.used[s] = true
+			}
+		case ast.Decl:
+			for _, name := range declNames(x) {
+				a.addName(name)
+			}
+		case *ast.ForClause:
+			if x.Key != nil {
+				a.addName(x.Key.Name)
+			}
+			a.addName(x.Value.Name)
+		}
+		return true
+	}, nil)
+}
+
+// declNames returns the names declared by d.
+func declNames(d ast.Node) []string {
+	var names []string
+	switch x := d.(type) {
+	case *ast.Field:
+		if al, ok := x.Label.(*ast.Alias); ok {
+			names = append(names, al.Ident.Name)
+		}
+		// Quoted labels are included, as they may be referred to
+		// by identifiers elsewhere.
+		if name, _, err := ast.LabelName(x.Label); err == nil {
+			names = append(names, name)
+		}
+	case *ast.Alias:
+		names = append(names, x.Ident.Name)
+	case *ast.LetClause:
+		names = append(names, x.Ident.Name)
+	}
+	return names
+}
+
+func (a *anonymizer) addName(name string) {
+	if name == "_" || strings.HasPrefix(name, "__") {
+		return
+	}
+	if _, ok := a.names[name]; !ok {
+		a.names[name] = ""
+	}
+}
+
+// replace returns the replacement for s, which is a name if isName is
+// set and a string otherwise. Names keep the prefix that makes them a
+// definition or hidden.
+func (a *anonymizer) replace(s string, isName bool) string {
+	if r := a.names[s]; r != "" {
+		return r
+	}
+	prefix := "s"
+	if isName {
+		prefix = "f"
+		for _, p := range []string{"_#", "#", "_"} {
+			if strings.HasPrefix(s, p) {
+				prefix = p + "f"
+				break
+			}
+		}
+	}
+	for {
+		a.n++
+		r := prefix + strconv.Itoa(a.n)
+		if !a.used[r] {
+			a.used[r] = true
+			a.names[s] = r
+			return r
+		}
+	}
+}
+
+// rewrite anonymizes f.
+func (a *anonymizer) rewrite(f *ast.File) {
+	// keep holds the nodes that must not be changed, and declared holds
+	// the identifiers that declare a name or select a field.
+	keep := map[ast.Node]bool{}
+	declared := map[*ast.Ident]bool{}
+	ast.Walk(f, func(n ast.Node) bool {
+		ast.SetComments(n, nil)
+		switch x := n.(type) {
+		case *ast.Package:
+			keep[x.Name] = true
+		case *ast.ImportSpec:
+			if x.Name != nil {
+				keep[x.Name] = true
+			}
+			keep[x.Path] = true
+		case *ast.Ident:
+			if _, ok := x.Node.(*ast.ImportSpec); ok {
+				keep[x] = true
+			}
+		case *ast.Field:
+			label := x.Label
+			if al, ok := label.(*ast.Alias); ok {
+				declared[al.Ident] = true
+				label, _ = al.Expr.(ast.Label)
+			}
+			if id, ok := label.(*ast.Ident); ok {
+				declared[id] = true
+			}
+		case *ast.Alias:
+			declared[x.Ident] = true
+		case *ast.LetClause:
+			declared[x.Ident] = true
+		case *ast.ForClause:
+			if x.Key != nil {
+				declared[x.Key] = true
+			}
+			declared[x.Value] = true
+		case *ast.SelectorExpr:
+			sel, ok := x.Sel.(*ast.Ident)
+			if !ok {
+				break
+			}
+			declared[sel] = true
+			if id, ok := x.X.(*ast.Ident); ok {
+				if spec, ok := id.Node.(*ast.ImportSpec); ok && !a.isLocal(spec) {
+					keep[sel] = true
+				}
+			}
+		case *ast.BinaryExpr:
+			if x.Op == token.MAT || x.Op == token.NMAT {
+				keep[x.Y] = true
+			}
+		case *ast.UnaryExpr:
+			if x.Op == token.MAT || x.Op == token.NMAT {
+				keep[x.X] = true
+			}
+		}
+		return true
+	}, nil)
+
+	ast.Walk(f, func(n ast.Node) bool {
+		if keep[n] {
+			return false
+		}
+		switch x := n.(type) {
+		case *ast.Ident:
+			if _, ok := a.names[x.Name]; !ok {
+				break
+			}
+			// Unresolved references that do not refer to a top-level
+			// declaration of another file refer to predeclared
+			// identifiers, such as int.
+			if declared[x] || x.Node != nil || a.topLevel[x.Name] {
+				x.Name = a.replace(x.Name, true)
+			}
+		case *ast.BasicLit:
+			if s, ok := stringValue(x); ok && s != "" {
+				_, isName := a.names[s]
+				x.Value = literal.String.Quote(a.replace(s, isName))
+			}
+		}
+		return true
+	}, nil)
+}
+
+// stringValue returns the value of x if it is a string literal.
+func stringValue(x *ast.BasicLit) (string, bool) {
+	if x.Kind != token.STRING {
+		return "", false
+	}
+	q, _, _, err := literal.ParseQuotes(x.Value, x.Value)
+	if err != nil || !q.IsDouble() {
+		return "", false
+	}
+	s, err := literal.Unquote(x.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// isLocal reports whether spec imports a package that is anonymized.
+func (a *anonymizer) isLocal(spec *ast.ImportSpec) bool {
+	if a.cfg.IsLocal == nil {
+		return false
+	}
+	path, err := strconv.Unquote(spec.Path.Value)
+	return err == nil && a.cfg.IsLocal(path)
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 The CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anonymize_test
+
+import (
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+	"cuelang.org/go/tools/anonymize"
+)
+
+func TestFiles(t *testing.T) {
+	const a = `package x
+
+import (
+	"strings"
+	"mod.test/lib"
+)
+
+// Services are exposed.
+#Service: {
+	name:   string
+	kind:   *"web" | "db"
+	"port": int
+	host:   =~"^[a-z]+$"
+}
+services: frontend: #Service & {
+	name: "frontend"
+	port: lib.defaultPort
+	host: strings.ToLower("ABC")
+	desc: "\(name) service" // interpolations are kept
+}
+for k, v in services {
+	names: "\(k)": v.name
+}
+`
+	const b = `package x
+
+let int = 3 // shadows the predeclared identifier in this file
+count:  int
+_count: len(services)
+`
+	const lib = `package lib
+
+defaultPort: 8080
+`
+	var files []*ast.File
+	for _, src := range []struct{ name, data string }{
+		{"a.cue", a},
+		{"b.cue", b},
+		{"lib.cue", lib},
+	} {
+		f, err := parser.ParseFile(src.name, src.data, parser.ParseComments)
+		qt.Assert(t, qt.IsNil(err))
+		files = append(files, f)
+	}
+	err := anonymize.Files(files, &anonymize.Config{
+		IsLocal: func(path string) bool { return path == "mod.test/lib" },
+	})
+	qt.Assert(t, qt.IsNil(err))
+
+	var got []string
+	for _, f := range files {
+		b, err := format.Node(f)
+		qt.Assert(t, qt.IsNil(err))
+		got = append(got, string(b))
+	}
+	qt.Assert(t, qt.DeepEquals(got, []string{`package x
+
+import (
+	"strings"
+	"mod.test/lib"
+)
+
+#f1: {
+	f2:   string
+	f3:   *"s4" | "s5"
+	"f6": int
+	f7:   =~"^[a-z]+$"
+}
+f8: f9: #f1 & {
+	f2:  "f9"
+	f6:  lib.f10
+	f7:  strings.ToLower("s11")
+	f12: "\(f2) service"
+}
+for f13, f14 in f8 {
+	f15: "\(f13)": f14.f2
+}
+`, `package x
+
+let f16 = 3
+f17:  f16
+_f18: len(f8)
+`, `package lib
+
+f10: 8080
+`}))
+}
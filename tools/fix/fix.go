@@ -15,11 +15,19 @@
 // Package fix contains functionality for writing CUE files with legacy
 // syntax to newer ones.
 //
+// Besides the built-in fixes, callers can define their own rewrites as a
+// [Rule] and apply them with the [Rules] option, either to a single file with
+// [File] or to all files of a set of instances, such as those of a whole
+// module, with [Instances]. [Diff] reports the changes that would be made
+// without modifying any files.
+//
 // Note: the transformations that are supported in this package will change
 // over time.
 package fix
 
 import (
+	"fmt"
+
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
 	"cuelang.org/go/cue/token"
@@ -29,6 +37,7 @@ type Option func(*options)
 
 type options struct {
 	simplify bool
+	rules    []Rule
 }
 
 // Simplify enables fixes that simplify the code, but are not strictly
@@ -43,7 +52,20 @@ func File(f *ast.File, o ...Option) *ast.File {
 	for _, f := range o {
 		f(&options)
 	}
+	f, err := fixFile(f, &options)
+	// TODO: this File method is public, and its signature was fixed
+	// before we started calling Sanitize. Ideally, we want to return
+	// this error, but that would require deprecating this File method,
+	// and creating a new one, which might happen in due course if we
+	// also discover that we need to be a bit more flexible than just
+	// accepting a File.
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
 
+func fixFile(f *ast.File, options *options) (*ast.File, error) {
 	// Make sure we use the "after" function, and not the "before",
 	// because "before" will stop recursion early which creates
 	// problems with nested expressions.
@@ -111,17 +133,13 @@ func File(f *ast.File, o ...Option) *ast.File {
 		f = simplify(f)
 	}
 
-	err := astutil.Sanitize(f)
-	// TODO: this File method is public, and its signature was fixed
-	// before we started calling Sanitize. Ideally, we want to return
-	// this error, but that would require deprecating this File method,
-	// and creating a new one, which might happen in due course if we
-	// also discover that we need to be a bit more flexible than just
-	// accepting a File.
-	if err != nil {
-		panic(err)
+	for _, r := range options.rules {
+		if err := r.Fix(f); err != nil {
+			return f, fmt.Errorf("%s: rule %s: %w", f.Filename, r.Name, err)
+		}
 	}
-	return f
+
+	return f, astutil.Sanitize(f)
 }
 
 func expandConcats(exprs ...ast.Expr) (result []ast.Expr) {
@@ -15,16 +15,23 @@
 package fix
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
+
+	"github.com/rogpeppe/go-internal/diff"
 
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
 )
 
 // Instances modifies all files contained in the given build instances at once.
 //
-// It also applies the fixes from [File].
+// It also applies the fixes from [File]. Unlike File, it reports the errors
+// of failing rules rather than panicking.
 func Instances(a []*build.Instance, o ...Option) errors.Error {
 	cwd, _ := os.Getwd()
 
@@ -33,19 +40,76 @@ func Instances(a []*build.Instance, o ...Option) errors.Error {
 		instances: a,
 		cwd:       cwd,
 	}
+	p.setOptions(o)
 
-	p.visitAll(func(f *ast.File) { File(f, o...) })
+	p.visitAll(func(f *ast.File) {
+		if _, err := fixFile(f, &p.options); err != nil {
+			p.err = errors.Append(p.err, errors.Promote(err, "fix"))
+		}
+	})
 
 	return p.err
 }
 
+// Diff reports the changes that [Instances] would make to the files of the
+// given build instances as a unified diff, with file names relative to the
+// current directory. Unlike Instances, it does not modify the files.
+func Diff(a []*build.Instance, o ...Option) ([]byte, errors.Error) {
+	cwd, _ := os.Getwd()
+
+	p := processor{
+		instances: a,
+		cwd:       cwd,
+	}
+	p.setOptions(o)
+
+	var buf bytes.Buffer
+	p.visitAll(func(f *ast.File) {
+		before, err := format.Node(f)
+		if err != nil {
+			p.err = errors.Append(p.err, errors.Promote(err, "format"))
+			return
+		}
+		// Fix a copy of the file, so that f is left untouched.
+		g, err := parser.ParseFile(f.Filename, before, parser.ParseComments)
+		if err != nil {
+			p.err = errors.Append(p.err, errors.Promote(err, "parse"))
+			return
+		}
+		g, err = fixFile(g, &p.options)
+		if err != nil {
+			p.err = errors.Append(p.err, errors.Promote(err, "fix"))
+			return
+		}
+		after, err := format.Node(g)
+		if err != nil {
+			p.err = errors.Append(p.err, errors.Promote(err, "format"))
+			return
+		}
+		name := f.Filename
+		if rel, err := filepath.Rel(p.cwd, name); err == nil {
+			name = rel
+		}
+		buf.Write(diff.Diff(name+".orig", before, name, after))
+	})
+
+	return buf.Bytes(), p.err
+}
+
 type processor struct {
 	instances []*build.Instance
 	cwd       string
+	options   options
 
 	err errors.Error
 }
 
+func (p *processor) setOptions(o []Option) {
+	for _, f := range o {
+		f(&p.options)
+	}
+}
+
 func (p *processor) visitAll(fn func(f *ast.File)) {
 	if p.err != nil {
 		return
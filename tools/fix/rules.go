@@ -0,0 +1,91 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fix
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"cuelang.org/go/cue/ast"
+)
+
+// A Rule rewrites files, for instance to rename a deprecated builtin or to
+// migrate the arguments of an attribute. Rules may be registered with
+// [Register] so that they can be selected by name, and are applied with
+// the [Rules] option.
+type Rule struct {
+	// Name identifies the rule. Names are conventionally lower case words
+	// separated by dashes, such as "rename-strings-contains".
+	Name string
+
+	// Doc is a one-line description of the rewrite.
+	Doc string
+
+	// Fix rewrites f in place. It should leave f unmodified if the rule
+	// does not apply.
+	Fix func(f *ast.File) error
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// Register makes r available by its name through [Lookup] and
+// [Registered]. It panics if r has no name or Fix function, or if a rule
+// with the same name was already registered.
+func Register(r Rule) {
+	if r.Name == "" || r.Fix == nil {
+		panic("fix: rule must have a name and a Fix function")
+	}
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	if _, ok := rules[r.Name]; ok {
+		panic(fmt.Sprintf("fix: rule %q registered twice", r.Name))
+	}
+	rules[r.Name] = r
+}
+
+// Lookup returns the registered rule with the given name.
+func Lookup(name string) (Rule, bool) {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	r, ok := rules[name]
+	return r, ok
+}
+
+// Registered returns all registered rules, sorted by name.
+func Registered() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	a := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		a = append(a, r)
+	}
+	slices.SortFunc(a, func(a, b Rule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return a
+}
+
+// Rules applies the given rules, in order, after the built-in fixes.
+//
+// As [File] cannot report errors, it panics if a rule fails. Use
+// [Instances] or [Diff] to have errors returned instead.
+func Rules(r ...Rule) Option {
+	return func(o *options) { o.rules = append(o.rules, r...) }
+}
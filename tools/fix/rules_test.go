@@ -0,0 +1,113 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fix
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-quicktest/qt"
+
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
+)
+
+// renameRule renames all references to the identifier from to to.
+func renameRule(from, to string) Rule {
+	return Rule{
+		Name: "rename-" + from,
+		Doc:  fmt.Sprintf("rename %s to %s", from, to),
+		Fix: func(f *ast.File) error {
+			astutil.Apply(f, func(c astutil.Cursor) bool {
+				if x, ok := c.Node().(*ast.Ident); ok && x.Name == from {
+					x.Name = to
+				}
+				return true
+			}, nil)
+			return nil
+		},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(renameRule("test-b", "b"))
+	Register(renameRule("test-a", "a"))
+
+	r, ok := Lookup("rename-test-a")
+	qt.Assert(t, qt.IsTrue(ok))
+	qt.Assert(t, qt.Equals(r.Doc, "rename test-a to a"))
+
+	var names []string
+	for _, r := range Registered() {
+		names = append(names, r.Name)
+	}
+	qt.Assert(t, qt.DeepEquals(names, []string{"rename-test-a", "rename-test-b"}))
+
+	qt.Assert(t, qt.PanicMatches(func() {
+		Register(renameRule("test-a", "c"))
+	}, `fix: rule "rename-test-a" registered twice`))
+}
+
+func TestRules(t *testing.T) {
+	f, err := parser.ParseFile("in.cue", "x: y + oldName\n")
+	qt.Assert(t, qt.IsNil(err))
+	File(f, Rules(renameRule("oldName", "newName")))
+	b, err := format.Node(f)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(b), "x: y + newName\n"))
+
+	failing := Rule{
+		Name: "failing",
+		Fix:  func(f *ast.File) error { return fmt.Errorf("cannot fix") },
+	}
+	qt.Assert(t, qt.PanicMatches(func() {
+		File(f, Rules(failing))
+	}, `in.cue: rule failing: cannot fix`))
+}
+
+func TestDiff(t *testing.T) {
+	cwd, err := os.Getwd()
+	qt.Assert(t, qt.IsNil(err))
+
+	inst := build.NewContext().NewInstance("", nil)
+	for _, file := range []struct{ name, src string }{
+		{"a.cue", "a: oldName\n"},
+		{"b.cue", "b: 1\n"}, // unchanged
+	} {
+		f, err := parser.ParseFile(filepath.Join(cwd, file.name), file.src)
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.IsNil(inst.AddSyntax(f)))
+	}
+
+	d, errs := Diff([]*build.Instance{inst}, Rules(renameRule("oldName", "newName")))
+	qt.Assert(t, qt.IsNil(errs))
+	qt.Assert(t, qt.Equals(string(d), `diff a.cue.orig a.cue
+--- a.cue.orig
++++ a.cue
+@@ -1,1 +1,1 @@
+-a: oldName
++a: newName
+`))
+
+	// The original files are left untouched.
+	b, err := format.Node(inst.Files[0])
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(b), "a: oldName\n"))
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"io"
+	"time"
+)
+
+// An EventKind identifies a step in the lifecycle of a task.
+type EventKind int
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=EventKind -trimprefix=Task
+
+const (
+	// TaskQueued indicates that all dependencies of a task have been
+	// resolved and that the task is ready to be run.
+	TaskQueued EventKind = iota
+
+	// TaskStarted indicates that the Runner of a task has been started.
+	TaskStarted
+
+	// TaskOutput indicates that a task has written output using a writer
+	// obtained from [Task.Output].
+	TaskOutput
+
+	// TaskCompleted indicates that a task terminated successfully and that
+	// its results have been added to the configuration.
+	TaskCompleted
+
+	// TaskFailed indicates that a task terminated with an error.
+	TaskFailed
+)
+
+// An Event reports a change in the lifecycle of a task.
+type Event struct {
+	Kind EventKind
+	Task *Task
+
+	// Time holds the time at which the event occurred.
+	Time time.Time
+
+	// Output holds the chunk of output written for a TaskOutput event.
+	// It must not be retained after the call to [Config.EventFunc] returns.
+	Output []byte
+
+	// Err holds the error of a TaskFailed event.
+	Err error
+}
+
+// event reports an event for task t if an EventFunc is configured.
+func (c *Controller) event(kind EventKind, t *Task, output []byte, err error) {
+	if c.cfg.EventFunc == nil {
+		return
+	}
+	c.eventMu.Lock()
+	defer c.eventMu.Unlock()
+	c.cfg.EventFunc(Event{
+		Kind:   kind,
+		Task:   t,
+		Time:   time.Now(),
+		Output: output,
+		Err:    err,
+	})
+}
+
+// Output returns a writer that a Runner may use to write the output of the
+// task. Writes are passed on to w and reported as TaskOutput events.
+// If no EventFunc is configured, Output returns w itself.
+func (t *Task) Output(w io.Writer) io.Writer {
+	if t.c.cfg.EventFunc == nil {
+		return w
+	}
+	return &outputWriter{t: t, w: w}
+}
+
+type outputWriter struct {
+	t *Task
+	w io.Writer
+}
+
+func (w *outputWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	if n > 0 {
+		w.t.c.event(TaskOutput, w.t, b[:n], nil)
+	}
+	return n, err
+}
@@ -71,6 +71,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 
 	"cuelang.org/go/cue"
@@ -150,6 +151,16 @@ type Config struct {
 	// updated. This includes directly after initialization. The task may be
 	// nil if this call is not the result of a task completing.
 	UpdateFunc func(c *Controller, t *Task) error
+
+	// EventFunc, if non-nil, is called for each step in the lifecycle of
+	// a task, allowing callers to report progress or to log the execution
+	// of a workflow. Calls are never made concurrently. With the exception
+	// of TaskOutput events, which are reported from the goroutine of the
+	// writing task, calls are made from the goroutine running the
+	// Controller, so that the methods of the Task may be used as from
+	// within UpdateFunc. EventFunc should return quickly, as it blocks the
+	// progress of the workflow.
+	EventFunc func(e Event)
 }
 
 // A Controller defines a set of Tasks to be executed.
@@ -178,6 +189,9 @@ type Controller struct {
 
 	done atomic.Bool
 
+	// eventMu serializes calls to Config.EventFunc.
+	eventMu sync.Mutex
+
 	// keys maps task keys to their index. This allows a recreation of the
 	// Instance while retaining the original task indices.
 	//
@@ -17,6 +17,7 @@ package flow_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"strings"
@@ -169,6 +170,50 @@ func TestFlowValuePanic(t *testing.T) {
 	t.Errorf("Value() did not panic")
 }
 
+func TestEvents(t *testing.T) {
+	v := cuecontext.New().CompileString(`
+	root: {
+		a: {$id: "valToOut", val: "hello"}
+		b: {$id: "print", val: a.out}
+		c: {$id: "failure", val: b.$id}
+	}
+	`)
+
+	var events []string
+	cfg := &flow.Config{
+		Root: cue.ParsePath("root"),
+		EventFunc: func(e flow.Event) {
+			s := fmt.Sprintf("%v %v", e.Kind, e.Task.Path())
+			switch e.Kind {
+			case flow.TaskOutput:
+				s += fmt.Sprintf(" %q", e.Output)
+			case flow.TaskFailed:
+				s += fmt.Sprintf(": %v", e.Err)
+			}
+			events = append(events, s)
+		},
+	}
+	c := flow.New(cfg, v, taskFunc)
+	if err := c.Run(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+
+	got := strings.Join(events, "\n")
+	want := `Queued root.a
+Started root.a
+Completed root.a
+Queued root.b
+Started root.b
+Output root.b "hello"
+Completed root.b
+Queued root.c
+Started root.c
+Failed root.c: task failed: failure`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
 func taskFunc(v cue.Value) (flow.Runner, error) {
 	idPath := cue.MakePath(cue.Str("$id"))
 	valPath := cue.MakePath(cue.Str("val"))
@@ -192,6 +237,16 @@ func taskFunc(v cue.Value) (flow.Runner, error) {
 			return nil
 		}), nil
 
+	case "print":
+		return flow.RunnerFunc(func(t *flow.Task) error {
+			str, err := t.Value().LookupPath(valPath).String()
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(t.Output(io.Discard), str)
+			return err
+		}), nil
+
 	case "failure":
 		return flow.RunnerFunc(func(t *flow.Task) error {
 			return errors.New("failure")
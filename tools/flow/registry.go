@@ -0,0 +1,156 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow
+
+import (
+	"fmt"
+	"sync"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/parser"
+)
+
+// idPath is the path of the field that identifies the type of a task.
+var idPath = cue.MakePath(cue.Str("$id"))
+
+// A Registry holds a set of task types, each identified by the value of the
+// $id field of a task and associated with a CUE schema for its fields.
+//
+// The TaskFunc method of a Registry can be passed to [New]. Task
+// declarations are then validated against the schema of their type when
+// the Controller is created, and again right before a task is run, once
+// the values of its dependencies are known. Errors are thus reported with
+// the positions of the offending fields rather than surfacing as runtime
+// failures of a Runner.
+//
+// The zero value is an empty Registry ready for use.
+type Registry struct {
+	mu    sync.Mutex
+	types map[string]*taskType
+}
+
+type taskType struct {
+	id     string
+	schema string
+	run    func(t *Task, v cue.Value) error
+}
+
+// Register registers a task type for tasks with the given $id.
+//
+// The schema is CUE source for an expression that every declaration of
+// the task must unify with, for instance
+//
+//	close({
+//		$id:     "example.com/fetch"
+//		url:     string
+//		retries: *3 | int & >=0
+//		body?:   string
+//	})
+//
+// A closed struct or a definition causes misspelled fields to be reported.
+// Fields that are filled in by the task when it completes, such as body
+// above, should be optional or left non-concrete. The schema may not
+// contain references to packages.
+//
+// When the task is run, f is called with the task and its value unified
+// with the schema, so that defaults from the schema are applied.
+//
+// It is an error to register the same $id twice.
+func (r *Registry) Register(id, schema string, f func(t *Task, v cue.Value) error) error {
+	if _, err := parser.ParseExpr(schemaFilename(id), schema); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[id]; ok {
+		return fmt.Errorf("task type %q already registered", id)
+	}
+	if r.types == nil {
+		r.types = map[string]*taskType{}
+	}
+	r.types[id] = &taskType{
+		id:     id,
+		schema: schema,
+		run:    f,
+	}
+	return nil
+}
+
+// TaskFunc implements [TaskFunc] for the registered task types. It reports
+// nil for values without an $id field and an error for values with an
+// unknown $id or for which the declaration does not match the schema of
+// its task type.
+func (r *Registry) TaskFunc(v cue.Value) (Runner, error) {
+	idv := v.LookupPath(idPath)
+	if !idv.Exists() {
+		return nil, nil
+	}
+	id, err := idv.String()
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	tt := r.types[id]
+	r.mu.Unlock()
+	if tt == nil {
+		return nil, errors.Newf(idv.Pos(), "unknown task type %q", id)
+	}
+	if _, err := tt.validate(v); err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+// Run implements [Runner]. It validates the task against the schema using
+// the current values of its dependencies before running it.
+func (tt *taskType) Run(t *Task, err error) error {
+	if err != nil {
+		return err
+	}
+	v, err := tt.validate(t.Value())
+	if err != nil {
+		return err
+	}
+	return tt.run(t, v)
+}
+
+// validate unifies v with the schema of tt and reports whether the result
+// is valid.
+func (tt *taskType) validate(v cue.Value) (cue.Value, error) {
+	schema, err := tt.compile(v.Context())
+	if err != nil {
+		return cue.Value{}, err
+	}
+	v = v.Unify(schema)
+	if err := v.Validate(); err != nil {
+		return cue.Value{}, errors.Wrapf(err, v.Pos(), "invalid %s task", tt.id)
+	}
+	return v, nil
+}
+
+// compile returns the schema of tt for use with values of ctx.
+//
+// The schema is compiled anew for each use rather than cached per context,
+// which would keep every context alive for as long as the Registry. Schemas
+// are small, so this is cheap compared to evaluating the tasks themselves.
+func (tt *taskType) compile(ctx *cue.Context) (cue.Value, error) {
+	v := ctx.CompileString(tt.schema, cue.Filename(schemaFilename(tt.id)))
+	return v, v.Err()
+}
+
+func schemaFilename(id string) string {
+	return "schema for " + id
+}
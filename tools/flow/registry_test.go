@@ -0,0 +1,109 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flow_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/tools/flow"
+)
+
+func TestRegistry(t *testing.T) {
+	var r flow.Registry
+	err := r.Register("repeat", `close({
+		$id:   "repeat"
+		text:  =~"^.{0,3}$"
+		count: *1 | int & >=1
+		out?:  string
+	})`, func(t *flow.Task, v cue.Value) error {
+		text, _ := v.LookupPath(cue.ParsePath("text")).String()
+		n, _ := v.LookupPath(cue.ParsePath("count")).Int64()
+		return t.Fill(map[string]string{"out": strings.Repeat(text, int(n))})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("repeat", `{}`, nil); err == nil {
+		t.Error("duplicate registration succeeded")
+	}
+	if err := r.Register("bad", `{`, nil); err == nil {
+		t.Error("registration of invalid schema succeeded")
+	}
+
+	testCases := []struct {
+		name string
+		in   string
+		out  string
+		err  string
+	}{{
+		name: "ok",
+		in: `
+		a: {$id: "repeat", text: "ab"}
+		b: {$id: "repeat", text: a.out, count: 3}
+		`,
+		out: `ababab`,
+	}, {
+		name: "unknown field",
+		in: `
+		a: {$id: "repeat", txt: "ab"}
+		`,
+		err: `invalid repeat task: root.a.txt: field not allowed (did you mean text?)`,
+	}, {
+		name: "invalid value",
+		in: `
+		a: {$id: "repeat", text: "ab", count: 0}
+		`,
+		err: `invalid repeat task: root.a.count: 2 errors in empty disjunction:`,
+	}, {
+		name: "invalid value from dependency",
+		in: `
+		a: {$id: "repeat", text: "ab", count: 2}
+		b: {$id: "repeat", text: a.out}
+		`,
+		err: `invalid repeat task: root.b.text: invalid value "abab" (out of bound =~"^.{0,3}$")`,
+	}, {
+		name: "unknown type",
+		in: `
+		a: {$id: "reverse", text: "ab"}
+		`,
+		err: `unknown task type "reverse"`,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := cuecontext.New().CompileString("root: {" + tc.in + "}")
+			c := flow.New(&flow.Config{Root: cue.ParsePath("root")}, v, r.TaskFunc)
+			err := c.Run(context.Background())
+			if tc.err != "" {
+				if got := fmt.Sprint(errors.Errors(err)[0]); got != tc.err {
+					t.Errorf("got error %q; want %q", got, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(errors.Details(err, nil))
+			}
+			out, _ := c.Value().LookupPath(cue.ParsePath("root.b.out")).String()
+			if out != tc.out {
+				t.Errorf("got %q; want %q", out, tc.out)
+			}
+		})
+	}
+}
@@ -71,6 +71,8 @@ func (c *Controller) runLoop() {
 
 				t.ctxt = eval.NewContext(value.ToInternal(t.v))
 
+				c.event(TaskStarted, t, nil, nil)
+
 				go func(t *Task) {
 					if err := t.r.Run(t, nil); err != nil {
 						t.err = errors.Promote(err, "task failed")
@@ -117,6 +119,7 @@ func (c *Controller) runLoop() {
 				fallthrough
 
 			default:
+				c.event(TaskFailed, t, nil, t.err)
 				c.addErr(t.err, "task failure")
 				return
 			}
@@ -132,6 +135,8 @@ func (c *Controller) runLoop() {
 
 			t.stats.Add(c.opCtx.Stats().Since(start))
 
+			c.event(TaskCompleted, t, nil, nil)
+
 			c.markReady(t)
 		}
 	}
@@ -141,6 +146,7 @@ func (c *Controller) markReady(t *Task) {
 	for _, x := range c.tasks {
 		if x.state == Waiting && x.isReady() {
 			x.state = Ready
+			c.event(TaskQueued, x, nil, nil)
 		}
 	}
 
@@ -0,0 +1,147 @@
+// Copyright 2026 CUE Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trim
+
+// This file contains the clean-up steps that run after fields have been
+// removed.
+
+import (
+	"cuelang.org/go/cue/ast"
+	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/token"
+	"cuelang.org/go/internal/core/adt"
+	"cuelang.org/go/internal/value"
+)
+
+// addOwners records the vertex of each struct literal contributing to v or
+// any of its descendants.
+func (t *trimmer) addOwners(v *adt.Vertex) {
+	if t.owners == nil {
+		t.owners = map[ast.Node]*adt.Vertex{}
+	}
+	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		if src, ok := c.Elem().Source().(*ast.StructLit); ok {
+			if _, ok := t.owners[src]; !ok {
+				t.owners[src] = v
+			}
+		}
+		return true
+	})
+	for _, a := range v.Arcs {
+		t.addOwners(a)
+	}
+}
+
+// collapse removes fields whose struct values became empty by trimming, if
+// the field is still declared elsewhere. It returns the fields it removed.
+func (t *trimmer) collapse(f *ast.File) (removed []Removal) {
+	collapsed := map[ast.Node]bool{}
+	astutil.Apply(f, nil, func(c astutil.Cursor) bool {
+		field, ok := c.Node().(*ast.Field)
+		if !ok {
+			return true
+		}
+		s, ok := field.Value.(*ast.StructLit)
+		if !ok || len(s.Elts) > 0 || !t.shrunk[s] {
+			return true
+		}
+		v := t.owners[s]
+		if v == nil {
+			return true
+		}
+		// Look for another declaration of the field that is kept.
+		var by ast.Node
+		v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+			src := c.Elem().Source()
+			if src == nil || src == s || collapsed[src] ||
+				(t.remove[src] != nil && !t.exclude[src]) {
+				return true
+			}
+			if f, ok := c.Field().(*adt.Field); ok && f.ArcType == adt.ArcMember {
+				by = src
+				return false
+			}
+			return true
+		})
+		if by == nil {
+			return true
+		}
+		collapsed[s] = true
+		t.shrunk[c.Parent().Node()] = true
+		c.Delete()
+		removed = append(removed, Removal{
+			Field: field,
+			Path:  value.Make(t.ctx, v).Path(),
+			By:    []token.Pos{by.Pos()},
+		})
+		return true
+	})
+	return removed
+}
+
+// removeUnusedLets removes let clauses from f that are no longer referenced.
+// As references are matched by name, lets that are shadowed may be kept.
+func removeUnusedLets(f *ast.File) {
+	for {
+		refs := map[string]int{}
+		lets := 0
+		ast.Walk(f, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.Ident:
+				refs[x.Name]++
+			case *ast.LetClause:
+				lets++
+			}
+			return true
+		}, nil)
+		if lets == 0 {
+			return
+		}
+
+		changed := false
+		astutil.Apply(f, func(c astutil.Cursor) bool {
+			// The identifier of the let clause itself accounts for one
+			// reference.
+			if x, ok := c.Node().(*ast.LetClause); ok && refs[x.Ident.Name] == 1 {
+				if _, ok := c.Parent().Node().(*ast.File); ok || isStruct(c.Parent().Node()) {
+					c.Delete()
+					changed = true
+				}
+			}
+			return true
+		}, nil)
+		if !changed {
+			return
+		}
+	}
+}
+
+func isStruct(n ast.Node) bool {
+	_, ok := n.(*ast.StructLit)
+	return ok
+}
+
+// removeEmptyImports removes import declarations without any imports.
+func removeEmptyImports(f *ast.File) {
+	k := 0
+	for _, d := range f.Decls {
+		if x, ok := d.(*ast.ImportDecl); ok && len(x.Specs) == 0 {
+			continue
+		}
+		f.Decls[k] = d
+		k++
+	}
+	f.Decls = f.Decls[:k]
+}
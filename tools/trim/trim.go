@@ -16,7 +16,9 @@
 // that "dominates" it. For instance, a value that is merged in from a
 // definition is considered to dominate a value from a regular struct that
 // mixes in this definition. Values derived from constraints and comprehensions
-// can also dominate other fields.
+// can also dominate other fields. A list is removed if all of its elements
+// are implied and its length is fixed by another list, such as the result of
+// a list comprehension.
 //
 // A value A is considered to be implied by a value B if A subsumes the default
 // value of B. For instance, if a definition defines a field `a: *1 | int` and
@@ -48,22 +50,60 @@
 package trim
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"slices"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/ast/astutil"
+	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/token"
 	"cuelang.org/go/internal/core/adt"
 	"cuelang.org/go/internal/core/debug"
 	"cuelang.org/go/internal/core/subsume"
 	"cuelang.org/go/internal/core/walk"
+	"cuelang.org/go/internal/diff"
 	"cuelang.org/go/internal/value"
 )
 
 // Config configures trim options.
 type Config struct {
 	Trace bool
+
+	// Schema, if it exists, restricts trimming to fields that are implied by
+	// this value, such as a definition or the value of a package. Fields
+	// implied only by other definitions, constraints or comprehensions are
+	// kept.
+	Schema cue.Value
+
+	// Verify, if set, re-evaluates the package after trimming and reports
+	// an error if the result differs structurally from the value before
+	// trimming when exported, that is, after resolving defaults, so that
+	// the trimmed files are known to be safe to write. The files are
+	// modified even if verification fails; callers should not write them
+	// in that case.
+	//
+	// The package is rebuilt from the build instance of the value to trim,
+	// with its files replaced by the trimmed ones with the same name. If
+	// the value was not built from an instance, it is rebuilt from the
+	// trimmed files alone.
+	Verify bool
+}
+
+// A Removal describes a field that was removed by trimming.
+type Removal struct {
+	// Field is the field that was removed from its file.
+	Field *ast.Field
+
+	// Path is the path of the removed field within the evaluated value.
+	Path cue.Path
+
+	// By holds the positions of the declarations that imply the value
+	// of the removed field.
+	By []token.Pos
 }
 
 // Files trims fields in the given files that can be implied from other fields,
@@ -71,15 +111,29 @@ type Config struct {
 // Trimming is done on a best-effort basis and only when the removed field
 // is clearly implied by another field, rather than equal sibling fields.
 func Files(files []*ast.File, inst cue.InstanceOrValue, cfg *Config) error {
+	_, err := FilesWithReport(files, inst, cfg)
+	return err
+}
+
+// FilesWithReport is like Files, but also reports the fields that were
+// removed, in the order in which they appeared in files.
+func FilesWithReport(files []*ast.File, inst cue.InstanceOrValue, cfg *Config) ([]Removal, error) {
 	r, v := value.ToInternal(inst.Value())
 
 	t := &trimmer{
-		Config:  *cfg,
-		ctx:     adt.NewContext(r, v),
-		remove:  map[ast.Node]bool{},
-		exclude: map[ast.Node]bool{},
-		debug:   Debug,
-		w:       os.Stderr,
+		Config:       *cfg,
+		ctx:          adt.NewContext(r, v),
+		remove:       map[ast.Node]*Removal{},
+		exclude:      map[ast.Node]bool{},
+		shrunk:       map[ast.Node]bool{},
+		debug:        Debug,
+		w:            os.Stderr,
+		comprehended: map[adt.Node]bool{},
+	}
+	if cfg.Schema.Exists() {
+		_, s := value.ToInternal(cfg.Schema)
+		t.schema = map[ast.Node]bool{}
+		t.addSchema(s)
 	}
 
 	// Mark certain expressions as off limits.
@@ -107,20 +161,79 @@ func Files(files []*ast.File, inst cue.InstanceOrValue, cfg *Config) error {
 
 	d, _, _, pickedDefault := t.addDominators(nil, v, false)
 	t.findSubordinates(d, v, pickedDefault)
+	t.addOwners(v)
 
 	// Remove subordinate values from files.
+	var removed []Removal
 	for _, f := range files {
+		n := len(removed)
 		astutil.Apply(f, func(c astutil.Cursor) bool {
-			if f, ok := c.Node().(*ast.Field); ok && t.remove[f.Value] && !t.exclude[f.Value] {
+			f, ok := c.Node().(*ast.Field)
+			if !ok || t.exclude[f.Value] {
+				return true
+			}
+			if r := t.remove[f.Value]; r != nil {
+				t.shrunk[c.Parent().Node()] = true
 				c.Delete()
+				r.Field = f
+				removed = append(removed, *r)
 			}
 			return true
 		}, nil)
+		if len(removed) > n {
+			// Clean up the declarations that only existed to support the
+			// removed fields.
+			removeUnusedLets(f)
+			removed = append(removed, t.collapse(f)...)
+		}
 		if err := astutil.Sanitize(f); err != nil {
-			return err
+			return removed, err
 		}
+		removeEmptyImports(f)
 	}
 
+	if cfg.Verify {
+		if err := verify(files, inst.Value()); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// verify checks that rebuilding v with the trimmed files results in the
+// same value.
+func verify(files []*ast.File, v cue.Value) error {
+	var b *build.Instance
+	if orig := v.BuildInstance(); orig != nil {
+		// Use a copy, as the runtime caches the results of building an
+		// instance.
+		inst := *orig
+		b = &inst
+		trimmed := map[string]*ast.File{}
+		for _, f := range files {
+			trimmed[f.Filename] = f
+		}
+		b.Files = slices.Clone(orig.Files)
+		for i, f := range b.Files {
+			if f := trimmed[f.Filename]; f != nil {
+				b.Files[i] = f
+			}
+		}
+	} else {
+		b = build.NewContext().NewInstance("", nil)
+		for _, f := range files {
+			if err := b.AddSyntax(f); err != nil {
+				return err
+			}
+		}
+	}
+	after := v.Context().BuildInstance(b)
+	if k, script := diff.Final.Diff(v, after); k != diff.Identity {
+		var buf bytes.Buffer
+		diff.Print(&buf, script)
+		return fmt.Errorf("trim: value differs after trimming:\n%s", buf.String())
+	}
 	return nil
 }
 
@@ -128,9 +241,25 @@ type trimmer struct {
 	Config
 
 	ctx     *adt.OpContext
-	remove  map[ast.Node]bool
+	remove  map[ast.Node]*Removal
 	exclude map[ast.Node]bool
 
+	// owners maps struct literals to the vertex to which they contribute.
+	owners map[ast.Node]*adt.Vertex
+
+	// shrunk holds the nodes from which fields were removed.
+	shrunk map[ast.Node]bool
+
+	// comprehended holds the nodes of the values of list comprehensions.
+	// Unlike those of struct comprehensions, the conjuncts they introduce
+	// are not marked as such by the evaluator.
+	comprehended map[adt.Node]bool
+
+	// schema holds the syntax of the values in Config.Schema. Only
+	// conjuncts originating from these nodes may dominate other conjuncts.
+	// It is nil if there is no schema.
+	schema map[ast.Node]bool
+
 	debug  bool
 	indent int
 	w      io.Writer
@@ -138,9 +267,50 @@ type trimmer struct {
 
 var Debug bool = false
 
-func (t *trimmer) markRemove(c adt.Conjunct) {
+// addSchema records the syntax of all conjuncts of v and its arcs.
+func (t *trimmer) addSchema(v *adt.Vertex) {
+	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		if src := c.Source(); src != nil && !t.schema[src] {
+			ast.Walk(src, func(n ast.Node) bool {
+				t.schema[n] = true
+				return true
+			}, nil)
+		}
+		return true
+	})
+	for _, a := range v.Arcs {
+		t.addSchema(a)
+	}
+}
+
+// inSchema reports whether c originates from Config.Schema.
+func (t *trimmer) inSchema(c adt.Conjunct) bool {
+	if t.schema == nil {
+		return true
+	}
+	src := c.Elem().Source()
+	if src == nil {
+		src = c.Source()
+	}
+	return src != nil && t.schema[src]
+}
+
+// markRemove marks the conjunct c of v for removal, recording the
+// dominators doms that imply it.
+func (t *trimmer) markRemove(c adt.Conjunct, v, doms *adt.Vertex) {
 	if src := c.Elem().Source(); src != nil {
-		t.remove[src] = true
+		if t.remove[src] == nil {
+			r := &Removal{Path: value.Make(t.ctx, v).Path()}
+			seen := map[token.Pos]bool{}
+			doms.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+				if src := c.Source(); src != nil && !seen[src.Pos()] {
+					seen[src.Pos()] = true
+					r.By = append(r.By, src.Pos())
+				}
+				return true
+			})
+			t.remove[src] = r
+		}
 		if t.debug {
 			t.logf("removing %s", debug.NodeString(t.ctx, c.Elem(), nil))
 		}
@@ -159,8 +329,8 @@ func (t *trimmer) markKeep(x adt.Expr) {
 const dominatorNode = adt.ComprehensionSpan | adt.DefinitionSpan | adt.ConstraintSpan
 
 // isDominator reports whether a node can remove other nodes.
-func isDominator(c adt.Conjunct) (ok, mayRemove bool) {
-	if !c.CloseInfo.IsInOneOf(dominatorNode) {
+func (t *trimmer) isDominator(c adt.Conjunct) (ok, mayRemove bool) {
+	if !c.CloseInfo.IsInOneOf(dominatorNode) && !t.comprehended[c.Field()] {
 		return false, false
 	}
 	switch f := c.Field().(type) {
@@ -181,7 +351,7 @@ func removable(c adt.Conjunct, v *adt.Vertex) bool {
 // themselves as it will eliminate the reason for the trigger.
 func (t *trimmer) allowRemove(v *adt.Vertex) (allow bool) {
 	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
-		_, allowRemove := isDominator(c)
+		_, allowRemove := t.isDominator(c)
 		loc := c.CloseInfo.Location() != c.Elem()
 		isSpan := c.CloseInfo.RootSpanType() != adt.ConstraintSpan
 		if allowRemove && (loc || isSpan) {
@@ -203,6 +373,76 @@ const (
 	yes
 )
 
+// trimList removes list literals from the conjuncts of v if all of their
+// elements are implied by dominators and the length of the list is fixed by
+// another conjunct, such as a list comprehension or a reference to a closed
+// list. It is called when all elements of v have been found to be implied,
+// but v itself was not contributed by a dominator.
+func (t *trimmer) trimList(v, doms *adt.Vertex) int {
+	var lists []adt.Conjunct
+	fixed := false
+	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		if l, ok := c.Elem().(*adt.ListLit); ok && isPlainList(l) {
+			if removable(c, v) {
+				lists = append(lists, c)
+			}
+			return true
+		}
+		// Evaluate the conjunct by itself to see if it determines the
+		// length of v.
+		n := &adt.Vertex{Parent: v.Parent, Label: v.Label}
+		n.AddConjunct(c)
+		n.Finalize(t.ctx)
+		if n.IsClosedList() && len(n.Elems()) == len(v.Elems()) {
+			fixed = true
+		}
+		return true
+	})
+	if !fixed || len(lists) == 0 {
+		return no
+	}
+	for _, c := range lists {
+		t.markRemove(c, v, doms)
+	}
+	return yes
+}
+
+// isPlainList reports whether l lists its elements literally, without
+// comprehensions or a trailing ellipsis.
+func isPlainList(l *adt.ListLit) bool {
+	for _, e := range l.Elems {
+		switch e.(type) {
+		case *adt.Comprehension, *adt.Ellipsis:
+			return false
+		}
+	}
+	return true
+}
+
+// addComprehended records the nodes of the values of the list
+// comprehensions in the conjuncts of v, so that the elements of v they
+// introduce are considered to be dominators.
+func (t *trimmer) addComprehended(v *adt.Vertex) {
+	visitor := &walk.Visitor{
+		Before: func(n adt.Node) bool {
+			t.comprehended[n] = true
+			return true
+		},
+	}
+	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
+		if l, ok := c.Elem().(*adt.ListLit); ok {
+			for _, e := range l.Elems {
+				if x, ok := e.(*adt.Comprehension); ok {
+					if y, ok := x.Value.(adt.Elem); ok {
+						visitor.Elem(y)
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
 // addDominators injects dominator values from v into d. If no default has
 // been selected from dominators so far, the values are erased. Otherwise,
 // both default and new values are merged.
@@ -232,17 +472,21 @@ func (t *trimmer) addDominators(d, v *adt.Vertex, hasDisjunction bool) (doms *ad
 
 	hasDoms := false
 	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
-		isDom, _ := isDominator(c)
+		isDom, _ := t.isDominator(c)
 		switch {
 		case isDom:
-			doms.AddConjunct(c)
+			if t.inSchema(c) {
+				doms.AddConjunct(c)
+			}
 		default:
 			if r, ok := c.Elem().(adt.Resolver); ok {
 				x, _ := t.ctx.Resolve(c, r)
 				// Even if this is not a dominator now, descendants will be.
 				if x != nil && x.Label.IsDef() {
 					x.VisitLeafConjuncts(func(c adt.Conjunct) bool {
-						doms.AddConjunct(c)
+						if t.inSchema(c) {
+							doms.AddConjunct(c)
+						}
 						return true
 					})
 					return false
@@ -283,6 +527,7 @@ func (t *trimmer) findSubordinates(doms, v *adt.Vertex, hasDisjunction bool) (re
 		}
 	}()
 
+	t.addComprehended(v)
 	doms, hasSubs, ambiguous, pickedDefault := t.addDominators(doms, v, hasDisjunction)
 
 	if ambiguous {
@@ -310,9 +555,14 @@ func (t *trimmer) findSubordinates(doms, v *adt.Vertex, hasDisjunction bool) (re
 	}
 
 	if !t.allowRemove(v) {
+		if v.IsList() && len(v.Arcs) > 0 {
+			return t.trimList(v, doms)
+		}
 		return no
 	}
 
+	arc := v // v may be replaced by its default below.
+
 	switch v.BaseValue.(type) {
 	case *adt.StructMarker, *adt.ListMarker:
 		// Rely on previous processing of the Arcs and the fact that we take the
@@ -344,9 +594,9 @@ func (t *trimmer) findSubordinates(doms, v *adt.Vertex, hasDisjunction bool) (re
 	}
 
 	v.VisitLeafConjuncts(func(c adt.Conjunct) bool {
-		_, allowRemove := isDominator(c)
+		_, allowRemove := t.isDominator(c)
 		if !allowRemove && removable(c, v) {
-			t.markRemove(c)
+			t.markRemove(c, arc, doms)
 		}
 		return true
 	})
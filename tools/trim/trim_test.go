@@ -15,10 +15,16 @@
 package trim
 
 import (
+	"fmt"
 	"testing"
 
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/ast"
 	"cuelang.org/go/cue/build"
+	"cuelang.org/go/cue/cuecontext"
 	"cuelang.org/go/cue/errors"
+	"cuelang.org/go/cue/format"
+	"cuelang.org/go/cue/parser"
 	"cuelang.org/go/internal/cuetdtest"
 	"cuelang.org/go/internal/cuetxtar"
 	"github.com/go-quicktest/qt"
@@ -51,7 +57,7 @@ func TestTrimFiles(t *testing.T) {
 
 		files := a.Files
 
-		err := Files(files, val, &Config{Trace: trace})
+		err := Files(files, val, &Config{Trace: trace, Verify: true})
 		if err != nil {
 			t.WriteErrors(errors.Promote(err, ""))
 		}
@@ -74,3 +80,92 @@ func TestTrimFiles(t *testing.T) {
 		}
 	})
 }
+
+func TestSchema(t *testing.T) {
+	const src = `
+#A: {a: 1, c: *3 | int}
+#B: {#A, b: 2}
+
+x: #B & {
+	a: 1
+	b: 2
+	c: 3
+}
+`
+	f, err := parser.ParseFile("in.cue", src)
+	qt.Assert(t, qt.IsNil(err))
+	v := cuecontext.New().BuildFile(f)
+	qt.Assert(t, qt.IsNil(v.Err()))
+
+	removed, err := FilesWithReport([]*ast.File{f}, v, &Config{
+		Schema: v.LookupPath(cue.ParsePath("#A")),
+	})
+	qt.Assert(t, qt.IsNil(err))
+
+	var got []string
+	for _, r := range removed {
+		got = append(got, fmt.Sprintf("%v by %v", r.Path, r.By))
+	}
+	qt.Assert(t, qt.DeepEquals(got, []string{
+		"x.a by [in.cue:2:6]",
+		"x.c by [in.cue:2:16]",
+	}))
+
+	b, err := format.Node(f)
+	qt.Assert(t, qt.IsNil(err))
+	qt.Assert(t, qt.Equals(string(b), `#A: {a: 1, c: *3 | int}
+#B: {#A, b: 2}
+
+x: #B & {
+	b: 2
+}
+`))
+}
+
+func TestVerify(t *testing.T) {
+	const src = `
+#A: {a: *1 | int, b: string}
+
+x: #A & {
+	a: 1
+	b: "foo"
+}
+`
+	ctx := cuecontext.New()
+	build := func(t *testing.T) (*ast.File, cue.Value) {
+		f, err := parser.ParseFile("in.cue", src)
+		qt.Assert(t, qt.IsNil(err))
+		inst := build.NewContext().NewInstance("", nil)
+		qt.Assert(t, qt.IsNil(inst.AddSyntax(f)))
+		v := ctx.BuildInstance(inst)
+		qt.Assert(t, qt.IsNil(v.Err()))
+		return f, v
+	}
+
+	t.Run("Unchanged", func(t *testing.T) {
+		f, v := build(t)
+		err := Files([]*ast.File{f}, v, &Config{Verify: true})
+		qt.Assert(t, qt.IsNil(err))
+		b, err := format.Node(f)
+		qt.Assert(t, qt.IsNil(err))
+		qt.Assert(t, qt.Equals(string(b), `#A: {a: *1 | int, b: string}
+
+x: #A & {
+	b: "foo"
+}
+`))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		// Simulate a trimming bug by changing the file after evaluation.
+		f, v := build(t)
+		ast.Walk(f, func(n ast.Node) bool {
+			if x, ok := n.(*ast.BasicLit); ok && x.Value == `"foo"` {
+				x.Value = `"bar"`
+			}
+			return true
+		}, nil)
+		err := Files([]*ast.File{f}, v, &Config{Verify: true})
+		qt.Assert(t, qt.ErrorMatches(err, `(?s)trim: value differs after trimming:.*"bar".*`))
+	})
+}